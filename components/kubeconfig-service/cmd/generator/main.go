@@ -9,7 +9,15 @@ import (
 	"syscall"
 
 	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authz"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/metrics"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/ratelimit"
 	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/reload"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/resolver"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/serviceaccount"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 
 	"github.com/gorilla/mux"
@@ -33,13 +41,57 @@ func main() {
 		log.Fatalf("Cannot create OIDC Authenticator, %v", err)
 	}
 
+	collector := metrics.NewCollector()
+	prometheus.MustRegister(collector)
+
 	ec := endpoints.NewEndpointClient(env.Config.GraphqlURL)
+	ec.SetMetrics(collector)
+	ec.SetClusterCache(cache.NewCache(env.Config.Cache.TTL))
+	serviceAccounts := serviceaccount.NewManager(readServiceAccountConfig(), ec.AdminKubeconfig)
+	if env.Config.ServiceAccount.Registry.ConfigMap != "" {
+		registry, err := serviceaccount.NewConfigMapRegistry(readServiceAccountRegistryConfig())
+		if err != nil {
+			log.Fatalf("Cannot create service account registry, %v", err)
+		}
+		serviceAccounts.SetRegistry(registry)
+	}
+	ec.SetServiceAccountManager(serviceAccounts)
+	go serviceAccounts.Run(fileWatcherCtx)
+	ec.SetMaxMergedRuntimes(env.Config.Merge.MaxRuntimes)
+	if env.Config.KEB.URL != "" {
+		log.Infof("Using Kyma Environment Broker for runtime lookup: %s", env.Config.KEB.URL)
+		ec.SetRuntimeResolver(resolver.NewResolver(readResolverConfig()))
+	}
+	var authorizer *authz.Authorizer
+	if env.Config.Authorization.Enabled {
+		//the tenants of the runtimes are looked up with the Kyma Environment Broker
+		if env.Config.KEB.URL == "" {
+			log.Fatal("Cannot enable authorization, the Kyma Environment Broker URL is required to look up the tenants of the runtimes")
+		}
+		log.Info("Authorizing the requests by the tenant of the caller")
+		authorizer = authz.NewAuthorizer(readAuthzConfig())
+		ec.SetAuthorizer(authorizer)
+	}
 	router := mux.NewRouter()
+	router.Use(collector.Middleware)
 	router.Use(authn.AuthMiddleware(oidcAuthenticator))
-	router.Methods("GET").Path("/kubeconfig/{tenantID}/{runtimeID}").HandlerFunc(ec.GetKubeConfig)
+	if env.Config.RateLimit.RequestsPerSecond > 0 {
+		log.Infof("Limiting the requests of each user to %v per second", env.Config.RateLimit.RequestsPerSecond)
+		router.Use(ratelimit.NewLimiter(readRateLimitConfig()).Middleware)
+	}
+	//the tenants of the merged kubeconfig are given in the request body and authorized by the endpoint
+	router.Methods("POST").Path("/kubeconfigs").HandlerFunc(ec.GetMergedKubeConfig)
+	//the tenant of the looked up runtime is authorized by the endpoint as well
+	router.Methods("GET").Path("/kubeconfig").HandlerFunc(ec.LookupKubeConfig)
+	tenantRouter := router.PathPrefix("/kubeconfig/{tenantID}").Subrouter()
+	if authorizer != nil {
+		tenantRouter.Use(authorizer.Middleware)
+	}
+	tenantRouter.Methods("GET").Path("/{runtimeID}").HandlerFunc(ec.GetKubeConfig)
 
 	healthRouter := mux.NewRouter()
 	healthRouter.Methods("GET").Path("/health/ready").HandlerFunc(ec.GetHealthStatus)
+	healthRouter.Methods("GET").Path("/metrics").Handler(promhttp.Handler())
 
 	term := make(chan os.Signal)
 	signal.Notify(term, os.Interrupt, syscall.SIGTERM)
@@ -91,6 +143,50 @@ func readAuthnConfig() *authn.OIDCConfig {
 	}
 }
 
+func readAuthzConfig() authz.Config {
+	return authz.Config{
+		TenantClaim:    env.Config.OIDC.Claim.Tenant,
+		GroupsPrefix:   env.Config.OIDC.Prefix.Groups,
+		OperatorGroups: env.Config.Authorization.OperatorGroups,
+	}
+}
+
+func readServiceAccountConfig() serviceaccount.Config {
+	return serviceaccount.Config{
+		Namespace:  env.Config.ServiceAccount.Namespace,
+		Roles:      env.Config.ServiceAccount.Roles,
+		DefaultTTL: env.Config.ServiceAccount.DefaultTTL,
+		MaxTTL:     env.Config.ServiceAccount.MaxTTL,
+		GCInterval: env.Config.ServiceAccount.GCInterval,
+	}
+}
+
+func readServiceAccountRegistryConfig() serviceaccount.RegistryConfig {
+	return serviceaccount.RegistryConfig{
+		Namespace: env.Config.ServiceAccount.Registry.Namespace,
+		ConfigMap: env.Config.ServiceAccount.Registry.ConfigMap,
+	}
+}
+
+func readResolverConfig() resolver.Config {
+	return resolver.Config{
+		URL:          env.Config.KEB.URL,
+		TokenURL:     env.Config.KEB.OAuth.TokenURL,
+		ClientID:     env.Config.KEB.OAuth.ClientID,
+		ClientSecret: env.Config.KEB.OAuth.ClientSecret,
+		Scopes:       env.Config.KEB.OAuth.Scopes,
+		CacheTTL:     env.Config.KEB.CacheTTL,
+	}
+}
+
+func readRateLimitConfig() ratelimit.Config {
+	return ratelimit.Config{
+		RequestsPerSecond: env.Config.RateLimit.RequestsPerSecond,
+		Burst:             env.Config.RateLimit.Burst,
+		IdleTimeout:       env.Config.RateLimit.IdleTimeout,
+	}
+}
+
 func setupOIDCAuthReloader(fileWatcherCtx context.Context, cfg *authn.OIDCConfig) (authenticator.Request, error) {
 	const eventBatchDelaySeconds = 10
 	filesToWatch := []string{cfg.CAFilePath}
@@ -5,17 +5,22 @@ go 1.13
 require (
 	github.com/avast/retry-go v2.6.0+incompatible
 	github.com/fsnotify/fsnotify v1.4.9
+	github.com/golang/protobuf v1.4.2 // indirect
 	github.com/gorilla/mux v1.7.4
 	github.com/kyma-project/control-plane/components/provisioner v0.0.0-20200702142454-d5c043eb0dbe
 	github.com/machinebox/graphql v0.2.3-0.20181106130121-3a9253180225
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.6.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/smartystreets/goconvey v1.6.4
 	github.com/stretchr/testify v1.5.1
 	github.com/vrischmann/envconfig v1.2.0
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
+	golang.org/x/time v0.0.0-20190921001708-c4c64cad1fd0
 	gopkg.in/yaml.v2 v2.3.0
+	k8s.io/api v0.18.10
 	k8s.io/apiserver v0.18.10
+	k8s.io/client-go v0.18.10
 )
 
 replace (
@@ -0,0 +1,61 @@
+package authn
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+type callerKey struct{}
+
+//Caller represents the authenticated caller of a request
+type Caller struct {
+	User user.Info
+	//Claims of the caller token, they are empty if the token could not be decoded
+	Claims map[string]interface{}
+}
+
+//WithCaller returns a copy of the context holding the caller
+func WithCaller(ctx context.Context, caller *Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+//CallerFrom returns the caller of the request context, if any
+func CallerFrom(ctx context.Context) (*Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(*Caller)
+	return caller, ok
+}
+
+//bearerToken returns the token of the "Authorization" header of the request
+func bearerToken(header string) string {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(parts[1])
+}
+
+//decodeClaims returns the claims of the JWT payload, the token signature must have been verified by the authenticator
+func decodeClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.Wrap(err, "while decoding JWT payload")
+	}
+
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errors.Wrap(err, "while unmarshalling JWT claims")
+	}
+
+	return claims, nil
+}
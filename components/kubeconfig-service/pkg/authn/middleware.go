@@ -7,11 +7,13 @@ import (
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 )
 
+//AuthMiddleware authenticates the requests and passes the authenticated caller to the next handler in the request context
 func AuthMiddleware(a authenticator.Request) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := bearerToken(r.Header.Get("Authorization"))
 
-			_, ok, err := a.AuthenticateRequest(r) //Strips "Authorization" Header value on auth success!
+			resp, ok, err := a.AuthenticateRequest(r) //Strips "Authorization" Header value on auth success!
 			if err != nil {
 				log.Errorf("Unable to authenticate the request due to an error: %v", err)
 			}
@@ -20,7 +22,18 @@ func AuthMiddleware(a authenticator.Request) func(http.Handler) http.Handler {
 				return
 			}
 
-			next.ServeHTTP(w, r)
+			caller := &Caller{Claims: map[string]interface{}{}}
+			if resp != nil {
+				caller.User = resp.User
+			}
+
+			if claims, err := decodeClaims(token); err != nil {
+				log.Debugf("Unable to decode the claims of the authenticated token: %v", err)
+			} else {
+				caller.Claims = claims
+			}
+
+			next.ServeHTTP(w, r.WithContext(WithCaller(r.Context(), caller)))
 		})
 	}
 }
@@ -0,0 +1,93 @@
+package authz
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	log "github.com/sirupsen/logrus"
+)
+
+//Config represents configuration used for request authorization
+type Config struct {
+	//TenantClaim is the claim holding the tenant, or the list of tenants, the caller belongs to
+	TenantClaim string
+	//GroupsPrefix is the prefix of the groups of the authenticated caller
+	GroupsPrefix string
+	//OperatorGroups are the groups allowed to access the runtimes of all tenants
+	OperatorGroups []string
+}
+
+//Authorizer checks that the callers are allowed to access the runtimes of a tenant
+type Authorizer struct {
+	config         Config
+	operatorGroups map[string]bool
+}
+
+//NewAuthorizer returns a new Authorizer
+func NewAuthorizer(config Config) *Authorizer {
+	operatorGroups := make(map[string]bool, len(config.OperatorGroups))
+	for _, group := range config.OperatorGroups {
+		operatorGroups[config.GroupsPrefix+group] = true
+	}
+
+	return &Authorizer{
+		config:         config,
+		operatorGroups: operatorGroups,
+	}
+}
+
+//Authorize returns true if the caller belongs to the tenant, either by a group named after the tenant or by the tenant claim,
+//or if the caller belongs to an operator group
+func (a *Authorizer) Authorize(caller *authn.Caller, tenant string) bool {
+	if caller == nil || tenant == "" {
+		return false
+	}
+
+	if caller.User != nil {
+		for _, group := range caller.User.GetGroups() {
+			if a.operatorGroups[group] || group == a.config.GroupsPrefix+tenant {
+				return true
+			}
+		}
+	}
+
+	if a.config.TenantClaim == "" {
+		return false
+	}
+
+	switch value := caller.Claims[a.config.TenantClaim].(type) {
+	case string:
+		return value == tenant
+	case []interface{}:
+		for _, item := range value {
+			if s, ok := item.(string); ok && s == tenant {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+//Middleware rejects with status code forbidden the requests of callers not allowed to access the tenant of the "tenantID" path variable
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant := mux.Vars(r)["tenantID"]
+
+		caller, _ := authn.CallerFrom(r.Context())
+		if !a.Authorize(caller, tenant) {
+			name := ""
+			if caller != nil && caller.User != nil {
+				name = caller.User.GetName()
+			}
+
+			log.Warnf("User %q is not allowed to access the runtimes of tenant %s", name, tenant)
+			http.Error(w, "Forbidden", http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,228 @@
+package authz
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const (
+	testTenant   = "tenant-1"
+	testClientID = "kubeconfig-service"
+)
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	authorizer := NewAuthorizer(Config{
+		TenantClaim:    "tenant",
+		GroupsPrefix:   "oidc:",
+		OperatorGroups: []string{"operators"},
+	})
+
+	for _, tc := range []struct {
+		name     string
+		caller   *authn.Caller
+		expected bool
+	}{
+		{
+			name:     "caller in operator group",
+			caller:   &authn.Caller{User: &user.DefaultInfo{Groups: []string{"oidc:operators"}}},
+			expected: true,
+		},
+		{
+			name:     "caller in tenant group",
+			caller:   &authn.Caller{User: &user.DefaultInfo{Groups: []string{"oidc:" + testTenant}}},
+			expected: true,
+		},
+		{
+			name:     "caller with tenant claim",
+			caller:   &authn.Caller{User: &user.DefaultInfo{}, Claims: map[string]interface{}{"tenant": testTenant}},
+			expected: true,
+		},
+		{
+			name:     "caller with tenant in claim list",
+			caller:   &authn.Caller{User: &user.DefaultInfo{}, Claims: map[string]interface{}{"tenant": []interface{}{"tenant-2", testTenant}}},
+			expected: true,
+		},
+		{
+			name:     "caller of another tenant",
+			caller:   &authn.Caller{User: &user.DefaultInfo{Groups: []string{"oidc:tenant-2"}}, Claims: map[string]interface{}{"tenant": "tenant-2"}},
+			expected: false,
+		},
+		{
+			name:     "caller in unprefixed operator group",
+			caller:   &authn.Caller{User: &user.DefaultInfo{Groups: []string{"operators"}}},
+			expected: false,
+		},
+		{
+			name:     "no caller",
+			caller:   nil,
+			expected: false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, authorizer.Authorize(tc.caller, testTenant))
+		})
+	}
+
+	t.Run("tenant claim not configured", func(t *testing.T) {
+		authorizer := NewAuthorizer(Config{})
+		caller := &authn.Caller{User: &user.DefaultInfo{}, Claims: map[string]interface{}{"tenant": testTenant}}
+
+		assert.False(t, authorizer.Authorize(caller, testTenant))
+	})
+}
+
+func TestAuthorizer_Middleware(t *testing.T) {
+	//given
+	issuer := newIssuerStub(t)
+	defer issuer.Close()
+
+	oidcAuthenticator, err := authn.NewOIDCAuthenticator(&authn.OIDCConfig{
+		IssuerURL:            issuer.URL,
+		ClientID:             testClientID,
+		CAFilePath:           issuer.caFile,
+		UsernameClaim:        "sub",
+		GroupsClaim:          "groups",
+		SupportedSigningAlgs: []string{"RS256"},
+	})
+	require.NoError(t, err)
+	defer oidcAuthenticator.Cancel()
+
+	authorizer := NewAuthorizer(Config{TenantClaim: "tenant", OperatorGroups: []string{"operators"}})
+
+	router := mux.NewRouter()
+	router.Use(authn.AuthMiddleware(oidcAuthenticator))
+	router.Use(authorizer.Middleware)
+	router.Methods("GET").Path("/kubeconfig/{tenantID}/{runtimeID}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	get := func(claims map[string]interface{}) int {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/kubeconfig/%s/runtime-1", testTenant), nil)
+		req.Header.Set("Authorization", "Bearer "+issuer.token(t, claims))
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+
+		return response.Code
+	}
+
+	//the authenticator is initialized asynchronously
+	require.Eventually(t, func() bool {
+		return get(map[string]interface{}{"tenant": testTenant}) != http.StatusUnauthorized
+	}, 30*time.Second, 500*time.Millisecond)
+
+	t.Run("When caller has the tenant claim", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, get(map[string]interface{}{"tenant": testTenant}))
+	})
+	t.Run("When caller belongs to another tenant", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, get(map[string]interface{}{"tenant": "tenant-2"}))
+	})
+	t.Run("When caller is an operator", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, get(map[string]interface{}{"groups": []string{"operators"}}))
+	})
+	t.Run("When token is not valid", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, get(map[string]interface{}{"tenant": testTenant, "aud": "another-client"}))
+	})
+}
+
+//issuerStub is a local OIDC issuer serving the discovery document and the keys verifying the tokens it signs
+type issuerStub struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	caFile string
+}
+
+func newIssuerStub(t *testing.T) *issuerStub {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	stub := &issuerStub{key: key}
+
+	handler := http.NewServeMux()
+	handler.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                                stub.URL,
+			"authorization_endpoint":                stub.URL + "/auth",
+			"token_endpoint":                        stub.URL + "/token",
+			"jwks_uri":                              stub.URL + "/keys",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	handler.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": "test",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	stub.Server = httptest.NewTLSServer(handler)
+
+	caFile, err := ioutil.TempFile("", "oidc-ca")
+	require.NoError(t, err)
+	defer caFile.Close()
+
+	require.NoError(t, pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: stub.Certificate().Raw}))
+	stub.caFile = caFile.Name()
+
+	return stub
+}
+
+func (s *issuerStub) Close() {
+	s.Server.Close()
+	os.Remove(s.caFile)
+}
+
+//token returns a token signed by the issuer for the subject "test-user" with the additional claims
+func (s *issuerStub) token(t *testing.T, claims map[string]interface{}) string {
+	payload := map[string]interface{}{
+		"iss": s.URL,
+		"aud": testClientID,
+		"sub": "test-user",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for claim, value := range claims {
+		payload[claim] = value
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": "test"})
+	require.NoError(t, err)
+	body, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(signed))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+//maxEntries is the number of cached clusters above which the expired entries are removed
+const maxEntries = 10000
+
+//Key identifies a runtime, the tenant is a part of the key so that the cluster is cached only for the tenant validated by the provisioner
+type Key struct {
+	TenantID  string
+	RuntimeID string
+}
+
+//Cluster represents the data of the runtime kubeconfig that doesn't depend on the caller, the credentials are never cached
+type Cluster struct {
+	ContextName string
+	CAData      string
+	ServerURL   string
+}
+
+type entry struct {
+	cluster   Cluster
+	expiresAt time.Time
+}
+
+//Cache is an in-process TTL cache of the runtime clusters
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	lock    sync.Mutex
+	entries map[Key]entry
+}
+
+//NewCache returns a new Cache, the clusters are not cached if the ttl is not positive
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[Key]entry),
+	}
+}
+
+//Get returns the cluster of the runtime if it is cached and not expired
+func (c *Cache) Get(key Key) (Cluster, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return Cluster{}, false
+	}
+	if !c.now().Before(e.expiresAt) {
+		delete(c.entries, key)
+		return Cluster{}, false
+	}
+
+	return e.cluster, true
+}
+
+//Set caches the cluster of the runtime for the ttl of the cache
+func (c *Cache) Set(key Key, cluster Cluster) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= maxEntries {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[key] = entry{cluster: cluster, expiresAt: now.Add(c.ttl)}
+}
+
+//Invalidate removes the cluster of the runtime from the cache
+func (c *Cache) Invalidate(key Key) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, key)
+}
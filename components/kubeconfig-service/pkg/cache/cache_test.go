@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	//given
+	key := Key{TenantID: "tenant", RuntimeID: "runtime"}
+	cluster := Cluster{ContextName: "c-1234", CAData: "Q0EK", ServerURL: "https://api.c-1234.kyma.local"}
+
+	t.Run("Should return cached cluster until it expires", func(t *testing.T) {
+		//given
+		cache := NewCache(time.Minute)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		//when
+		cache.Set(key, cluster)
+		cached, ok := cache.Get(key)
+
+		//then
+		assert.True(t, ok)
+		assert.Equal(t, cluster, cached)
+
+		//when
+		now = now.Add(time.Minute)
+		_, ok = cache.Get(key)
+
+		//then
+		assert.False(t, ok)
+	})
+
+	t.Run("Should not share cluster between tenants", func(t *testing.T) {
+		//given
+		cache := NewCache(time.Minute)
+		cache.Set(key, cluster)
+
+		//when
+		_, ok := cache.Get(Key{TenantID: "other", RuntimeID: key.RuntimeID})
+
+		//then
+		assert.False(t, ok)
+	})
+
+	t.Run("Should not return invalidated cluster", func(t *testing.T) {
+		//given
+		cache := NewCache(time.Minute)
+		cache.Set(key, cluster)
+
+		//when
+		cache.Invalidate(key)
+		_, ok := cache.Get(key)
+
+		//then
+		assert.False(t, ok)
+	})
+
+	t.Run("Should not cache cluster if disabled", func(t *testing.T) {
+		//given
+		cache := NewCache(0)
+
+		//when
+		cache.Set(key, cluster)
+		_, ok := cache.Get(key)
+
+		//then
+		assert.False(t, ok)
+	})
+}
@@ -53,6 +53,59 @@ func (c Caller) RuntimeStatus(runtimeID string) (schema.RuntimeStatus, error) {
 	return response, nil
 }
 
+//Kubeconfig returns the kubeconfig of the runtime, without loading the rest of the runtime configuration
+func (c Caller) Kubeconfig(runtimeID string) (string, error) {
+	query := c.queryProvider.kubeconfig(runtimeID)
+	req := c.newRequest(query)
+
+	var response *string
+	err := c.executeRequest(req, &response)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to get Runtime kubeconfig")
+	}
+	if response == nil {
+		return "", nil
+	}
+	return *response, nil
+}
+
+//RuntimeKubeconfig represents the kubeconfig of a runtime and the name of its shoot
+type RuntimeKubeconfig struct {
+	Kubeconfig string
+	ShootName  string
+}
+
+//RuntimeKubeconfig returns the kubeconfig of the runtime with the name of its shoot
+func (c Caller) RuntimeKubeconfig(runtimeID string) (RuntimeKubeconfig, error) {
+	query := c.queryProvider.runtimeKubeconfig(runtimeID)
+	req := c.newRequest(query)
+
+	//the cluster configuration is not decoded into schema.GardenerConfig which requires the provider specific configuration
+	var response struct {
+		RuntimeConfiguration *struct {
+			Kubeconfig    *string `json:"kubeconfig"`
+			ClusterConfig *struct {
+				Name *string `json:"name"`
+			} `json:"clusterConfig"`
+		} `json:"runtimeConfiguration"`
+	}
+	err := c.executeRequest(req, &response)
+	if err != nil {
+		return RuntimeKubeconfig{}, errors.Wrap(err, "Failed to get Runtime kubeconfig")
+	}
+
+	var result RuntimeKubeconfig
+	if config := response.RuntimeConfiguration; config != nil {
+		if config.Kubeconfig != nil {
+			result.Kubeconfig = *config.Kubeconfig
+		}
+		if config.ClusterConfig != nil && config.ClusterConfig.Name != nil {
+			result.ShootName = *config.ClusterConfig.Name
+		}
+	}
+	return result, nil
+}
+
 type graphQLResponseWrapper struct {
 	Result interface{} `json:"result"`
 }
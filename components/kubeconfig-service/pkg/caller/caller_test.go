@@ -110,5 +110,54 @@ func TestSpec(t *testing.T) {
 
 			})
 		})
+
+		Convey("Kubeconfig()", func() {
+			Convey("Should return only the kubeconfig of the runtime", func(c C) {
+
+				//given
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+					c.So(r.Header.Get(caller.TenantHeader), ShouldEqual, testTenant)
+
+					b, err := ioutil.ReadAll(r.Body)
+					c.So(err, ShouldBeNil)
+
+					//Assertion on runtimeKubeconfig parameter (embedded in the query)
+					c.So(string(b), ShouldContainSubstring, fmt.Sprintf(`result: runtimeKubeconfig(id: \"%s\")`, testRuntimeID))
+
+					_, err = io.WriteString(w, fmt.Sprintf(`{"data": {"result": "%s"}}`, testKubeconfig))
+					c.So(err, ShouldBeNil)
+				}))
+				defer srv.Close()
+
+				cllr := caller.NewCaller(srv.URL, testTenant)
+
+				//when
+				res, err := cllr.Kubeconfig(testRuntimeID)
+
+				//then
+				So(err, ShouldBeNil)
+				So(res, ShouldEqual, testKubeconfig)
+			})
+
+			Convey("Should return empty kubeconfig of runtime not provisioned yet", func(c C) {
+
+				//given
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, err := io.WriteString(w, `{"data": {"result": null}}`)
+					c.So(err, ShouldBeNil)
+				}))
+				defer srv.Close()
+
+				cllr := caller.NewCaller(srv.URL, testTenant)
+
+				//when
+				res, err := cllr.Kubeconfig(testRuntimeID)
+
+				//then
+				So(err, ShouldBeNil)
+				So(res, ShouldBeEmpty)
+			})
+		})
 	})
 }
@@ -17,3 +17,22 @@ func runtimeStatusData() string {
 				kubeconfig
 			}`
 }
+
+func (qp queryProvider) runtimeKubeconfig(runtimeID string) string {
+	return fmt.Sprintf(`query {
+	result: runtimeStatus(id: "%s") {
+		runtimeConfiguration {
+			kubeconfig
+			clusterConfig {
+				name
+			}
+		}
+	}
+}`, runtimeID)
+}
+
+func (qp queryProvider) kubeconfig(runtimeID string) string {
+	return fmt.Sprintf(`query {
+	result: runtimeKubeconfig(id: "%s")
+}`, runtimeID)
+}
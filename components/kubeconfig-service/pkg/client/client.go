@@ -1,7 +1,9 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"html"
 	"io"
@@ -15,6 +17,18 @@ import (
 // Client is the interface to interact with the kubeconfig-service as an HTTP client using OIDC ID token in JWT format.
 type Client interface {
 	GetKubeConfig(tenantID, runtimeID string) (string, error)
+	GetMergedKubeConfig(runtimes []Runtime) (string, error)
+}
+
+// Runtime identifies a runtime of a tenant
+type Runtime struct {
+	TenantID  string `json:"tenantID"`
+	RuntimeID string `json:"runtimeID"`
+}
+
+// MergeRequest is the body of the request for a single kubeconfig of multiple runtimes
+type MergeRequest struct {
+	Runtimes []Runtime `json:"runtimes"`
 }
 
 type client struct {
@@ -43,6 +57,27 @@ func (c *client) GetKubeConfig(tenantID, runtimeID string) (string, error) {
 		return "", errors.Wrapf(err, "while calling %s", url)
 	}
 
+	return readKubeConfig(url, resp)
+}
+
+// GetMergedKubeConfig returns a single kubeconfig with a cluster, a context and a user for each of the runtimes
+func (c *client) GetMergedKubeConfig(runtimes []Runtime) (string, error) {
+	url := fmt.Sprintf("%s/kubeconfigs", c.url)
+
+	body, err := json.Marshal(MergeRequest{Runtimes: runtimes})
+	if err != nil {
+		return "", errors.Wrap(err, "while marshalling request")
+	}
+
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", errors.Wrapf(err, "while calling %s", url)
+	}
+
+	return readKubeConfig(url, resp)
+}
+
+func readKubeConfig(url string, resp *http.Response) (kubeconfig string, err error) {
 	// Drain response body and close, return error to context if there isn't any.
 	defer func() {
 		derr := drainResponseBody(resp.Body)
@@ -56,6 +91,10 @@ func (c *client) GetKubeConfig(tenantID, runtimeID string) (string, error) {
 	}()
 
 	if resp.StatusCode != http.StatusOK {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 4096))
+		if len(message) > 0 {
+			return "", fmt.Errorf("calling %s returned %s status: %s", url, resp.Status, bytes.TrimSpace(message))
+		}
 		return "", fmt.Errorf("calling %s returned %s status", url, resp.Status)
 	}
 	body, err := ioutil.ReadAll(resp.Body)
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -61,3 +62,56 @@ func TestClient_GetKubeConfig(t *testing.T) {
 	assert.True(t, called)
 	assert.Equal(t, testKubeConfig, kc)
 }
+
+func TestClient_GetMergedKubeConfig(t *testing.T) {
+	// given
+	runtimes := []Runtime{
+		{TenantID: testTenant, RuntimeID: testRuntime},
+		{TenantID: testTenant, RuntimeID: "test-runtime-id-0002"},
+	}
+
+	called := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/kubeconfigs", r.URL.Path)
+		assert.Equal(t, r.Header.Get("Authorization"), fmt.Sprintf("Bearer %s", fixToken))
+
+		var request MergeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, runtimes, request.Runtimes)
+		called = true
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte(testKubeConfig))
+		require.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	client := NewClient(context.TODO(), ts.URL, fixToken)
+
+	// when
+	kc, err := client.GetMergedKubeConfig(runtimes)
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, testKubeConfig, kc)
+}
+
+func TestClient_GetMergedKubeConfig_Error(t *testing.T) {
+	// given
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not allowed to access the runtimes of tenant "+testTenant, http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	client := NewClient(context.TODO(), ts.URL, fixToken)
+
+	// when
+	_, err := client.GetMergedKubeConfig([]Runtime{{TenantID: testTenant, RuntimeID: testRuntime}})
+
+	// then
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden")
+	assert.Contains(t, err.Error(), "not allowed to access the runtimes of tenant "+testTenant)
+}
@@ -1,8 +1,16 @@
 package endpoints
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authz"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/metrics"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/resolver"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/serviceaccount"
 	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/transformer"
 
 	"github.com/gorilla/mux"
@@ -13,6 +21,11 @@ import (
 const (
 	mimeTypeYaml = "application/x-yaml"
 	mimeTypeText = "text/plain"
+
+	kubeconfigTypeOIDC           = "oidc"
+	kubeconfigTypeServiceAccount = "serviceaccount"
+
+	defaultMaxMergedRuntimes = 100
 )
 
 //EndpointClient Wrpper for Endpoints
@@ -21,33 +34,123 @@ type EndpointClient struct {
 	oidcIssuerURL    string
 	oidcClientID     string
 	oidcClientSecret string
+	serviceAccounts  *serviceaccount.Manager
+	authorizer       *authz.Authorizer
+	runtimes         *resolver.Resolver
+	clusters         *cache.Cache
+	metrics          *metrics.Collector
+
+	maxMergedRuntimes int
 }
 
 //NewEndpointClient return new instance of EndpointClient
 func NewEndpointClient(gqlURL string) *EndpointClient {
 	return &EndpointClient{
-		gqlURL: gqlURL,
+		gqlURL:            gqlURL,
+		clusters:          cache.NewCache(0),
+		metrics:           metrics.NewCollector(),
+		maxMergedRuntimes: defaultMaxMergedRuntimes,
 	}
 }
 
+//SetClusterCache enables the caching of the runtime clusters, so that the provisioner is not called for each kubeconfig
+func (ec *EndpointClient) SetClusterCache(clusters *cache.Cache) {
+	ec.clusters = clusters
+}
+
+//SetMetrics sets the collector of the cache lookups
+func (ec *EndpointClient) SetMetrics(collector *metrics.Collector) {
+	ec.metrics = collector
+}
+
+//SetAuthorizer enables the authorization of the tenants of the runtimes given in the request, the tenants of the runtimes
+//are looked up with the runtime resolver which must be set as well
+func (ec *EndpointClient) SetAuthorizer(authorizer *authz.Authorizer) {
+	ec.authorizer = authorizer
+}
+
+//SetRuntimeResolver enables the kubeconfigs of the runtimes identified by shoot name, global account and subaccount, or instance ID
+func (ec *EndpointClient) SetRuntimeResolver(runtimes *resolver.Resolver) {
+	ec.runtimes = runtimes
+}
+
+//SetMaxMergedRuntimes sets the maximum number of runtimes of a merged kubeconfig
+func (ec *EndpointClient) SetMaxMergedRuntimes(max int) {
+	ec.maxMergedRuntimes = max
+}
+
+//SetServiceAccountManager enables the service account kubeconfigs created by the manager
+func (ec *EndpointClient) SetServiceAccountManager(manager *serviceaccount.Manager) {
+	ec.serviceAccounts = manager
+}
+
 //GetKubeConfig REST Path for Kubeconfig operations
 func (ec EndpointClient) GetKubeConfig(w http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
-	tenant := vars["tenantID"]
-	runtime := vars["runtimeID"]
+	//the middleware authorizes the caller for the tenant of the path, the runtime must belong to it
+	if ec.authorizer != nil {
+		if err := ec.checkRuntimeTenant(req.Context(), vars["tenantID"], vars["runtimeID"]); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	ec.serveKubeConfig(w, req, vars["tenantID"], vars["runtimeID"])
+}
 
-	log.Infof("Generating kubeconfig for %s/%s", tenant, runtime)
+//LookupKubeConfig REST Path for Kubeconfig operations of the runtime identified by the query parameters
+func (ec EndpointClient) LookupKubeConfig(w http.ResponseWriter, req *http.Request) {
+	if ec.runtimes == nil {
+		writeError(w, &requestError{status: http.StatusNotFound, message: "runtime lookup is not enabled"})
+		return
+	}
 
-	kubeConfig, err := ec.generateKubeConfig(tenant, runtime)
+	lookup, err := resolver.ParseLookup(req.URL.Query())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	rt, err := ec.runtimes.Resolve(req.Context(), lookup)
 	if err != nil {
-		w.Header().Add("Content-Type", mimeTypeText)
-		w.WriteHeader(http.StatusInternalServerError)
-		_, err2 := w.Write([]byte(err.Error()))
-		log.Errorf("Error while processing the kubeconfig file: %s", err)
-		if err2 != nil {
-			log.Errorf("Error while sending response: %s", err2)
+		writeError(w, err)
+		return
+	}
+
+	//the tenant is known only once the runtime is resolved, so it is authorized here instead of by the middleware
+	if ec.authorizer != nil {
+		requester, _ := authn.CallerFrom(req.Context())
+		if !ec.authorizer.Authorize(requester, rt.TenantID) {
+			writeError(w, &requestError{
+				status:  http.StatusForbidden,
+				message: fmt.Sprintf("not allowed to access the runtimes of tenant %s", rt.TenantID),
+			})
+			return
 		}
 	}
+
+	ec.serveKubeConfig(w, req, rt.TenantID, rt.RuntimeID)
+}
+
+func (ec EndpointClient) serveKubeConfig(w http.ResponseWriter, req *http.Request, tenant, runtime string) {
+	var (
+		kubeConfig []byte
+		err        error
+	)
+
+	switch kubeconfigType := req.URL.Query().Get("type"); kubeconfigType {
+	case "", kubeconfigTypeOIDC:
+		log.Infof("Generating kubeconfig for %s/%s", tenant, runtime)
+		kubeConfig, err = ec.generateKubeConfig(tenant, runtime)
+	case kubeconfigTypeServiceAccount:
+		log.Infof("Generating service account kubeconfig for %s/%s", tenant, runtime)
+		kubeConfig, err = ec.generateServiceAccountKubeConfig(req, tenant, runtime)
+	default:
+		err = &serviceaccount.InvalidRequestError{Message: fmt.Sprintf("unknown kubeconfig type %s", kubeconfigType)}
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
 	w.Header().Add("Content-Type", mimeTypeYaml)
 	_, err = w.Write(kubeConfig)
 	if err != nil {
@@ -55,32 +158,138 @@ func (ec EndpointClient) GetKubeConfig(w http.ResponseWriter, req *http.Request)
 	}
 }
 
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if _, ok := err.(*serviceaccount.InvalidRequestError); ok {
+		status = http.StatusBadRequest
+	}
+	switch e := err.(type) {
+	case *requestError:
+		status = e.status
+	case *resolver.InvalidLookupError:
+		status = http.StatusBadRequest
+	case *resolver.NotFoundError:
+		status = http.StatusNotFound
+	}
+
+	w.Header().Add("Content-Type", mimeTypeText)
+	w.WriteHeader(status)
+	_, err2 := w.Write([]byte(err.Error()))
+	log.Errorf("Error while processing the kubeconfig file: %s", err)
+	if err2 != nil {
+		log.Errorf("Error while sending response: %s", err2)
+	}
+}
+
 //GetHealthStatus REST Path for health checks
 func (ec EndpointClient) GetHealthStatus(w http.ResponseWriter, req *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
-func (ec EndpointClient) callGQL(tenantID, runtimeID string) (string, error) {
-	c := caller.NewCaller(ec.gqlURL, tenantID)
-	status, err := c.RuntimeStatus(runtimeID)
+//checkRuntimeTenant returns a forbidden error if the runtime does not belong to the tenant
+func (ec EndpointClient) checkRuntimeTenant(ctx context.Context, tenant, runtime string) error {
+	if ec.runtimes == nil {
+		return &requestError{status: http.StatusForbidden, message: "the tenant of the runtime can't be looked up"}
+	}
+
+	runtimeTenant, err := ec.runtimes.RuntimeTenant(ctx, runtime)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if runtimeTenant != tenant {
+		return &requestError{
+			status:  http.StatusForbidden,
+			message: fmt.Sprintf("runtime %s does not belong to tenant %s", runtime, tenant),
+		}
 	}
-	return *status.RuntimeConfiguration.Kubeconfig, nil
+
+	return nil
 }
 
-func (ec EndpointClient) generateKubeConfig(tenant, runtime string) ([]byte, error) {
-	rawConfig, err := ec.callGQL(tenant, runtime)
+func (ec EndpointClient) callGQL(tenantID, runtimeID string) (string, error) {
+	c := caller.NewCaller(ec.gqlURL, tenantID)
+	return c.Kubeconfig(runtimeID)
+}
+
+//runtimeCluster returns the transformer of the runtime cluster from the cache, or from the provisioner on a cache miss,
+//it returns nil if the runtime has no kubeconfig yet
+func (ec EndpointClient) runtimeCluster(key cache.Key) (*transformer.Client, error) {
+	cluster, ok := ec.clusters.Get(key)
+	ec.metrics.ObserveCacheLookup(ok)
+	if ok {
+		return transformer.NewClusterClient(cluster.ContextName, cluster.CAData, cluster.ServerURL), nil
+	}
+
+	rawConfig, err := ec.callGQL(key.TenantID, key.RuntimeID)
 	if err != nil || rawConfig == "" {
 		return nil, err
 	}
+
+	return ec.cacheCluster(key, rawConfig)
+}
+
+//cacheCluster returns the transformer of the raw kubeconfig and caches its cluster
+func (ec EndpointClient) cacheCluster(key cache.Key, rawConfig string) (*transformer.Client, error) {
 	tc, err := transformer.NewClient(rawConfig)
 	if err != nil {
 		return nil, err
 	}
+	ec.clusters.Set(key, cache.Cluster{ContextName: tc.ContextName, CAData: tc.CAData, ServerURL: tc.ServerURL})
+
+	return tc, nil
+}
+
+func (ec EndpointClient) generateKubeConfig(tenant, runtime string) ([]byte, error) {
+	key := cache.Key{TenantID: tenant, RuntimeID: runtime}
+	tc, err := ec.runtimeCluster(key)
+	if err != nil || tc == nil {
+		ec.clusters.Invalidate(key)
+		return nil, err
+	}
 	kubeConfig, err := tc.TransformKubeconfig()
 	if err != nil {
+		ec.clusters.Invalidate(key)
 		return nil, err
 	}
 	return kubeConfig, nil
 }
+
+func (ec EndpointClient) generateServiceAccountKubeConfig(req *http.Request, tenant, runtime string) ([]byte, error) {
+	if ec.serviceAccounts == nil {
+		return nil, &serviceaccount.InvalidRequestError{Message: "service account kubeconfigs are not enabled"}
+	}
+
+	request, err := ec.serviceAccounts.ParseRequest(req.URL.Query())
+	if err != nil {
+		return nil, err
+	}
+	if caller, ok := authn.CallerFrom(req.Context()); ok && caller.User != nil {
+		request.Requester = caller.User.GetName()
+	}
+
+	//the admin credentials needed to create the service account are never cached, the cluster is refreshed instead
+	key := cache.Key{TenantID: tenant, RuntimeID: runtime}
+	rawConfig, err := ec.callGQL(tenant, runtime)
+	if err != nil {
+		ec.clusters.Invalidate(key)
+		return nil, err
+	}
+	tc, err := ec.cacheCluster(key, rawConfig)
+	if err != nil {
+		ec.clusters.Invalidate(key)
+		return nil, err
+	}
+
+	token, err := ec.serviceAccounts.Create(req.Context(), tenant, runtime, rawConfig, request)
+	if err != nil {
+		ec.clusters.Invalidate(key)
+		return nil, err
+	}
+
+	return tc.ServiceAccountKubeconfig(token.ServiceAccount, token.Namespace, token.Token)
+}
+
+//AdminKubeconfig returns the admin kubeconfig of the runtime from the provisioner
+func (ec EndpointClient) AdminKubeconfig(tenant, runtime string) (string, error) {
+	return ec.callGQL(tenant, runtime)
+}
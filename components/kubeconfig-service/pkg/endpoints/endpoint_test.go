@@ -0,0 +1,213 @@
+package endpoints
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authz"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/cache"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/caller"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/resolver"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const kubeconfigResponse = `{"data": {"result": %q}}`
+
+func TestEndpointClient_GetKubeConfig(t *testing.T) {
+	//given
+	calls := 0
+	provisioner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "runtimeKubeconfig")
+
+		switch r.Header.Get(caller.TenantHeader) {
+		case testTenant:
+			fmt.Fprintf(w, kubeconfigResponse, fmt.Sprintf(rawKubeconfig, "c-1234", "c-1234", "c-1234"))
+		default:
+			fmt.Fprint(w, `{"data": {"result": "not a kubeconfig"}}`)
+		}
+	}))
+	defer provisioner.Close()
+
+	ec := NewEndpointClient(provisioner.URL)
+	ec.SetClusterCache(cache.NewCache(time.Minute))
+
+	get := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/kubeconfig/%s/runtime1", tenant), nil)
+		req = mux.SetURLVars(req, map[string]string{"tenantID": tenant, "runtimeID": "runtime1"})
+		rr := httptest.NewRecorder()
+		ec.GetKubeConfig(rr, req)
+		return rr
+	}
+
+	t.Run("Should call provisioner once for cached runtime", func(t *testing.T) {
+		//when
+		first := get(testTenant)
+		second := get(testTenant)
+
+		//then
+		assert.Equal(t, http.StatusOK, first.Code)
+		assert.Contains(t, first.Body.String(), "server: https://api.c-1234.kyma.local")
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Should not cache runtime of other tenant", func(t *testing.T) {
+		//given
+		calls = 0
+
+		//when
+		rr := get(otherTenant)
+
+		//then
+		assert.Equal(t, http.StatusInternalServerError, rr.Code)
+		assert.Equal(t, 1, calls)
+		_, cached := ec.clusters.Get(cache.Key{TenantID: otherTenant, RuntimeID: "runtime1"})
+		assert.False(t, cached)
+	})
+}
+
+func TestEndpointClient_GetKubeConfig_Authorization(t *testing.T) {
+	//given
+	provisioner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testTenant, r.Header.Get(caller.TenantHeader))
+
+		fmt.Fprintf(w, kubeconfigResponse, fmt.Sprintf(rawKubeconfig, "c-1234", "c-1234", "c-1234"))
+	}))
+	defer provisioner.Close()
+
+	keb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("runtime_id") {
+		case "runtime1":
+			fmt.Fprintf(w, `{"data": [{"runtimeID": "runtime1", "globalAccountID": %q}]}`, testTenant)
+		case "runtime2":
+			fmt.Fprintf(w, `{"data": [{"runtimeID": "runtime2", "globalAccountID": %q}]}`, otherTenant)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer keb.Close()
+
+	ec := NewEndpointClient(provisioner.URL)
+	ec.SetAuthorizer(authz.NewAuthorizer(authz.Config{}))
+	ec.SetRuntimeResolver(resolver.NewResolver(resolver.Config{URL: keb.URL}))
+
+	for _, tc := range []struct {
+		name           string
+		runtime        string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Should return kubeconfig of runtime of the tenant",
+			runtime:        "runtime1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "server: https://api.c-1234.kyma.local",
+		},
+		{
+			name:           "Should reject runtime of other tenant",
+			runtime:        "runtime2",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "runtime runtime2 does not belong to tenant " + testTenant,
+		},
+		{
+			name:           "Should return not found for unknown runtime",
+			runtime:        "runtime3",
+			expectedStatus: http.StatusNotFound,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/kubeconfig/%s/%s", testTenant, tc.runtime), nil)
+			req = mux.SetURLVars(req, map[string]string{"tenantID": testTenant, "runtimeID": tc.runtime})
+			rr := httptest.NewRecorder()
+
+			//when
+			ec.GetKubeConfig(rr, req)
+
+			//then
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tc.expectedBody)
+		})
+	}
+}
+
+func TestEndpointClient_LookupKubeConfig(t *testing.T) {
+	//given
+	provisioner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testTenant, r.Header.Get(caller.TenantHeader))
+
+		kubeconfig := fmt.Sprintf(rawKubeconfig, "c-1234", "c-1234", "c-1234")
+		fmt.Fprintf(w, kubeconfigResponse, kubeconfig)
+	}))
+	defer provisioner.Close()
+
+	keb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("shoot") {
+		case "c-1234":
+			fmt.Fprintf(w, `{"data": [{"runtimeID": "runtime1", "globalAccountID": %q, "shootName": "c-1234"}]}`, testTenant)
+		case "c-5678":
+			fmt.Fprintf(w, `{"data": [{"runtimeID": "runtime2", "globalAccountID": %q, "shootName": "c-5678"}]}`, otherTenant)
+		default:
+			fmt.Fprint(w, `{"data": []}`)
+		}
+	}))
+	defer keb.Close()
+
+	ec := NewEndpointClient(provisioner.URL)
+	ec.SetAuthorizer(authz.NewAuthorizer(authz.Config{}))
+	ec.SetRuntimeResolver(resolver.NewResolver(resolver.Config{URL: keb.URL}))
+
+	for _, tc := range []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Should return kubeconfig of runtime resolved by shoot",
+			query:          "shoot=c-1234",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "server: https://api.c-1234.kyma.local",
+		},
+		{
+			name:           "Should reject runtime of other tenant",
+			query:          "shoot=c-5678",
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "not allowed to access the runtimes of tenant " + otherTenant,
+		},
+		{
+			name:           "Should return not found for unknown runtime",
+			query:          "shoot=c-0000",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Should reject lookup without parameters",
+			expectedStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/kubeconfig?"+tc.query, nil)
+			req = req.WithContext(authn.WithCaller(req.Context(), &authn.Caller{
+				User: &user.DefaultInfo{Name: "john@example.com", Groups: []string{testTenant}},
+			}))
+			rr := httptest.NewRecorder()
+
+			//when
+			ec.LookupKubeConfig(rr, req)
+
+			//then
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Contains(t, rr.Body.String(), tc.expectedBody)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/caller"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/client"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/transformer"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	//mergeWorkers is the number of runtimes fetched from the provisioner in parallel
+	mergeWorkers = 10
+	//maxMergeRequestSize is the maximum size of the body of a merge request
+	maxMergeRequestSize = 1 << 20
+)
+
+//requestError is returned when the request is rejected with the status code
+type requestError struct {
+	status  int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{status: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+//GetMergedKubeConfig REST Path for the kubeconfig of multiple runtimes
+func (ec EndpointClient) GetMergedKubeConfig(w http.ResponseWriter, req *http.Request) {
+	var request client.MergeRequest
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxMergeRequestSize)).Decode(&request); err != nil {
+		writeError(w, badRequest("invalid request body: %s", err))
+		return
+	}
+
+	runtimes, err := ec.validateMergeRequest(request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	requester, _ := authn.CallerFrom(req.Context())
+	if ec.authorizer != nil {
+		for _, rt := range runtimes {
+			if !ec.authorizer.Authorize(requester, rt.TenantID) {
+				writeError(w, &requestError{
+					status:  http.StatusForbidden,
+					message: fmt.Sprintf("not allowed to access the runtimes of tenant %s", rt.TenantID),
+				})
+				return
+			}
+			if err := ec.checkRuntimeTenant(req.Context(), rt.TenantID, rt.RuntimeID); err != nil {
+				writeError(w, err)
+				return
+			}
+		}
+	}
+
+	log.Infof("Generating merged kubeconfig for %d runtimes", len(runtimes))
+	kubeConfig, err := ec.generateMergedKubeConfig(runtimes)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Add("Content-Type", mimeTypeYaml)
+	_, err = w.Write(kubeConfig)
+	if err != nil {
+		log.Errorf("Error while sending response: %s", err)
+	}
+}
+
+//validateMergeRequest returns the distinct runtimes of the request
+func (ec EndpointClient) validateMergeRequest(request client.MergeRequest) ([]client.Runtime, error) {
+	if len(request.Runtimes) == 0 {
+		return nil, badRequest("at least one runtime is required")
+	}
+
+	seen := make(map[client.Runtime]bool, len(request.Runtimes))
+	runtimes := make([]client.Runtime, 0, len(request.Runtimes))
+	for _, rt := range request.Runtimes {
+		if rt.TenantID == "" || rt.RuntimeID == "" {
+			return nil, badRequest("tenantID and runtimeID are required for each runtime")
+		}
+		if seen[rt] {
+			continue
+		}
+		seen[rt] = true
+		runtimes = append(runtimes, rt)
+	}
+
+	if len(runtimes) > ec.maxMergedRuntimes {
+		return nil, badRequest("at most %d runtimes can be merged, got %d", ec.maxMergedRuntimes, len(runtimes))
+	}
+
+	return runtimes, nil
+}
+
+//generateMergedKubeConfig returns a kubeconfig with a cluster, a context and a user named after the shoot of each runtime
+func (ec EndpointClient) generateMergedKubeConfig(runtimes []client.Runtime) ([]byte, error) {
+	clients := make([]*transformer.Client, len(runtimes))
+	errs := make([]error, len(runtimes))
+
+	var wg sync.WaitGroup
+	indexes := make(chan int)
+	for w := 0; w < mergeWorkers && w < len(runtimes); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				clients[i], errs[i] = ec.runtimeTransformer(runtimes[i])
+			}
+		}()
+	}
+	for i := range runtimes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	names := make(map[string]bool, len(runtimes))
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "while getting kubeconfig of runtime %s/%s", runtimes[i].TenantID, runtimes[i].RuntimeID)
+		}
+		//the shoot names are unique, the context names of different runtimes are made unique just in case
+		if names[clients[i].ContextName] {
+			clients[i].ContextName = fmt.Sprintf("%s-%s", clients[i].ContextName, runtimes[i].RuntimeID)
+		}
+		names[clients[i].ContextName] = true
+	}
+
+	return transformer.MergeKubeconfigs(clients)
+}
+
+//runtimeTransformer returns the transformer of the runtime kubeconfig, with the shoot name as the context name
+func (ec EndpointClient) runtimeTransformer(rt client.Runtime) (*transformer.Client, error) {
+	runtime, err := caller.NewCaller(ec.gqlURL, rt.TenantID).RuntimeKubeconfig(rt.RuntimeID)
+	if err != nil {
+		return nil, err
+	}
+	if runtime.Kubeconfig == "" {
+		return nil, errors.New("runtime has no kubeconfig")
+	}
+
+	tc, err := transformer.NewClient(runtime.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if runtime.ShootName != "" {
+		tc.ContextName = runtime.ShootName
+	}
+
+	return tc, nil
+}
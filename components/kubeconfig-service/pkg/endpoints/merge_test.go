@@ -0,0 +1,134 @@
+package endpoints
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authz"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/caller"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/client"
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/resolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+const (
+	testTenant      = "tenant-1"
+	otherTenant     = "tenant-2"
+	rawKubeconfig   = "apiVersion: v1\nkind: Config\ncurrent-context: garden--%s\nclusters:\n- name: garden--%s\n  cluster:\n    server: https://api.%s.kyma.local\n    certificate-authority-data: Q0EK\n"
+	runtimeResponse = `{"data": {"result": {"runtimeConfiguration": {"kubeconfig": %q, "clusterConfig": {"name": %q}}}}}`
+)
+
+func TestEndpointClient_GetMergedKubeConfig(t *testing.T) {
+	//given
+	provisioner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		//the shoot name is derived from the runtime ID given in the query
+		shoot := "c-" + strings.SplitN(strings.SplitN(string(body), `runtimeStatus(id: \"`, 2)[1], `\"`, 2)[0]
+		assert.Equal(t, testTenant, r.Header.Get(caller.TenantHeader))
+
+		_, err = fmt.Fprintf(w, runtimeResponse, fmt.Sprintf(rawKubeconfig, shoot, shoot, shoot), shoot)
+		require.NoError(t, err)
+	}))
+	defer provisioner.Close()
+
+	keb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runtime := r.URL.Query().Get("runtime_id")
+		tenant := testTenant
+		if runtime == "runtime4" {
+			tenant = otherTenant
+		}
+		fmt.Fprintf(w, `{"data": [{"runtimeID": %q, "globalAccountID": %q}]}`, runtime, tenant)
+	}))
+	defer keb.Close()
+
+	ec := NewEndpointClient(provisioner.URL)
+	ec.SetAuthorizer(authz.NewAuthorizer(authz.Config{}))
+	ec.SetRuntimeResolver(resolver.NewResolver(resolver.Config{URL: keb.URL}))
+	ec.SetMaxMergedRuntimes(2)
+
+	for _, tc := range []struct {
+		name           string
+		runtimes       []client.Runtime
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name: "Should return kubeconfig of the runtimes named after the shoots",
+			runtimes: []client.Runtime{
+				{TenantID: testTenant, RuntimeID: "runtime1"},
+				{TenantID: testTenant, RuntimeID: "runtime2"},
+				{TenantID: testTenant, RuntimeID: "runtime1"},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				"current-context: c-runtime1",
+				"- name: c-runtime1\n  cluster:\n    certificate-authority-data: Q0EK\n    server: https://api.c-runtime1.kyma.local",
+				"- name: c-runtime2\n  cluster:\n    certificate-authority-data: Q0EK\n    server: https://api.c-runtime2.kyma.local",
+				"- name: c-runtime2\n  context:\n    cluster: c-runtime2\n    user: c-runtime2",
+			},
+		},
+		{
+			name:           "Should reject runtime of other tenant",
+			runtimes:       []client.Runtime{{TenantID: testTenant, RuntimeID: "runtime1"}, {TenantID: otherTenant, RuntimeID: "runtime3"}},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   []string{"not allowed to access the runtimes of tenant " + otherTenant},
+		},
+		{
+			name:           "Should reject runtime not belonging to the tenant",
+			runtimes:       []client.Runtime{{TenantID: testTenant, RuntimeID: "runtime1"}, {TenantID: testTenant, RuntimeID: "runtime4"}},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   []string{"runtime runtime4 does not belong to tenant " + testTenant},
+		},
+		{
+			name:           "Should reject empty request",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"at least one runtime is required"},
+		},
+		{
+			name:           "Should reject runtime without ID",
+			runtimes:       []client.Runtime{{TenantID: testTenant}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Should reject too many runtimes",
+			runtimes: []client.Runtime{
+				{TenantID: testTenant, RuntimeID: "runtime1"},
+				{TenantID: testTenant, RuntimeID: "runtime2"},
+				{TenantID: testTenant, RuntimeID: "runtime3"},
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   []string{"at most 2 runtimes can be merged, got 3"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(client.MergeRequest{Runtimes: tc.runtimes})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/kubeconfigs", bytes.NewReader(body))
+			req = req.WithContext(authn.WithCaller(req.Context(), &authn.Caller{
+				User: &user.DefaultInfo{Name: "john@example.com", Groups: []string{testTenant}},
+			}))
+			rr := httptest.NewRecorder()
+
+			//when
+			ec.GetMergedKubeConfig(rr, req)
+
+			//then
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			for _, expected := range tc.expectedBody {
+				assert.Contains(t, rr.Body.String(), expected)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package env
 
 import (
+	"time"
+
 	"github.com/vrischmann/envconfig"
 )
 
@@ -26,6 +28,7 @@ type EnvConfig struct {
 		Claim     struct {
 			Username string `envconfig:"default=email"`
 			Groups   string `envconfig:"default=groups"`
+			Tenant   string `envconfig:"optional"`
 		}
 		Prefix struct {
 			Username string `envconfig:"optional"`
@@ -33,6 +36,42 @@ type EnvConfig struct {
 		}
 		SupportedSigningAlgs []string `envconfig:"default=RS256"`
 	}
+	ServiceAccount struct {
+		Namespace  string        `envconfig:"default=default"`
+		Roles      []string      `envconfig:"default=view"`
+		DefaultTTL time.Duration `envconfig:"default=1h"`
+		MaxTTL     time.Duration `envconfig:"default=24h"`
+		GCInterval time.Duration `envconfig:"default=10m"`
+		Registry   struct {
+			Namespace string `envconfig:"optional"`
+			ConfigMap string `envconfig:"optional"`
+		}
+	}
+	Authorization struct {
+		Enabled        bool     `envconfig:"default=false"`
+		OperatorGroups []string `envconfig:"optional"`
+	}
+	KEB struct {
+		URL   string `envconfig:"optional"`
+		OAuth struct {
+			TokenURL     string   `envconfig:"optional"`
+			ClientID     string   `envconfig:"optional"`
+			ClientSecret string   `envconfig:"optional"`
+			Scopes       []string `envconfig:"optional"`
+		}
+		CacheTTL time.Duration `envconfig:"default=10m"`
+	}
+	Merge struct {
+		MaxRuntimes int `envconfig:"default=100"`
+	}
+	Cache struct {
+		TTL time.Duration `envconfig:"default=5m"`
+	}
+	RateLimit struct {
+		RequestsPerSecond float64       `envconfig:"default=5"`
+		Burst             int           `envconfig:"default=20"`
+		IdleTimeout       time.Duration `envconfig:"default=10m"`
+	}
 	LogLevel string `envconfig:"default=info"`
 }
 
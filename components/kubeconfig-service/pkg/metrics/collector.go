@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	prometheusNamespace = "kcp"
+	prometheusSubsystem = "kubeconfig_service"
+
+	reasonUnauthorized = "unauthorized"
+	reasonForbidden    = "forbidden"
+	reasonRateLimited  = "rate_limited"
+)
+
+//Collector provides the following metrics:
+// - kcp_kubeconfig_service_cache_lookups_total{"result"}, the lookups of the runtime clusters in the cache, the result is "hit" or "miss"
+// - kcp_kubeconfig_service_request_duration_seconds{"path", "code"}, the latency of the requests by route and status code
+// - kcp_kubeconfig_service_rejected_requests_total{"reason"}, the requests rejected as "unauthorized", "forbidden" or "rate_limited"
+type Collector struct {
+	cacheLookups     *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	rejectedRequests *prometheus.CounterVec
+}
+
+//NewCollector returns a new Collector, it has to be registered to expose the metrics
+func NewCollector() *Collector {
+	return &Collector{
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Subsystem: prometheusSubsystem,
+			Name:      "cache_lookups_total",
+			Help:      "Lookups of the runtime clusters in the cache",
+		}, []string{"result"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: prometheusNamespace,
+			Subsystem: prometheusSubsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the requests",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"path", "code"}),
+		rejectedRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Subsystem: prometheusSubsystem,
+			Name:      "rejected_requests_total",
+			Help:      "Requests rejected by the authentication, the authorization or the rate limiting",
+		}, []string{"reason"}),
+	}
+}
+
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	c.cacheLookups.Describe(ch)
+	c.requestDuration.Describe(ch)
+	c.rejectedRequests.Describe(ch)
+}
+
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.cacheLookups.Collect(ch)
+	c.requestDuration.Collect(ch)
+	c.rejectedRequests.Collect(ch)
+}
+
+//ObserveCacheLookup counts a lookup of a runtime cluster in the cache
+func (c *Collector) ObserveCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	c.cacheLookups.WithLabelValues(result).Inc()
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+//Middleware observes the latency of the requests and counts the rejected ones,
+//it has to be the first middleware of the router to observe the rejections of the other ones
+func (c *Collector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		c.requestDuration.WithLabelValues(pathOf(r), strconv.Itoa(recorder.status)).Observe(time.Since(start).Seconds())
+		switch recorder.status {
+		case http.StatusUnauthorized:
+			c.rejectedRequests.WithLabelValues(reasonUnauthorized).Inc()
+		case http.StatusForbidden:
+			c.rejectedRequests.WithLabelValues(reasonForbidden).Inc()
+		case http.StatusTooManyRequests:
+			c.rejectedRequests.WithLabelValues(reasonRateLimited).Inc()
+		}
+	})
+}
+
+//pathOf returns the template of the matched route, so that the runtimes are not a part of the label
+func pathOf(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,53 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector_Middleware(t *testing.T) {
+	//given
+	collector := NewCollector()
+
+	router := mux.NewRouter()
+	router.Use(collector.Middleware)
+	router.Methods("GET").Path("/kubeconfig/{tenantID}/{runtimeID}").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch mux.Vars(r)["runtimeID"] {
+		case "forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case "limited":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte("kubeconfig"))
+		}
+	})
+
+	//when
+	for _, runtime := range []string{"runtime", "forbidden", "limited", "limited"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/kubeconfig/tenant/"+runtime, nil))
+	}
+
+	//then
+	assert.Equal(t, 3, testutil.CollectAndCount(collector.requestDuration))
+	assert.Equal(t, float64(1), testutil.ToFloat64(collector.rejectedRequests.WithLabelValues(reasonForbidden)))
+	assert.Equal(t, float64(2), testutil.ToFloat64(collector.rejectedRequests.WithLabelValues(reasonRateLimited)))
+}
+
+func TestCollector_ObserveCacheLookup(t *testing.T) {
+	//given
+	collector := NewCollector()
+
+	//when
+	collector.ObserveCacheLookup(true)
+	collector.ObserveCacheLookup(false)
+	collector.ObserveCacheLookup(true)
+
+	//then
+	assert.Equal(t, float64(2), testutil.ToFloat64(collector.cacheLookups.WithLabelValues("hit")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(collector.cacheLookups.WithLabelValues("miss")))
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+//Config represents configuration of the rate limiting
+type Config struct {
+	//RequestsPerSecond is the rate at which the tokens of the bucket of a user are refilled
+	RequestsPerSecond float64
+	//Burst is the size of the bucket of a user
+	Burst int
+	//IdleTimeout is the duration after which the bucket of a user who sent no request is removed
+	IdleTimeout time.Duration
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+//Limiter limits the requests of each user with a token bucket
+type Limiter struct {
+	config Config
+	now    func() time.Time
+
+	lock      sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+//NewLimiter returns a new Limiter
+func NewLimiter(config Config) *Limiter {
+	return &Limiter{
+		config:    config,
+		now:       time.Now,
+		buckets:   make(map[string]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+//Allow takes a token from the bucket of the user, if there is none it returns the duration after which the next request is allowed
+func (l *Limiter) Allow(user string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[user]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(l.config.RequestsPerSecond), l.config.Burst)}
+		l.buckets[user] = b
+	}
+	b.lastSeen = now
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Second
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+
+	return true, 0
+}
+
+//sweep removes the buckets of the users idle for longer than the idle timeout, at most once per idle timeout
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.config.IdleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for user, b := range l.buckets {
+		if now.Sub(b.lastSeen) >= l.config.IdleTimeout {
+			delete(l.buckets, user)
+		}
+	}
+}
+
+//Middleware rejects the requests of the authenticated caller over the limit with the 429 status code
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := userOf(r)
+
+		allowed, retryAfter := l.Allow(user)
+		if !allowed {
+			log.Warnf("Rate limit of %s exceeded", user)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, fmt.Sprintf("Too many requests, retry after %s", retryAfter.Round(time.Second)), http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+//userOf returns the name of the authenticated caller, or the remote host if the request is not authenticated
+func userOf(r *http.Request) string {
+	if caller, ok := authn.CallerFrom(r.Context()); ok && caller.User != nil {
+		return caller.User.GetName()
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
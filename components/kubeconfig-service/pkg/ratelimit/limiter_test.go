@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/authn"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authentication/user"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	//given
+	limiter := NewLimiter(Config{RequestsPerSecond: 1, Burst: 2, IdleTimeout: time.Minute})
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+
+	t.Run("Should allow requests up to the burst", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			allowed, _ := limiter.Allow("john")
+			assert.True(t, allowed)
+		}
+
+		//when
+		allowed, retryAfter := limiter.Allow("john")
+
+		//then
+		assert.False(t, allowed)
+		assert.Equal(t, time.Second, retryAfter)
+	})
+
+	t.Run("Should limit each user separately", func(t *testing.T) {
+		//when
+		allowed, _ := limiter.Allow("jane")
+
+		//then
+		assert.True(t, allowed)
+	})
+
+	t.Run("Should allow request once the bucket is refilled", func(t *testing.T) {
+		//given
+		now = now.Add(time.Second)
+
+		//when
+		allowed, _ := limiter.Allow("john")
+
+		//then
+		assert.True(t, allowed)
+	})
+
+	t.Run("Should remove buckets of idle users", func(t *testing.T) {
+		//given
+		now = now.Add(time.Minute)
+
+		//when
+		limiter.Allow("john")
+
+		//then
+		assert.Len(t, limiter.buckets, 1)
+		assert.Contains(t, limiter.buckets, "john")
+	})
+}
+
+func TestLimiter_Middleware(t *testing.T) {
+	//given
+	limiter := NewLimiter(Config{RequestsPerSecond: 0.5, Burst: 1, IdleTimeout: time.Minute})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(name string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/kubeconfig/tenant/runtime", nil)
+		req = req.WithContext(authn.WithCaller(req.Context(), &authn.Caller{User: &user.DefaultInfo{Name: name}}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	//when
+	first := request("john")
+	second := request("john")
+	other := request("jane")
+
+	//then
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.Equal(t, "2", second.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, other.Code)
+}
@@ -0,0 +1,277 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	shootParam         = "shoot"
+	globalAccountParam = "account"
+	subAccountParam    = "subaccount"
+	instanceIDParam    = "instance_id"
+	runtimeIDParam     = "runtime_id"
+
+	requestTimeout = 30 * time.Second
+	//maxCacheEntries is the number of cached runtimes above which the expired entries are removed
+	maxCacheEntries = 10000
+)
+
+//Config represents configuration of the runtime resolution
+type Config struct {
+	//URL is the URL of the Kyma Environment Broker runtime API
+	URL string
+	//TokenURL, ClientID, ClientSecret and Scopes are the OAuth2 client credentials used to call the runtime API, if TokenURL is not empty
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	//CacheTTL is the duration for which a resolved runtime is cached
+	CacheTTL time.Duration
+}
+
+//Lookup represents the parameters identifying a runtime, either the shoot name, the global account and subaccount pair, or the instance ID.
+//The runtime ID is used to look up the tenant of a runtime and can't be given in the query parameters.
+type Lookup struct {
+	Shoot           string
+	GlobalAccountID string
+	SubAccountID    string
+	InstanceID      string
+	RuntimeID       string
+}
+
+//Runtime represents a resolved runtime
+type Runtime struct {
+	TenantID  string
+	RuntimeID string
+	ShootName string
+}
+
+//InvalidLookupError is returned when the lookup parameters are not valid
+type InvalidLookupError struct {
+	Message string
+}
+
+func (e *InvalidLookupError) Error() string {
+	return e.Message
+}
+
+//NotFoundError is returned when no runtime matches the lookup, or more than one
+type NotFoundError struct {
+	Message string
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Message
+}
+
+type cacheEntry struct {
+	runtime   Runtime
+	expiresAt time.Time
+}
+
+//Resolver resolves runtimes with the Kyma Environment Broker runtime API and caches the results
+type Resolver struct {
+	config     Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	lock  sync.Mutex
+	cache map[Lookup]cacheEntry
+}
+
+//NewResolver returns a new Resolver
+func NewResolver(config Config) *Resolver {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if config.TokenURL != "" {
+		credentials := clientcredentials.Config{
+			ClientID:     config.ClientID,
+			ClientSecret: config.ClientSecret,
+			TokenURL:     config.TokenURL,
+			Scopes:       config.Scopes,
+		}
+		httpClient = credentials.Client(context.Background())
+		httpClient.Timeout = requestTimeout
+	}
+
+	return &Resolver{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+		cache:      make(map[Lookup]cacheEntry),
+	}
+}
+
+//ParseLookup returns the lookup of the "shoot", "account" and "subaccount", or "instance_id" query parameters
+func ParseLookup(query url.Values) (Lookup, error) {
+	lookup := Lookup{
+		Shoot:           query.Get(shootParam),
+		GlobalAccountID: query.Get(globalAccountParam),
+		SubAccountID:    query.Get(subAccountParam),
+		InstanceID:      query.Get(instanceIDParam),
+	}
+
+	given := 0
+	if lookup.Shoot != "" {
+		given++
+	}
+	if lookup.GlobalAccountID != "" || lookup.SubAccountID != "" {
+		if lookup.GlobalAccountID == "" || lookup.SubAccountID == "" {
+			return lookup, &InvalidLookupError{Message: "both account and subaccount are required"}
+		}
+		given++
+	}
+	if lookup.InstanceID != "" {
+		given++
+	}
+	if given != 1 {
+		return lookup, &InvalidLookupError{Message: "exactly one of the following is required: shoot, account and subaccount, instance_id"}
+	}
+
+	return lookup, nil
+}
+
+//Resolve returns the runtime matching the lookup, from the cache if the runtime was resolved before
+func (r *Resolver) Resolve(ctx context.Context, lookup Lookup) (Runtime, error) {
+	if runtime, ok := r.cached(lookup); ok {
+		return runtime, nil
+	}
+
+	runtime, err := r.resolve(ctx, lookup)
+	if err != nil {
+		return Runtime{}, err
+	}
+
+	r.store(lookup, runtime)
+
+	return runtime, nil
+}
+
+//RuntimeTenant returns the tenant the runtime belongs to
+func (r *Resolver) RuntimeTenant(ctx context.Context, runtimeID string) (string, error) {
+	runtime, err := r.Resolve(ctx, Lookup{RuntimeID: runtimeID})
+	if err != nil {
+		return "", err
+	}
+
+	return runtime.TenantID, nil
+}
+
+type runtimeDTO struct {
+	RuntimeID       string `json:"runtimeID"`
+	GlobalAccountID string `json:"globalAccountID"`
+	ShootName       string `json:"shootName"`
+}
+
+type runtimesPage struct {
+	Data []runtimeDTO `json:"data"`
+}
+
+func (r *Resolver) resolve(ctx context.Context, lookup Lookup) (Runtime, error) {
+	query := url.Values{}
+	switch {
+	case lookup.Shoot != "":
+		query.Set(shootParam, lookup.Shoot)
+	case lookup.InstanceID != "":
+		query.Set(instanceIDParam, lookup.InstanceID)
+	case lookup.RuntimeID != "":
+		query.Set(runtimeIDParam, lookup.RuntimeID)
+	default:
+		query.Set(globalAccountParam, lookup.GlobalAccountID)
+		query.Set(subAccountParam, lookup.SubAccountID)
+	}
+
+	endpoint := fmt.Sprintf("%s/runtimes?%s", strings.TrimSuffix(r.config.URL, "/"), query.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Runtime{}, errors.Wrap(err, "while creating request")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return Runtime{}, errors.Wrapf(err, "while calling %s", endpoint)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Runtime{}, errors.Wrap(err, "while reading response body")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Runtime{}, fmt.Errorf("calling %s returned %s status: %s", endpoint, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var page runtimesPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return Runtime{}, errors.Wrap(err, "while unmarshalling runtimes")
+	}
+
+	//the instances without a runtime, not provisioned yet or not at all, can't have a kubeconfig
+	var runtimes []runtimeDTO
+	for _, dto := range page.Data {
+		if dto.RuntimeID != "" {
+			runtimes = append(runtimes, dto)
+		}
+	}
+
+	switch len(runtimes) {
+	case 0:
+		return Runtime{}, &NotFoundError{Message: fmt.Sprintf("no runtime matches %s", query.Encode())}
+	case 1:
+		log.Infof("Resolved runtime %s/%s of %s", runtimes[0].GlobalAccountID, runtimes[0].RuntimeID, query.Encode())
+		return Runtime{
+			TenantID:  runtimes[0].GlobalAccountID,
+			RuntimeID: runtimes[0].RuntimeID,
+			ShootName: runtimes[0].ShootName,
+		}, nil
+	default:
+		return Runtime{}, &NotFoundError{Message: fmt.Sprintf("%d runtimes match %s", len(runtimes), query.Encode())}
+	}
+}
+
+func (r *Resolver) cached(lookup Lookup) (Runtime, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry, ok := r.cache[lookup]
+	if !ok {
+		return Runtime{}, false
+	}
+	if !r.now().Before(entry.expiresAt) {
+		delete(r.cache, lookup)
+		return Runtime{}, false
+	}
+
+	return entry.runtime, true
+}
+
+func (r *Resolver) store(lookup Lookup, runtime Runtime) {
+	if r.config.CacheTTL <= 0 {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if len(r.cache) >= maxCacheEntries {
+		for key, entry := range r.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(r.cache, key)
+			}
+		}
+	}
+
+	r.cache[lookup] = cacheEntry{runtime: runtime, expiresAt: now.Add(r.config.CacheTTL)}
+}
@@ -0,0 +1,142 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLookup(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		query    url.Values
+		expected *Lookup
+	}{
+		{name: "shoot", query: url.Values{"shoot": {"c-1234"}}, expected: &Lookup{Shoot: "c-1234"}},
+		{name: "account and subaccount", query: url.Values{"account": {"ga"}, "subaccount": {"sa"}}, expected: &Lookup{GlobalAccountID: "ga", SubAccountID: "sa"}},
+		{name: "instance ID", query: url.Values{"instance_id": {"instance"}}, expected: &Lookup{InstanceID: "instance"}},
+		{name: "nothing", query: url.Values{}},
+		{name: "account without subaccount", query: url.Values{"account": {"ga"}}},
+		{name: "shoot and instance ID", query: url.Values{"shoot": {"c-1234"}, "instance_id": {"instance"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lookup, err := ParseLookup(tc.query)
+
+			if tc.expected == nil {
+				assert.IsType(t, &InvalidLookupError{}, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, *tc.expected, lookup)
+		})
+	}
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	//given
+	calls := 0
+	keb := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/runtimes", r.URL.Path)
+		assert.Equal(t, "Bearer keb-token", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Query().Get("shoot") == "c-1234":
+			fmt.Fprint(w, `{"data": [{"runtimeID": "runtime-1", "globalAccountID": "ga", "subAccountID": "sa", "shootName": "c-1234"}], "count": 1, "totalCount": 1}`)
+		case r.URL.Query().Get("account") == "ga" && r.URL.Query().Get("subaccount") == "sa":
+			fmt.Fprint(w, `{"data": [{"runtimeID": "runtime-1", "globalAccountID": "ga"}, {"runtimeID": "runtime-2", "globalAccountID": "ga"}], "count": 2, "totalCount": 2}`)
+		case r.URL.Query().Get("runtime_id") == "runtime-3":
+			fmt.Fprint(w, `{"data": [{"runtimeID": "runtime-3", "globalAccountID": "ga-2"}], "count": 1, "totalCount": 1}`)
+		case r.URL.Query().Get("instance_id") == "provisioning":
+			fmt.Fprint(w, `{"data": [{"runtimeID": "", "globalAccountID": "ga"}], "count": 1, "totalCount": 1}`)
+		default:
+			fmt.Fprint(w, `{"data": [], "count": 0, "totalCount": 0}`)
+		}
+	}))
+	defer keb.Close()
+
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", user)
+		assert.Equal(t, "secret", password)
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token": "keb-token", "token_type": "bearer", "expires_in": 3600}`)
+	}))
+	defer tokens.Close()
+
+	resolver := NewResolver(Config{
+		URL:          keb.URL,
+		TokenURL:     tokens.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		CacheTTL:     time.Minute,
+	})
+	now := time.Now()
+	resolver.now = func() time.Time { return now }
+
+	t.Run("Should resolve runtime and cache it", func(t *testing.T) {
+		//when
+		first, err := resolver.Resolve(context.Background(), Lookup{Shoot: "c-1234"})
+		require.NoError(t, err)
+		second, err := resolver.Resolve(context.Background(), Lookup{Shoot: "c-1234"})
+		require.NoError(t, err)
+
+		//then
+		expected := Runtime{TenantID: "ga", RuntimeID: "runtime-1", ShootName: "c-1234"}
+		assert.Equal(t, expected, first)
+		assert.Equal(t, expected, second)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Should resolve runtime again once cache expired", func(t *testing.T) {
+		//given
+		calls = 0
+		now = now.Add(2 * time.Minute)
+
+		//when
+		_, err := resolver.Resolve(context.Background(), Lookup{Shoot: "c-1234"})
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Should return tenant of runtime", func(t *testing.T) {
+		//when
+		tenant, err := resolver.RuntimeTenant(context.Background(), "runtime-3")
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, "ga-2", tenant)
+	})
+
+	for name, lookup := range map[string]Lookup{
+		"Should not resolve unknown runtime":         {Shoot: "c-unknown"},
+		"Should not resolve ambiguous runtime":       {GlobalAccountID: "ga", SubAccountID: "sa"},
+		"Should not resolve runtime not provisioned": {InstanceID: "provisioning"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			//given
+			calls = 0
+
+			//when
+			_, err := resolver.Resolve(context.Background(), lookup)
+			_, err2 := resolver.Resolve(context.Background(), lookup)
+
+			//then
+			assert.IsType(t, &NotFoundError{}, err)
+			assert.IsType(t, &NotFoundError{}, err2)
+			assert.Equal(t, 2, calls, "not found runtimes should not be cached")
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package serviceaccount
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const apiTimeout = 30 * time.Second
+
+//newRuntimeClient returns a client of the API server of a runtime with the credentials of its admin kubeconfig
+func newRuntimeClient(rawKubeconfig string) (kubernetes.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(rawKubeconfig))
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing admin kubeconfig")
+	}
+	config.Timeout = apiTimeout
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating client of runtime")
+	}
+	return client, nil
+}
+
+//newInClusterClient returns a client of the API server of the cluster kubeconfig-service runs in,
+//with the credentials of the pod service account
+func newInClusterClient() (kubernetes.Interface, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "while reading in-cluster configuration")
+	}
+	config.Timeout = apiTimeout
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating in-cluster client")
+	}
+	return client, nil
+}
+
+//createBinding binds the requested ClusterRole to the service account in the namespace of the request,
+//or for the whole cluster if the request has no namespace
+func createBinding(ctx context.Context, client kubernetes.Interface, request Request, sa *corev1.ServiceAccount) error {
+	meta := metav1.ObjectMeta{
+		Name:   sa.Name,
+		Labels: map[string]string{labelManaged: "true", labelServiceAccount: sa.Name},
+	}
+	roleRef := rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: request.Role}
+	subjects := []rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: sa.Name, Namespace: sa.Namespace}}
+
+	if request.Namespace == "" {
+		binding := &rbacv1.ClusterRoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: subjects}
+		_, err := client.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+		return err
+	}
+
+	meta.Namespace = request.Namespace
+	binding := &rbacv1.RoleBinding{ObjectMeta: meta, RoleRef: roleRef, Subjects: subjects}
+	_, err := client.RbacV1().RoleBindings(request.Namespace).Create(ctx, binding, metav1.CreateOptions{})
+	return err
+}
+
+func createToken(ctx context.Context, client kubernetes.Interface, sa *corev1.ServiceAccount, ttl time.Duration) (*authenticationv1.TokenRequest, error) {
+	expirationSeconds := int64(ttl.Seconds())
+	request := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+	}
+	return client.CoreV1().ServiceAccounts(sa.Namespace).CreateToken(ctx, sa.Name, request, metav1.CreateOptions{})
+}
+
+//deleteBindings deletes the ClusterRoleBindings and the RoleBindings of the namespace of the service account
+func deleteBindings(ctx context.Context, client kubernetes.Interface, sa corev1.ServiceAccount) error {
+	selector := metav1.ListOptions{LabelSelector: labelServiceAccount + "=" + sa.Name}
+
+	clusterBindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, selector)
+	if err != nil {
+		return errors.Wrapf(err, "while listing ClusterRoleBindings of service account %s/%s", sa.Namespace, sa.Name)
+	}
+	for _, binding := range clusterBindings.Items {
+		err := client.RbacV1().ClusterRoleBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{})
+		if ignoreNotFound(err) != nil {
+			return errors.Wrapf(err, "while deleting ClusterRoleBinding %s", binding.Name)
+		}
+	}
+
+	bindings, err := client.RbacV1().RoleBindings(sa.Namespace).List(ctx, selector)
+	if err != nil {
+		return errors.Wrapf(err, "while listing RoleBindings of service account %s/%s", sa.Namespace, sa.Name)
+	}
+	for _, binding := range bindings.Items {
+		err := client.RbacV1().RoleBindings(sa.Namespace).Delete(ctx, binding.Name, metav1.DeleteOptions{})
+		if ignoreNotFound(err) != nil {
+			return errors.Wrapf(err, "while deleting RoleBinding %s/%s", binding.Namespace, binding.Name)
+		}
+	}
+
+	return nil
+}
+
+func ignoreNotFound(err error) error {
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+package serviceaccount
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+//KubeconfigProvider returns the admin kubeconfig of a runtime
+type KubeconfigProvider func(tenantID, runtimeID string) (string, error)
+
+//Token represents the token of a service account created in a runtime
+type Token struct {
+	ServiceAccount string
+	Namespace      string
+	Token          string
+	ExpiresAt      time.Time
+}
+
+type runtimeKey struct {
+	tenantID  string
+	runtimeID string
+}
+
+func (k runtimeKey) String() string {
+	return k.tenantID + "." + k.runtimeID
+}
+
+//Manager creates service accounts with a bounded lifetime in the runtimes and deletes them once expired
+type Manager struct {
+	config          Config
+	adminKubeconfig KubeconfigProvider
+	newClient       func(rawKubeconfig string) (kubernetes.Interface, error)
+	now             func() time.Time
+
+	lock sync.Mutex
+	//runtimes are the runtimes which may have service accounts to delete
+	runtimes map[runtimeKey]bool
+	//registry persists the runtimes, they are kept in memory only if nil
+	registry *ConfigMapRegistry
+}
+
+//NewManager returns a new Manager
+func NewManager(config Config, adminKubeconfig KubeconfigProvider) *Manager {
+	return &Manager{
+		config:          config,
+		adminKubeconfig: adminKubeconfig,
+		newClient:       newRuntimeClient,
+		now:             time.Now,
+		runtimes:        make(map[runtimeKey]bool),
+	}
+}
+
+//SetRegistry persists the runtimes which may have service accounts to delete in the registry
+func (m *Manager) SetRegistry(registry *ConfigMapRegistry) {
+	m.registry = registry
+}
+
+//ParseRequest returns the request of the "role", "ttl" and "namespace" query parameters
+func (m *Manager) ParseRequest(query url.Values) (Request, error) {
+	request := Request{
+		Role:      query.Get("role"),
+		TTL:       m.config.DefaultTTL,
+		Namespace: query.Get("namespace"),
+	}
+
+	if request.Role == "" {
+		return request, invalidRequest("role is required")
+	}
+	if !m.roleAllowed(request.Role) {
+		return request, invalidRequest("role %s is not allowed, allowed roles: %v", request.Role, m.config.Roles)
+	}
+
+	if ttl := query.Get("ttl"); ttl != "" {
+		parsed, err := time.ParseDuration(ttl)
+		if err != nil {
+			return request, invalidRequest("invalid ttl %s: %s", ttl, err)
+		}
+		request.TTL = parsed
+	}
+	if request.TTL < minTTL || request.TTL > m.config.MaxTTL {
+		return request, invalidRequest("ttl must be between %s and %s", minTTL, m.config.MaxTTL)
+	}
+
+	return request, nil
+}
+
+func (m *Manager) roleAllowed(role string) bool {
+	for _, allowed := range m.config.Roles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
+
+//Create creates a service account bound to the requested role in the runtime and returns a token expiring with it
+func (m *Manager) Create(ctx context.Context, tenantID, runtimeID, rawKubeconfig string, request Request) (*Token, error) {
+	client, err := m.newClient(rawKubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := serviceAccountName()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := request.Namespace
+	if namespace == "" {
+		namespace = m.config.Namespace
+	}
+	expiresAt := m.now().Add(request.TTL).UTC()
+
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{labelManaged: "true"},
+			Annotations: map[string]string{
+				annotationExpiresAt: expiresAt.Format(time.RFC3339),
+				annotationRequester: request.Requester,
+			},
+		},
+	}
+	if _, err := client.CoreV1().ServiceAccounts(namespace).Create(ctx, sa, metav1.CreateOptions{}); err != nil {
+		return nil, errors.Wrapf(err, "while creating service account %s/%s", namespace, name)
+	}
+
+	m.register(ctx, runtimeKey{tenantID: tenantID, runtimeID: runtimeID})
+
+	if err := createBinding(ctx, client, request, sa); err != nil {
+		//the service account is deleted by the collection once expired if it can't be deleted now
+		_ = m.delete(ctx, client, *sa)
+		return nil, errors.Wrapf(err, "while binding role %s to service account %s/%s", request.Role, namespace, name)
+	}
+
+	token, err := createToken(ctx, client, sa, request.TTL)
+	if err != nil {
+		_ = m.delete(ctx, client, *sa)
+		return nil, errors.Wrapf(err, "while creating token of service account %s/%s", namespace, name)
+	}
+
+	log.Infof("Created service account %s/%s with role %s in runtime %s/%s for %s, expiring at %s",
+		namespace, name, request.Role, tenantID, runtimeID, request.Requester, expiresAt.Format(time.RFC3339))
+
+	//the expired service accounts of the runtime are deleted on each request in addition to the periodic collection
+	if _, err := m.collect(ctx, client); err != nil {
+		log.Warnf("Unable to delete the expired service accounts of runtime %s/%s: %s", tenantID, runtimeID, err)
+	}
+
+	return &Token{
+		ServiceAccount: name,
+		Namespace:      namespace,
+		Token:          token.Status.Token,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+//Run deletes the expired service accounts of the runtimes periodically until the context is done
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.GCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.collectAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (m *Manager) collectAll(ctx context.Context) {
+	//the runtimes registered before a restart or by other replicas are collected as well
+	if m.registry != nil {
+		registered, err := m.registry.list(ctx)
+		if err != nil {
+			log.Warnf("Unable to get the registered runtimes: %s", err)
+		}
+		m.lock.Lock()
+		for _, key := range registered {
+			m.runtimes[key] = true
+		}
+		m.lock.Unlock()
+	}
+
+	m.lock.Lock()
+	runtimes := make([]runtimeKey, 0, len(m.runtimes))
+	for key := range m.runtimes {
+		runtimes = append(runtimes, key)
+	}
+	m.lock.Unlock()
+
+	for _, key := range runtimes {
+		rawKubeconfig, err := m.adminKubeconfig(key.tenantID, key.runtimeID)
+		if err != nil {
+			log.Warnf("Unable to get the admin kubeconfig of runtime %s/%s: %s", key.tenantID, key.runtimeID, err)
+			continue
+		}
+
+		client, err := m.newClient(rawKubeconfig)
+		if err != nil {
+			log.Warnf("Unable to create the client of runtime %s/%s: %s", key.tenantID, key.runtimeID, err)
+			continue
+		}
+
+		remaining, err := m.collect(ctx, client)
+		if err != nil {
+			log.Warnf("Unable to delete the expired service accounts of runtime %s/%s: %s", key.tenantID, key.runtimeID, err)
+			continue
+		}
+
+		if remaining == 0 {
+			m.unregister(ctx, key)
+		}
+	}
+}
+
+//collect deletes the expired service accounts of the runtime and returns the number of service accounts not expired yet
+func (m *Manager) collect(ctx context.Context, client kubernetes.Interface) (int, error) {
+	accounts, err := client.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: labelManaged + "=true"})
+	if err != nil {
+		return 0, errors.Wrap(err, "while listing service accounts")
+	}
+
+	remaining := 0
+	for _, sa := range accounts.Items {
+		expiresAt, err := time.Parse(time.RFC3339, sa.Annotations[annotationExpiresAt])
+		if err == nil && m.now().Before(expiresAt) {
+			remaining++
+			continue
+		}
+
+		if err := m.delete(ctx, client, sa); err != nil {
+			return 0, err
+		}
+	}
+
+	return remaining, nil
+}
+
+//delete deletes the service account and its bindings
+func (m *Manager) delete(ctx context.Context, client kubernetes.Interface, sa corev1.ServiceAccount) error {
+	if err := deleteBindings(ctx, client, sa); err != nil {
+		return err
+	}
+
+	err := client.CoreV1().ServiceAccounts(sa.Namespace).Delete(ctx, sa.Name, metav1.DeleteOptions{})
+	if ignoreNotFound(err) != nil {
+		return errors.Wrapf(err, "while deleting service account %s/%s", sa.Namespace, sa.Name)
+	}
+
+	log.Infof("Deleted service account %s/%s", sa.Namespace, sa.Name)
+
+	return nil
+}
+
+func (m *Manager) register(ctx context.Context, key runtimeKey) {
+	m.lock.Lock()
+	registered := m.runtimes[key]
+	m.runtimes[key] = true
+	m.lock.Unlock()
+
+	if registered || m.registry == nil {
+		return
+	}
+	if err := m.registry.add(ctx, key); err != nil {
+		log.Warnf("Unable to register runtime %s/%s, its service accounts are not deleted after a restart: %s", key.tenantID, key.runtimeID, err)
+	}
+}
+
+func (m *Manager) unregister(ctx context.Context, key runtimeKey) {
+	m.lock.Lock()
+	delete(m.runtimes, key)
+	m.lock.Unlock()
+
+	if m.registry == nil {
+		return
+	}
+	if err := m.registry.remove(ctx, key); err != nil {
+		log.Warnf("Unable to unregister runtime %s/%s: %s", key.tenantID, key.runtimeID, err)
+	}
+}
+
+func serviceAccountName() (string, error) {
+	suffix := make([]byte, 5)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", errors.Wrap(err, "while generating service account name")
+	}
+	return "kubeconfig-" + hex.EncodeToString(suffix), nil
+}
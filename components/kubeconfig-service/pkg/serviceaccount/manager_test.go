@@ -0,0 +1,234 @@
+package serviceaccount
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testTenant     = "tenant-1"
+	testRuntime    = "runtime-1"
+	testKubeconfig = "admin-kubeconfig"
+)
+
+var testConfig = Config{
+	Namespace:  "default",
+	Roles:      []string{"view", "edit"},
+	DefaultTTL: time.Hour,
+	MaxTTL:     24 * time.Hour,
+	GCInterval: time.Minute,
+}
+
+func TestManager_ParseRequest(t *testing.T) {
+	manager := NewManager(testConfig, nil)
+
+	t.Run("Should use default ttl", func(t *testing.T) {
+		request, err := manager.ParseRequest(url.Values{"role": {"view"}, "namespace": {"test"}})
+
+		require.NoError(t, err)
+		assert.Equal(t, Request{Role: "view", TTL: time.Hour, Namespace: "test"}, request)
+	})
+
+	for _, tc := range []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "missing role", query: url.Values{}},
+		{name: "role not allowed", query: url.Values{"role": {"cluster-admin"}}},
+		{name: "invalid ttl", query: url.Values{"role": {"view"}, "ttl": {"1 hour"}}},
+		{name: "ttl too short", query: url.Values{"role": {"view"}, "ttl": {"1m"}}},
+		{name: "ttl too long", query: url.Values{"role": {"view"}, "ttl": {"48h"}}},
+	} {
+		t.Run("Should reject "+tc.name, func(t *testing.T) {
+			_, err := manager.ParseRequest(tc.query)
+
+			assert.IsType(t, &InvalidRequestError{}, err)
+		})
+	}
+}
+
+func TestManager_Create(t *testing.T) {
+	t.Run("Should create cluster-scoped service account", func(t *testing.T) {
+		//given
+		cluster := newFakeCluster(t)
+		manager := cluster.newManager(nil)
+
+		//when
+		token, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "view", TTL: time.Hour, Requester: "john@example.com"})
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, "default", token.Namespace)
+		assert.Equal(t, "token-of-"+token.ServiceAccount, token.Token)
+
+		sa := cluster.serviceAccount("default", token.ServiceAccount)
+		require.NotNil(t, sa)
+		assert.Equal(t, "john@example.com", sa.Annotations[annotationRequester])
+		assert.Equal(t, token.ExpiresAt.Format(time.RFC3339), sa.Annotations[annotationExpiresAt])
+
+		binding, err := cluster.RbacV1().ClusterRoleBindings().Get(context.Background(), token.ServiceAccount, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", binding.RoleRef.Name)
+		assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: token.ServiceAccount, Namespace: "default"}}, binding.Subjects)
+		assert.Equal(t, int64(3600), cluster.tokenTTLs[token.ServiceAccount])
+	})
+
+	t.Run("Should create namespaced service account", func(t *testing.T) {
+		//given
+		cluster := newFakeCluster(t)
+		manager := cluster.newManager(nil)
+
+		//when
+		token, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "edit", TTL: time.Hour, Namespace: "ci"})
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, "ci", token.Namespace)
+		assert.NotNil(t, cluster.serviceAccount("ci", token.ServiceAccount))
+
+		binding, err := cluster.RbacV1().RoleBindings("ci").Get(context.Background(), token.ServiceAccount, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "edit", binding.RoleRef.Name)
+		assert.Empty(t, cluster.clusterRoleBindings())
+	})
+
+	t.Run("Should delete service account when token can't be created", func(t *testing.T) {
+		//given
+		cluster := newFakeCluster(t)
+		cluster.tokenErr = apierrors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "token", nil)
+		manager := cluster.newManager(nil)
+
+		//when
+		_, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "view", TTL: time.Hour})
+
+		//then
+		assert.Error(t, err)
+		assert.Empty(t, cluster.serviceAccounts())
+		assert.Empty(t, cluster.clusterRoleBindings())
+	})
+}
+
+func TestManager_Collect(t *testing.T) {
+	//given
+	cluster := newFakeCluster(t)
+	manager := cluster.newManager(func(tenantID, runtimeID string) (string, error) {
+		assert.Equal(t, testTenant, tenantID)
+		assert.Equal(t, testRuntime, runtimeID)
+		return testKubeconfig, nil
+	})
+
+	now := time.Now()
+	manager.now = func() time.Time { return now }
+
+	expired, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "view", TTL: time.Hour})
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Minute)
+	valid, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "view", TTL: time.Hour, Namespace: "ci"})
+	require.NoError(t, err)
+
+	//when
+	now = now.Add(45 * time.Minute)
+	manager.collectAll(context.Background())
+
+	//then
+	assert.Nil(t, cluster.serviceAccount("default", expired.ServiceAccount))
+	assert.Empty(t, cluster.clusterRoleBindings())
+	assert.NotNil(t, cluster.serviceAccount("ci", valid.ServiceAccount))
+	_, err = cluster.RbacV1().RoleBindings("ci").Get(context.Background(), valid.ServiceAccount, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, manager.runtimes, 1, "runtime should be collected again")
+
+	//when
+	now = now.Add(time.Hour)
+	manager.collectAll(context.Background())
+
+	//then
+	assert.Empty(t, cluster.serviceAccounts())
+	bindings, err := cluster.RbacV1().RoleBindings("ci").List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, bindings.Items)
+	assert.Empty(t, manager.runtimes, "runtime without service accounts should not be collected anymore")
+}
+
+//fakeCluster is the API server of a runtime, the tokens of its service accounts are created by a reactor
+type fakeCluster struct {
+	*fake.Clientset
+	t *testing.T
+
+	tokenTTLs map[string]int64
+	tokenErr  error
+}
+
+func newFakeCluster(t *testing.T) *fakeCluster {
+	cluster := &fakeCluster{
+		Clientset: fake.NewSimpleClientset(),
+		t:         t,
+		tokenTTLs: make(map[string]int64),
+	}
+	cluster.PrependReactor("create", "serviceaccounts", cluster.createToken)
+
+	return cluster
+}
+
+func (c *fakeCluster) createToken(action k8stesting.Action) (bool, runtime.Object, error) {
+	create, ok := action.(k8stesting.CreateActionImpl)
+	if !ok || create.GetSubresource() != "token" {
+		return false, nil, nil
+	}
+	if c.tokenErr != nil {
+		return true, nil, c.tokenErr
+	}
+
+	request := create.GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+	c.tokenTTLs[create.Name] = *request.Spec.ExpirationSeconds
+	request.Status.Token = "token-of-" + create.Name
+
+	return true, request, nil
+}
+
+//newManager returns a manager creating the clients of the admin kubeconfig testKubeconfig with the fake cluster
+func (c *fakeCluster) newManager(adminKubeconfig KubeconfigProvider) *Manager {
+	manager := NewManager(testConfig, adminKubeconfig)
+	manager.newClient = func(rawKubeconfig string) (kubernetes.Interface, error) {
+		assert.Equal(c.t, testKubeconfig, rawKubeconfig)
+		return c, nil
+	}
+
+	return manager
+}
+
+func (c *fakeCluster) serviceAccount(namespace, name string) *corev1.ServiceAccount {
+	sa, err := c.CoreV1().ServiceAccounts(namespace).Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return sa
+}
+
+func (c *fakeCluster) serviceAccounts() []corev1.ServiceAccount {
+	list, err := c.CoreV1().ServiceAccounts(metav1.NamespaceAll).List(context.Background(), metav1.ListOptions{})
+	require.NoError(c.t, err)
+	return list.Items
+}
+
+func (c *fakeCluster) clusterRoleBindings() []rbacv1.ClusterRoleBinding {
+	list, err := c.RbacV1().ClusterRoleBindings().List(context.Background(), metav1.ListOptions{})
+	require.NoError(c.t, err)
+	return list.Items
+}
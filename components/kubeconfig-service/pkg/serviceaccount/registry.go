@@ -0,0 +1,102 @@
+package serviceaccount
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+//ConfigMapRegistry persists the runtimes which may have service accounts to delete in a ConfigMap of the cluster
+//kubeconfig-service runs in, so they are collected after a restart. The keys of the ConfigMap are "<tenantID>.<runtimeID>".
+//The keys are added and removed with merge patches, so the ConfigMap can be shared by several replicas.
+type ConfigMapRegistry struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+//NewConfigMapRegistry returns a new ConfigMapRegistry using the credentials of the pod service account
+func NewConfigMapRegistry(config RegistryConfig) (*ConfigMapRegistry, error) {
+	client, err := newInClusterClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := config.Namespace
+	if namespace == "" {
+		data, err := ioutil.ReadFile(inClusterNamespaceFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "while reading pod namespace")
+		}
+		namespace = strings.TrimSpace(string(data))
+	}
+
+	return &ConfigMapRegistry{client: client, namespace: namespace, name: config.ConfigMap}, nil
+}
+
+func (r *ConfigMapRegistry) list(ctx context.Context) ([]runtimeKey, error) {
+	cm, err := r.client.CoreV1().ConfigMaps(r.namespace).Get(ctx, r.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "while getting ConfigMap %s/%s", r.namespace, r.name)
+	}
+
+	runtimes := make([]runtimeKey, 0, len(cm.Data))
+	for key := range cm.Data {
+		parts := strings.SplitN(key, ".", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		runtimes = append(runtimes, runtimeKey{tenantID: parts[0], runtimeID: parts[1]})
+	}
+
+	return runtimes, nil
+}
+
+func (r *ConfigMapRegistry) add(ctx context.Context, key runtimeKey) error {
+	value := "true"
+	err := r.patch(ctx, key, &value)
+	if !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "while updating ConfigMap %s/%s", r.namespace, r.name)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: r.name, Namespace: r.namespace, Labels: map[string]string{labelManaged: "true"}},
+		Data:       map[string]string{key.String(): value},
+	}
+	_, err = r.client.CoreV1().ConfigMaps(r.namespace).Create(ctx, cm, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		//the ConfigMap was created by another replica in the meantime
+		return errors.Wrapf(r.patch(ctx, key, &value), "while updating ConfigMap %s/%s", r.namespace, r.name)
+	}
+	return errors.Wrapf(err, "while creating ConfigMap %s/%s", r.namespace, r.name)
+}
+
+func (r *ConfigMapRegistry) remove(ctx context.Context, key runtimeKey) error {
+	return errors.Wrapf(ignoreNotFound(r.patch(ctx, key, nil)), "while updating ConfigMap %s/%s", r.namespace, r.name)
+}
+
+//patch sets the value of the runtime key, a nil value removes the key
+func (r *ConfigMapRegistry) patch(ctx context.Context, key runtimeKey, value *string) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"data": map[string]*string{key.String(): value},
+	})
+	if err != nil {
+		return errors.Wrap(err, "while marshalling patch")
+	}
+
+	_, err = r.client.CoreV1().ConfigMaps(r.namespace).Patch(ctx, r.name, types.MergePatchType, data, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,76 @@
+package serviceaccount
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestManager_Registry(t *testing.T) {
+	//given
+	cluster := newFakeCluster(t)
+	registryClient := fake.NewSimpleClientset()
+	registry := &ConfigMapRegistry{client: registryClient, namespace: "kcp-system", name: "kubeconfig-service-runtimes"}
+
+	now := time.Now()
+	adminKubeconfig := func(tenantID, runtimeID string) (string, error) {
+		return testKubeconfig, nil
+	}
+
+	manager := cluster.newManager(adminKubeconfig)
+	manager.SetRegistry(registry)
+
+	//when
+	token, err := manager.Create(context.Background(), testTenant, testRuntime, testKubeconfig, Request{Role: "view", TTL: time.Hour})
+	require.NoError(t, err)
+
+	//then
+	cm, err := registryClient.CoreV1().ConfigMaps("kcp-system").Get(context.Background(), "kubeconfig-service-runtimes", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{testTenant + "." + testRuntime: "true"}, cm.Data)
+	assert.Equal(t, "true", cm.Labels[labelManaged])
+
+	//when the service is restarted and the service account is expired
+	restarted := cluster.newManager(adminKubeconfig)
+	restarted.SetRegistry(registry)
+	restarted.now = func() time.Time { return now.Add(2 * time.Hour) }
+	restarted.collectAll(context.Background())
+
+	//then
+	assert.Nil(t, cluster.serviceAccount("default", token.ServiceAccount))
+	assert.Empty(t, restarted.runtimes)
+
+	cm, err = registryClient.CoreV1().ConfigMaps("kcp-system").Get(context.Background(), "kubeconfig-service-runtimes", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, cm.Data, "runtime without service accounts should be unregistered")
+}
+
+func TestConfigMapRegistry(t *testing.T) {
+	//given
+	registry := &ConfigMapRegistry{client: fake.NewSimpleClientset(), namespace: "kcp-system", name: "kubeconfig-service-runtimes"}
+	first := runtimeKey{tenantID: testTenant, runtimeID: testRuntime}
+	second := runtimeKey{tenantID: testTenant, runtimeID: "runtime-2"}
+
+	//when the ConfigMap does not exist
+	runtimes, err := registry.list(context.Background())
+
+	//then
+	require.NoError(t, err)
+	assert.Empty(t, runtimes)
+	assert.NoError(t, registry.remove(context.Background(), first))
+
+	//when
+	require.NoError(t, registry.add(context.Background(), first))
+	require.NoError(t, registry.add(context.Background(), second))
+	require.NoError(t, registry.remove(context.Background(), first))
+	runtimes, err = registry.list(context.Background())
+
+	//then
+	require.NoError(t, err)
+	assert.Equal(t, []runtimeKey{second}, runtimes)
+}
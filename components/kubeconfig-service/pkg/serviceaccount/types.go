@@ -0,0 +1,63 @@
+package serviceaccount
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	labelManaged        = "kubeconfig-service.kyma-project.io/managed"
+	labelServiceAccount = "kubeconfig-service.kyma-project.io/serviceaccount"
+	annotationExpiresAt = "kubeconfig-service.kyma-project.io/expires-at"
+	annotationRequester = "kubeconfig-service.kyma-project.io/requested-by"
+
+	//minTTL is the minimum lifetime of a token accepted by the TokenRequest API
+	minTTL = 10 * time.Minute
+)
+
+//Config represents configuration of the service account kubeconfigs
+type Config struct {
+	//Namespace of the service accounts bound to a ClusterRole for the whole cluster
+	Namespace string
+	//Roles are the ClusterRoles which can be requested
+	Roles []string
+	//DefaultTTL is the lifetime of the service accounts if none is requested
+	DefaultTTL time.Duration
+	//MaxTTL is the maximum lifetime of the service accounts
+	MaxTTL time.Duration
+	//GCInterval is the interval at which the expired service accounts are deleted
+	GCInterval time.Duration
+}
+
+//RegistryConfig represents configuration of the ConfigMap persisting the runtimes which may have service accounts to delete
+type RegistryConfig struct {
+	//Namespace of the ConfigMap, the namespace of the pod if empty
+	Namespace string
+	//ConfigMap is the name of the ConfigMap
+	ConfigMap string
+}
+
+//Request represents the parameters of a service account kubeconfig
+type Request struct {
+	//Role is the ClusterRole bound to the service account
+	Role string
+	//TTL is the lifetime of the service account and its token
+	TTL time.Duration
+	//Namespace restricts the role to the namespace if not empty, the role is bound for the whole cluster otherwise
+	Namespace string
+	//Requester is the name of the user who requested the service account
+	Requester string
+}
+
+//InvalidRequestError is returned when the request parameters are not valid
+type InvalidRequestError struct {
+	Message string
+}
+
+func (e *InvalidRequestError) Error() string {
+	return e.Message
+}
+
+func invalidRequest(format string, args ...interface{}) error {
+	return &InvalidRequestError{Message: fmt.Sprintf(format, args...)}
+}
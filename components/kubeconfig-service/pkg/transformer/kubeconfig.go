@@ -52,3 +52,60 @@ users:
       - "--oidc-client-secret={{ .OIDCClientSecret }}"
       command: kubectl
 `
+
+const serviceAccountKubeconfigTemplate = `
+---
+apiVersion: v1
+kind: Config
+current-context: {{ .ContextName }}
+clusters:
+- name: {{ .ContextName }}
+  cluster:
+    certificate-authority-data: {{ .CAData }}
+    server: {{ .ServerURL }}
+contexts:
+- name: {{ .ContextName }}
+  context:
+    cluster: {{ .ContextName }}
+    namespace: {{ .Namespace }}
+    user: {{ .ServiceAccount }}
+users:
+- name: {{ .ServiceAccount }}
+  user:
+    token: {{ .Token }}
+`
+
+const mergedKubeconfigTemplate = `
+---
+apiVersion: v1
+kind: Config
+current-context: {{ (index . 0).ContextName }}
+clusters:
+{{- range . }}
+- name: {{ .ContextName }}
+  cluster:
+    certificate-authority-data: {{ .CAData }}
+    server: {{ .ServerURL }}
+{{- end }}
+contexts:
+{{- range . }}
+- name: {{ .ContextName }}
+  context:
+    cluster: {{ .ContextName }}
+    user: {{ .ContextName }}
+{{- end }}
+users:
+{{- range . }}
+- name: {{ .ContextName }}
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+      - oidc-login
+      - get-token
+      - "--oidc-issuer-url={{ .OIDCIssuerURL }}"
+      - "--oidc-client-id={{ .OIDCClientID }}"
+      - "--oidc-client-secret={{ .OIDCClientSecret }}"
+      command: kubectl
+{{- end }}
+`
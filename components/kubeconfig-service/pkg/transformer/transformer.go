@@ -2,6 +2,7 @@ package transformer
 
 import (
 	"bytes"
+	"errors"
 	"html/template"
 
 	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/env"
@@ -26,14 +27,19 @@ func NewClient(rawKubeCfg string) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	return NewClusterClient(kubeCfg.CurrentContext, kubeCfg.Clusters[0].Cluster.CertificateAuthorityData, kubeCfg.Clusters[0].Cluster.Server), nil
+}
+
+//NewClusterClient Create new instance of TransformerClient of the cluster with the context name, CA data and server URL
+func NewClusterClient(contextName, caData, serverURL string) *Client {
 	return &Client{
-		ContextName:      kubeCfg.CurrentContext,
-		CAData:           kubeCfg.Clusters[0].Cluster.CertificateAuthorityData,
-		ServerURL:        kubeCfg.Clusters[0].Cluster.Server,
+		ContextName:      contextName,
+		CAData:           caData,
+		ServerURL:        serverURL,
 		OIDCClientID:     env.Config.OIDC.Kubeconfig.ClientID,
 		OIDCClientSecret: env.Config.OIDC.Kubeconfig.ClientSecret,
 		OIDCIssuerURL:    env.Config.OIDC.Kubeconfig.IssuerURL,
-	}, nil
+	}
 }
 
 //TransformKubeconfig injects OIDC data into raw kubeconfig structure
@@ -46,15 +52,56 @@ func (c *Client) TransformKubeconfig() ([]byte, error) {
 	return []byte(out), nil
 }
 
+//ServiceAccountKubeconfig returns a kubeconfig authenticating with the token of the service account
+func (c *Client) ServiceAccountKubeconfig(serviceAccount, namespace, token string) ([]byte, error) {
+	data := struct {
+		*Client
+		ServiceAccount string
+		Namespace      string
+		Token          string
+	}{
+		Client:         c,
+		ServiceAccount: serviceAccount,
+		Namespace:      namespace,
+		Token:          token,
+	}
+
+	out, err := parseTemplate(serviceAccountKubeconfigTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
+//MergeKubeconfigs returns a kubeconfig with a cluster, a context and a user named after the context name of each client,
+//the context of the first client is the current context
+func MergeKubeconfigs(clients []*Client) ([]byte, error) {
+	if len(clients) == 0 {
+		return nil, errors.New("no kubeconfig to merge")
+	}
+
+	out, err := parseTemplate(mergedKubeconfigTemplate, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(out), nil
+}
+
 func (c *Client) parseTemplate() (string, error) {
+	return parseTemplate(kubeconfigTemplate, c)
+}
+
+func parseTemplate(text string, data interface{}) (string, error) {
 	var result bytes.Buffer
 	t := template.New("kubeconfigParser")
-	t, err := t.Parse(kubeconfigTemplate)
+	t, err := t.Parse(text)
 	if err != nil {
 		return "", err
 	}
 
-	err = t.Execute(&result, c)
+	err = t.Execute(&result, data)
 	if err != nil {
 		return "", err
 	}
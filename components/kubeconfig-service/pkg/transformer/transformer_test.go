@@ -44,6 +44,43 @@ func TestSpec(t *testing.T) {
 			So(string(res), ShouldEqual, expectedTransformedKubeconfig)
 		})
 	})
+
+	Convey("MergeKubeconfigs()", t, func() {
+		Convey("Should return kubeconfig with an entry for each client", func() {
+			//given
+			first, err := transformer.NewClient(testInputRawKubeconfig)
+			So(err, ShouldBeNil)
+			second, err := transformer.NewClient(testInputRawKubeconfig)
+			So(err, ShouldBeNil)
+			second.ContextName = "c-178e034"
+			second.ServerURL = "https://api.c-178e034.kymatest.com"
+			//when
+			res, err := transformer.MergeKubeconfigs([]*transformer.Client{first, second})
+			//then
+			So(err, ShouldBeNil)
+			So(string(res), ShouldEqual, expectedMergedKubeconfig)
+		})
+
+		Convey("Should return error when there is no client", func() {
+			//when
+			_, err := transformer.MergeKubeconfigs(nil)
+			//then
+			So(err, ShouldNotBeNil)
+		})
+	})
+
+	Convey("client.ServiceAccountKubeconfig()", t, func() {
+		Convey("Should return service account kubeconfig", func() {
+			//given
+			c, err := transformer.NewClient(testInputRawKubeconfig)
+			So(err, ShouldBeNil)
+			//when
+			res, err := c.ServiceAccountKubeconfig("kubeconfig-0a1b2c3d4e", "ci", "eyFakeToken")
+			//then
+			So(err, ShouldBeNil)
+			So(string(res), ShouldEqual, expectedServiceAccountKubeconfig)
+		})
+	})
 }
 
 const (
@@ -99,4 +136,74 @@ users:
       - "--oidc-client-secret=testClientSecret"
       command: kubectl
 `
+
+	expectedServiceAccountKubeconfig = `
+---
+apiVersion: v1
+kind: Config
+current-context: test--aa1234b
+clusters:
+- name: test--aa1234b
+  cluster:
+    certificate-authority-data: LS0FakeFakeQo=
+    server: https://api.kymatest.com
+contexts:
+- name: test--aa1234b
+  context:
+    cluster: test--aa1234b
+    namespace: ci
+    user: kubeconfig-0a1b2c3d4e
+users:
+- name: kubeconfig-0a1b2c3d4e
+  user:
+    token: eyFakeToken
+`
+
+	expectedMergedKubeconfig = `
+---
+apiVersion: v1
+kind: Config
+current-context: test--aa1234b
+clusters:
+- name: test--aa1234b
+  cluster:
+    certificate-authority-data: LS0FakeFakeQo=
+    server: https://api.kymatest.com
+- name: c-178e034
+  cluster:
+    certificate-authority-data: LS0FakeFakeQo=
+    server: https://api.c-178e034.kymatest.com
+contexts:
+- name: test--aa1234b
+  context:
+    cluster: test--aa1234b
+    user: test--aa1234b
+- name: c-178e034
+  context:
+    cluster: c-178e034
+    user: c-178e034
+users:
+- name: test--aa1234b
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+      - oidc-login
+      - get-token
+      - "--oidc-issuer-url=testIssuerURL"
+      - "--oidc-client-id=testClientId"
+      - "--oidc-client-secret=testClientSecret"
+      command: kubectl
+- name: c-178e034
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      args:
+      - oidc-login
+      - get-token
+      - "--oidc-issuer-url=testIssuerURL"
+      - "--oidc-client-id=testClientId"
+      - "--oidc-client-secret=testClientSecret"
+      command: kubectl
+`
 )
@@ -22,6 +22,7 @@ import (
 	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/appinfo"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/auditlog"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/avs"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
@@ -54,6 +55,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/suspension"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/webhook"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -130,6 +132,14 @@ type Config struct {
 
 	AuditLog auditlog.Config
 
+	Webhook webhook.Config
+
+	// ParallelSteps makes the provisioning, deprovisioning and Kyma upgrade managers run the steps of the same weight concurrently
+	ParallelSteps bool `envconfig:"default=false"`
+
+	// EventOutbox enables storing application events in the database, so they are not lost on restarts
+	EventOutbox event.OutboxConfig
+
 	VersionConfig struct {
 		Namespace string
 		Name      string
@@ -262,17 +272,32 @@ func main() {
 	iasTypeSetter := provisioning.NewIASType(bundleBuilder, cfg.IAS.Disabled)
 
 	// application event broker
-	eventBroker := event.NewPubSub(logs)
+	var eventBroker event.Broker = event.NewPubSub(logs)
+	if cfg.EventOutbox.Enabled {
+		outbox := event.NewOutbox(cfg.EventOutbox, db.OutboxEvents(), logs.WithField("service", "eventOutbox"))
+		prometheus.MustRegister(outbox)
+		outbox.Run(ctx)
+		eventBroker = outbox
+	}
 
 	// metrics collectors
 	metrics.RegisterAll(eventBroker, db.Operations(), db.Instances())
 
+	// webhook notifications about operation and orchestration state changes
+	if !cfg.Webhook.Disabled {
+		webhook.NewNotifier(cfg.Webhook, db.WebhookDeadLetters(), logs.WithField("service", "webhookNotifier")).Subscribe(eventBroker)
+	}
+
 	//setup runtime overrides appender
 	runtimeOverrides := runtimeoverrides.NewRuntimeOverrides(ctx, cli)
 
 	// setup operation managers
 	provisionManager := provisioning.NewManager(db.Operations(), eventBroker, logs.WithField("provisioning", "manager"))
 	deprovisionManager := deprovisioning.NewManager(db.Operations(), eventBroker, logs.WithField("deprovisioning", "manager"))
+	if cfg.ParallelSteps {
+		provisionManager.EnableParallelSteps()
+		deprovisionManager.EnableParallelSteps()
+	}
 
 	serviceManagerClientFactory := servicemanager.NewClientFactory(cfg.ServiceManager)
 
@@ -497,6 +522,8 @@ func main() {
 	deprovisionQueue.Run(ctx.Done(), workersAmount)
 
 	suspensionCtxHandler := suspension.NewContextUpdateHandler(db.Operations(), provisionQueue, deprovisionQueue, logs)
+	reprovisioner := suspension.NewReprovisioner(db.Operations(), db.Instances(), suspensionCtxHandler, logs.WithField("service", "reprovisioner"))
+	reprovisioner.Subscribe(eventBroker)
 
 	servicesConfig, err := broker.NewServicesConfigFromFile(cfg.CatalogFilePath)
 	fatalOnError(err)
@@ -507,11 +534,13 @@ func main() {
 	plansValidator, err := broker.NewPlansSchemaValidator(defaultPlansConfig)
 	fatalOnError(err)
 
+	deprovisionEndpoint := broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs)
+
 	// create KymaEnvironmentBroker endpoints
 	kymaEnvBroker := &broker.KymaEnvironmentBroker{
 		broker.NewServices(cfg.Broker, servicesConfig, logs),
 		broker.NewProvision(cfg.Broker, cfg.Gardener, db.Operations(), db.Instances(), provisionQueue, inputFactory, plansValidator, defaultPlansConfig, cfg.EnableOnDemandVersion, logs),
-		broker.NewDeprovision(db.Instances(), db.Operations(), deprovisionQueue, logs),
+		deprovisionEndpoint,
 		broker.NewUpdate(db.Instances(), db.Operations(), suspensionCtxHandler, cfg.UpdateProcessingEnabled, logs),
 		broker.NewGetInstance(db.Instances(), logs),
 		broker.NewLastOperation(db.Operations(), db.Instances(), logs),
@@ -549,6 +578,8 @@ func main() {
 		fatalOnError(err)
 		err = processOperationsInProgressByType(internal.OperationTypeDeprovision, db.Operations(), deprovisionQueue, logs)
 		fatalOnError(err)
+		err = reprovisioner.Resume()
+		fatalOnError(err)
 		err = reprocessOrchestrations(orchestrationExt.UpgradeKymaOrchestration, db.Orchestrations(), db.Operations(), kymaQueue, logs)
 		fatalOnError(err)
 		err = reprocessOrchestrations(orchestrationExt.UpgradeClusterOrchestration, db.Orchestrations(), db.Operations(), clusterQueue, logs)
@@ -559,6 +590,7 @@ func main() {
 
 	// create OSB API endpoints
 	router.Use(middleware.AddRegionToContext(cfg.DefaultRequestRegion))
+	router.Use(middleware.AddActorToContext())
 	for _, prefix := range []string{
 		"/oauth/",          // oauth2 handled by Ory
 		"/oauth/{region}/", // oauth2 handled by Ory with region
@@ -574,6 +606,14 @@ func main() {
 	runtimeHandler := runtime.NewHandler(db.Instances(), db.Operations(), cfg.MaxPaginationPage, cfg.DefaultRequestRegion)
 	runtimeHandler.AttachRoutes(router)
 
+	// create runtime actions endpoints
+	runtimeActionHandler := runtime.NewActionHandler(db.Instances(), db.Operations(), suspensionCtxHandler, deprovisionEndpoint, reprovisioner, audit.NewRecorder(db.AuditEvents(), logs), logs)
+	runtimeActionHandler.AttachRoutes(router)
+
+	// create /audit
+	auditHandler := audit.NewHandler(db.AuditEvents(), cfg.MaxPaginationPage, logs)
+	auditHandler.AttachRoutes(router)
+
 	router.StrictSlash(true).PathPrefix("/").Handler(http.StripPrefix("/", http.FileServer(http.Dir("/swagger"))))
 	svr := handlers.CustomLoggingHandler(os.Stdout, router, func(writer io.Writer, params handlers.LogFormatterParams) {
 		logs.Infof("Call handled: method=%s url=%s statusCode=%d size=%d", params.Request.Method, params.URL.Path, params.StatusCode, params.Size)
@@ -720,6 +760,9 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 	clsProvisioner := cls.NewProvisioner(db.CLSInstances(), clsClient)
 
 	upgradeKymaManager := upgrade_kyma.NewManager(db.Operations(), pub, logs.WithField("upgradeKyma", "manager"))
+	if cfg.ParallelSteps {
+		upgradeKymaManager.EnableParallelSteps()
+	}
 	upgradeKymaInit := upgrade_kyma.NewInitialisationStep(db.Operations(), db.Orchestrations(), db.Instances(),
 		provisionerClient, inputFactory, upgradeEvalManager, icfg, runtimeVerConfigurator, smcf)
 
@@ -794,7 +837,7 @@ func NewKymaOrchestrationProcessingQueue(ctx context.Context, db storage.BrokerS
 	}
 
 	orchestrateKymaManager := manager.NewUpgradeKymaManager(db.Orchestrations(), db.Operations(), db.Instances(),
-		upgradeKymaManager, runtimeResolver, pollingInterval, smcf, logs.WithField("upgradeKyma", "orchestration"))
+		upgradeKymaManager, runtimeResolver, pollingInterval, smcf, pub, logs.WithField("upgradeKyma", "orchestration"))
 	queue := process.NewQueue(orchestrateKymaManager, logs)
 
 	queue.Run(ctx.Done(), 3)
@@ -827,7 +870,7 @@ func NewClusterOrchestrationProcessingQueue(ctx context.Context, db storage.Brok
 	}
 
 	orchestrateClusterManager := manager.NewUpgradeClusterManager(db.Orchestrations(), db.Operations(), db.Instances(),
-		upgradeClusterManager, runtimeResolver, pollingInterval, logs.WithField("upgradeCluster", "orchestration"))
+		upgradeClusterManager, runtimeResolver, pollingInterval, pub, logs.WithField("upgradeCluster", "orchestration"))
 	queue := process.NewQueue(orchestrateClusterManager, logs)
 
 	queue.Run(ctx.Done(), 3)
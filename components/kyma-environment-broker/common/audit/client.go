@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/oauth"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const defaultPageSize = 100
+
+// Client is the interface to interact with the KEB /audit API as an HTTP client using OIDC ID token in JWT format.
+type Client interface {
+	ListEvents(params ListParameters) (EventsPage, error)
+}
+
+type client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewClient constructs and returns new Client for KEB /audit API
+// It takes the following arguments:
+//   - ctx  : context in which the http request will be executed
+//   - url  : base url of all KEB APIs, e.g. https://kyma-env-broker.kyma.local
+//   - auth : TokenSource object which provides the ID token for the HTTP request,
+//            if it implements oauth.Invalidator, the requests rejected as unauthorized are retried with a new token
+func NewClient(ctx context.Context, url string, auth oauth2.TokenSource) Client {
+	return &client{
+		url:        url,
+		httpClient: oauth.NewClient(ctx, auth),
+	}
+}
+
+// ListEvents fetches the audit events from KEB according to the given parameters.
+// If params.Page or params.PageSize is not set (zero), the client will fetch and return all events.
+func (c *client) ListEvents(params ListParameters) (EventsPage, error) {
+	events := EventsPage{}
+	getAll := false
+	fetchedAll := false
+	if params.Page == 0 || params.PageSize == 0 {
+		getAll = true
+		params.Page = 1
+		params.PageSize = defaultPageSize
+	}
+
+	for !fetchedAll {
+		req, err := http.NewRequest("GET", fmt.Sprintf("%s/audit", c.url), nil)
+		if err != nil {
+			return events, errors.Wrap(err, "while creating request")
+		}
+		setQuery(req.URL, params)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return events, errors.Wrapf(err, "while calling %s", req.URL.String())
+		}
+
+		// Drain response body and close, return error to context if there isn't any.
+		defer func() {
+			derr := drainResponseBody(resp.Body)
+			if err == nil {
+				err = derr
+			}
+			cerr := resp.Body.Close()
+			if err == nil {
+				err = cerr
+			}
+		}()
+
+		if resp.StatusCode != http.StatusOK {
+			return events, fmt.Errorf("calling %s returned %d (%s) status", req.URL.String(), resp.StatusCode, resp.Status)
+		}
+
+		var ep EventsPage
+		decoder := json.NewDecoder(resp.Body)
+		err = decoder.Decode(&ep)
+		if err != nil {
+			return events, errors.Wrap(err, "while decoding response body")
+		}
+
+		events.TotalCount = ep.TotalCount
+		events.Count += ep.Count
+		events.Data = append(events.Data, ep.Data...)
+		if getAll {
+			params.Page++
+			fetchedAll = events.Count >= events.TotalCount
+		} else {
+			fetchedAll = true
+		}
+	}
+
+	return events, nil
+}
+
+func setQuery(url *url.URL, params ListParameters) {
+	query := url.Query()
+	query.Add(pagination.PageParam, strconv.Itoa(params.Page))
+	query.Add(pagination.PageSizeParam, strconv.Itoa(params.PageSize))
+	setParamList(query, ActorParam, params.Actors)
+	setParamList(query, OrchestrationIDParam, params.OrchestrationIDs)
+	setParamList(query, InstanceIDParam, params.InstanceIDs)
+	setParamList(query, RuntimeIDParam, params.RuntimeIDs)
+	for _, a := range params.Actions {
+		query.Add(ActionParam, string(a))
+	}
+	for _, r := range params.Results {
+		query.Add(ResultParam, string(r))
+	}
+	url.RawQuery = query.Encode()
+}
+
+func setParamList(query url.Values, key string, values []string) {
+	for _, value := range values {
+		query.Add(key, value)
+	}
+}
+
+func drainResponseBody(body io.Reader) error {
+	if body == nil {
+		return nil
+	}
+	_, err := io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	return err
+}
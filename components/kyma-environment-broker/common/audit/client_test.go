@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type FakeTokenSource string
+
+var fixToken FakeTokenSource = "fake-token-1234"
+
+func (t FakeTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{
+		AccessToken: string(t),
+		Expiry:      time.Now().Add(time.Duration(12 * time.Hour)),
+	}, nil
+}
+
+var event1 = fixEventDTO("event1")
+var event2 = fixEventDTO("event2")
+
+func TestClient_ListEvents(t *testing.T) {
+	t.Run("test request URL and response are correct", func(t *testing.T) {
+		//given
+		called := 0
+		params := ListParameters{
+			Page:             1,
+			PageSize:         50,
+			Actors:           []string{"john.doe@example.com"},
+			Actions:          []Action{UpgradeKymaAction, CancelOrchestrationAction},
+			OrchestrationIDs: []string{"oid1", "oid2"},
+			InstanceIDs:      []string{"id1", "id2"},
+			RuntimeIDs:       []string{"rid1", "rid2"},
+			Results:          []Result{Failed},
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called++
+			assert.Equal(t, http.MethodGet, r.Method)
+			assert.Equal(t, "/audit", r.URL.Path)
+			assert.Equal(t, r.Header.Get("Authorization"), fmt.Sprintf("Bearer %s", fixToken))
+			query := r.URL.Query()
+			assert.ElementsMatch(t, []string{strconv.Itoa(params.Page)}, query[pagination.PageParam])
+			assert.ElementsMatch(t, []string{strconv.Itoa(params.PageSize)}, query[pagination.PageSizeParam])
+			assert.ElementsMatch(t, params.Actors, query[ActorParam])
+			assert.ElementsMatch(t, []string{string(UpgradeKymaAction), string(CancelOrchestrationAction)}, query[ActionParam])
+			assert.ElementsMatch(t, params.OrchestrationIDs, query[OrchestrationIDParam])
+			assert.ElementsMatch(t, params.InstanceIDs, query[InstanceIDParam])
+			assert.ElementsMatch(t, params.RuntimeIDs, query[RuntimeIDParam])
+			assert.ElementsMatch(t, []string{string(Failed)}, query[ResultParam])
+
+			err := respondEvents(w, []EventDTO{event1, event2}, 2)
+			require.NoError(t, err)
+		}))
+		defer ts.Close()
+		client := NewClient(context.TODO(), ts.URL, fixToken)
+
+		//when
+		ep, err := client.ListEvents(params)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 1, called)
+		assert.Equal(t, 2, ep.Count)
+		assert.Equal(t, 2, ep.TotalCount)
+		assert.Len(t, ep.Data, 2)
+		assert.Equal(t, ep.Data[0].ID, event1.ID)
+		assert.Equal(t, ep.Data[1].ID, event2.ID)
+	})
+
+	t.Run("test pagination", func(t *testing.T) {
+		called := 0
+		params := ListParameters{
+			PageSize: 2,
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called++
+
+			err := respondEvents(w, []EventDTO{event1, event2}, 4)
+			require.NoError(t, err)
+		}))
+		defer ts.Close()
+		client := NewClient(context.TODO(), ts.URL, fixToken)
+
+		//when
+		ep, err := client.ListEvents(params)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, 2, called)
+		assert.Equal(t, 4, ep.Count)
+		assert.Equal(t, 4, ep.TotalCount)
+		assert.Len(t, ep.Data, 4)
+	})
+}
+
+func fixEventDTO(id string) EventDTO {
+	return EventDTO{
+		ID:              id,
+		Actor:           "john.doe@example.com",
+		Action:          UpgradeKymaAction,
+		OrchestrationID: id,
+		Parameters:      json.RawMessage(`{"dryRun":true}`),
+		Result:          Succeeded,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func respondEvents(w http.ResponseWriter, events []EventDTO, totalCount int) error {
+	ep := EventsPage{
+		Data:       events,
+		Count:      len(events),
+		TotalCount: totalCount,
+	}
+	data, err := json.Marshal(ep)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(data)
+	return err
+}
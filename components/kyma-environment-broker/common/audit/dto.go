@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Action is the type of the operator action captured by an audit event
+type Action string
+
+const (
+	UpgradeKymaAction         Action = "upgradeKyma"
+	UpgradeClusterAction      Action = "upgradeCluster"
+	CancelOrchestrationAction Action = "cancelOrchestration"
+	RetryOrchestrationAction  Action = "retryOrchestration"
+	SuspendRuntimeAction      Action = "suspendRuntime"
+	UnsuspendRuntimeAction    Action = "unsuspendRuntime"
+	DeprovisionRuntimeAction  Action = "deprovisionRuntime"
+	ReprovisionRuntimeAction  Action = "reprovisionRuntime"
+)
+
+// Result is the outcome of the operator action captured by an audit event
+type Result string
+
+const (
+	Succeeded Result = "succeeded"
+	Failed    Result = "failed"
+)
+
+// UnknownActor is recorded when the identity of the caller cannot be determined from the request
+const UnknownActor = "unknown"
+
+type EventDTO struct {
+	ID              string          `json:"id"`
+	Actor           string          `json:"actor"`
+	Action          Action          `json:"action"`
+	OrchestrationID string          `json:"orchestrationID,omitempty"`
+	InstanceID      string          `json:"instanceID,omitempty"`
+	RuntimeID       string          `json:"runtimeID,omitempty"`
+	Parameters      json.RawMessage `json:"parameters,omitempty"`
+	Result          Result          `json:"result"`
+	Error           string          `json:"error,omitempty"`
+	CreatedAt       time.Time       `json:"createdAt"`
+}
+
+type EventsPage struct {
+	Data       []EventDTO `json:"data"`
+	Count      int        `json:"count"`
+	TotalCount int        `json:"totalCount"`
+}
+
+const (
+	ActorParam           = "actor"
+	ActionParam          = "action"
+	OrchestrationIDParam = "orchestration_id"
+	InstanceIDParam      = "instance_id"
+	RuntimeIDParam       = "runtime_id"
+	ResultParam          = "result"
+)
+
+// ListParameters hold attributes of list audit events queries.
+type ListParameters struct {
+	Page             int
+	PageSize         int
+	Actors           []string
+	Actions          []Action
+	OrchestrationIDs []string
+	InstanceIDs      []string
+	RuntimeIDs       []string
+	Results          []Result
+}
@@ -0,0 +1,112 @@
+package oauth
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// Invalidator is implemented by the token sources which can discard their current token,
+// so that a new token is obtained on the next Token call
+type Invalidator interface {
+	Invalidate()
+}
+
+// NewClient returns an HTTP client which authorizes the requests with the token of the given source.
+// The token is obtained again from the source when it expires. If the source implements Invalidator,
+// a request rejected with the 401 (Unauthorized) status is retried once with a new token,
+// which covers tokens revoked or rotated before their expiry.
+func NewClient(ctx context.Context, src oauth2.TokenSource) *http.Client {
+	base := http.DefaultTransport
+	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && c.Transport != nil {
+		base = c.Transport
+	}
+
+	return &http.Client{
+		Transport: &transport{
+			base:   base,
+			source: src,
+		},
+	}
+}
+
+type transport struct {
+	base   http.RoundTripper
+	source oauth2.TokenSource
+
+	mux   sync.Mutex
+	token *oauth2.Token
+}
+
+// RoundTrip authorizes the request, and retries it with a new token if it is rejected as unauthorized
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.currentToken()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	invalidator, ok := t.source.(Invalidator)
+	if !ok || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	// Drain and close the rejected response, so that the connection can be reused for the retry
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	token, err = t.refreshToken(token, invalidator)
+	if err != nil {
+		return nil, err
+	}
+	return t.send(retry, token)
+}
+
+func (t *transport) send(req *http.Request, token *oauth2.Token) (*http.Response, error) {
+	authorized := req.Clone(req.Context())
+	token.SetAuthHeader(authorized)
+	return t.base.RoundTrip(authorized)
+}
+
+func (t *transport) currentToken() (*oauth2.Token, error) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.token.Valid() {
+		return t.token, nil
+	}
+	token, err := t.source.Token()
+	if err != nil {
+		return nil, errors.Wrap(err, "while obtaining token")
+	}
+	t.token = token
+	return token, nil
+}
+
+// refreshToken invalidates the rejected token, unless another request has already replaced it, and obtains a new one
+func (t *transport) refreshToken(rejected *oauth2.Token, invalidator Invalidator) (*oauth2.Token, error) {
+	t.mux.Lock()
+	if t.token == rejected {
+		invalidator.Invalidate()
+		t.token = nil
+	}
+	t.mux.Unlock()
+
+	return t.currentToken()
+}
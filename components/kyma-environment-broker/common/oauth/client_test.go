@@ -0,0 +1,111 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+type fakeTokenSource struct {
+	issued      int
+	invalidated int
+}
+
+func (s *fakeTokenSource) Token() (*oauth2.Token, error) {
+	s.issued++
+	return &oauth2.Token{
+		AccessToken: fmt.Sprintf("token-%d", s.issued),
+		Expiry:      time.Now().Add(time.Hour),
+	}, nil
+}
+
+func (s *fakeTokenSource) Invalidate() {
+	s.invalidated++
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token() (*oauth2.Token, error) {
+	return &oauth2.Token{AccessToken: string(s), Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func TestClient(t *testing.T) {
+	// the server accepts only the second token issued
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	t.Run("should retry unauthorized request with new token", func(t *testing.T) {
+		// given
+		bodies = nil
+		src := &fakeTokenSource{}
+		client := NewClient(context.Background(), src)
+
+		// when
+		resp, err := client.Post(ts.URL, "application/json", strings.NewReader(`{"dryRun": true}`))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, src.issued)
+		assert.Equal(t, 1, src.invalidated)
+		assert.Equal(t, []string{`{"dryRun": true}`, `{"dryRun": true}`}, bodies)
+
+		// when
+		resp, err = client.Get(ts.URL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, src.issued, "valid token should be reused")
+	})
+
+	t.Run("should not retry if token source cannot be invalidated", func(t *testing.T) {
+		// given
+		bodies = nil
+		client := NewClient(context.Background(), staticTokenSource("token-1"))
+
+		// when
+		resp, err := client.Get(ts.URL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Len(t, bodies, 1)
+	})
+
+	t.Run("should retry only once", func(t *testing.T) {
+		// given
+		bodies = nil
+		src := &fakeTokenSource{issued: 5}
+		client := NewClient(context.Background(), src)
+
+		// when
+		resp, err := client.Get(ts.URL)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Len(t, bodies, 2)
+		assert.Equal(t, 1, src.invalidated)
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/oauth"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
@@ -26,6 +27,7 @@ type Client interface {
 	ListOperations(orchestrationID string, params ListParameters) (OperationResponseList, error)
 	GetOperation(orchestrationID, operationID string) (OperationDetailResponse, error)
 	UpgradeKyma(params Parameters) (UpgradeResponse, error)
+	UpgradeCluster(params Parameters) (UpgradeResponse, error)
 	CancelOrchestration(orchestrationID string) error
 }
 
@@ -38,11 +40,12 @@ type client struct {
 // It takes the following arguments:
 //   - ctx  : context in which the http request will be executed
 //   - url  : base url of all KEB APIs, e.g. https://kyma-env-broker.kyma.local
-//   - auth : TokenSource object which provides the ID token for the HTTP request
+//   - auth : TokenSource object which provides the ID token for the HTTP request,
+//            if it implements oauth.Invalidator, the requests rejected as unauthorized are retried with a new token
 func NewClient(ctx context.Context, url string, auth oauth2.TokenSource) Client {
 	return &client{
 		url:        url,
-		httpClient: oauth2.NewClient(ctx, auth),
+		httpClient: oauth.NewClient(ctx, auth),
 	}
 }
 
@@ -244,15 +247,26 @@ func (c client) GetOperation(orchestrationID, operationID string) (OperationDeta
 // UpgradeKyma creates a new Kyma upgrade orchestration according to the given orchestration parameters.
 // If successful, the UpgradeResponse returned contains the ID of the newly created orchestration.
 func (c client) UpgradeKyma(params Parameters) (UpgradeResponse, error) {
+	return c.upgrade("kyma", params)
+}
+
+// UpgradeCluster creates a new cluster upgrade orchestration according to the given orchestration parameters.
+// If successful, the UpgradeResponse returned contains the ID of the newly created orchestration.
+func (c client) UpgradeCluster(params Parameters) (UpgradeResponse, error) {
+	return c.upgrade("cluster", params)
+}
+
+func (c client) upgrade(kind string, params Parameters) (UpgradeResponse, error) {
 	ur := UpgradeResponse{}
 	blob, err := json.Marshal(params)
 	if err != nil {
 		return ur, errors.Wrap(err, "while converting upgrade parameters to JSON")
 	}
 
-	resp, err := c.httpClient.Post(fmt.Sprintf("%s/upgrade/kyma", c.url), "application/json", bytes.NewBuffer(blob))
+	url := fmt.Sprintf("%s/upgrade/%s", c.url, kind)
+	resp, err := c.httpClient.Post(url, "application/json", bytes.NewBuffer(blob))
 	if err != nil {
-		return ur, errors.Wrapf(err, "while calling %s/upgrade/kyma", c.url)
+		return ur, errors.Wrapf(err, "while calling %s", url)
 	}
 
 	// Drain response body and close, return error to context if there isn't any.
@@ -268,7 +282,7 @@ func (c client) UpgradeKyma(params Parameters) (UpgradeResponse, error) {
 	}()
 
 	if resp.StatusCode != http.StatusAccepted {
-		return ur, fmt.Errorf("calling %s/upgrade/kyma returned %s status", c.url, resp.Status)
+		return ur, fmt.Errorf("calling %s returned %s status", url, resp.Status)
 	}
 
 	decoder := json.NewDecoder(resp.Body)
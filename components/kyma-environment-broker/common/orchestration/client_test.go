@@ -226,6 +226,54 @@ func TestClient_UpgradeKyma(t *testing.T) {
 	})
 }
 
+func TestClient_UpgradeCluster(t *testing.T) {
+	t.Run("test_URL_request_body_NoError_path", func(t *testing.T) {
+		// given
+		called := 0
+		params := Parameters{
+			Targets: TargetSpec{
+				Include: []RuntimeTarget{
+					{
+						Target: TargetAll,
+					},
+				},
+			},
+			Strategy: StrategySpec{
+				Type:     ParallelStrategy,
+				Schedule: Immediate,
+			},
+			Kubernetes: KubernetesParameters{
+				KubernetesVersion:   "1.19.8",
+				MachineImageVersion: "184.0.0",
+			},
+		}
+		orchestrationID := orch1.OrchestrationID
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called++
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/upgrade/cluster", r.URL.Path)
+			assert.Equal(t, fmt.Sprintf("Bearer %s", fixToken), r.Header.Get("Authorization"))
+			reqBody := Parameters{}
+			err := json.NewDecoder(r.Body).Decode(&reqBody)
+			require.NoError(t, err)
+			assert.True(t, reflect.DeepEqual(params, reqBody))
+
+			err = respondUpgrade(w, orchestrationID)
+			require.NoError(t, err)
+		}))
+		defer ts.Close()
+		client := NewClient(context.TODO(), ts.URL, fixToken)
+
+		// when
+		ur, err := client.UpgradeCluster(params)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, called)
+		assert.Equal(t, orchestrationID, ur.OrchestrationID)
+	})
+}
+
 func TestClient_CancelOrchestration(t *testing.T) {
 	t.Run("test_URL__NoError_path", func(t *testing.T) {
 		// given
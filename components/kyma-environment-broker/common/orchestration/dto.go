@@ -13,6 +13,8 @@ type Parameters struct {
 	DryRun   bool         `json:"dryRun,omitempty"`
 	// upgrade kyma specific parameters
 	Kyma KymaParameters `json:""`
+	// upgrade cluster specific parameters
+	Kubernetes KubernetesParameters `json:"kubernetes,omitempty"`
 }
 
 // KymaParameters hold the attributes of kyma upgrade specific orchestration create requests.
@@ -20,6 +22,13 @@ type KymaParameters struct {
 	Version string `json:"kymaVersion,omitempty"`
 }
 
+// KubernetesParameters hold the attributes of cluster upgrade specific orchestration create requests.
+// The versions which are not specified are configured by KEB.
+type KubernetesParameters struct {
+	KubernetesVersion   string `json:"kubernetesVersion,omitempty"`
+	MachineImageVersion string `json:"machineImageVersion,omitempty"`
+}
+
 const (
 	// StateParam parameter used in list orchestrations / operations queries to filter by state
 	StateParam = "state"
@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strconv"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/oauth"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
@@ -20,6 +21,10 @@ const defaultPageSize = 100
 // Client is the interface to interact with the KEB /runtimes API as an HTTP client using OIDC ID token in JWT format.
 type Client interface {
 	ListRuntimes(params ListParameters) (RuntimesPage, error)
+	SuspendRuntime(instanceID string) (ActionResponse, error)
+	UnsuspendRuntime(instanceID string) (ActionResponse, error)
+	DeprovisionRuntime(instanceID string) (ActionResponse, error)
+	ReprovisionRuntime(instanceID string) (ActionResponse, error)
 }
 
 type client struct {
@@ -31,11 +36,12 @@ type client struct {
 // It takes the following arguments:
 //   - ctx  : context in which the http request will be executed
 //   - url  : base url of all KEB APIs, e.g. https://kyma-env-broker.kyma.local
-//   - auth : TokenSource object which provides the ID token for the HTTP request
+//   - auth : TokenSource object which provides the ID token for the HTTP request,
+//            if it implements oauth.Invalidator, the requests rejected as unauthorized are retried with a new token
 func NewClient(ctx context.Context, url string, auth oauth2.TokenSource) Client {
 	return &client{
 		url:        url,
-		httpClient: oauth2.NewClient(ctx, auth),
+		httpClient: oauth.NewClient(ctx, auth),
 	}
 }
 
@@ -100,6 +106,65 @@ func (c *client) ListRuntimes(params ListParameters) (RuntimesPage, error) {
 	return runtimes, nil
 }
 
+// SuspendRuntime starts the suspension of the trial runtime of the given instance
+func (c *client) SuspendRuntime(instanceID string) (ActionResponse, error) {
+	return c.runAction(instanceID, "suspend")
+}
+
+// UnsuspendRuntime starts the unsuspension of the suspended trial runtime of the given instance
+func (c *client) UnsuspendRuntime(instanceID string) (ActionResponse, error) {
+	return c.runAction(instanceID, "unsuspend")
+}
+
+// DeprovisionRuntime starts the deprovisioning of the runtime of the given instance, the instance is removed afterwards
+func (c *client) DeprovisionRuntime(instanceID string) (ActionResponse, error) {
+	return c.runAction(instanceID, "deprovision")
+}
+
+// ReprovisionRuntime starts the suspension of the trial runtime of the given instance, the runtime is unsuspended
+// by KEB when the suspension succeeds
+func (c *client) ReprovisionRuntime(instanceID string) (ActionResponse, error) {
+	return c.runAction(instanceID, "reprovision")
+}
+
+func (c *client) runAction(instanceID, action string) (ActionResponse, error) {
+	response := ActionResponse{}
+	url := fmt.Sprintf("%s/runtimes/%s/%s", c.url, instanceID, action)
+
+	req, err := http.NewRequest(http.MethodPut, url, nil)
+	if err != nil {
+		return response, errors.Wrapf(err, "while creating %s request", action)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return response, errors.Wrapf(err, "while calling %s", url)
+	}
+
+	// Drain response body and close, return error to context if there isn't any.
+	defer func() {
+		derr := drainResponseBody(resp.Body)
+		if err == nil {
+			err = derr
+		}
+		cerr := resp.Body.Close()
+		if err == nil {
+			err = cerr
+		}
+	}()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return response, fmt.Errorf("calling %s returned %d (%s) status", url, resp.StatusCode, resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return response, errors.Wrap(err, "while decoding response body")
+	}
+
+	return response, nil
+}
+
 func setQuery(url *url.URL, params ListParameters) {
 	query := url.Query()
 	query.Add(pagination.PageParam, strconv.Itoa(params.Page))
@@ -112,6 +112,46 @@ func TestClient_ListRuntimes(t *testing.T) {
 	})
 }
 
+func TestClient_RuntimeActions(t *testing.T) {
+	for action, call := range map[string]func(c Client, instanceID string) (ActionResponse, error){
+		"suspend":     Client.SuspendRuntime,
+		"unsuspend":   Client.UnsuspendRuntime,
+		"deprovision": Client.DeprovisionRuntime,
+		"reprovision": Client.ReprovisionRuntime,
+	} {
+		t.Run(action, func(t *testing.T) {
+			//given
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, r.Header.Get("Authorization"), fmt.Sprintf("Bearer %s", fixToken))
+				if r.URL.Path != fmt.Sprintf("/runtimes/instance1/%s", action) {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusAccepted)
+				err := json.NewEncoder(w).Encode(ActionResponse{InstanceID: "instance1", OperationID: "operation1"})
+				require.NoError(t, err)
+			}))
+			defer ts.Close()
+			client := NewClient(context.TODO(), ts.URL, fixToken)
+
+			//when
+			resp, err := call(client, "instance1")
+
+			//then
+			require.NoError(t, err)
+			assert.Equal(t, ActionResponse{InstanceID: "instance1", OperationID: "operation1"}, resp)
+
+			//when
+			_, err = call(client, "instance2")
+
+			//then
+			assert.Error(t, err)
+		})
+	}
+}
+
 func fixRuntimeDTO(id string) RuntimeDTO {
 	return RuntimeDTO{
 		InstanceID:       id,
@@ -45,6 +45,13 @@ type Operation struct {
 	OrchestrationID string    `json:"orchestrationID,omitempty"`
 }
 
+// ActionResponse is returned by the operator actions on a runtime, e.g. suspension.
+// OperationID identifies the operation started by the action.
+type ActionResponse struct {
+	InstanceID  string `json:"instanceID"`
+	OperationID string `json:"operationID"`
+}
+
 type RuntimesPage struct {
 	Data       []RuntimeDTO `json:"data"`
 	Count      int          `json:"count"`
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Handler exposes the stored audit events
+type Handler struct {
+	events storage.AuditEvents
+	log    logrus.FieldLogger
+
+	defaultMaxPage int
+}
+
+func NewHandler(events storage.AuditEvents, defaultMaxPage int, log logrus.FieldLogger) *Handler {
+	return &Handler{
+		events:         events,
+		log:            log,
+		defaultMaxPage: defaultMaxPage,
+	}
+}
+
+func (h *Handler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/audit", h.listEvents).Methods(http.MethodGet)
+}
+
+func (h *Handler) listEvents(w http.ResponseWriter, r *http.Request) {
+	pageSize, page, err := pagination.ExtractPaginationConfigFromRequest(r, h.defaultMaxPage)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrap(err, "while getting query parameters"))
+		return
+	}
+	query := r.URL.Query()
+	filter := dbmodel.AuditEventFilter{
+		Page:     page,
+		PageSize: pageSize,
+		// For optional filters, zero value (nil) is ok if not supplied
+		Actors:           query[pkg.ActorParam],
+		Actions:          query[pkg.ActionParam],
+		OrchestrationIDs: query[pkg.OrchestrationIDParam],
+		InstanceIDs:      query[pkg.InstanceIDParam],
+		RuntimeIDs:       query[pkg.RuntimeIDParam],
+		Results:          query[pkg.ResultParam],
+	}
+
+	events, count, totalCount, err := h.events.List(filter)
+	if err != nil {
+		h.log.Errorf("while getting audit events: %v", err)
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "while getting audit events"))
+		return
+	}
+
+	response := pkg.EventsPage{
+		Data:       make([]pkg.EventDTO, 0, len(events)),
+		Count:      count,
+		TotalCount: totalCount,
+	}
+	for _, e := range events {
+		response.Data = append(response.Data, eventToDTO(e))
+	}
+
+	httputil.WriteResponse(w, http.StatusOK, response)
+}
+
+func eventToDTO(e internal.AuditEvent) pkg.EventDTO {
+	dto := pkg.EventDTO{
+		ID:              e.ID,
+		Actor:           e.Actor,
+		Action:          e.Action,
+		OrchestrationID: e.OrchestrationID,
+		InstanceID:      e.InstanceID,
+		RuntimeID:       e.RuntimeID,
+		Result:          e.Result,
+		Error:           e.Error,
+		CreatedAt:       e.CreatedAt,
+	}
+	if e.Parameters != "" {
+		dto.Parameters = json.RawMessage(e.Parameters)
+	}
+
+	return dto
+}
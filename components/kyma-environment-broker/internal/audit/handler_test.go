@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ListEvents(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	recorder := NewRecorder(db.AuditEvents(), logrus.New())
+	handler := NewHandler(db.AuditEvents(), 100, logrus.New())
+
+	recorder.Record(context.Background(), pkg.UpgradeKymaAction, Target{OrchestrationID: "orchestration-1"}, orchestration.Parameters{DryRun: true}, nil)
+	recorder.Record(context.Background(), pkg.CancelOrchestrationAction, Target{OrchestrationID: "orchestration-1"}, nil, errors.New("while updating orchestration"))
+	recorder.Record(context.Background(), pkg.UpgradeClusterAction, Target{OrchestrationID: "orchestration-2"}, orchestration.Parameters{}, nil)
+
+	router := mux.NewRouter()
+	handler.AttachRoutes(router)
+
+	t.Run("list all events", func(t *testing.T) {
+		// when
+		out := callList(t, router, "/audit")
+
+		// then
+		assert.Equal(t, 3, out.Count)
+		assert.Equal(t, 3, out.TotalCount)
+		for _, e := range out.Data {
+			assert.Equal(t, pkg.UnknownActor, e.Actor)
+			assert.NotEmpty(t, e.ID)
+		}
+	})
+
+	t.Run("filter events", func(t *testing.T) {
+		// when
+		out := callList(t, router, fmt.Sprintf("/audit?%s=%s&%s=%s", pkg.OrchestrationIDParam, "orchestration-1", pkg.ResultParam, pkg.Failed))
+
+		// then
+		require.Len(t, out.Data, 1)
+		assert.Equal(t, pkg.CancelOrchestrationAction, out.Data[0].Action)
+		assert.Equal(t, pkg.Failed, out.Data[0].Result)
+		assert.Equal(t, "while updating orchestration", out.Data[0].Error)
+		assert.Empty(t, out.Data[0].Parameters)
+	})
+
+	t.Run("parameters are returned as JSON", func(t *testing.T) {
+		// when
+		out := callList(t, router, fmt.Sprintf("/audit?%s=%s", pkg.ActionParam, pkg.UpgradeKymaAction))
+
+		// then
+		require.Len(t, out.Data, 1)
+		var params orchestration.Parameters
+		err := json.Unmarshal(out.Data[0].Parameters, &params)
+		require.NoError(t, err)
+		assert.True(t, params.DryRun)
+	})
+}
+
+func callList(t *testing.T, router *mux.Router, url string) pkg.EventsPage {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var out pkg.EventsPage
+	err = json.Unmarshal(rr.Body.Bytes(), &out)
+	require.NoError(t, err)
+
+	return out
+}
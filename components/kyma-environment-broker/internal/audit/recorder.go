@@ -0,0 +1,94 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// Target identifies the objects affected by an operator action
+type Target struct {
+	OrchestrationID string
+	InstanceID      string
+	RuntimeID       string
+}
+
+// defaultRecordTimeout bounds the time an API request waits for its audit event to be stored
+const defaultRecordTimeout = 2 * time.Second
+
+// Recorder stores audit events of operator actions performed through the KEB API
+type Recorder struct {
+	events  storage.AuditEvents
+	log     logrus.FieldLogger
+	timeout time.Duration
+}
+
+func NewRecorder(events storage.AuditEvents, log logrus.FieldLogger) *Recorder {
+	return &Recorder{
+		events:  events,
+		log:     log,
+		timeout: defaultRecordTimeout,
+	}
+}
+
+// Record stores the audit event of the given action. The actor is taken from the request context,
+// the result is derived from actionErr. Failing to store the event does not fail the action itself,
+// the error is only logged. If the storage does not respond within the timeout, Record returns
+// and the event is stored in the background, so a slow database does not block the API request.
+func (r *Recorder) Record(ctx context.Context, action pkg.Action, target Target, params interface{}, actionErr error) {
+	actor, found := middleware.ActorFromContext(ctx)
+	if !found {
+		actor = pkg.UnknownActor
+	}
+
+	event := internal.AuditEvent{
+		ID:              uuid.New().String(),
+		Actor:           actor,
+		Action:          action,
+		OrchestrationID: target.OrchestrationID,
+		InstanceID:      target.InstanceID,
+		RuntimeID:       target.RuntimeID,
+		Result:          pkg.Succeeded,
+		CreatedAt:       time.Now(),
+	}
+	if actionErr != nil {
+		event.Result = pkg.Failed
+		event.Error = actionErr.Error()
+	}
+	if params != nil {
+		p, err := json.Marshal(params)
+		if err != nil {
+			r.log.Warnf("while marshalling parameters of audit event %s: %v", action, err)
+		}
+		event.Parameters = string(p)
+	}
+
+	r.log.Infof("Audit: actor=%s action=%s orchestrationID=%s instanceID=%s runtimeID=%s result=%s", actor, action, target.OrchestrationID, target.InstanceID, target.RuntimeID, event.Result)
+	stored := make(chan error, 1)
+	go func() {
+		stored <- r.events.Insert(event)
+	}()
+
+	select {
+	case err := <-stored:
+		r.logInsertError(event, err)
+	case <-time.After(r.timeout):
+		r.log.Warnf("storing audit event %s of %s takes longer than %s, continuing in the background", action, actor, r.timeout)
+		go func() {
+			r.logInsertError(event, <-stored)
+		}()
+	}
+}
+
+func (r *Recorder) logInsertError(event internal.AuditEvent, err error) {
+	if err != nil {
+		r.log.Errorf("while storing audit event %s of %s: %v", event.Action, event.Actor, err)
+	}
+}
@@ -0,0 +1,52 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_RecordDoesNotBlockOnSlowStorage(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	events := &slowAuditEvents{AuditEvents: db.AuditEvents(), release: make(chan struct{})}
+	recorder := NewRecorder(events, logrus.New())
+	recorder.timeout = 10 * time.Millisecond
+
+	// when
+	start := time.Now()
+	recorder.Record(context.Background(), pkg.CancelOrchestrationAction, Target{OrchestrationID: "orchestration-1"}, nil, nil)
+
+	// then
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	stored, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	// when
+	close(events.release)
+
+	// then
+	assert.Eventually(t, func() bool {
+		stored, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{})
+		return err == nil && len(stored) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+type slowAuditEvents struct {
+	storage.AuditEvents
+	release chan struct{}
+}
+
+func (s *slowAuditEvents) Insert(event internal.AuditEvent) error {
+	<-s.release
+	return s.AuditEvents.Insert(event)
+}
@@ -0,0 +1,409 @@
+package event
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// Broker dispatches published events to the subscribed handlers
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+type OutboxConfig struct {
+	Enabled bool `envconfig:"default=false"`
+
+	PollInterval     time.Duration `envconfig:"default=1s"`
+	BatchSize        int           `envconfig:"default=100"`
+	MaxAttempts      int           `envconfig:"default=5"`
+	RetryInterval    time.Duration `envconfig:"default=1s"`
+	MaxRetryInterval time.Duration `envconfig:"default=1m"`
+
+	// ReplayFromOffset makes the subscriptions receive again all events starting from the given offset
+	// when they are registered, 0 disables the replay
+	ReplayFromOffset int64 `envconfig:"default=0"`
+	// ReplaySubscriptions limits the replay to the given subscriptions, all subscriptions are replayed if empty
+	ReplaySubscriptions []string `envconfig:"optional"`
+}
+
+// EventError replaces the errors carried by the events, which in general cannot be serialized.
+// Handlers of events dispatched by the Outbox receive the error message only.
+type EventError struct {
+	Message string
+}
+
+func (e EventError) Error() string {
+	return e.Message
+}
+
+func init() {
+	gob.Register(EventError{})
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Outbox implements an event broker which stores the published events in the database before they are
+// dispatched to the handlers. Every subscription has its own offset of the last acknowledged event,
+// so events published while the application was down are dispatched after the restart (at-least-once delivery).
+// The storage commits the events in the order of their offsets, so a subscription never skips an event
+// committed after an event with a higher offset was acknowledged.
+// Handler errors are retried with an exponential backoff, the event is acknowledged after the last attempt
+// and counted by the compass_keb_outbox_events_dropped_total metric.
+type Outbox struct {
+	config  OutboxConfig
+	events  storage.OutboxEvents
+	log     logrus.FieldLogger
+	dropped *prometheus.CounterVec
+
+	mu            sync.Mutex
+	ctx           context.Context
+	subscriptions []*subscription
+}
+
+type subscription struct {
+	// mu guards the subscription offset stored in the database
+	mu sync.Mutex
+
+	name      string
+	eventType reflect.Type
+	handler   Handler
+	wakeUp    chan struct{}
+}
+
+func NewOutbox(config OutboxConfig, events storage.OutboxEvents, log logrus.FieldLogger) *Outbox {
+	return &Outbox{
+		config: config,
+		events: events,
+		log:    log,
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "compass",
+			Subsystem: "keb",
+			Name:      "outbox_events_dropped_total",
+			Help:      "Number of events acknowledged after all attempts of the handler failed",
+		}, []string{"subscription"}),
+	}
+}
+
+func (o *Outbox) Describe(ch chan<- *prometheus.Desc) {
+	o.dropped.Describe(ch)
+}
+
+func (o *Outbox) Collect(ch chan<- prometheus.Metric) {
+	o.dropped.Collect(ch)
+}
+
+// Publish stores the event, the event is not dispatched if it cannot be stored
+func (o *Outbox) Publish(ctx context.Context, ev interface{}) error {
+	tt := reflect.TypeOf(ev)
+	payload, err := encodeEvent(ev)
+	if err != nil {
+		return errors.Wrapf(err, "while encoding event %s", eventTypeName(tt))
+	}
+
+	offset, err := o.events.Insert(internal.OutboxEvent{
+		Type:      eventTypeName(tt),
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "while storing event %s", eventTypeName(tt))
+	}
+	o.log.Debugf("stored event %s with offset %d", eventTypeName(tt), offset)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, s := range o.subscriptions {
+		if s.eventType == tt {
+			select {
+			case s.wakeUp <- struct{}{}:
+			default:
+			}
+		}
+	}
+	return nil
+}
+
+// Subscribe registers the handler for the given event type. The subscription continues from the last event
+// acknowledged under the given name, so the name must not change between restarts.
+func (o *Outbox) Subscribe(name string, evType interface{}, evHandler Handler) {
+	tt := reflect.TypeOf(evType)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.subscriptionByName(name) != nil {
+		panic(fmt.Sprintf("outbox subscription %s already exists", name))
+	}
+
+	s := &subscription{
+		name:      name,
+		eventType: tt,
+		handler:   evHandler,
+		wakeUp:    make(chan struct{}, 1),
+	}
+	o.subscriptions = append(o.subscriptions, s)
+	if o.config.ReplayFromOffset > 0 && o.replayConfigured(name) {
+		if err := o.replay(s, o.config.ReplayFromOffset); err != nil {
+			o.log.Errorf("while replaying subscription %s: %v", name, err)
+		}
+	}
+	if o.ctx != nil {
+		go o.process(o.ctx, s)
+	}
+}
+
+// Run starts dispatching the stored events to the subscriptions until the context is canceled
+func (o *Outbox) Run(ctx context.Context) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.ctx = ctx
+	for _, s := range o.subscriptions {
+		go o.process(ctx, s)
+	}
+}
+
+// Replay makes the given subscriptions receive again all events starting from the given offset.
+// All subscriptions are replayed if none is given.
+func (o *Outbox) Replay(fromOffset int64, subscriptions ...string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	toReplay := o.subscriptions
+	if len(subscriptions) > 0 {
+		toReplay = make([]*subscription, 0, len(subscriptions))
+		for _, name := range subscriptions {
+			s := o.subscriptionByName(name)
+			if s == nil {
+				return errors.Errorf("subscription %s does not exist", name)
+			}
+			toReplay = append(toReplay, s)
+		}
+	}
+
+	for _, s := range toReplay {
+		if err := o.replay(s, fromOffset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *Outbox) replay(s *subscription, fromOffset int64) error {
+	s.mu.Lock()
+	err := o.events.SetSubscriptionOffset(s.name, fromOffset-1)
+	s.mu.Unlock()
+	if err != nil {
+		return errors.Wrapf(err, "while setting offset of subscription %s", s.name)
+	}
+	select {
+	case s.wakeUp <- struct{}{}:
+	default:
+	}
+	o.log.Infof("subscription %s will be replayed from offset %d", s.name, fromOffset)
+
+	return nil
+}
+
+// replayConfigured returns true if the subscription is replayed by the configuration
+func (o *Outbox) replayConfigured(name string) bool {
+	if len(o.config.ReplaySubscriptions) == 0 {
+		return true
+	}
+	for _, s := range o.config.ReplaySubscriptions {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscriptions returns names of all subscriptions
+func (o *Outbox) Subscriptions() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	names := make([]string, 0, len(o.subscriptions))
+	for _, s := range o.subscriptions {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+func (o *Outbox) subscriptionByName(name string) *subscription {
+	for _, s := range o.subscriptions {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (o *Outbox) process(ctx context.Context, s *subscription) {
+	log := o.log.WithField("subscription", s.name)
+	log.Infof("starting subscription")
+
+	for ctx.Err() == nil {
+		processed, err := o.processBatch(ctx, s)
+		if err != nil {
+			log.Errorf("while processing events: %v", err)
+		}
+		if processed == o.config.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.wakeUp:
+		case <-time.After(o.config.PollInterval):
+		}
+	}
+	log.Infof("stopping subscription")
+}
+
+// processBatch dispatches the next batch of events and returns the number of acknowledged events
+func (o *Outbox) processBatch(ctx context.Context, s *subscription) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, err := o.subscriptionOffset(s)
+	if err != nil {
+		return 0, err
+	}
+	events, err := o.events.ListAfter(offset, []string{eventTypeName(s.eventType)}, o.config.BatchSize)
+	if err != nil {
+		return 0, errors.Wrapf(err, "while listing events after offset %d", offset)
+	}
+
+	for i, e := range events {
+		if ctx.Err() != nil || !o.dispatch(ctx, s, e) {
+			return i, nil
+		}
+		err = o.events.SetSubscriptionOffset(s.name, e.Offset)
+		if err != nil {
+			return i, errors.Wrapf(err, "while acknowledging event %d", e.Offset)
+		}
+	}
+
+	return len(events), nil
+}
+
+// subscriptionOffset returns the offset of the last acknowledged event. New subscriptions start
+// from the last stored event, so they do not receive the whole history.
+func (o *Outbox) subscriptionOffset(s *subscription) (int64, error) {
+	offset, err := o.events.GetSubscriptionOffset(s.name)
+	switch {
+	case err == nil:
+		return offset, nil
+	case dberr.IsNotFound(err):
+		offset, err = o.events.GetLastOffset()
+		if err != nil {
+			return 0, errors.Wrap(err, "while getting last offset")
+		}
+		err = o.events.SetSubscriptionOffset(s.name, offset)
+		if err != nil {
+			return 0, errors.Wrap(err, "while creating subscription offset")
+		}
+		return offset, nil
+	default:
+		return 0, errors.Wrap(err, "while getting subscription offset")
+	}
+}
+
+// dispatch calls the handler until it succeeds or the attempts are exhausted. It returns false
+// if the event must not be acknowledged, because the outbox is being stopped.
+func (o *Outbox) dispatch(ctx context.Context, s *subscription, e internal.OutboxEvent) bool {
+	log := o.log.WithField("subscription", s.name).WithField("offset", e.Offset)
+
+	ev, err := decodeEvent(s.eventType, e.Payload)
+	if err != nil {
+		log.Errorf("skipping event which cannot be decoded: %v", err)
+		return true
+	}
+
+	interval := o.config.RetryInterval
+	for attempt := 1; ; attempt++ {
+		err := s.handler(ctx, ev)
+		if err == nil {
+			return true
+		}
+		if attempt >= o.config.MaxAttempts {
+			log.Errorf("giving up event after %d attempt(s): %v", attempt, err)
+			o.dropped.WithLabelValues(s.name).Inc()
+			return true
+		}
+		log.Warnf("attempt %d of handling event failed: %v", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(interval):
+		}
+		interval *= 2
+		if o.config.MaxRetryInterval > 0 && interval > o.config.MaxRetryInterval {
+			interval = o.config.MaxRetryInterval
+		}
+	}
+}
+
+func eventTypeName(tt reflect.Type) string {
+	return fmt.Sprintf("%s.%s", tt.PkgPath(), tt.Name())
+}
+
+func encodeEvent(ev interface{}) ([]byte, error) {
+	v := reflect.New(reflect.TypeOf(ev)).Elem()
+	v.Set(reflect.ValueOf(ev))
+	prepareFields(v)
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).EncodeValue(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEvent(tt reflect.Type, payload []byte) (interface{}, error) {
+	v := reflect.New(tt)
+	if err := gob.NewDecoder(bytes.NewReader(payload)).DecodeValue(v); err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+// prepareFields replaces errors in the (embedded) struct fields with EventError and clears other interface fields.
+// The other interface fields, such as InputCreator or SMClientFactory of the operations, hold the dependencies
+// of the processing, which are not part of the event data and cannot be serialized.
+func prepareFields(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !f.CanSet() {
+			continue
+		}
+		switch {
+		case f.Type() == errorType:
+			if !f.IsNil() {
+				f.Set(reflect.ValueOf(EventError{Message: f.Interface().(error).Error()}))
+			}
+		case f.Kind() == reflect.Interface:
+			f.Set(reflect.Zero(f.Type()))
+		default:
+			prepareFields(f)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestOutbox(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handlerA := &outboxHandler{}
+	handlerB := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handlerA", outboxEventA{}, handlerA.Handle)
+	svc.Subscribe("handlerB", outboxEventB{}, handlerB.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	// when
+	svc.Publish(context.TODO(), outboxEventA{Msg: "first event"})
+	svc.Publish(context.TODO(), outboxEventB{Msg: "second event", Err: errors.New("some error")})
+	svc.Publish(context.TODO(), outboxEventA{Msg: "third event"})
+
+	// then
+	handlerA.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "third event"})
+	handlerB.assertEvents(t, outboxEventB{Msg: "second event", Err: event.EventError{Message: "some error"}})
+}
+
+func TestOutbox_ProvisioningStepProcessed(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handler", process.ProvisioningStepProcessed{}, handler.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	operation := fixture.FixProvisioningOperation("operation-1", "instance-1")
+	operation.State = domain.Succeeded
+	operation.SMClientFactory = servicemanager.NewFakeServiceManagerClientFactory(nil, nil)
+	require.NotNil(t, operation.InputCreator)
+
+	// when
+	svc.Publish(context.TODO(), process.ProvisioningStepProcessed{
+		StepProcessed: process.StepProcessed{
+			StepName: "Create_Runtime",
+			Duration: time.Second,
+			Error:    errors.New("some error"),
+		},
+		OldOperation: operation,
+		Operation:    operation,
+	})
+
+	// then
+	events := handler.waitForEvents(t, 1)
+	require.Len(t, events, 1)
+	got, ok := events[0].(process.ProvisioningStepProcessed)
+	require.True(t, ok)
+	assert.Equal(t, "Create_Runtime", got.StepName)
+	assert.Equal(t, time.Second, got.Duration)
+	assert.Equal(t, event.EventError{Message: "some error"}, got.Error)
+	assert.Equal(t, operation.ID, got.Operation.ID)
+	assert.Equal(t, operation.InstanceID, got.Operation.InstanceID)
+	assert.Equal(t, operation.RuntimeID, got.Operation.RuntimeID)
+	assert.Equal(t, domain.Succeeded, got.Operation.State)
+	assert.Equal(t, operation.ProvisioningParameters.PlanID, got.Operation.ProvisioningParameters.PlanID)
+	assert.Equal(t, operation.RuntimeVersion, got.Operation.RuntimeVersion)
+	assert.Nil(t, got.Operation.InputCreator)
+	assert.Nil(t, got.Operation.SMClientFactory)
+	assert.Equal(t, operation.ID, got.OldOperation.ID)
+}
+
+func TestOutbox_SubscriptionNameMustBeUnique(t *testing.T) {
+	// given
+	handler := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+
+	// then
+	assert.Panics(t, func() {
+		svc.Subscribe("handler", outboxEventB{}, handler.Handle)
+	})
+}
+
+func TestOutbox_WhenHandlerReturnsError(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := &outboxHandler{failures: 2}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	// when
+	svc.Publish(context.TODO(), outboxEventA{Msg: "first event"})
+	svc.Publish(context.TODO(), outboxEventA{Msg: "second event"})
+
+	// then
+	handler.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "second event"})
+	assert.Equal(t, 4, handler.calls())
+}
+
+func TestOutbox_WhenHandlerAttemptsAreExhausted(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := &outboxHandler{failures: 3}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	// when
+	require.NoError(t, svc.Publish(context.TODO(), outboxEventA{Msg: "first event"}))
+	require.NoError(t, svc.Publish(context.TODO(), outboxEventA{Msg: "second event"}))
+
+	// then
+	handler.assertEvents(t, outboxEventA{Msg: "second event"})
+	assert.Equal(t, 4, handler.calls())
+	assert.Equal(t, float64(1), testutil.ToFloat64(svc))
+}
+
+func TestOutbox_DispatchesEventsAfterRestart(t *testing.T) {
+	// given
+	events := storage.NewMemoryStorage().OutboxEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), events, logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	svc.Publish(context.TODO(), outboxEventA{Msg: "first event"})
+	handler.assertEvents(t, outboxEventA{Msg: "first event"})
+	cancel()
+
+	// when
+	svc.Publish(context.TODO(), outboxEventA{Msg: "second event"})
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	restarted := &outboxHandler{}
+	svc = event.NewOutbox(fixOutboxConfig(), events, logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, restarted.Handle)
+	svc.Run(ctx)
+
+	// then
+	restarted.assertEvents(t, outboxEventA{Msg: "second event"})
+}
+
+func TestOutbox_Replay(t *testing.T) {
+	// given
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), storage.NewMemoryStorage().OutboxEvents(), logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	svc.Publish(context.TODO(), outboxEventA{Msg: "first event"})
+	svc.Publish(context.TODO(), outboxEventA{Msg: "second event"})
+	handler.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "second event"})
+
+	// when
+	err := svc.Replay(2, svc.Subscriptions()...)
+
+	// then
+	require.NoError(t, err)
+	handler.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "second event"}, outboxEventA{Msg: "second event"})
+	assert.Error(t, svc.Replay(1, "not-existing"))
+}
+
+func TestOutbox_ReplayConfigured(t *testing.T) {
+	// given
+	events := storage.NewMemoryStorage().OutboxEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := &outboxHandler{}
+	other := &outboxHandler{}
+	svc := event.NewOutbox(fixOutboxConfig(), events, logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, handler.Handle)
+	svc.Subscribe("other", outboxEventA{}, other.Handle)
+	svc.Run(ctx)
+	waitForSubscriptions(t)
+
+	require.NoError(t, svc.Publish(context.TODO(), outboxEventA{Msg: "first event"}))
+	require.NoError(t, svc.Publish(context.TODO(), outboxEventA{Msg: "second event"}))
+	handler.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "second event"})
+	other.assertEvents(t, outboxEventA{Msg: "first event"}, outboxEventA{Msg: "second event"})
+	waitForSubscriptions(t)
+	cancel()
+
+	// when
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	config := fixOutboxConfig()
+	config.ReplayFromOffset = 2
+	config.ReplaySubscriptions = []string{"handler"}
+	replayed := &outboxHandler{}
+	notReplayed := &outboxHandler{}
+	svc = event.NewOutbox(config, events, logrus.New())
+	svc.Subscribe("handler", outboxEventA{}, replayed.Handle)
+	svc.Subscribe("other", outboxEventA{}, notReplayed.Handle)
+	svc.Run(ctx)
+
+	// then
+	replayed.assertEvents(t, outboxEventA{Msg: "second event"})
+	waitForSubscriptions(t)
+	assert.Zero(t, notReplayed.calls())
+}
+
+func fixOutboxConfig() event.OutboxConfig {
+	return event.OutboxConfig{
+		Enabled:          true,
+		PollInterval:     10 * time.Millisecond,
+		BatchSize:        10,
+		MaxAttempts:      3,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: 2 * time.Millisecond,
+	}
+}
+
+// waitForSubscriptions gives the subscriptions time to store their initial offsets,
+// new subscriptions do not receive events published before they started
+func waitForSubscriptions(t *testing.T) {
+	t.Helper()
+	time.Sleep(50 * time.Millisecond)
+}
+
+type outboxHandler struct {
+	mu       sync.Mutex
+	failures int
+	called   int
+	events   []interface{}
+}
+
+func (h *outboxHandler) Handle(ctx context.Context, ev interface{}) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.called++
+	if h.failures > 0 {
+		h.failures--
+		return errors.New("some error")
+	}
+	h.events = append(h.events, ev)
+	return nil
+}
+
+func (h *outboxHandler) calls() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.called
+}
+
+func (h *outboxHandler) assertEvents(t *testing.T, expected ...interface{}) {
+	t.Helper()
+	got := h.waitForEvents(t, len(expected))
+	assert.Equal(t, expected, got)
+}
+
+func (h *outboxHandler) waitForEvents(t *testing.T, count int) []interface{} {
+	t.Helper()
+	var got []interface{}
+	err := wait.PollImmediate(10*time.Millisecond, 2*time.Second, func() (bool, error) {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		got = append([]interface{}(nil), h.events...)
+		return len(got) >= count, nil
+	})
+	require.NoError(t, err, "got events: %+v", got)
+	return got
+}
+
+type outboxEventA struct {
+	Msg string
+}
+
+type outboxEventB struct {
+	Msg string
+	Err error
+}
@@ -11,11 +11,15 @@ import (
 type Handler = func(ctx context.Context, ev interface{}) error
 
 type Publisher interface {
-	Publish(ctx context.Context, event interface{})
+	// Publish dispatches the event to the subscribed handlers, it returns an error if the event cannot be accepted
+	Publish(ctx context.Context, event interface{}) error
 }
 
 type Subscriber interface {
-	Subscribe(evType interface{}, evHandler Handler)
+	// Subscribe registers the handler for the given event type. The name identifies the subscription,
+	// it must be unique and must not change between releases, because durable brokers continue
+	// the subscription from the last acknowledged event after a restart.
+	Subscribe(name string, evType interface{}, evHandler Handler)
 }
 
 // PubSub implements a simple event broker which allows to send event across the application.
@@ -33,7 +37,7 @@ func NewPubSub(log logrus.FieldLogger) *PubSub {
 	}
 }
 
-func (b *PubSub) Publish(ctx context.Context, ev interface{}) {
+func (b *PubSub) Publish(ctx context.Context, ev interface{}) error {
 	tt := reflect.TypeOf(ev)
 	hList, found := b.handlers[tt]
 	if found {
@@ -46,9 +50,11 @@ func (b *PubSub) Publish(ctx context.Context, ev interface{}) {
 			}(handler)
 		}
 	}
+	return nil
 }
 
-func (b *PubSub) Subscribe(evType interface{}, evHandler Handler) {
+// Subscribe registers the handler for the given event type, the name is not used by the in-memory broker
+func (b *PubSub) Subscribe(_ string, evType interface{}, evHandler Handler) {
 	tt := reflect.TypeOf(evType)
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -40,9 +40,9 @@ func TestPubSub(t *testing.T) {
 		return nil
 	}
 	svc := event.NewPubSub(logrus.New())
-	svc.Subscribe(eventA{}, handlerA1)
-	svc.Subscribe(eventB{}, handlerB)
-	svc.Subscribe(eventA{}, handlerA2)
+	svc.Subscribe("handlerA1", eventA{}, handlerA1)
+	svc.Subscribe("handlerB", eventB{}, handlerB)
+	svc.Subscribe("handlerA2", eventA{}, handlerA2)
 
 	// when
 	svc.Publish(context.TODO(), eventA{msg: "first event"})
@@ -71,7 +71,7 @@ func TestPubSub_WhenHandlerReturnsError(t *testing.T) {
 		return errors.New("some error")
 	}
 	svc := event.NewPubSub(logger)
-	svc.Subscribe(eventA{}, handlerA1)
+	svc.Subscribe("handlerA1", eventA{}, handlerA1)
 
 	// when
 	svc.Publish(context.TODO(), eventA{msg: "first event"})
@@ -14,11 +14,12 @@ func RegisterAll(sub event.Subscriber, operationStatsGetter OperationsStatsGette
 	prometheus.MustRegister(NewOperationsCollector(operationStatsGetter))
 	prometheus.MustRegister(NewInstancesCollector(instanceStatsGetter))
 
-	sub.Subscribe(process.ProvisioningStepProcessed{}, opResultCollector.OnProvisioningStepProcessed)
-	sub.Subscribe(process.DeprovisioningStepProcessed{}, opResultCollector.OnDeprovisioningStepProcessed)
-	sub.Subscribe(process.UpgradeKymaStepProcessed{}, opResultCollector.OnUpgradeStepProcessed)
-	sub.Subscribe(process.ProvisioningStepProcessed{}, opDurationCollector.OnProvisioningStepProcessed)
-	sub.Subscribe(process.DeprovisioningStepProcessed{}, opDurationCollector.OnDeprovisioningStepProcessed)
-	sub.Subscribe(process.ProvisioningStepProcessed{}, stepResultCollector.OnProvisioningStepProcessed)
-	sub.Subscribe(process.DeprovisioningStepProcessed{}, stepResultCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe("metrics.operationResult.provisioning", process.ProvisioningStepProcessed{}, opResultCollector.OnProvisioningStepProcessed)
+	sub.Subscribe("metrics.operationResult.deprovisioning", process.DeprovisioningStepProcessed{}, opResultCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe("metrics.operationResult.upgradeKyma", process.UpgradeKymaStepProcessed{}, opResultCollector.OnUpgradeStepProcessed)
+	sub.Subscribe("metrics.operationDuration.provisioning", process.ProvisioningStepProcessed{}, opDurationCollector.OnProvisioningStepProcessed)
+	sub.Subscribe("metrics.operationDuration.deprovisioning", process.DeprovisioningStepProcessed{}, opDurationCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe("metrics.stepResult.provisioning", process.ProvisioningStepProcessed{}, stepResultCollector.OnProvisioningStepProcessed)
+	sub.Subscribe("metrics.stepResult.deprovisioning", process.DeprovisioningStepProcessed{}, stepResultCollector.OnDeprovisioningStepProcessed)
+	sub.Subscribe("metrics.stepResult.upgradeKyma", process.UpgradeKymaStepProcessed{}, stepResultCollector.OnUpgradeKymaStepProcessed)
 }
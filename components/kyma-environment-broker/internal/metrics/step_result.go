@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,9 +19,16 @@ import (
 // 0 - Failed
 // 1 - Succeeded
 // 2 - In progress
+//
+// For steps with a retry policy (see process.StepWithRetryPolicy) it also provides the counters:
+// - compass_keb_step_attempts_total{"operation_type", "step_name"} - number of runs of the steps
+// - compass_keb_step_retries_exhausted_total{"operation_type", "step_name", "action"} - number of steps which ran out
+// of attempts, the action is "failed" if the operation was failed or "skipped" if the step was skipped
 type StepResultCollector struct {
 	provisioningResultGauge   *prometheus.GaugeVec
 	deprovisioningResultGauge *prometheus.GaugeVec
+	attemptsCounter           *prometheus.CounterVec
+	exhaustedCounter          *prometheus.CounterVec
 }
 
 func NewStepResultCollector() *StepResultCollector {
@@ -37,17 +45,33 @@ func NewStepResultCollector() *StepResultCollector {
 			Name:      "deprovisioning_step_result",
 			Help:      "Result of the deprovisioning step",
 		}, []string{"operation_id", "runtime_id", "instance_id", "step_name", "global_account_id", "plan_id"}),
+		attemptsCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Subsystem: prometheusSubsystem,
+			Name:      "step_attempts_total",
+			Help:      "Number of runs of the steps with a retry policy",
+		}, []string{"operation_type", "step_name"}),
+		exhaustedCounter: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespace,
+			Subsystem: prometheusSubsystem,
+			Name:      "step_retries_exhausted_total",
+			Help:      "Number of steps with a retry policy which ran out of attempts",
+		}, []string{"operation_type", "step_name", "action"}),
 	}
 }
 
 func (c *StepResultCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.provisioningResultGauge.Describe(ch)
 	c.deprovisioningResultGauge.Describe(ch)
+	c.attemptsCounter.Describe(ch)
+	c.exhaustedCounter.Describe(ch)
 }
 
 func (c *StepResultCollector) Collect(ch chan<- prometheus.Metric) {
 	c.provisioningResultGauge.Collect(ch)
 	c.deprovisioningResultGauge.Collect(ch)
+	c.attemptsCounter.Collect(ch)
+	c.exhaustedCounter.Collect(ch)
 }
 
 func (c *StepResultCollector) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
@@ -76,6 +100,7 @@ func (c *StepResultCollector) OnProvisioningStepProcessed(ctx context.Context, e
 		stepProcessed.StepName,
 		pp.ErsContext.GlobalAccountID,
 		pp.PlanID).Set(resultValue)
+	c.countAttempts(internal.OperationTypeProvision, stepProcessed.StepProcessed)
 
 	return nil
 }
@@ -111,5 +136,31 @@ func (c *StepResultCollector) OnDeprovisioningStepProcessed(ctx context.Context,
 		stepProcessed.StepName,
 		pp.ErsContext.GlobalAccountID,
 		pp.PlanID).Set(resultValue)
+	c.countAttempts(internal.OperationTypeDeprovision, stepProcessed.StepProcessed)
 	return nil
 }
+
+func (c *StepResultCollector) OnUpgradeKymaStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.UpgradeKymaStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected UpgradeKymaStepProcessed but got %+v", ev)
+	}
+
+	c.countAttempts(internal.OperationTypeUpgradeKyma, stepProcessed.StepProcessed)
+	return nil
+}
+
+func (c *StepResultCollector) countAttempts(operationType internal.OperationType, stepProcessed process.StepProcessed) {
+	if stepProcessed.Attempt == 0 {
+		return
+	}
+	c.attemptsCounter.WithLabelValues(string(operationType), stepProcessed.StepName).Inc()
+	if !stepProcessed.Exhausted {
+		return
+	}
+	action := "skipped"
+	if stepProcessed.Error != nil {
+		action = "failed"
+	}
+	c.exhaustedCounter.WithLabelValues(string(operationType), stepProcessed.StepName, action).Inc()
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// ActorHeader is the header with the identity of the caller. It is set by the header mutator of the API gateway
+// (Oathkeeper) in front of the broker from the verified token, any value sent by the client is overwritten.
+const ActorHeader = "X-Actor"
+
+// AddActorToContext extracts the identity of the caller from the header set by the API gateway.
+func AddActorToContext() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			actor := strings.TrimSpace(req.Header.Get(ActorHeader))
+			if actor == "" {
+				next.ServeHTTP(w, req)
+				return
+			}
+
+			newCtx := context.WithValue(req.Context(), actorKey, actor)
+			next.ServeHTTP(w, req.WithContext(newCtx))
+		})
+	}
+}
+
+// ActorFromContext returns the identity of the caller associated with the context if possible.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorKey).(string)
+	return actor, ok
+}
@@ -14,6 +14,8 @@ type key int
 const (
 	// requestRegionKey is the context key for the region from the request path.
 	requestRegionKey key = iota + 1
+	// actorKey is the context key for the identity of the caller set by the API gateway.
+	actorKey
 )
 
 func AddRegionToContext(defaultRegion string) mux.MiddlewareFunc {
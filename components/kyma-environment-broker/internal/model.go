@@ -8,6 +8,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
@@ -143,6 +144,9 @@ type Operation struct {
 	// following fields are serialized to JSON and stored in the storage
 	InstanceDetails
 
+	// StepAttempts holds the attempts of the steps with a retry policy by the step name
+	StepAttempts map[string]StepAttempt `json:"step_attempts,omitempty"`
+
 	ID        string        `json:"-"`
 	Version   int           `json:"-"`
 	CreatedAt time.Time     `json:"-"`
@@ -159,6 +163,12 @@ type Operation struct {
 	OrchestrationID string `json:"-"`
 }
 
+// StepAttempt holds the number of runs of the step which must be retried and the time of the first run
+type StepAttempt struct {
+	Count     int       `json:"count"`
+	StartedAt time.Time `json:"started_at"`
+}
+
 func (o *Operation) IsFinished() bool {
 	return o.State != orchestration.InProgress && o.State != orchestration.Pending && o.State != orchestration.Canceling
 }
@@ -275,6 +285,8 @@ type DeprovisioningOperation struct {
 
 	// Temporary indicates that this deprovisioning operation must not remove the instance
 	Temporary bool `json:"temporary"`
+	// Reprovisioning indicates that the runtime must be unsuspended when this suspension operation succeeds
+	Reprovisioning bool `json:"reprovisioning"`
 }
 
 // UpgradeKymaOperation holds all information about upgrade Kyma operation
@@ -295,6 +307,9 @@ type UpgradeClusterOperation struct {
 
 	orchestration.RuntimeOperation `json:"runtime_operation"`
 	InputCreator                   ProvisionerInputCreator `json:"-"`
+
+	// Kubernetes holds the versions requested by the orchestration, which override the versions configured in KEB
+	Kubernetes orchestration.KubernetesParameters `json:"kubernetes"`
 }
 
 func NewRuntimeState(runtimeID, operationID string, kymaConfig *gqlschema.KymaConfigInput, clusterConfig *gqlschema.GardenerConfigInput) RuntimeState {
@@ -331,6 +346,40 @@ type RuntimeState struct {
 	ClusterConfig gqlschema.GardenerConfigInput `json:"clusterConfig"`
 }
 
+// AuditEvent captures an action performed by an operator through the KEB API
+type AuditEvent struct {
+	ID              string
+	Actor           string
+	Action          audit.Action
+	OrchestrationID string
+	InstanceID      string
+	RuntimeID       string
+	Parameters      string
+	Result          audit.Result
+	Error           string
+	CreatedAt       time.Time
+}
+
+// WebhookDeadLetter is a webhook notification which could not be delivered after all retries
+type WebhookDeadLetter struct {
+	ID        string
+	URL       string
+	EventType string
+	Payload   string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// OutboxEvent is an event stored in the database before it is dispatched to the subscribers.
+// The offset is assigned by the storage and defines the order of the events.
+type OutboxEvent struct {
+	Offset    int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
 // OperationStats provide number of operations per type and state
 type OperationStats struct {
 	Provisioning   map[domain.LastOperationState]int
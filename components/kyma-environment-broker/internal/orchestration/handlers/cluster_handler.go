@@ -2,31 +2,38 @@ package handlers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/mod/semver"
 )
 
 type clusterHandler struct {
 	orchestrations storage.Orchestrations
 	queue          *process.Queue
+	auditor        *audit.Recorder
 	converter      Converter
 	log            logrus.FieldLogger
 }
 
-func NewClusterHandler(orchestrations storage.Orchestrations, q *process.Queue, log logrus.FieldLogger) *clusterHandler {
+func NewClusterHandler(orchestrations storage.Orchestrations, q *process.Queue, auditor *audit.Recorder, log logrus.FieldLogger) *clusterHandler {
 	return &clusterHandler{
 		orchestrations: orchestrations,
 		queue:          q,
+		auditor:        auditor,
 		log:            log,
 		converter:      Converter{},
 	}
@@ -43,6 +50,7 @@ func (h *clusterHandler) createOrchestration(w http.ResponseWriter, r *http.Requ
 		err := json.NewDecoder(r.Body).Decode(&params)
 		if err != nil {
 			h.log.Errorf("while decoding request body: %v", err)
+			h.auditor.Record(r.Context(), auditExt.UpgradeClusterAction, audit.Target{}, params, err)
 			httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while decoding request body"))
 			return
 		}
@@ -52,10 +60,20 @@ func (h *clusterHandler) createOrchestration(w http.ResponseWriter, r *http.Requ
 	err := validateTarget(params.Targets)
 	if err != nil {
 		h.log.Errorf("while validating target: %v", err)
+		h.auditor.Record(r.Context(), auditExt.UpgradeClusterAction, audit.Target{}, params, err)
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating target"))
 		return
 	}
 
+	// validate Kubernetes and machine image versions
+	err = validateKubernetesParameters(params.Kubernetes)
+	if err != nil {
+		h.log.Errorf("while validating kubernetes parameters: %v", err)
+		h.auditor.Record(r.Context(), auditExt.UpgradeClusterAction, audit.Target{}, params, err)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating kubernetes parameters"))
+		return
+	}
+
 	// defaults strategy if not specified to Parallel with Immediate schedule
 	defaultOrchestrationStrategy(&params.Strategy)
 
@@ -71,6 +89,7 @@ func (h *clusterHandler) createOrchestration(w http.ResponseWriter, r *http.Requ
 	}
 
 	err = h.orchestrations.Insert(o)
+	h.auditor.Record(r.Context(), auditExt.UpgradeClusterAction, audit.Target{OrchestrationID: o.OrchestrationID}, params, err)
 	if err != nil {
 		h.log.Errorf("while inserting orchestration to storage: %v", err)
 		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while inserting orchestration to storage"))
@@ -83,3 +102,21 @@ func (h *clusterHandler) createOrchestration(w http.ResponseWriter, r *http.Requ
 
 	httputil.WriteResponse(w, http.StatusAccepted, response)
 }
+
+// validateKubernetesParameters checks that the requested Kubernetes and machine image versions, if any, are full semantic versions, e.g. 1.19.8.
+// Whether the versions are supported by the cloud provider is verified by Gardener during the upgrade of the cluster.
+func validateKubernetesParameters(params orchestration.KubernetesParameters) error {
+	for name, version := range map[string]string{
+		"kubernetes version":    params.KubernetesVersion,
+		"machine image version": params.MachineImageVersion,
+	} {
+		if version == "" {
+			continue
+		}
+		if !semver.IsValid(fmt.Sprintf("v%s", version)) || strings.Count(version, ".") != 2 {
+			return fmt.Errorf("invalid %s %s, expected a version in the <major>.<minor>.<patch> format", name, version)
+		}
+	}
+
+	return nil
+}
@@ -7,9 +7,12 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/gorilla/mux"
@@ -53,13 +56,96 @@ func TestClusterHandler_AttachRoutes(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotEmpty(t, out.OrchestrationID)
 	})
+
+	t.Run("upgrade with kubernetes versions", func(t *testing.T) {
+		// given
+		handler := fixClusterHandler(t)
+
+		params := orchestration.Parameters{
+			Targets: orchestration.TargetSpec{
+				Include: []orchestration.RuntimeTarget{
+					{
+						RuntimeID: "test",
+					},
+				},
+			},
+			Kubernetes: orchestration.KubernetesParameters{
+				KubernetesVersion:   "1.19.8",
+				MachineImageVersion: "184.0.0",
+			},
+		}
+		p, err := json.Marshal(&params)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/upgrade/cluster", bytes.NewBuffer(p))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler.AttachRoutes(router)
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var out orchestration.UpgradeResponse
+		err = json.Unmarshal(rr.Body.Bytes(), &out)
+		require.NoError(t, err)
+
+		o, err := handler.orchestrations.GetByID(out.OrchestrationID)
+		require.NoError(t, err)
+		assert.Equal(t, params.Kubernetes, o.Parameters.Kubernetes)
+	})
+
+	t.Run("upgrade with invalid kubernetes version", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		logs := logrus.New()
+		handler := NewClusterHandler(db.Orchestrations(), process.NewQueue(&testExecutor{}, logs), audit.NewRecorder(db.AuditEvents(), logs), logs)
+
+		params := orchestration.Parameters{
+			Targets: orchestration.TargetSpec{
+				Include: []orchestration.RuntimeTarget{
+					{
+						RuntimeID: "test",
+					},
+				},
+			},
+			Kubernetes: orchestration.KubernetesParameters{
+				KubernetesVersion: "1.19",
+			},
+		}
+		p, err := json.Marshal(&params)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest("POST", "/upgrade/cluster", bytes.NewBuffer(p))
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		handler.AttachRoutes(router)
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+		events, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, auditExt.UpgradeClusterAction, events[0].Action)
+		assert.Equal(t, auditExt.Failed, events[0].Result)
+	})
 }
 
 func fixClusterHandler(t *testing.T) *clusterHandler {
 	db := storage.NewMemoryStorage()
 	logs := logrus.New()
 	q := process.NewQueue(&testExecutor{}, logs)
-	handler := NewClusterHandler(db.Orchestrations(), q, logs)
+	handler := NewClusterHandler(db.Orchestrations(), q, audit.NewRecorder(db.AuditEvents(), logs), logs)
 
 	return handler
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"github.com/gorilla/mux"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pkg/errors"
@@ -18,11 +19,12 @@ type handler struct {
 }
 
 func NewOrchestrationHandler(db storage.BrokerStorage, kymaQueue *process.Queue, clusterQueue *process.Queue, defaultMaxPage int, log logrus.FieldLogger) Handler {
+	auditor := audit.NewRecorder(db.AuditEvents(), log)
 	return &handler{
 		handlers: []Handler{
-			NewKymaHandler(db.Orchestrations(), kymaQueue, log),
-			NewClusterHandler(db.Orchestrations(), clusterQueue, log),
-			NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), defaultMaxPage, log),
+			NewKymaHandler(db.Orchestrations(), kymaQueue, auditor, log),
+			NewClusterHandler(db.Orchestrations(), clusterQueue, auditor, log),
+			NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), kymaQueue, clusterQueue, auditor, defaultMaxPage, log),
 		},
 	}
 }
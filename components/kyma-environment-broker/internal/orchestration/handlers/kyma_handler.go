@@ -14,7 +14,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
@@ -27,15 +29,17 @@ import (
 type kymaHandler struct {
 	orchestrations storage.Orchestrations
 	queue          *process.Queue
+	auditor        *audit.Recorder
 	converter      Converter
 	gitClient      *github.Client
 	log            logrus.FieldLogger
 }
 
-func NewKymaHandler(orchestrations storage.Orchestrations, q *process.Queue, log logrus.FieldLogger) *kymaHandler {
+func NewKymaHandler(orchestrations storage.Orchestrations, q *process.Queue, auditor *audit.Recorder, log logrus.FieldLogger) *kymaHandler {
 	return &kymaHandler{
 		orchestrations: orchestrations,
 		queue:          q,
+		auditor:        auditor,
 		log:            log,
 		converter:      Converter{},
 		gitClient:      github.NewClient(nil),
@@ -53,6 +57,7 @@ func (h *kymaHandler) createOrchestration(w http.ResponseWriter, r *http.Request
 		err := json.NewDecoder(r.Body).Decode(&params)
 		if err != nil {
 			h.log.Errorf("while decoding request body: %v", err)
+			h.auditor.Record(r.Context(), auditExt.UpgradeKymaAction, audit.Target{}, params, err)
 			httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while decoding request body"))
 			return
 		}
@@ -62,6 +67,7 @@ func (h *kymaHandler) createOrchestration(w http.ResponseWriter, r *http.Request
 	err := validateTarget(params.Targets)
 	if err != nil {
 		h.log.Errorf("while validating target: %v", err)
+		h.auditor.Record(r.Context(), auditExt.UpgradeKymaAction, audit.Target{}, params, err)
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating target"))
 		return
 	}
@@ -70,6 +76,7 @@ func (h *kymaHandler) createOrchestration(w http.ResponseWriter, r *http.Request
 	err = h.ValidateKymaVersion(params.Kyma.Version)
 	if err != nil {
 		h.log.Errorf("while validating kyma version: %v", err)
+		h.auditor.Record(r.Context(), auditExt.UpgradeKymaAction, audit.Target{}, params, err)
 		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.Wrapf(err, "while validating kyma version"))
 		return
 	}
@@ -89,6 +96,7 @@ func (h *kymaHandler) createOrchestration(w http.ResponseWriter, r *http.Request
 	}
 
 	err = h.orchestrations.Insert(o)
+	h.auditor.Record(r.Context(), auditExt.UpgradeKymaAction, audit.Target{OrchestrationID: o.OrchestrationID}, params, err)
 	if err != nil {
 		h.log.Errorf("while inserting orchestration to storage: %v", err)
 		httputil.WriteErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "while inserting orchestration to storage"))
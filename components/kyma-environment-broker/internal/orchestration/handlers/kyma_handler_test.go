@@ -14,6 +14,7 @@ import (
 	"github.com/google/go-github/github"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/stretchr/testify/assert"
@@ -101,7 +102,7 @@ func fixKymaHandler(t *testing.T) *kymaHandler {
 	db := storage.NewMemoryStorage()
 	logs := logrus.New()
 	q := process.NewQueue(&testExecutor{}, logs)
-	kHandler := NewKymaHandler(db.Orchestrations(), q, logs)
+	kHandler := NewKymaHandler(db.Orchestrations(), q, audit.NewRecorder(db.AuditEvents(), logs), logs)
 
 	// fix github client
 	mockServer := fixGithubServer(t)
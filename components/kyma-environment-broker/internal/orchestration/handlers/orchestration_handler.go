@@ -5,9 +5,12 @@ import (
 
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
@@ -28,12 +31,14 @@ type orchestrationHandler struct {
 	log       logrus.FieldLogger
 
 	canceler *Canceler
+	retrier  *Retrier
+	auditor  *audit.Recorder
 
 	defaultMaxPage int
 }
 
 // NewOrchestrationStatusHandler exposes data about orchestrations and allows to manage them
-func NewOrchestrationStatusHandler(operations storage.Operations, orchestrations storage.Orchestrations, runtimeStates storage.RuntimeStates, defaultMaxPage int, log logrus.FieldLogger) *orchestrationHandler {
+func NewOrchestrationStatusHandler(operations storage.Operations, orchestrations storage.Orchestrations, runtimeStates storage.RuntimeStates, kymaQueue, clusterQueue *process.Queue, auditor *audit.Recorder, defaultMaxPage int, log logrus.FieldLogger) *orchestrationHandler {
 	return &orchestrationHandler{
 		operations:     operations,
 		orchestrations: orchestrations,
@@ -42,6 +47,8 @@ func NewOrchestrationStatusHandler(operations storage.Operations, orchestrations
 		defaultMaxPage: defaultMaxPage,
 		converter:      Converter{},
 		canceler:       NewCanceler(orchestrations, log),
+		retrier:        NewRetrier(orchestrations, operations, kymaQueue, clusterQueue, log),
+		auditor:        auditor,
 	}
 }
 
@@ -49,6 +56,7 @@ func (h *orchestrationHandler) AttachRoutes(router *mux.Router) {
 	router.HandleFunc("/orchestrations", h.listOrchestration).Methods(http.MethodGet)
 	router.HandleFunc("/orchestrations/{orchestration_id}", h.getOrchestration).Methods(http.MethodGet)
 	router.HandleFunc("/orchestrations/{orchestration_id}/cancel", h.cancelOrchestrationByID).Methods(http.MethodPut)
+	router.HandleFunc("/orchestrations/{orchestration_id}/retry", h.retryOrchestrationByID).Methods(http.MethodPut)
 	router.HandleFunc("/orchestrations/{orchestration_id}/operations", h.listOperations).Methods(http.MethodGet)
 	router.HandleFunc("/orchestrations/{orchestration_id}/operations/{operation_id}", h.getOperation).Methods(http.MethodGet)
 }
@@ -84,6 +92,7 @@ func (h *orchestrationHandler) cancelOrchestrationByID(w http.ResponseWriter, r
 	orchestrationID := mux.Vars(r)["orchestration_id"]
 
 	err := h.canceler.CancelForID(orchestrationID)
+	h.auditor.Record(r.Context(), auditExt.CancelOrchestrationAction, audit.Target{OrchestrationID: orchestrationID}, nil, err)
 	if err != nil {
 		h.log.Errorf("while canceling orchestration %s: %v", orchestrationID, err)
 		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), errors.Wrapf(err, "while canceling orchestration %s", orchestrationID))
@@ -95,6 +104,22 @@ func (h *orchestrationHandler) cancelOrchestrationByID(w http.ResponseWriter, r
 	httputil.WriteResponse(w, http.StatusOK, response)
 }
 
+func (h *orchestrationHandler) retryOrchestrationByID(w http.ResponseWriter, r *http.Request) {
+	orchestrationID := mux.Vars(r)["orchestration_id"]
+
+	retryID, err := h.retrier.RetryForID(orchestrationID)
+	h.auditor.Record(r.Context(), auditExt.RetryOrchestrationAction, audit.Target{OrchestrationID: orchestrationID}, commonOrchestration.UpgradeResponse{OrchestrationID: retryID}, err)
+	if err != nil {
+		h.log.Errorf("while retrying orchestration %s: %v", orchestrationID, err)
+		httputil.WriteErrorResponse(w, h.resolveErrorStatus(err), errors.Wrapf(err, "while retrying orchestration %s", orchestrationID))
+		return
+	}
+
+	response := commonOrchestration.UpgradeResponse{OrchestrationID: retryID}
+
+	httputil.WriteResponse(w, http.StatusAccepted, response)
+}
+
 func (h *orchestrationHandler) listOrchestration(w http.ResponseWriter, r *http.Request) {
 	pageSize, page, err := pagination.ExtractPaginationConfigFromRequest(r, h.defaultMaxPage)
 	if err != nil {
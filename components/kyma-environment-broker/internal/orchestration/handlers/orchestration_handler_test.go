@@ -7,9 +7,13 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/stretchr/testify/assert"
 
@@ -30,7 +34,7 @@ func TestStatusHandler_AttachRoutes(t *testing.T) {
 		require.NoError(t, err)
 
 		logs := logrus.New()
-		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), 100, logs)
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), nil, nil, audit.NewRecorder(db.AuditEvents(), logs), 100, logs)
 
 		req, err := http.NewRequest("GET", "/orchestrations?page_size=1", nil)
 		require.NoError(t, err)
@@ -146,7 +150,7 @@ func TestStatusHandler_AttachRoutes(t *testing.T) {
 		require.NoError(t, err)
 
 		logs := logrus.New()
-		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), 100, logs)
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), nil, nil, audit.NewRecorder(db.AuditEvents(), logs), 100, logs)
 
 		urlPath := fmt.Sprintf("/orchestrations/%s/operations", fixID)
 		req, err := http.NewRequest("GET", urlPath, nil)
@@ -198,7 +202,7 @@ func TestStatusHandler_AttachRoutes(t *testing.T) {
 		require.NoError(t, err)
 
 		logs := logrus.New()
-		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), 100, logs)
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), nil, nil, audit.NewRecorder(db.AuditEvents(), logs), 100, logs)
 
 		req, err := http.NewRequest("PUT", fmt.Sprintf("/orchestrations/%s/cancel", fixID), nil)
 		require.NoError(t, err)
@@ -222,5 +226,103 @@ func TestStatusHandler_AttachRoutes(t *testing.T) {
 		o, err := db.Orchestrations().GetByID(fixID)
 		require.NoError(t, err)
 		assert.Equal(t, orchestration.Canceling, o.State)
+
+		events, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{OrchestrationIDs: []string{fixID}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, auditExt.CancelOrchestrationAction, events[0].Action)
+		assert.Equal(t, auditExt.Succeeded, events[0].Result)
+	})
+
+	t.Run("retry orchestration", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+
+		err := db.Orchestrations().Insert(internal.Orchestration{
+			OrchestrationID: fixID,
+			Type:            orchestration.UpgradeKymaOrchestration,
+			State:           orchestration.Failed,
+			Parameters:      orchestration.Parameters{Kyma: orchestration.KymaParameters{Version: "1.22.0"}},
+		})
+		require.NoError(t, err)
+		for id, state := range map[string]domain.LastOperationState{"runtime-failed": domain.Failed, "runtime-succeeded": domain.Succeeded} {
+			err = db.Operations().InsertUpgradeKymaOperation(internal.UpgradeKymaOperation{
+				Operation: internal.Operation{
+					ID:              id,
+					InstanceID:      id,
+					OrchestrationID: fixID,
+					State:           state,
+				},
+				RuntimeOperation: orchestration.RuntimeOperation{
+					Runtime: orchestration.Runtime{RuntimeID: id},
+				},
+			})
+			require.NoError(t, err)
+		}
+
+		logs := logrus.New()
+		kymaQueue := process.NewQueue(&testExecutor{}, logs)
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), kymaQueue, nil, audit.NewRecorder(db.AuditEvents(), logs), 100, logs)
+
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/orchestrations/%s/retry", fixID), nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		kymaHandler.AttachRoutes(router)
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+
+		var out orchestration.UpgradeResponse
+		err = json.Unmarshal(rr.Body.Bytes(), &out)
+		require.NoError(t, err)
+		assert.NotEqual(t, fixID, out.OrchestrationID)
+
+		o, err := db.Orchestrations().GetByID(out.OrchestrationID)
+		require.NoError(t, err)
+		assert.Equal(t, orchestration.Pending, o.State)
+		assert.Equal(t, orchestration.UpgradeKymaOrchestration, o.Type)
+		assert.Equal(t, "1.22.0", o.Parameters.Kyma.Version)
+		assert.Equal(t, []orchestration.RuntimeTarget{{RuntimeID: "runtime-failed"}}, o.Parameters.Targets.Include)
+
+		events, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{OrchestrationIDs: []string{fixID}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, auditExt.RetryOrchestrationAction, events[0].Action)
+		assert.Equal(t, auditExt.Succeeded, events[0].Result)
+	})
+
+	t.Run("retry orchestration which did not fail", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+
+		err := db.Orchestrations().Insert(internal.Orchestration{OrchestrationID: fixID, Type: orchestration.UpgradeKymaOrchestration, State: orchestration.InProgress})
+		require.NoError(t, err)
+
+		logs := logrus.New()
+		kymaHandler := NewOrchestrationStatusHandler(db.Operations(), db.Orchestrations(), db.RuntimeStates(), nil, nil, audit.NewRecorder(db.AuditEvents(), logs), 100, logs)
+
+		req, err := http.NewRequest("PUT", fmt.Sprintf("/orchestrations/%s/retry", fixID), nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		kymaHandler.AttachRoutes(router)
+
+		// when
+		router.ServeHTTP(rr, req)
+
+		// then
+		require.Equal(t, http.StatusBadRequest, rr.Code)
+
+		events, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{OrchestrationIDs: []string{fixID}})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, auditExt.RetryOrchestrationAction, events[0].Action)
+		assert.Equal(t, auditExt.Failed, events[0].Result)
 	})
 }
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	orchestrationExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+type Retrier struct {
+	orchestrations storage.Orchestrations
+	operations     storage.Operations
+	queues         map[orchestrationExt.Type]*process.Queue
+	log            logrus.FieldLogger
+}
+
+func NewRetrier(orchestrations storage.Orchestrations, operations storage.Operations, kymaQueue, clusterQueue *process.Queue, logger logrus.FieldLogger) *Retrier {
+	return &Retrier{
+		orchestrations: orchestrations,
+		operations:     operations,
+		queues: map[orchestrationExt.Type]*process.Queue{
+			orchestrationExt.UpgradeKymaOrchestration:    kymaQueue,
+			orchestrationExt.UpgradeClusterOrchestration: clusterQueue,
+		},
+		log: logger,
+	}
+}
+
+// RetryForID schedules a new orchestration with the parameters of the failed orchestration with the given ID,
+// which targets only the runtimes of its failed operations. It returns the ID of the new orchestration.
+func (r *Retrier) RetryForID(orchestrationID string) (string, error) {
+	o, err := r.orchestrations.GetByID(orchestrationID)
+	if err != nil {
+		return "", errors.Wrap(err, "while getting orchestration")
+	}
+	if o.State != orchestrationExt.Failed {
+		return "", apiErrors.NewBadRequest(fmt.Sprintf("orchestration is in %s state, only failed orchestrations can be retried", o.State))
+	}
+	queue, found := r.queues[o.Type]
+	if !found {
+		return "", apiErrors.NewBadRequest(fmt.Sprintf("orchestration of %s type cannot be retried", o.Type))
+	}
+
+	runtimeIDs, err := r.failedRuntimeIDs(*o)
+	if err != nil {
+		return "", errors.Wrap(err, "while getting failed operations")
+	}
+	if len(runtimeIDs) == 0 {
+		return "", apiErrors.NewBadRequest("orchestration has no failed operations")
+	}
+
+	params := o.Parameters
+	params.Targets = orchestrationExt.TargetSpec{}
+	for _, id := range runtimeIDs {
+		params.Targets.Include = append(params.Targets.Include, orchestrationExt.RuntimeTarget{RuntimeID: id})
+	}
+
+	now := time.Now()
+	retry := internal.Orchestration{
+		OrchestrationID: uuid.New().String(),
+		Type:            o.Type,
+		State:           orchestrationExt.Pending,
+		Description:     fmt.Sprintf("retry of orchestration %s, queued for processing", orchestrationID),
+		Parameters:      params,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = r.orchestrations.Insert(retry)
+	if err != nil {
+		return "", errors.Wrap(err, "while inserting orchestration to storage")
+	}
+	queue.Add(retry.OrchestrationID)
+
+	return retry.OrchestrationID, nil
+}
+
+func (r *Retrier) failedRuntimeIDs(o internal.Orchestration) ([]string, error) {
+	filter := dbmodel.OperationFilter{States: []string{orchestrationExt.Failed}}
+	var runtimeIDs []string
+
+	switch o.Type {
+	case orchestrationExt.UpgradeKymaOrchestration:
+		operations, _, _, err := r.operations.ListUpgradeKymaOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range operations {
+			runtimeIDs = append(runtimeIDs, op.RuntimeOperation.RuntimeID)
+		}
+	case orchestrationExt.UpgradeClusterOrchestration:
+		operations, _, _, err := r.operations.ListUpgradeClusterOperationsByOrchestrationID(o.OrchestrationID, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, op := range operations {
+			runtimeIDs = append(runtimeIDs, op.RuntimeOperation.RuntimeID)
+		}
+	}
+
+	return runtimeIDs, nil
+}
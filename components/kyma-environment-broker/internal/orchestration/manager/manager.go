@@ -1,12 +1,15 @@
 package manager
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration/strategies"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
 	"github.com/pkg/errors"
@@ -27,6 +30,7 @@ type orchestrationManager struct {
 	resolver             orchestration.RuntimeResolver
 	factory              OperationFactory
 	executor             orchestration.OperationExecutor
+	publisher            event.Publisher
 	log                  logrus.FieldLogger
 	pollingInterval      time.Duration
 }
@@ -39,6 +43,7 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		return m.failOrchestration(o, errors.Wrap(err, "while getting orchestration"))
 	}
 
+	oldState := o.State
 	operations, err := m.resolveOperations(o)
 	if err != nil {
 		return m.failOrchestration(o, errors.Wrap(err, "while resolving operations"))
@@ -49,6 +54,7 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		logger.Errorf("while updating orchestration: %v", err)
 		return m.pollingInterval, nil
 	}
+	m.publishStateChange(oldState, *o)
 	// do not perform any action if the orchestration is finished
 	if o.IsFinished() {
 		m.log.Infof("Orchestration was already finished, state: %s", o.State)
@@ -61,6 +67,7 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		return 0, errors.Wrap(err, "while executing upgrade strategy")
 	}
 
+	oldState = o.State
 	o, err = m.waitForCompletion(o, strategy, execID, logger)
 	if err != nil {
 		return 0, errors.Wrap(err, "while waiting for orchestration to finish")
@@ -72,6 +79,7 @@ func (m *orchestrationManager) Execute(orchestrationID string) (time.Duration, e
 		logger.Errorf("while updating orchestration: %v", err)
 		return m.pollingInterval, nil
 	}
+	m.publishStateChange(oldState, *o)
 
 	logger.Infof("Finished processing orchestration, state: %s", o.State)
 	return 0, nil
@@ -229,6 +237,7 @@ func (m *orchestrationManager) failOrchestration(o *internal.Orchestration, err
 }
 
 func (m *orchestrationManager) updateOrchestration(o *internal.Orchestration, state, description string) time.Duration {
+	oldState := o.State
 	o.UpdatedAt = time.Now()
 	o.State = state
 	o.Description = description
@@ -238,6 +247,21 @@ func (m *orchestrationManager) updateOrchestration(o *internal.Orchestration, st
 			m.log.Errorf("while updating orchestration: %v", err)
 			return time.Minute
 		}
+		return 0
 	}
+	m.publishStateChange(oldState, *o)
 	return 0
 }
+
+func (m *orchestrationManager) publishStateChange(oldState string, o internal.Orchestration) {
+	if oldState == o.State {
+		return
+	}
+	err := m.publisher.Publish(context.TODO(), process.OrchestrationStateChanged{
+		OldState:      oldState,
+		Orchestration: o,
+	})
+	if err != nil {
+		m.log.Errorf("while publishing state change of orchestration %s: %v", o.OrchestrationID, err)
+	}
+}
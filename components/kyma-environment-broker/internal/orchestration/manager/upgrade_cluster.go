@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -19,7 +20,7 @@ type upgradeClusterFactory struct {
 
 func NewUpgradeClusterManager(orchestrationStorage storage.Orchestrations, operationStorage storage.Operations, instanceStorage storage.Instances,
 	kymaClusterExecutor orchestration.OperationExecutor, resolver orchestration.RuntimeResolver,
-	pollingInterval time.Duration, log logrus.FieldLogger) process.Executor {
+	pollingInterval time.Duration, publisher event.Publisher, log logrus.FieldLogger) process.Executor {
 	return &orchestrationManager{
 		orchestrationStorage: orchestrationStorage,
 		operationStorage:     operationStorage,
@@ -30,6 +31,7 @@ func NewUpgradeClusterManager(orchestrationStorage storage.Orchestrations, opera
 		},
 		executor:        kymaClusterExecutor,
 		pollingInterval: pollingInterval,
+		publisher:       publisher,
 		log:             log,
 	}
 }
@@ -55,6 +57,7 @@ func (u *upgradeClusterFactory) NewOperation(o internal.Orchestration, r orchest
 			Runtime: r,
 			DryRun:  o.Parameters.DryRun,
 		},
+		Kubernetes: o.Parameters.Kubernetes,
 	}
 
 	err := u.operationStorage.InsertUpgradeClusterOperation(op)
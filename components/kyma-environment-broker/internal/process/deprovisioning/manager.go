@@ -3,6 +3,7 @@ package deprovisioning
 import (
 	"context"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
@@ -24,6 +25,8 @@ type Manager struct {
 	log              logrus.FieldLogger
 	steps            map[int][]Step
 	operationStorage storage.Operations
+	operationManager *process.DeprovisionOperationManager
+	parallelSteps    bool
 
 	publisher event.Publisher
 }
@@ -32,13 +35,23 @@ func NewManager(storage storage.Operations, pub event.Publisher, logger logrus.F
 	return &Manager{
 		log:              logger,
 		operationStorage: storage,
+		operationManager: process.NewDeprovisionOperationManager(storage),
 		steps:            make(map[int][]Step, 0),
 		publisher:        pub,
 	}
 }
 
+// EnableParallelSteps makes the manager run the steps of the same weight concurrently
+func (m *Manager) EnableParallelSteps() {
+	m.parallelSteps = true
+}
+
+// initWeight is the weight of the initialisation steps. They run before all other steps and never in parallel,
+// because they set the dependencies of the operation, such as the InputCreator, used by the other steps.
+const initWeight = 0
+
 func (m *Manager) InitStep(step Step) {
-	m.AddStep(0, step)
+	m.steps[initWeight] = append(m.steps[initWeight], step)
 }
 
 func (m *Manager) AddStep(weight int, step Step) {
@@ -51,19 +64,55 @@ func (m *Manager) AddStep(weight int, step Step) {
 func (m *Manager) runStep(step Step, operation internal.DeprovisioningOperation, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.DeprovisioningStepProcessed{
-		StepProcessed: process.StepProcessed{
-			StepName: step.Name(),
-			Duration: time.Since(start),
-			When:     when,
-			Error:    err,
-		},
-		OldOperation: operation,
-		Operation:    processedOperation,
+	stepProcessed := process.StepProcessed{
+		StepName: step.Name(),
+		Duration: time.Since(start),
+	}
+	if policy, ok := process.RetryPolicyOf(step, processedOperation.StepAttempts, err); ok && !isFinished(processedOperation) {
+		processedOperation, when, err = m.applyRetryPolicy(policy, &stepProcessed, processedOperation, when, err, logger)
+	}
+	stepProcessed.When = when
+	stepProcessed.Error = err
+	publishErr := m.publisher.Publish(context.TODO(), process.DeprovisioningStepProcessed{
+		OldOperation:  operation,
+		Operation:     processedOperation,
+		StepProcessed: stepProcessed,
 	})
+	if publishErr != nil {
+		logger.Errorf("while publishing processed step %s: %v", step.Name(), publishErr)
+	}
 	return processedOperation, when, err
 }
 
+// applyRetryPolicy counts the attempts of the step and decides if the step is repeated, skipped or fails the operation
+func (m *Manager) applyRetryPolicy(policy process.RetryPolicy, stepProcessed *process.StepProcessed, operation internal.DeprovisioningOperation, when time.Duration, err error, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	decision := policy.Decide(stepProcessed.StepName, operation.StepAttempts, when, err, time.Now())
+	stepProcessed.Attempt = decision.Attempt
+	stepProcessed.Exhausted = decision.Exhausted
+
+	switch {
+	case decision.Exhausted && !policy.Skippable:
+		logger.Errorf("Step failed after %d attempt(s): %s", decision.Attempt, decision.Reason)
+		return m.operationManager.OperationFailed(operation, decision.Reason, logger)
+	case decision.Exhausted:
+		logger.Warnf("Step skipped after %d attempt(s): %s", decision.Attempt, decision.Reason)
+	case err != nil:
+		logger.Warnf("Attempt %d of the step failed, retrying in %s: %s", decision.Attempt, decision.When, err)
+	}
+	if !decision.Updated {
+		return operation, decision.When, nil
+	}
+
+	updated, repeat := m.operationManager.UpdateOperation(operation, func(op *internal.DeprovisioningOperation) {
+		op.StepAttempts = process.WithStepAttempt(op.StepAttempts, stepProcessed.StepName, decision.StepAttempt)
+	}, logger)
+	if repeat != 0 {
+		return operation, repeat, nil
+	}
+	updated.SMClientFactory = operation.SMClientFactory
+	return updated, decision.When, nil
+}
+
 func (m *Manager) Execute(operationID string) (time.Duration, error) {
 	op, err := m.operationStorage.GetDeprovisioningOperationByID(operationID)
 	if err != nil {
@@ -85,6 +134,31 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 
 	for _, weightStep := range m.sortWeight() {
 		steps := m.steps[weightStep]
+		if m.parallelSteps && weightStep != initWeight && len(steps) > 1 {
+			logWeight := logOperation.WithField("weight", weightStep)
+			logWeight.Infof("Start %d steps in parallel", len(steps))
+
+			operation, when, err = m.runStepsInParallel(steps, operation, logWeight)
+			if err != nil {
+				logWeight.Errorf("Process operation failed: %s", err)
+				return 0, err
+			}
+			if isFinished(operation) {
+				if operation.RuntimeID == "" && operation.State == domain.Succeeded {
+					logWeight.Infof("Operation %q has no runtime ID. Process finished.", operation.ID)
+					return when, nil
+				}
+				logWeight.Infof("Operation %q got status %s. Process finished.", operation.ID, operation.State)
+				return 0, nil
+			}
+			if when == 0 {
+				logWeight.Info("Process operation successful")
+				continue
+			}
+
+			logWeight.Infof("Process operation will be repeated in %s ...", when)
+			return when, nil
+		}
 		for _, step := range steps {
 			logStep := logOperation.WithField("step", step.Name())
 			logStep.Infof("Start step")
@@ -94,7 +168,7 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 				logStep.Errorf("Process operation failed: %s", err)
 				return 0, err
 			}
-			if operation.State != domain.InProgress && operation.State != orchestration.Pending {
+			if isFinished(operation) {
 				if operation.RuntimeID == "" && operation.State == domain.Succeeded {
 					logStep.Infof("Operation %q has no runtime ID. Process finished.", operation.ID)
 					return when, nil
@@ -116,6 +190,72 @@ func (m *Manager) Execute(operationID string) (time.Duration, error) {
 	return 0, nil
 }
 
+type stepResult struct {
+	operation internal.DeprovisioningOperation
+	when      time.Duration
+	err       error
+}
+
+// runStepsInParallel runs the steps concurrently and merges the operations returned by the steps.
+// The first failed or finished operation (in the order the steps were added) is returned without merging.
+func (m *Manager) runStepsInParallel(steps []Step, operation internal.DeprovisioningOperation, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration, error) {
+	results := make([]stepResult, len(steps))
+	var wg sync.WaitGroup
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step Step) {
+			defer wg.Done()
+			logStep := logger.WithField("step", step.Name())
+			logStep.Infof("Start step")
+			results[i].operation, results[i].when, results[i].err = m.runStep(step, operation, logStep)
+		}(i, step)
+	}
+	wg.Wait()
+
+	merger := process.NewParallelMerger(operation)
+	var when time.Duration
+	for i, result := range results {
+		if result.err != nil || isFinished(result.operation) {
+			return result.operation, result.when, result.err
+		}
+		if err := merger.Merge(steps[i].Name(), result.operation); err != nil {
+			return operation, 0, err
+		}
+		if result.when != 0 && (when == 0 || result.when < when) {
+			when = result.when
+		}
+	}
+	merged := merger.Result().(internal.DeprovisioningOperation)
+
+	updated, repeat := m.storeMergedOperation(merged, logger)
+	if repeat != 0 {
+		return merged, repeat, nil
+	}
+	return updated, when, nil
+}
+
+// storeMergedOperation saves the merged operation on top of the latest version stored by the steps
+func (m *Manager) storeMergedOperation(operation internal.DeprovisioningOperation, logger logrus.FieldLogger) (internal.DeprovisioningOperation, time.Duration) {
+	latest, err := m.operationStorage.GetDeprovisioningOperationByID(operation.ID)
+	if err != nil {
+		logger.Errorf("while getting operation: %v", err)
+		return operation, time.Minute
+	}
+	operation.Version = latest.Version
+	updated, err := m.operationStorage.UpdateDeprovisioningOperation(operation)
+	if err != nil {
+		logger.Errorf("while updating merged operation: %v", err)
+		return operation, time.Minute
+	}
+	updated.SMClientFactory = operation.SMClientFactory
+
+	return *updated, 0
+}
+
+func isFinished(operation internal.DeprovisioningOperation) bool {
+	return operation.State != domain.InProgress && operation.State != orchestration.Pending
+}
+
 func (m *Manager) sortWeight() []int {
 	var weight []int
 	for w := range m.steps {
@@ -11,6 +11,10 @@ type StepProcessed struct {
 	Duration time.Duration
 	When     time.Duration
 	Error    error
+	// Attempt is the number of the run of the step with a retry policy, 0 for steps without a retry policy
+	Attempt int
+	// Exhausted is set when the step with a retry policy ran out of attempts or exceeded its timeout
+	Exhausted bool
 }
 
 type ProvisioningStepProcessed struct {
@@ -36,3 +40,9 @@ type UpgradeClusterStepProcessed struct {
 	OldOperation internal.UpgradeClusterOperation
 	Operation    internal.UpgradeClusterOperation
 }
+
+// OrchestrationStateChanged is published by the orchestration manager when the orchestration moves to a new state
+type OrchestrationStateChanged struct {
+	OldState      string
+	Orchestration internal.Orchestration
+}
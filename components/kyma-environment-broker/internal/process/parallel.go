@@ -0,0 +1,238 @@
+package process
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/provisioner/pkg/gqlschema"
+	"github.com/pkg/errors"
+)
+
+// notMergedFields are bookkeeping fields of the operation, which are maintained by the storage
+var notMergedFields = map[string]bool{
+	"Operation.Version":   true,
+	"Operation.UpdatedAt": true,
+}
+
+// lastWinsFields may be changed by many steps run in parallel, the change made by the last step
+// (in the order the steps were added to the manager) is taken without a conflict
+var lastWinsFields = map[string]bool{
+	"Operation.Description": true,
+}
+
+// ParallelMerger merges operations returned by steps of the same weight which were run concurrently.
+// Every field changed by a step (compared to the base operation) is copied to the merged operation.
+// Changes of the same field made by different steps to different values are reported as a conflict.
+// Maps are merged per key, so steps may change different entries of the same map (e.g. StepAttempts).
+// Steps must be merged in the order they were added to the manager to get deterministic results.
+type ParallelMerger struct {
+	base   reflect.Value
+	merged reflect.Value
+	owners map[string]string
+	// copied holds paths of maps which were copied, the maps of the base operation must not be modified
+	copied map[string]bool
+}
+
+// NewParallelMerger creates a merger for the given operation passed to all steps run in parallel
+func NewParallelMerger(base interface{}) *ParallelMerger {
+	merged := reflect.New(reflect.TypeOf(base)).Elem()
+	merged.Set(reflect.ValueOf(base))
+
+	return &ParallelMerger{
+		base:   reflect.ValueOf(base),
+		merged: merged,
+		owners: make(map[string]string),
+		copied: make(map[string]bool),
+	}
+}
+
+// Merge applies changes of the operation returned by the given step
+func (m *ParallelMerger) Merge(stepName string, result interface{}) error {
+	r := reflect.ValueOf(result)
+	if r.Type() != m.base.Type() {
+		return errors.Errorf("step %s returned %s instead of %s", stepName, r.Type(), m.base.Type())
+	}
+	return m.merge(stepName, "", m.base, r, m.merged)
+}
+
+// Result returns the merged operation
+func (m *ParallelMerger) Result() interface{} {
+	return m.merged.Interface()
+}
+
+func (m *ParallelMerger) merge(stepName, path string, base, result, merged reflect.Value) error {
+	if notMergedFields[path] {
+		return nil
+	}
+	if isMergeable(base.Type()) {
+		for i := 0; i < base.NumField(); i++ {
+			name := base.Type().Field(i).Name
+			err := m.merge(stepName, joinPath(path, name), base.Field(i), result.Field(i), merged.Field(i))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if base.Kind() == reflect.Map {
+		return m.mergeMap(stepName, path, base, result, merged)
+	}
+
+	if reflect.DeepEqual(base.Interface(), result.Interface()) {
+		return nil
+	}
+	owner, changed := m.owners[path]
+	if changed && !lastWinsFields[path] && !reflect.DeepEqual(merged.Interface(), result.Interface()) {
+		return errors.Errorf("conflicting changes of %s made by steps %s and %s", path, owner, stepName)
+	}
+	m.owners[path] = stepName
+	merged.Set(result)
+
+	return nil
+}
+
+func (m *ParallelMerger) mergeMap(stepName, path string, base, result, merged reflect.Value) error {
+	for _, key := range append(base.MapKeys(), result.MapKeys()...) {
+		resultValue := result.MapIndex(key)
+		if equalValues(base.MapIndex(key), resultValue) {
+			continue
+		}
+		keyPath := fmt.Sprintf("%s[%v]", path, key.Interface())
+		owner, changed := m.owners[keyPath]
+		if changed && !equalValues(merged.MapIndex(key), resultValue) {
+			return errors.Errorf("conflicting changes of %s made by steps %s and %s", keyPath, owner, stepName)
+		}
+		m.owners[keyPath] = stepName
+
+		if !m.copied[path] {
+			copied := reflect.MakeMapWithSize(merged.Type(), merged.Len())
+			for _, k := range merged.MapKeys() {
+				copied.SetMapIndex(k, merged.MapIndex(k))
+			}
+			merged.Set(copied)
+			m.copied[path] = true
+		}
+		// the zero value of a missing entry removes the key
+		merged.SetMapIndex(key, resultValue)
+	}
+
+	return nil
+}
+
+// equalValues compares map entries, invalid values represent missing entries
+func equalValues(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// isMergeable returns true for structs whose fields are merged separately. Structs with unexported
+// fields (e.g. time.Time) are compared as a whole.
+func isMergeable(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).PkgPath != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func joinPath(path, name string) string {
+	return strings.TrimPrefix(fmt.Sprintf("%s.%s", path, name), ".")
+}
+
+// syncInputCreator allows steps run in parallel to use the same ProvisionerInputCreator
+type syncInputCreator struct {
+	mu      sync.Mutex
+	creator internal.ProvisionerInputCreator
+}
+
+// NewSyncInputCreator returns the ProvisionerInputCreator which can be used concurrently
+func NewSyncInputCreator(creator internal.ProvisionerInputCreator) internal.ProvisionerInputCreator {
+	if _, ok := creator.(*syncInputCreator); ok || creator == nil {
+		return creator
+	}
+	return &syncInputCreator{creator: creator}
+}
+
+// UnwrapInputCreator returns the ProvisionerInputCreator wrapped by NewSyncInputCreator
+func UnwrapInputCreator(creator internal.ProvisionerInputCreator) internal.ProvisionerInputCreator {
+	if s, ok := creator.(*syncInputCreator); ok {
+		return s.creator
+	}
+	return creator
+}
+
+func (s *syncInputCreator) SetProvisioningParameters(params internal.ProvisioningParameters) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.SetProvisioningParameters(params)
+	return s
+}
+
+func (s *syncInputCreator) SetShootName(name string) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.SetShootName(name)
+	return s
+}
+
+func (s *syncInputCreator) SetLabel(key, value string) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.SetLabel(key, value)
+	return s
+}
+
+func (s *syncInputCreator) SetOverrides(component string, overrides []*gqlschema.ConfigEntryInput) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.SetOverrides(component, overrides)
+	return s
+}
+
+func (s *syncInputCreator) AppendOverrides(component string, overrides []*gqlschema.ConfigEntryInput) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.AppendOverrides(component, overrides)
+	return s
+}
+
+func (s *syncInputCreator) AppendGlobalOverrides(overrides []*gqlschema.ConfigEntryInput) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.AppendGlobalOverrides(overrides)
+	return s
+}
+
+func (s *syncInputCreator) CreateProvisionRuntimeInput() (gqlschema.ProvisionRuntimeInput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creator.CreateProvisionRuntimeInput()
+}
+
+func (s *syncInputCreator) CreateUpgradeRuntimeInput() (gqlschema.UpgradeRuntimeInput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creator.CreateUpgradeRuntimeInput()
+}
+
+func (s *syncInputCreator) CreateUpgradeShootInput() (gqlschema.UpgradeShootInput, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creator.CreateUpgradeShootInput()
+}
+
+func (s *syncInputCreator) EnableOptionalComponent(componentName string) internal.ProvisionerInputCreator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.creator.EnableOptionalComponent(componentName)
+	return s
+}
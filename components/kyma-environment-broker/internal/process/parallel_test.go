@@ -0,0 +1,125 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+)
+
+func TestParallelMerger_Merge(t *testing.T) {
+	t.Run("should merge changes of different fields", func(t *testing.T) {
+		// given
+		base := fixture.FixProvisioningOperation("op-id", "instance-id")
+		xsuaa := base
+		xsuaa.XSUAA.XSAppname = "xsapp"
+		xsuaa.XSUAA.Instance.InstanceID = "xsuaa-instance"
+		xsuaa.Description = "xsuaa provisioned"
+		ems := base
+		ems.Ems.Instance.InstanceID = "ems-instance"
+		ems.Description = "ems provisioned"
+		ems.Version = base.Version + 1
+		creds := base
+		target := "target-secret"
+		creds.ProvisioningParameters.Parameters.TargetSecret = &target
+
+		// when
+		merger := NewParallelMerger(base)
+		require.NoError(t, merger.Merge("xsuaa", xsuaa))
+		require.NoError(t, merger.Merge("ems", ems))
+		require.NoError(t, merger.Merge("creds", creds))
+		merged := merger.Result().(internal.ProvisioningOperation)
+
+		// then
+		assert.Equal(t, "xsapp", merged.XSUAA.XSAppname)
+		assert.Equal(t, "xsuaa-instance", merged.XSUAA.Instance.InstanceID)
+		assert.Equal(t, "ems-instance", merged.Ems.Instance.InstanceID)
+		assert.Equal(t, &target, merged.ProvisioningParameters.Parameters.TargetSecret)
+		assert.Equal(t, "ems provisioned", merged.Description)
+		assert.Equal(t, base.Version, merged.Version)
+		assert.Equal(t, base.Cls, merged.Cls)
+	})
+
+	t.Run("should give the same result for the same order of steps", func(t *testing.T) {
+		// given
+		base := fixture.FixProvisioningOperation("op-id", "instance-id")
+		first := base
+		first.Description = "first"
+		first.Lms.TenantID = "tenant"
+		second := base
+		second.Description = "second"
+		second.Lms.TenantID = "tenant"
+
+		for i := 0; i < 10; i++ {
+			// when
+			merger := NewParallelMerger(base)
+			require.NoError(t, merger.Merge("first", first))
+			require.NoError(t, merger.Merge("second", second))
+			merged := merger.Result().(internal.ProvisioningOperation)
+
+			// then
+			assert.Equal(t, "second", merged.Description)
+			assert.Equal(t, "tenant", merged.Lms.TenantID)
+		}
+	})
+
+	t.Run("should detect conflicting changes of instance details", func(t *testing.T) {
+		// given
+		base := fixture.FixProvisioningOperation("op-id", "instance-id")
+		first := base
+		first.Avs.AvsEvaluationInternalId = 1
+		second := base
+		second.Avs.AvsEvaluationInternalId = 2
+
+		// when
+		merger := NewParallelMerger(base)
+		require.NoError(t, merger.Merge("first", first))
+		err := merger.Merge("second", second)
+
+		// then
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Operation.InstanceDetails.Avs.AvsEvaluationInternalId")
+		assert.Contains(t, err.Error(), "first")
+		assert.Contains(t, err.Error(), "second")
+	})
+
+	t.Run("should merge changes of different map entries", func(t *testing.T) {
+		// given
+		base := fixture.FixProvisioningOperation("op-id", "instance-id")
+		base.StepAttempts = map[string]internal.StepAttempt{"removed": {Count: 1}, "kept": {Count: 1}}
+		first := base
+		first.StepAttempts = map[string]internal.StepAttempt{"kept": {Count: 1}, "first": {Count: 1}}
+		second := base
+		second.StepAttempts = map[string]internal.StepAttempt{"removed": {Count: 1}, "kept": {Count: 1}, "second": {Count: 2}}
+
+		// when
+		merger := NewParallelMerger(base)
+		require.NoError(t, merger.Merge("first", first))
+		require.NoError(t, merger.Merge("second", second))
+		merged := merger.Result().(internal.ProvisioningOperation)
+
+		// then
+		assert.Equal(t, map[string]internal.StepAttempt{"kept": {Count: 1}, "first": {Count: 1}, "second": {Count: 2}}, merged.StepAttempts)
+		assert.Len(t, base.StepAttempts, 2)
+	})
+
+	t.Run("should detect conflicting changes of a map entry", func(t *testing.T) {
+		// given
+		base := fixture.FixProvisioningOperation("op-id", "instance-id")
+		first := base
+		first.StepAttempts = map[string]internal.StepAttempt{"step": {Count: 1}}
+		second := base
+		second.StepAttempts = map[string]internal.StepAttempt{"step": {Count: 2}}
+
+		// when
+		merger := NewParallelMerger(base)
+		require.NoError(t, merger.Merge("first", first))
+		err := merger.Merge("second", second)
+
+		// then
+		assert.EqualError(t, err, "conflicting changes of Operation.StepAttempts[step] made by steps first and second")
+	})
+}
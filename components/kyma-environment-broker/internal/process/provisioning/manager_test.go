@@ -10,13 +10,17 @@ import (
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	inputAutomock "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/input/automock"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -68,7 +72,7 @@ func TestManager_Execute(t *testing.T) {
 
 			eventBroker := event.NewPubSub(logrus.New())
 			eventCollector := &collectingEventHandler{}
-			eventBroker.Subscribe(process.ProvisioningStepProcessed{}, eventCollector.OnEvent)
+			eventBroker.Subscribe("collector", process.ProvisioningStepProcessed{}, eventCollector.OnEvent)
 
 			manager := NewManager(memoryStorage.Operations(), eventBroker, log)
 			manager.InitStep(&sInit)
@@ -99,6 +103,249 @@ func TestManager_Execute(t *testing.T) {
 	}
 }
 
+func TestManager_ExecuteInParallel(t *testing.T) {
+	t.Run("should merge operations returned by steps of the same weight", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+		assert.NoError(t, err)
+
+		started := &sync.WaitGroup{}
+		started.Add(2)
+		sXSUAA := newParallelTestStep(t, "xsuaa", started, func(op *internal.ProvisioningOperation) {
+			op.XSUAA.XSAppname = "xsapp"
+		})
+		sEMS := newParallelTestStep(t, "ems", started, func(op *internal.ProvisioningOperation) {
+			op.Ems.BindingID = "ems-binding"
+		})
+		sFinal := testStep{name: "final", storage: memoryStorage.Operations()}
+
+		manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+		manager.EnableParallelSteps()
+		manager.AddStep(1, sXSUAA)
+		manager.AddStep(1, sEMS)
+		manager.AddStep(2, &sFinal)
+
+		// when
+		repeat, err := manager.Execute(operationIDSuccess)
+
+		// then
+		assert.NoError(t, err)
+		assert.Zero(t, repeat)
+
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+		assert.NoError(t, err)
+		assert.Equal(t, "xsapp", operation.XSUAA.XSAppname)
+		assert.Equal(t, "ems-binding", operation.Ems.BindingID)
+		assert.Equal(t, "ems final", strings.Trim(operation.Description, " "))
+	})
+
+	t.Run("should fail on conflicting changes of instance details", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+		assert.NoError(t, err)
+
+		started := &sync.WaitGroup{}
+		started.Add(2)
+		s1 := newParallelTestStep(t, "one", started, func(op *internal.ProvisioningOperation) {
+			op.Lms.TenantID = "tenant-1"
+		})
+		s2 := newParallelTestStep(t, "two", started, func(op *internal.ProvisioningOperation) {
+			op.Lms.TenantID = "tenant-2"
+		})
+
+		manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+		manager.EnableParallelSteps()
+		manager.AddStep(1, s1)
+		manager.AddStep(1, s2)
+
+		// when
+		_, err = manager.Execute(operationIDSuccess)
+
+		// then
+		assert.EqualError(t, err, "conflicting changes of Operation.InstanceDetails.Lms.TenantID made by steps one and two")
+	})
+}
+
+func TestManager_ExecuteInParallelWithInitialisationStep(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	operation := fixProvisionOperation(operationIDSuccess)
+	err := memoryStorage.Operations().InsertProvisioningOperation(operation)
+	assert.NoError(t, err)
+	instance := fixture.FixInstance(operation.InstanceID)
+	instance.RuntimeID = ""
+	err = memoryStorage.Instances().Insert(instance)
+	assert.NoError(t, err)
+
+	inputBuilder := &inputAutomock.CreatorForPlan{}
+	inputBuilder.On("CreateProvisionInput", mock.Anything, mock.Anything).Return(fixture.FixInputCreator(), nil)
+	initStep := NewInitialisationStep(memoryStorage.Operations(), memoryStorage.Instances(), nil, nil, inputBuilder,
+		nil, nil, nil, time.Hour, time.Hour, nil, servicemanager.NewClientFactory(servicemanager.Config{}))
+
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	assertDependencies := func(op *internal.ProvisioningOperation) {
+		assert.NotNil(t, op.InputCreator)
+		assert.NotNil(t, op.SMClientFactory)
+	}
+	sXSUAA := newParallelTestStep(t, "xsuaa", started, assertDependencies)
+	sEMS := newParallelTestStep(t, "ems", started, assertDependencies)
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.EnableParallelSteps()
+	manager.InitStep(initStep)
+	manager.AddStep(1, sXSUAA)
+	manager.AddStep(1, sEMS)
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, repeat)
+	inputBuilder.AssertExpectations(t)
+}
+
+func TestManager_ExecuteWithRetryPolicy(t *testing.T) {
+	t.Run("should store attempts and fail the operation when the attempts are exhausted", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+		assert.NoError(t, err)
+
+		step := &retryTestStep{name: "retry", failures: 3, policy: process.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}}
+		manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+		manager.AddStep(1, step)
+
+		for attempt, expectedRepeat := range []time.Duration{time.Second, 2 * time.Second} {
+			// when
+			repeat, err := manager.Execute(operationIDSuccess)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, expectedRepeat, repeat)
+			operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+			assert.NoError(t, err)
+			assert.Equal(t, attempt+1, operation.StepAttempts["retry"].Count)
+		}
+
+		// when
+		_, err = manager.Execute(operationIDSuccess)
+
+		// then
+		assert.Error(t, err)
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Failed, operation.State)
+		assert.Contains(t, operation.Description, "step retry exceeded 3 attempt(s): attempt 3 failed")
+	})
+
+	t.Run("should remove attempts when the step succeeds", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+		assert.NoError(t, err)
+
+		step := &retryTestStep{name: "retry", failures: 1, policy: process.RetryPolicy{MaxAttempts: 3}}
+		sFinal := testStep{name: "final", storage: memoryStorage.Operations()}
+		manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+		manager.AddStep(1, step)
+		manager.AddStep(2, &sFinal)
+
+		// when
+		repeat, err := manager.Execute(operationIDSuccess)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Second, repeat)
+		repeat, err = manager.Execute(operationIDSuccess)
+
+		// then
+		assert.NoError(t, err)
+		assert.Zero(t, repeat)
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+		assert.NoError(t, err)
+		assert.Empty(t, operation.StepAttempts)
+		assert.Equal(t, "final", strings.Trim(operation.Description, " "))
+	})
+
+	t.Run("should skip the skippable step when the attempts are exhausted", func(t *testing.T) {
+		// given
+		memoryStorage := storage.NewMemoryStorage()
+		err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+		assert.NoError(t, err)
+
+		step := &retryTestStep{name: "retry", failures: 1, policy: process.RetryPolicy{MaxAttempts: 1, Skippable: true}}
+		sFinal := testStep{name: "final", storage: memoryStorage.Operations()}
+		eventBroker := event.NewPubSub(logrus.New())
+		eventCollector := &collectingEventHandler{}
+		eventBroker.Subscribe("collector", process.ProvisioningStepProcessed{}, eventCollector.OnEvent)
+		manager := NewManager(memoryStorage.Operations(), eventBroker, logrus.New())
+		manager.AddStep(1, step)
+		manager.AddStep(2, &sFinal)
+
+		// when
+		repeat, err := manager.Execute(operationIDSuccess)
+
+		// then
+		assert.NoError(t, err)
+		assert.Zero(t, repeat)
+		operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.InProgress, operation.State)
+		assert.Equal(t, "final", strings.Trim(operation.Description, " "))
+
+		assert.NoError(t, wait.PollImmediate(20*time.Millisecond, 2*time.Second, func() (bool, error) {
+			eventCollector.mu.Lock()
+			defer eventCollector.mu.Unlock()
+			if len(eventCollector.Events) != 2 {
+				return false, nil
+			}
+			for _, ev := range eventCollector.Events {
+				processed := ev.(process.ProvisioningStepProcessed)
+				if processed.StepName == "retry" {
+					return processed.Attempt == 1 && processed.Exhausted && processed.Error == nil, nil
+				}
+			}
+			return false, nil
+		}))
+	})
+}
+
+func TestManager_ExecuteWithDefaultRetryPolicy(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertProvisioningOperation(fixProvisionOperation(operationIDSuccess))
+	assert.NoError(t, err)
+
+	step := &temporaryErrorTestStep{name: "temporary", failures: 1}
+	sFinal := testStep{name: "final", storage: memoryStorage.Operations()}
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.AddStep(1, step)
+	manager.AddStep(2, &sFinal)
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, process.DefaultRetryPolicy.Backoff, repeat)
+	operation, err := memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, operation.StepAttempts["temporary"].Count)
+
+	// when
+	repeat, err = manager.Execute(operationIDSuccess)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, repeat)
+	operation, err = memoryStorage.Operations().GetProvisioningOperationByID(operationIDSuccess)
+	assert.NoError(t, err)
+	assert.Empty(t, operation.StepAttempts)
+	assert.Equal(t, "final", strings.Trim(operation.Description, " "))
+}
+
 func fixProvisionOperation(ID string) internal.ProvisioningOperation {
 	provisioningOperation := fixture.FixProvisioningOperation(ID, "fea2c1a1-139d-43f6-910a-a618828a79d5")
 	provisioningOperation.State = domain.InProgress
@@ -137,6 +384,49 @@ func (ts *testStep) Run(operation internal.ProvisioningOperation, logger logrus.
 	}
 }
 
+// retryTestStep fails the given number of times, the attempts are counted by the manager
+type retryTestStep struct {
+	name     string
+	failures int
+	runs     int
+	policy   process.RetryPolicy
+}
+
+func (s *retryTestStep) Name() string {
+	return s.name
+}
+
+func (s *retryTestStep) RetryPolicy() process.RetryPolicy {
+	return s.policy
+}
+
+func (s *retryTestStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	s.runs++
+	if s.runs <= s.failures {
+		return operation, 0, fmt.Errorf("attempt %d failed", s.runs)
+	}
+	return operation, 0, nil
+}
+
+// temporaryErrorTestStep returns a temporary error the given number of times and does not declare a retry policy
+type temporaryErrorTestStep struct {
+	name     string
+	failures int
+	runs     int
+}
+
+func (s *temporaryErrorTestStep) Name() string {
+	return s.name
+}
+
+func (s *temporaryErrorTestStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	s.runs++
+	if s.runs <= s.failures {
+		return operation, 0, kebError.NewTemporaryError("attempt %d failed", s.runs)
+	}
+	return operation, 0, nil
+}
+
 type collectingEventHandler struct {
 	mu     sync.Mutex
 	Events []interface{}
@@ -149,3 +439,43 @@ func (h *collectingEventHandler) OnEvent(ctx context.Context, ev interface{}) er
 	h.Events = append(h.Events, ev)
 	return nil
 }
+
+// parallelTestStep waits until all steps of the same weight are started, so it blocks if the steps are run sequentially
+type parallelTestStep struct {
+	t       *testing.T
+	name    string
+	started *sync.WaitGroup
+	update  func(op *internal.ProvisioningOperation)
+}
+
+func newParallelTestStep(t *testing.T, name string, started *sync.WaitGroup, update func(op *internal.ProvisioningOperation)) *parallelTestStep {
+	return &parallelTestStep{
+		t:       t,
+		name:    name,
+		started: started,
+		update:  update,
+	}
+}
+
+func (s *parallelTestStep) Name() string {
+	return s.name
+}
+
+func (s *parallelTestStep) Run(operation internal.ProvisioningOperation, logger logrus.FieldLogger) (internal.ProvisioningOperation, time.Duration, error) {
+	s.started.Done()
+	done := make(chan struct{})
+	go func() {
+		s.started.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.t.Errorf("step %s was not run in parallel", s.name)
+	}
+
+	s.update(&operation)
+	operation.Description = fmt.Sprintf("%s %s", operation.Description, s.name)
+
+	return operation, 0, nil
+}
@@ -0,0 +1,155 @@
+package process
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+)
+
+// RetryPolicy declares how the manager repeats a step which returned an error or requested a retry.
+// The policy is enforced by the manager, so the step does not need to count its attempts.
+type RetryPolicy struct {
+	// MaxAttempts limits the number of runs of the step, 0 means no limit
+	MaxAttempts int
+	// Backoff is the interval before the first retry, it is doubled with every attempt up to MaxBackoff.
+	// The interval returned by the step is used if Backoff is not set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout limits the time since the first run of the step, 0 means no limit
+	Timeout time.Duration
+	// Skippable steps which ran out of attempts are skipped, other steps fail the operation
+	Skippable bool
+	// ErrorsOnly limits the policy to the runs of the step which returned an error. The runs which request a retry
+	// without an error, e.g. while waiting for an external operation, are repeated after the requested interval
+	// and are not counted as attempts.
+	ErrorsOnly bool
+}
+
+// NewFixedRetryPolicy returns the policy which repeats the step returning an error every interval until the timeout
+// is exceeded. The runs of the step which request a retry without an error are not counted.
+func NewFixedRetryPolicy(interval, timeout time.Duration) RetryPolicy {
+	return RetryPolicy{
+		Backoff:    interval,
+		MaxBackoff: interval,
+		Timeout:    timeout,
+		ErrorsOnly: true,
+	}
+}
+
+// StepWithRetryPolicy is implemented by steps which declare their retry policy
+type StepWithRetryPolicy interface {
+	RetryPolicy() RetryPolicy
+}
+
+// DefaultRetryPolicy is applied by the managers to the steps which do not declare their retry policy and return
+// a temporary error. Such steps are repeated every 10 seconds for up to 30 minutes, other errors fail the operation.
+var DefaultRetryPolicy = NewFixedRetryPolicy(10*time.Second, 30*time.Minute)
+
+// RetryPolicyOf returns the retry policy the manager applies to the result of the step. Steps which do not declare
+// their policy get the default policy when they return a temporary error or have attempts left by such an error.
+func RetryPolicyOf(step interface{ Name() string }, attempts map[string]internal.StepAttempt, err error) (RetryPolicy, bool) {
+	if s, ok := step.(StepWithRetryPolicy); ok {
+		return s.RetryPolicy(), true
+	}
+	if _, found := attempts[step.Name()]; found || kebError.IsTemporaryError(err) {
+		return DefaultRetryPolicy, true
+	}
+	return RetryPolicy{}, false
+}
+
+// RetryDecision is the result of the retry policy applied to the step result
+type RetryDecision struct {
+	// StepAttempt must be stored in the operation, nil means the attempts of the step must be removed
+	StepAttempt *internal.StepAttempt
+	// Updated is set when the step attempts stored in the operation must be changed
+	Updated bool
+	// Attempt is the number of the current run of the step
+	Attempt int
+	// When is the interval after which the step must be retried, 0 if the step must not be retried
+	When time.Duration
+	// Exhausted is set when the step ran out of attempts or exceeded its timeout
+	Exhausted bool
+	// Reason describes why the step ran out of attempts
+	Reason string
+}
+
+// Decide applies the policy to the result of the step. Steps which succeeded have their attempts removed.
+func (p RetryPolicy) Decide(stepName string, attempts map[string]internal.StepAttempt, when time.Duration, err error, now time.Time) RetryDecision {
+	if p.ErrorsOnly && err == nil && when > 0 {
+		return RetryDecision{When: when}
+	}
+
+	previous, found := attempts[stepName]
+	current := previous
+	current.Count++
+	if current.StartedAt.IsZero() {
+		current.StartedAt = now
+	}
+
+	decision := RetryDecision{
+		Attempt: current.Count,
+	}
+	if err == nil && when == 0 {
+		decision.Updated = found
+		return decision
+	}
+
+	switch {
+	case p.MaxAttempts > 0 && current.Count >= p.MaxAttempts:
+		decision.Exhausted = true
+		decision.Reason = fmt.Sprintf("step %s exceeded %d attempt(s)", stepName, p.MaxAttempts)
+	case p.Timeout > 0 && now.Sub(current.StartedAt) >= p.Timeout:
+		decision.Exhausted = true
+		decision.Reason = fmt.Sprintf("step %s exceeded timeout %s", stepName, p.Timeout)
+	}
+	if decision.Exhausted {
+		if err != nil {
+			decision.Reason = fmt.Sprintf("%s: %s", decision.Reason, err)
+		}
+		decision.Updated = found
+		return decision
+	}
+
+	decision.When = p.backoff(current.Count, when)
+	decision.StepAttempt = &current
+	decision.Updated = true
+	return decision
+}
+
+func (p RetryPolicy) backoff(attempt int, when time.Duration) time.Duration {
+	if p.Backoff == 0 {
+		if when == 0 {
+			return time.Second
+		}
+		return when
+	}
+
+	backoff := p.Backoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// WithStepAttempt returns a copy of the attempts with the attempt of the given step replaced, or removed if nil.
+// The attempts are copied, because the operation passed to the steps must not be modified.
+func WithStepAttempt(attempts map[string]internal.StepAttempt, stepName string, attempt *internal.StepAttempt) map[string]internal.StepAttempt {
+	result := make(map[string]internal.StepAttempt, len(attempts)+1)
+	for name, a := range attempts {
+		result[name] = a
+	}
+	if attempt == nil {
+		delete(result, stepName)
+	} else {
+		result[stepName] = *attempt
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
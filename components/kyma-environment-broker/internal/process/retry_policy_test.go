@@ -0,0 +1,173 @@
+package process
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Decide(t *testing.T) {
+	now := time.Now()
+	someErr := errors.New("some error")
+
+	for name, tc := range map[string]struct {
+		policy   RetryPolicy
+		attempts map[string]internal.StepAttempt
+		when     time.Duration
+		err      error
+		expected RetryDecision
+	}{
+		"first failure": {
+			policy: RetryPolicy{MaxAttempts: 3, Backoff: time.Second},
+			err:    someErr,
+			expected: RetryDecision{
+				StepAttempt: &internal.StepAttempt{Count: 1, StartedAt: now},
+				Updated:     true,
+				Attempt:     1,
+				When:        time.Second,
+			},
+		},
+		"backoff is doubled up to max backoff": {
+			policy:   RetryPolicy{Backoff: time.Second, MaxBackoff: 3 * time.Second},
+			attempts: map[string]internal.StepAttempt{"step": {Count: 2, StartedAt: now.Add(-time.Minute)}},
+			err:      someErr,
+			expected: RetryDecision{
+				StepAttempt: &internal.StepAttempt{Count: 3, StartedAt: now.Add(-time.Minute)},
+				Updated:     true,
+				Attempt:     3,
+				When:        3 * time.Second,
+			},
+		},
+		"interval requested by the step without backoff": {
+			policy: RetryPolicy{MaxAttempts: 3},
+			when:   5 * time.Second,
+			expected: RetryDecision{
+				StepAttempt: &internal.StepAttempt{Count: 1, StartedAt: now},
+				Updated:     true,
+				Attempt:     1,
+				When:        5 * time.Second,
+			},
+		},
+		"attempts exhausted": {
+			policy:   RetryPolicy{MaxAttempts: 3, Backoff: time.Second},
+			attempts: map[string]internal.StepAttempt{"step": {Count: 2, StartedAt: now}},
+			err:      someErr,
+			expected: RetryDecision{
+				Updated:   true,
+				Attempt:   3,
+				Exhausted: true,
+				Reason:    "step step exceeded 3 attempt(s): some error",
+			},
+		},
+		"timeout exceeded": {
+			policy:   RetryPolicy{Timeout: time.Minute},
+			attempts: map[string]internal.StepAttempt{"step": {Count: 1, StartedAt: now.Add(-time.Hour)}},
+			when:     time.Second,
+			expected: RetryDecision{
+				Updated:   true,
+				Attempt:   2,
+				Exhausted: true,
+				Reason:    "step step exceeded timeout 1m0s",
+			},
+		},
+		"success removes attempts": {
+			policy:   RetryPolicy{MaxAttempts: 3},
+			attempts: map[string]internal.StepAttempt{"step": {Count: 1, StartedAt: now}},
+			expected: RetryDecision{
+				Updated: true,
+				Attempt: 2,
+			},
+		},
+		"retry requested without error is not counted by errors only policy": {
+			policy:   NewFixedRetryPolicy(10*time.Second, time.Minute),
+			attempts: map[string]internal.StepAttempt{"step": {Count: 1, StartedAt: now.Add(-time.Hour)}},
+			when:     time.Second,
+			expected: RetryDecision{
+				When: time.Second,
+			},
+		},
+		"fixed interval on error": {
+			policy:   NewFixedRetryPolicy(10*time.Second, time.Minute),
+			attempts: map[string]internal.StepAttempt{"step": {Count: 3, StartedAt: now}},
+			err:      someErr,
+			expected: RetryDecision{
+				StepAttempt: &internal.StepAttempt{Count: 4, StartedAt: now},
+				Updated:     true,
+				Attempt:     4,
+				When:        10 * time.Second,
+			},
+		},
+		"success without attempts": {
+			policy:   RetryPolicy{MaxAttempts: 3},
+			expected: RetryDecision{Attempt: 1},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			decision := tc.policy.Decide("step", tc.attempts, tc.when, tc.err, now)
+
+			// then
+			assert.Equal(t, tc.expected, decision)
+		})
+	}
+}
+
+func TestWithStepAttempt(t *testing.T) {
+	// given
+	attempts := map[string]internal.StepAttempt{"first": {Count: 1}}
+
+	// when
+	added := WithStepAttempt(attempts, "second", &internal.StepAttempt{Count: 2})
+	removed := WithStepAttempt(attempts, "first", nil)
+
+	// then
+	assert.Equal(t, map[string]internal.StepAttempt{"first": {Count: 1}, "second": {Count: 2}}, added)
+	assert.Nil(t, removed)
+	assert.Equal(t, map[string]internal.StepAttempt{"first": {Count: 1}}, attempts)
+}
+
+func TestRetryPolicyOf(t *testing.T) {
+	// given
+	policy := NewFixedRetryPolicy(time.Second, time.Minute)
+	attempts := map[string]internal.StepAttempt{"retried": {Count: 1}}
+
+	// when
+	declared, declaredFound := RetryPolicyOf(stepWithPolicy{name: "declared", policy: policy}, nil, nil)
+	temporary, temporaryFound := RetryPolicyOf(namedStep{name: "temporary"}, nil, kebError.NewTemporaryError("temporary"))
+	retried, retriedFound := RetryPolicyOf(namedStep{name: "retried"}, attempts, nil)
+	_, failedFound := RetryPolicyOf(namedStep{name: "failed"}, attempts, errors.New("failed"))
+
+	// then
+	assert.True(t, declaredFound)
+	assert.Equal(t, policy, declared)
+	assert.True(t, temporaryFound)
+	assert.Equal(t, DefaultRetryPolicy, temporary)
+	assert.True(t, retriedFound)
+	assert.Equal(t, DefaultRetryPolicy, retried)
+	assert.False(t, failedFound)
+}
+
+type namedStep struct {
+	name string
+}
+
+func (s namedStep) Name() string {
+	return s.name
+}
+
+type stepWithPolicy struct {
+	name   string
+	policy RetryPolicy
+}
+
+func (s stepWithPolicy) Name() string {
+	return s.name
+}
+
+func (s stepWithPolicy) RetryPolicy() RetryPolicy {
+	return s.policy
+}
@@ -48,7 +48,7 @@ func (m *Manager) AddStep(weight int, step Step) {
 func (m *Manager) runStep(step Step, operation internal.UpgradeClusterOperation, logger logrus.FieldLogger) (internal.UpgradeClusterOperation, time.Duration, error) {
 	start := time.Now()
 	processedOperation, when, err := step.Run(operation, logger)
-	m.publisher.Publish(context.TODO(), process.UpgradeClusterStepProcessed{
+	publishErr := m.publisher.Publish(context.TODO(), process.UpgradeClusterStepProcessed{
 		OldOperation: operation,
 		Operation:    processedOperation,
 		StepProcessed: process.StepProcessed{
@@ -58,6 +58,9 @@ func (m *Manager) runStep(step Step, operation internal.UpgradeClusterOperation,
 			Error:    err,
 		},
 	})
+	if publishErr != nil {
+		logger.Errorf("while publishing processed step %s: %v", step.Name(), publishErr)
+	}
 	return processedOperation, when, err
 }
 
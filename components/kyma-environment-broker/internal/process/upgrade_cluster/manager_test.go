@@ -70,7 +70,7 @@ func TestManager_Execute(t *testing.T) {
 
 			eventBroker := event.NewPubSub(logrus.New())
 			eventCollector := &collectingEventHandler{}
-			eventBroker.Subscribe(process.UpgradeClusterStepProcessed{}, eventCollector.OnEvent)
+			eventBroker.Subscribe("collector", process.UpgradeClusterStepProcessed{}, eventCollector.OnEvent)
 
 			manager := NewManager(operations, eventBroker, log)
 			manager.InitStep(&sInit)
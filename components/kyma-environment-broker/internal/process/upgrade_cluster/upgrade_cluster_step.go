@@ -125,6 +125,16 @@ func (s *UpgradeClusterStep) createUpgradeShootInput(operation internal.UpgradeC
 		return input, errors.Wrap(err, "while building upgradeShootInput for provisioner")
 	}
 
+	// versions requested by the orchestration take precedence over the configured ones
+	if input.GardenerConfig != nil {
+		if operation.Kubernetes.KubernetesVersion != "" {
+			input.GardenerConfig.KubernetesVersion = &operation.Kubernetes.KubernetesVersion
+		}
+		if operation.Kubernetes.MachineImageVersion != "" {
+			input.GardenerConfig.MachineImageVersion = &operation.Kubernetes.MachineImageVersion
+		}
+	}
+
 	return input, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process/input"
@@ -71,6 +72,43 @@ func TestUpgradeKymaStep_Run(t *testing.T) {
 	assert.Equal(t, fixProvisionerOperationID, operation.ProvisionerOperationID)
 }
 
+func TestUpgradeClusterStep_RunWithRequestedVersions(t *testing.T) {
+	// given
+	log := logrus.New()
+	memoryStorage := storage.NewMemoryStorage()
+
+	operation := fixUpgradeClusterOperationWithInputCreator(t)
+	operation.Kubernetes = orchestration.KubernetesParameters{
+		KubernetesVersion:   "1.18.14",
+		MachineImageVersion: "27.1.0",
+	}
+	err := memoryStorage.Operations().InsertUpgradeClusterOperation(operation)
+	assert.NoError(t, err)
+
+	provisionerClient := &provisionerAutomock.Client{}
+	provisionerClient.On("UpgradeShoot", fixGlobalAccountID, fixRuntimeID, gqlschema.UpgradeShootInput{
+		GardenerConfig: &gqlschema.GardenerUpgradeInput{
+			KubernetesVersion:   ptr.String("1.18.14"),
+			MachineImage:        ptr.String(fixMachineImage),
+			MachineImageVersion: ptr.String("27.1.0"),
+		},
+	}).Return(gqlschema.OperationStatus{
+		ID:        StringPtr(fixProvisionerOperationID),
+		RuntimeID: StringPtr(fixRuntimeID),
+	}, nil)
+
+	step := NewUpgradeClusterStep(memoryStorage.Operations(), memoryStorage.RuntimeStates(), provisionerClient, nil)
+
+	// when
+	operation, repeat, err := step.Run(operation, log.WithFields(logrus.Fields{"step": "TEST"}))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, repeat)
+	assert.Equal(t, fixProvisionerOperationID, operation.ProvisionerOperationID)
+	provisionerClient.AssertExpectations(t)
+}
+
 func fixUpgradeClusterOperationWithInputCreator(t *testing.T) internal.UpgradeClusterOperation {
 	upgradeOperation := fixture.FixUpgradeClusterOperation(fixUpgradeOperationID, fixInstanceID)
 	upgradeOperation.Description = ""
@@ -12,6 +12,7 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/servicemanager"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v7/domain"
 	"github.com/sirupsen/logrus"
@@ -68,7 +69,7 @@ func TestManager_Execute(t *testing.T) {
 
 			eventBroker := event.NewPubSub(logrus.New())
 			eventCollector := &collectingEventHandler{}
-			eventBroker.Subscribe(process.UpgradeKymaStepProcessed{}, eventCollector.OnEvent)
+			eventBroker.Subscribe("collector", process.UpgradeKymaStepProcessed{}, eventCollector.OnEvent)
 
 			manager := NewManager(operations, eventBroker, log)
 			manager.InitStep(&sInit)
@@ -98,6 +99,70 @@ func TestManager_Execute(t *testing.T) {
 	}
 }
 
+func TestManager_ExecuteInParallel(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertUpgradeKymaOperation(fixOperation(operationIDSuccess))
+	assert.NoError(t, err)
+
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	s1 := &parallelTestStep{t: t, name: "one", started: started, update: func(op *internal.UpgradeKymaOperation) time.Duration {
+		op.Ems.BindingID = "ems-binding"
+		return 0
+	}}
+	s2 := &parallelTestStep{t: t, name: "two", started: started, update: func(op *internal.UpgradeKymaOperation) time.Duration {
+		op.Cls.BindingID = "cls-binding"
+		return 5 * time.Second
+	}}
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.EnableParallelSteps()
+	manager.AddStep(1, s1)
+	manager.AddStep(1, s2)
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, repeat)
+
+	operation, err := memoryStorage.Operations().GetUpgradeKymaOperationByID(operationIDSuccess)
+	assert.NoError(t, err)
+	assert.Equal(t, "ems-binding", operation.Ems.BindingID)
+	assert.Equal(t, "cls-binding", operation.Cls.BindingID)
+}
+
+func TestManager_ExecuteInParallelAfterInitStep(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	err := memoryStorage.Operations().InsertUpgradeKymaOperation(fixOperation(operationIDSuccess))
+	assert.NoError(t, err)
+
+	started := &sync.WaitGroup{}
+	started.Add(2)
+	assertDependencies := func(op *internal.UpgradeKymaOperation) time.Duration {
+		assert.NotNil(t, op.SMClientFactory)
+		return 0
+	}
+	s1 := &parallelTestStep{t: t, name: "one", started: started, update: assertDependencies}
+	s2 := &parallelTestStep{t: t, name: "two", started: started, update: assertDependencies}
+
+	manager := NewManager(memoryStorage.Operations(), event.NewPubSub(logrus.New()), logrus.New())
+	manager.EnableParallelSteps()
+	manager.InitStep(&initTestStep{})
+	manager.AddStep(1, s1)
+	manager.AddStep(1, s2)
+
+	// when
+	repeat, err := manager.Execute(operationIDSuccess)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, repeat)
+}
+
 func fixOperation(ID string) internal.UpgradeKymaOperation {
 	upgradeOperation := fixture.FixUpgradeKymaOperation(ID, "fea2c1a1-139d-43f6-910a-a618828a79d5")
 	upgradeOperation.State = domain.InProgress
@@ -147,3 +212,44 @@ func (h *collectingEventHandler) OnEvent(ctx context.Context, ev interface{}) er
 	h.Events = append(h.Events, ev)
 	return nil
 }
+
+// parallelTestStep waits until all steps of the same weight are started, so it blocks if the steps are run sequentially
+type parallelTestStep struct {
+	t       *testing.T
+	name    string
+	started *sync.WaitGroup
+	update  func(op *internal.UpgradeKymaOperation) time.Duration
+}
+
+func (s *parallelTestStep) Name() string {
+	return s.name
+}
+
+func (s *parallelTestStep) Run(operation internal.UpgradeKymaOperation, logger logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	s.started.Done()
+	done := make(chan struct{})
+	go func() {
+		s.started.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.t.Errorf("step %s was not run in parallel", s.name)
+	}
+
+	when := s.update(&operation)
+	return operation, when, nil
+}
+
+// initTestStep sets the dependencies of the operation, which are not stored, like the initialisation step
+type initTestStep struct{}
+
+func (s *initTestStep) Name() string {
+	return "init"
+}
+
+func (s *initTestStep) Run(operation internal.UpgradeKymaOperation, logger logrus.FieldLogger) (internal.UpgradeKymaOperation, time.Duration, error) {
+	operation.SMClientFactory = servicemanager.NewFakeServiceManagerClientFactory(nil, nil)
+	return operation, 0, nil
+}
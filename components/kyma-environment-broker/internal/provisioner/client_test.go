@@ -539,6 +539,10 @@ func (tqr testQueryResolver) RuntimeStatus(_ context.Context, id string) (*schem
 	return nil, nil
 }
 
+func (tqr testQueryResolver) RuntimeKubeconfig(_ context.Context, id string) (*string, error) {
+	return nil, nil
+}
+
 func (tqr testQueryResolver) RuntimeOperationStatus(_ context.Context, id string) (*schema.OperationStatus, error) {
 	tqr.t.Log("RuntimeOperationStatus - testQueryResolver")
 
@@ -0,0 +1,214 @@
+package runtime
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ContextUpdateHandler performs the suspension and unsuspension of trial runtimes on the change of the ERS context
+type ContextUpdateHandler interface {
+	Handle(instance *internal.Instance, newCtx internal.ERSContext) error
+}
+
+// Deprovisioner starts the deprovisioning of an instance, it is implemented by the OSB API deprovisioning endpoint
+type Deprovisioner interface {
+	Deprovision(ctx context.Context, instanceID string, details domain.DeprovisionDetails, asyncAllowed bool) (domain.DeprovisionServiceSpec, error)
+}
+
+// Reprovisioner reprovisions the trial runtime of an instance by its suspension followed by the unsuspension
+type Reprovisioner interface {
+	Reprovision(instance *internal.Instance) (string, error)
+}
+
+// ActionHandler exposes the operator actions on the runtimes, separately from the OSB API.
+// The actions reuse the logic of the OSB API, and are recorded as audit events of the caller.
+type ActionHandler struct {
+	instances     storage.Instances
+	operations    storage.Operations
+	suspension    ContextUpdateHandler
+	deprovisioner Deprovisioner
+	reprovisioner Reprovisioner
+	auditor       *audit.Recorder
+	log           logrus.FieldLogger
+}
+
+func NewActionHandler(instances storage.Instances, operations storage.Operations, suspension ContextUpdateHandler, deprovisioner Deprovisioner, reprovisioner Reprovisioner, auditor *audit.Recorder, log logrus.FieldLogger) *ActionHandler {
+	return &ActionHandler{
+		instances:     instances,
+		operations:    operations,
+		suspension:    suspension,
+		deprovisioner: deprovisioner,
+		reprovisioner: reprovisioner,
+		auditor:       auditor,
+		log:           log,
+	}
+}
+
+func (h *ActionHandler) AttachRoutes(router *mux.Router) {
+	router.HandleFunc("/runtimes/{instance_id}/suspend", h.suspend).Methods(http.MethodPut)
+	router.HandleFunc("/runtimes/{instance_id}/unsuspend", h.unsuspend).Methods(http.MethodPut)
+	router.HandleFunc("/runtimes/{instance_id}/deprovision", h.deprovision).Methods(http.MethodPut)
+	router.HandleFunc("/runtimes/{instance_id}/reprovision", h.reprovision).Methods(http.MethodPut)
+}
+
+func (h *ActionHandler) suspend(w http.ResponseWriter, r *http.Request) {
+	h.runAction(w, r, auditExt.SuspendRuntimeAction, func(_ context.Context, instance *internal.Instance) (string, error) {
+		return h.setActive(instance, false)
+	})
+}
+
+func (h *ActionHandler) unsuspend(w http.ResponseWriter, r *http.Request) {
+	h.runAction(w, r, auditExt.UnsuspendRuntimeAction, func(_ context.Context, instance *internal.Instance) (string, error) {
+		return h.setActive(instance, true)
+	})
+}
+
+func (h *ActionHandler) deprovision(w http.ResponseWriter, r *http.Request) {
+	h.runAction(w, r, auditExt.DeprovisionRuntimeAction, func(ctx context.Context, instance *internal.Instance) (string, error) {
+		spec, err := h.deprovisioner.Deprovision(ctx, instance.InstanceID, domain.DeprovisionDetails{
+			PlanID:    instance.ServicePlanID,
+			ServiceID: instance.ServiceID,
+		}, true)
+		if err != nil {
+			return "", errors.Wrap(err, "while deprovisioning")
+		}
+		return spec.OperationData, nil
+	})
+}
+
+func (h *ActionHandler) reprovision(w http.ResponseWriter, r *http.Request) {
+	h.runAction(w, r, auditExt.ReprovisionRuntimeAction, func(_ context.Context, instance *internal.Instance) (string, error) {
+		if !broker.IsTrialPlan(instance.ServicePlanID) {
+			return "", apiErrors.NewBadRequest("only trial runtimes can be reprovisioned")
+		}
+		isActive, err := h.isActive(instance.InstanceID)
+		if err != nil {
+			return "", err
+		}
+		if !isActive {
+			return "", apiErrors.NewBadRequest("the runtime is suspended, unsuspend it instead")
+		}
+
+		operationID, err := h.reprovisioner.Reprovision(instance)
+		if err != nil {
+			return "", errors.Wrap(err, "while reprovisioning")
+		}
+		return operationID, nil
+	})
+}
+
+// runAction runs the action on the instance given in the request, records it as an audit event, and responds with the started operation
+func (h *ActionHandler) runAction(w http.ResponseWriter, r *http.Request, action auditExt.Action, run func(ctx context.Context, instance *internal.Instance) (string, error)) {
+	instanceID := mux.Vars(r)["instance_id"]
+	target := audit.Target{InstanceID: instanceID}
+
+	var operationID string
+	instance, err := h.instances.GetByID(instanceID)
+	if err != nil {
+		err = errors.Wrapf(err, "while getting instance %s", instanceID)
+	} else {
+		target.RuntimeID = instance.RuntimeID
+		operationID, err = run(r.Context(), instance)
+	}
+
+	h.auditor.Record(r.Context(), action, target, nil, err)
+	if err != nil {
+		h.log.Errorf("while running %s action on instance %s: %v", action, instanceID, err)
+		httputil.WriteErrorResponse(w, resolveErrorStatus(err), err)
+		return
+	}
+
+	httputil.WriteResponse(w, http.StatusAccepted, pkg.ActionResponse{
+		InstanceID:  instanceID,
+		OperationID: operationID,
+	})
+}
+
+// setActive suspends or unsuspends the trial runtime in the same way as the context update of the OSB API,
+// and returns the ID of the latest suspension or unsuspension operation
+func (h *ActionHandler) setActive(instance *internal.Instance, active bool) (string, error) {
+	if !broker.IsTrialPlan(instance.ServicePlanID) {
+		return "", apiErrors.NewBadRequest("only trial runtimes can be suspended and unsuspended")
+	}
+
+	isActive, err := h.isActive(instance.InstanceID)
+	if err != nil {
+		return "", err
+	}
+	if isActive && active {
+		return "", apiErrors.NewBadRequest("the runtime is not suspended")
+	}
+	if !isActive && !active {
+		return "", apiErrors.NewConflict(schema.GroupResource{Resource: "runtimes"}, instance.InstanceID, errors.New("the runtime is already suspended"))
+	}
+
+	instance.Parameters.ErsContext.Active = &isActive
+	err = h.suspension.Handle(instance, internal.ERSContext{Active: &active})
+	if err != nil {
+		return "", errors.Wrap(err, "while handling the context update")
+	}
+	instance.Parameters.ErsContext.Active = &active
+	_, err = h.instances.Update(*instance)
+	if err != nil {
+		return "", errors.Wrap(err, "while updating instance")
+	}
+
+	if active {
+		operation, err := h.operations.GetProvisioningOperationByInstanceID(instance.InstanceID)
+		if err != nil {
+			return "", errors.Wrap(err, "while getting unsuspension operation")
+		}
+		return operation.ID, nil
+	}
+	operation, err := h.operations.GetDeprovisioningOperationByInstanceID(instance.InstanceID)
+	if err != nil {
+		return "", errors.Wrap(err, "while getting suspension operation")
+	}
+	return operation.ID, nil
+}
+
+// isActive tells whether the runtime of the instance is active, which means it was not suspended after its last (un)suspension
+func (h *ActionHandler) isActive(instanceID string) (bool, error) {
+	provisioning, err := h.operations.GetProvisioningOperationByInstanceID(instanceID)
+	if err != nil {
+		return false, errors.Wrap(err, "while getting provisioning operation")
+	}
+	deprovisioning, err := h.operations.GetDeprovisioningOperationByInstanceID(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return true, nil
+	case err != nil:
+		return false, errors.Wrap(err, "while getting deprovisioning operation")
+	}
+
+	return deprovisioning.CreatedAt.Before(provisioning.CreatedAt), nil
+}
+
+func resolveErrorStatus(err error) int {
+	cause := errors.Cause(err)
+	switch {
+	case dberr.IsNotFound(cause):
+		return http.StatusNotFound
+	case apiErrors.IsBadRequest(cause):
+		return http.StatusBadRequest
+	case apiErrors.IsConflict(cause):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,262 @@
+package runtime_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	auditExt "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	pkg "github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/runtime"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/suspension"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionHandler(t *testing.T) {
+	t.Run("should suspend trial runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+
+		// when
+		rr := putAction(router, "inst-1", "suspend")
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		response := decodeActionResponse(t, rr)
+		assert.Equal(t, "inst-1", response.InstanceID)
+		require.Len(t, queue.ids, 1)
+		assert.Equal(t, queue.ids[0], response.OperationID)
+
+		operation, err := db.Operations().GetDeprovisioningOperationByID(response.OperationID)
+		require.NoError(t, err)
+		assert.True(t, operation.Temporary)
+
+		instance, err := db.Instances().GetByID("inst-1")
+		require.NoError(t, err)
+		require.NotNil(t, instance.Parameters.ErsContext.Active)
+		assert.False(t, *instance.Parameters.ErsContext.Active)
+
+		assertAuditEvent(t, db, auditExt.SuspendRuntimeAction, auditExt.Succeeded)
+	})
+
+	t.Run("should unsuspend suspended trial runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+		suspensionOp := fixture.FixDeprovisioningOperation("suspension-1", "inst-1")
+		suspensionOp.Temporary = true
+		suspensionOp.CreatedAt = time.Now().Add(time.Minute)
+		require.NoError(t, db.Operations().InsertDeprovisioningOperation(suspensionOp))
+
+		// when
+		rr := putAction(router, "inst-1", "unsuspend")
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		response := decodeActionResponse(t, rr)
+		require.Len(t, queue.ids, 1)
+		assert.Equal(t, queue.ids[0], response.OperationID)
+
+		_, err := db.Operations().GetProvisioningOperationByID(response.OperationID)
+		require.NoError(t, err)
+
+		assertAuditEvent(t, db, auditExt.UnsuspendRuntimeAction, auditExt.Succeeded)
+	})
+
+	t.Run("should reject unsuspension of active runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+
+		// when
+		rr := putAction(router, "inst-1", "unsuspend")
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Empty(t, queue.ids)
+		assertAuditEvent(t, db, auditExt.UnsuspendRuntimeAction, auditExt.Failed)
+	})
+
+	t.Run("should return conflict on suspension of suspended runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+		suspensionOp := fixture.FixDeprovisioningOperation("suspension-1", "inst-1")
+		suspensionOp.Temporary = true
+		suspensionOp.CreatedAt = time.Now().Add(time.Minute)
+		require.NoError(t, db.Operations().InsertDeprovisioningOperation(suspensionOp))
+
+		// when
+		rr := putAction(router, "inst-1", "suspend")
+
+		// then
+		assert.Equal(t, http.StatusConflict, rr.Code)
+		assert.Empty(t, queue.ids)
+		assertAuditEvent(t, db, auditExt.SuspendRuntimeAction, auditExt.Failed)
+	})
+
+	t.Run("should reject suspension of non-trial runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.AzurePlanID)
+
+		// when
+		rr := putAction(router, "inst-1", "suspend")
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Empty(t, queue.ids)
+		assertAuditEvent(t, db, auditExt.SuspendRuntimeAction, auditExt.Failed)
+	})
+
+	t.Run("should deprovision runtime", func(t *testing.T) {
+		// given
+		deprovisioner := &fakeDeprovisioner{operationID: "deprovisioning-1"}
+		db, router, _ := fixActionHandler(t, deprovisioner)
+		fixActionInstance(t, db, "inst-1", broker.AzurePlanID)
+
+		// when
+		rr := putAction(router, "inst-1", "deprovision")
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		response := decodeActionResponse(t, rr)
+		assert.Equal(t, "deprovisioning-1", response.OperationID)
+		assert.Equal(t, "inst-1", deprovisioner.instanceID)
+		assert.Equal(t, broker.AzurePlanID, deprovisioner.details.PlanID)
+
+		assertAuditEvent(t, db, auditExt.DeprovisionRuntimeAction, auditExt.Succeeded)
+	})
+
+	t.Run("should reprovision trial runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+
+		// when
+		rr := putAction(router, "inst-1", "reprovision")
+
+		// then
+		require.Equal(t, http.StatusAccepted, rr.Code)
+		response := decodeActionResponse(t, rr)
+		require.Len(t, queue.ids, 1)
+		assert.Equal(t, queue.ids[0], response.OperationID)
+
+		operation, err := db.Operations().GetDeprovisioningOperationByID(response.OperationID)
+		require.NoError(t, err)
+		assert.True(t, operation.Temporary)
+		assert.True(t, operation.Reprovisioning)
+
+		assertAuditEvent(t, db, auditExt.ReprovisionRuntimeAction, auditExt.Succeeded)
+	})
+
+	t.Run("should reject reprovisioning of suspended runtime", func(t *testing.T) {
+		// given
+		db, router, queue := fixActionHandler(t, &fakeDeprovisioner{})
+		fixActionInstance(t, db, "inst-1", broker.TrialPlanID)
+		suspensionOp := fixture.FixDeprovisioningOperation("suspension-1", "inst-1")
+		suspensionOp.Temporary = true
+		suspensionOp.CreatedAt = time.Now().Add(time.Minute)
+		require.NoError(t, db.Operations().InsertDeprovisioningOperation(suspensionOp))
+
+		// when
+		rr := putAction(router, "inst-1", "reprovision")
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+		assert.Empty(t, queue.ids)
+		assertAuditEvent(t, db, auditExt.ReprovisionRuntimeAction, auditExt.Failed)
+	})
+
+	t.Run("should return 404 for not existing instance", func(t *testing.T) {
+		// given
+		db, router, _ := fixActionHandler(t, &fakeDeprovisioner{})
+
+		// when
+		rr := putAction(router, "not-existing", "deprovision")
+
+		// then
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+		assertAuditEvent(t, db, auditExt.DeprovisionRuntimeAction, auditExt.Failed)
+	})
+}
+
+func fixActionHandler(t *testing.T, deprovisioner runtime.Deprovisioner) (storage.BrokerStorage, *mux.Router, *fakeQueue) {
+	db := storage.NewMemoryStorage()
+	queue := &fakeQueue{}
+	log := logrus.New()
+	suspensionHandler := suspension.NewContextUpdateHandler(db.Operations(), queue, queue, log)
+	reprovisioner := suspension.NewReprovisioner(db.Operations(), db.Instances(), suspensionHandler, log)
+	handler := runtime.NewActionHandler(db.Instances(), db.Operations(), suspensionHandler, deprovisioner, reprovisioner, audit.NewRecorder(db.AuditEvents(), log), log)
+
+	router := mux.NewRouter()
+	handler.AttachRoutes(router)
+
+	return db, router, queue
+}
+
+func fixActionInstance(t *testing.T, db storage.BrokerStorage, id, planID string) {
+	instance := fixture.FixInstance(id)
+	instance.ServicePlanID = planID
+	instance.Parameters.PlanID = planID
+	require.NoError(t, db.Instances().Insert(instance))
+	require.NoError(t, db.Operations().InsertProvisioningOperation(fixture.FixProvisioningOperation(fmt.Sprintf("provisioning-%s", id), id)))
+}
+
+func putAction(router *mux.Router, instanceID, action string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/runtimes/%s/%s", instanceID, action), nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	return rr
+}
+
+func decodeActionResponse(t *testing.T, rr *httptest.ResponseRecorder) pkg.ActionResponse {
+	var response pkg.ActionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+
+	return response
+}
+
+func assertAuditEvent(t *testing.T, db storage.BrokerStorage, action auditExt.Action, result auditExt.Result) {
+	events, _, _, err := db.AuditEvents().List(dbmodel.AuditEventFilter{})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, action, events[0].Action)
+	assert.Equal(t, result, events[0].Result)
+}
+
+type fakeQueue struct {
+	ids []string
+}
+
+func (q *fakeQueue) Add(id string) {
+	q.ids = append(q.ids, id)
+}
+
+type fakeDeprovisioner struct {
+	operationID string
+	instanceID  string
+	details     domain.DeprovisionDetails
+}
+
+func (d *fakeDeprovisioner) Deprovision(_ context.Context, instanceID string, details domain.DeprovisionDetails, _ bool) (domain.DeprovisionServiceSpec, error) {
+	d.instanceID = instanceID
+	d.details = details
+
+	return domain.DeprovisionServiceSpec{IsAsync: true, OperationData: d.operationID}, nil
+}
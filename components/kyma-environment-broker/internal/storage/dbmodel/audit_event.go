@@ -0,0 +1,63 @@
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+// AuditEventFilter holds the filters when listing audit events
+type AuditEventFilter struct {
+	Page             int
+	PageSize         int
+	Actors           []string
+	Actions          []string
+	OrchestrationIDs []string
+	InstanceIDs      []string
+	RuntimeIDs       []string
+	Results          []string
+}
+
+type AuditEventDTO struct {
+	ID              string
+	Actor           string
+	Action          string
+	OrchestrationID string
+	InstanceID      string
+	RuntimeID       string
+	Parameters      string
+	Result          string
+	Error           string
+	CreatedAt       time.Time
+}
+
+func NewAuditEventDTO(e internal.AuditEvent) AuditEventDTO {
+	return AuditEventDTO{
+		ID:              e.ID,
+		Actor:           e.Actor,
+		Action:          string(e.Action),
+		OrchestrationID: e.OrchestrationID,
+		InstanceID:      e.InstanceID,
+		RuntimeID:       e.RuntimeID,
+		Parameters:      e.Parameters,
+		Result:          string(e.Result),
+		Error:           e.Error,
+		CreatedAt:       e.CreatedAt,
+	}
+}
+
+func (e *AuditEventDTO) ToAuditEvent() internal.AuditEvent {
+	return internal.AuditEvent{
+		ID:              e.ID,
+		Actor:           e.Actor,
+		Action:          audit.Action(e.Action),
+		OrchestrationID: e.OrchestrationID,
+		InstanceID:      e.InstanceID,
+		RuntimeID:       e.RuntimeID,
+		Parameters:      e.Parameters,
+		Result:          audit.Result(e.Result),
+		Error:           e.Error,
+		CreatedAt:       e.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+type OutboxEventDTO struct {
+	ID        int64
+	Type      string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type OutboxSubscriptionDTO struct {
+	Name        string
+	EventOffset int64
+	UpdatedAt   time.Time
+}
+
+func NewOutboxEventDTO(e internal.OutboxEvent) OutboxEventDTO {
+	return OutboxEventDTO{
+		ID:        e.Offset,
+		Type:      e.Type,
+		Payload:   e.Payload,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func (e *OutboxEventDTO) ToOutboxEvent() internal.OutboxEvent {
+	return internal.OutboxEvent{
+		Offset:    e.ID,
+		Type:      e.Type,
+		Payload:   e.Payload,
+		CreatedAt: e.CreatedAt,
+	}
+}
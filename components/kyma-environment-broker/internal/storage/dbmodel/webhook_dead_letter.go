@@ -0,0 +1,41 @@
+package dbmodel
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+)
+
+type WebhookDeadLetterDTO struct {
+	ID        string
+	URL       string
+	EventType string
+	Payload   string
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+func NewWebhookDeadLetterDTO(l internal.WebhookDeadLetter) WebhookDeadLetterDTO {
+	return WebhookDeadLetterDTO{
+		ID:        l.ID,
+		URL:       l.URL,
+		EventType: l.EventType,
+		Payload:   l.Payload,
+		Attempts:  l.Attempts,
+		LastError: l.LastError,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+func (l *WebhookDeadLetterDTO) ToWebhookDeadLetter() internal.WebhookDeadLetter {
+	return internal.WebhookDeadLetter{
+		ID:        l.ID,
+		URL:       l.URL,
+		EventType: l.EventType,
+		Payload:   l.Payload,
+		Attempts:  l.Attempts,
+		LastError: l.LastError,
+		CreatedAt: l.CreatedAt,
+	}
+}
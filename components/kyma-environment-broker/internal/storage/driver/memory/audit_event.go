@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/pagination"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+type auditEvents struct {
+	mu sync.Mutex
+
+	events map[string]internal.AuditEvent
+}
+
+func NewAuditEvents() *auditEvents {
+	return &auditEvents{
+		events: make(map[string]internal.AuditEvent, 0),
+	}
+}
+
+func (s *auditEvents) Insert(event internal.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[event.ID]; ok {
+		return dberr.AlreadyExists("audit event with id %s already exist", event.ID)
+	}
+	s.events[event.ID] = event
+
+	return nil
+}
+
+func (s *auditEvents) List(filter dbmodel.AuditEventFilter) ([]internal.AuditEvent, int, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.AuditEvent, 0)
+	offset := pagination.ConvertPageAndPageSizeToOffset(filter.PageSize, filter.Page)
+
+	events := s.filter(filter)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+
+	for i := offset; (filter.PageSize < 1 || i < offset+filter.PageSize) && i < len(events); i++ {
+		result = append(result, events[i])
+	}
+
+	return result,
+		len(result),
+		len(events),
+		nil
+}
+
+func (s *auditEvents) filter(filter dbmodel.AuditEventFilter) []internal.AuditEvent {
+	events := make([]internal.AuditEvent, 0, len(s.events))
+	equal := func(a, b string) bool { return a == b }
+	for _, v := range s.events {
+		if ok := matchFilter(v.Actor, filter.Actors, equal); !ok {
+			continue
+		}
+		if ok := matchFilter(string(v.Action), filter.Actions, equal); !ok {
+			continue
+		}
+		if ok := matchFilter(v.OrchestrationID, filter.OrchestrationIDs, equal); !ok {
+			continue
+		}
+		if ok := matchFilter(v.InstanceID, filter.InstanceIDs, equal); !ok {
+			continue
+		}
+		if ok := matchFilter(v.RuntimeID, filter.RuntimeIDs, equal); !ok {
+			continue
+		}
+		if ok := matchFilter(string(v.Result), filter.Results, equal); !ok {
+			continue
+		}
+
+		events = append(events, v)
+	}
+
+	return events
+}
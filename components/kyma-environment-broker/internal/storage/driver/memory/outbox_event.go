@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type outboxEvents struct {
+	mu sync.Mutex
+
+	events        []internal.OutboxEvent
+	subscriptions map[string]int64
+}
+
+func NewOutboxEvents() *outboxEvents {
+	return &outboxEvents{
+		events:        make([]internal.OutboxEvent, 0),
+		subscriptions: make(map[string]int64),
+	}
+}
+
+func (s *outboxEvents) Insert(event internal.OutboxEvent) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	event.Offset = int64(len(s.events) + 1)
+	s.events = append(s.events, event)
+
+	return event.Offset, nil
+}
+
+func (s *outboxEvents) ListAfter(offset int64, types []string, limit int) ([]internal.OutboxEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.OutboxEvent, 0)
+	equal := func(a, b string) bool { return a == b }
+	for _, e := range s.events {
+		if len(result) >= limit {
+			break
+		}
+		if e.Offset <= offset {
+			continue
+		}
+		if ok := matchFilter(e.Type, types, equal); !ok {
+			continue
+		}
+		result = append(result, e)
+	}
+
+	return result, nil
+}
+
+func (s *outboxEvents) GetLastOffset() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.events)), nil
+}
+
+func (s *outboxEvents) GetSubscriptionOffset(subscription string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	offset, ok := s.subscriptions[subscription]
+	if !ok {
+		return 0, dberr.NotFound("outbox subscription %s not found", subscription)
+	}
+	return offset, nil
+}
+
+func (s *outboxEvents) SetSubscriptionOffset(subscription string, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.subscriptions[subscription] = offset
+	return nil
+}
@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+)
+
+type webhookDeadLetters struct {
+	mu sync.Mutex
+
+	letters map[string]internal.WebhookDeadLetter
+}
+
+func NewWebhookDeadLetters() *webhookDeadLetters {
+	return &webhookDeadLetters{
+		letters: make(map[string]internal.WebhookDeadLetter, 0),
+	}
+}
+
+func (s *webhookDeadLetters) Insert(letter internal.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.letters[letter.ID]; ok {
+		return dberr.AlreadyExists("webhook dead letter with id %s already exist", letter.ID)
+	}
+	s.letters[letter.ID] = letter
+
+	return nil
+}
+
+func (s *webhookDeadLetters) List() ([]internal.WebhookDeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.WebhookDeadLetter, 0, len(s.letters))
+	for _, l := range s.letters {
+		result = append(result, l)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package postsql
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type auditEvents struct {
+	postsql.Factory
+}
+
+func NewAuditEvents(sess postsql.Factory) *auditEvents {
+	return &auditEvents{
+		Factory: sess,
+	}
+}
+
+func (s *auditEvents) Insert(event internal.AuditEvent) error {
+	dto := dbmodel.NewAuditEventDTO(event)
+
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.InsertAuditEvent(dto)
+		if err != nil {
+			log.Errorf("while saving audit event ID %s: %v", event.ID, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func (s *auditEvents) List(filter dbmodel.AuditEventFilter) ([]internal.AuditEvent, int, int, error) {
+	sess := s.NewReadSession()
+	var (
+		events            = make([]internal.AuditEvent, 0)
+		lastErr           error
+		count, totalCount int
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		var dtos []dbmodel.AuditEventDTO
+		dtos, count, totalCount, lastErr = sess.ListAuditEvents(filter)
+		if lastErr != nil {
+			log.Errorf("while getting audit events: %v", lastErr)
+			return false, nil
+		}
+		for _, dto := range dtos {
+			events = append(events, dto.ToAuditEvent())
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, -1, -1, lastErr
+	}
+	return events, count, totalCount, nil
+}
@@ -0,0 +1,146 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type outboxEvents struct {
+	postsql.Factory
+}
+
+func NewOutboxEvents(sess postsql.Factory) *outboxEvents {
+	return &outboxEvents{
+		Factory: sess,
+	}
+}
+
+func (s *outboxEvents) Insert(event internal.OutboxEvent) (int64, error) {
+	dto := dbmodel.NewOutboxEventDTO(event)
+
+	var (
+		offset  int64
+		lastErr error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		offset, lastErr = s.insert(dto)
+		if lastErr != nil {
+			log.Errorf("while saving outbox event of type %s: %v", event.Type, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return 0, lastErr
+	}
+	return offset, nil
+}
+
+// insert stores the event in a transaction, which serializes the inserts until it is committed
+func (s *outboxEvents) insert(dto dbmodel.OutboxEventDTO) (int64, error) {
+	sess, err := s.NewSessionWithinTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	offset, err := sess.InsertOutboxEvent(dto)
+	if err != nil {
+		return 0, err
+	}
+	if err := sess.Commit(); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+func (s *outboxEvents) ListAfter(offset int64, types []string, limit int) ([]internal.OutboxEvent, error) {
+	sess := s.NewReadSession()
+	var (
+		events  = make([]internal.OutboxEvent, 0)
+		lastErr error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		var dtos []dbmodel.OutboxEventDTO
+		dtos, lastErr = sess.ListOutboxEvents(offset, types, limit)
+		if lastErr != nil {
+			log.Errorf("while getting outbox events after offset %d: %v", offset, lastErr)
+			return false, nil
+		}
+		for _, dto := range dtos {
+			events = append(events, dto.ToOutboxEvent())
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+	return events, nil
+}
+
+func (s *outboxEvents) GetLastOffset() (int64, error) {
+	sess := s.NewReadSession()
+	var (
+		offset  int64
+		lastErr error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		offset, lastErr = sess.GetLastOutboxEventID()
+		if lastErr != nil {
+			log.Errorf("while getting last outbox event offset: %v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return 0, lastErr
+	}
+	return offset, nil
+}
+
+func (s *outboxEvents) GetSubscriptionOffset(subscription string) (int64, error) {
+	sess := s.NewReadSession()
+	var (
+		dto     dbmodel.OutboxSubscriptionDTO
+		lastErr dberr.Error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		dto, lastErr = sess.GetOutboxSubscription(subscription)
+		if lastErr != nil {
+			if dberr.IsNotFound(lastErr) {
+				return false, dberr.NotFound("Outbox subscription %s not exist", subscription)
+			}
+			log.Errorf("while getting outbox subscription %s: %v", subscription, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return 0, lastErr
+	}
+	return dto.EventOffset, nil
+}
+
+func (s *outboxEvents) SetSubscriptionOffset(subscription string, offset int64) error {
+	dto := dbmodel.OutboxSubscriptionDTO{
+		Name:        subscription,
+		EventOffset: offset,
+		UpdatedAt:   time.Now(),
+	}
+
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.UpsertOutboxSubscription(dto)
+		if err != nil {
+			log.Errorf("while saving outbox subscription %s: %v", subscription, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
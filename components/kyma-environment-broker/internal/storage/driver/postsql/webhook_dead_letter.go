@@ -0,0 +1,57 @@
+package postsql
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/postsql"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type webhookDeadLetters struct {
+	postsql.Factory
+}
+
+func NewWebhookDeadLetters(sess postsql.Factory) *webhookDeadLetters {
+	return &webhookDeadLetters{
+		Factory: sess,
+	}
+}
+
+func (s *webhookDeadLetters) Insert(letter internal.WebhookDeadLetter) error {
+	dto := dbmodel.NewWebhookDeadLetterDTO(letter)
+
+	sess := s.NewWriteSession()
+	return wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		err := sess.InsertWebhookDeadLetter(dto)
+		if err != nil {
+			log.Errorf("while saving webhook dead letter ID %s: %v", letter.ID, err)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+func (s *webhookDeadLetters) List() ([]internal.WebhookDeadLetter, error) {
+	sess := s.NewReadSession()
+	var (
+		letters = make([]internal.WebhookDeadLetter, 0)
+		lastErr error
+	)
+	err := wait.PollImmediate(defaultRetryInterval, defaultRetryTimeout, func() (bool, error) {
+		var dtos []dbmodel.WebhookDeadLetterDTO
+		dtos, lastErr = sess.ListWebhookDeadLetters()
+		if lastErr != nil {
+			log.Errorf("while getting webhook dead letters: %v", lastErr)
+			return false, nil
+		}
+		for _, dto := range dtos {
+			letters = append(letters, dto.ToWebhookDeadLetter())
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, lastErr
+	}
+	return letters, nil
+}
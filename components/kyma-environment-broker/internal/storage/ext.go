@@ -63,6 +63,28 @@ type Orchestrations interface {
 	List(filter dbmodel.OrchestrationFilter) ([]internal.Orchestration, int, int, error)
 }
 
+type AuditEvents interface {
+	Insert(event internal.AuditEvent) error
+	List(filter dbmodel.AuditEventFilter) ([]internal.AuditEvent, int, int, error)
+}
+
+type WebhookDeadLetters interface {
+	Insert(letter internal.WebhookDeadLetter) error
+	List() ([]internal.WebhookDeadLetter, error)
+}
+
+type OutboxEvents interface {
+	// Insert stores the event and returns its offset. The inserts are serialized, so the events are committed
+	// in the order of their offsets and a listed offset is never preceded by an event committed later.
+	Insert(event internal.OutboxEvent) (int64, error)
+	// ListAfter returns at most limit events of the given types with the offset greater than the given one
+	ListAfter(offset int64, types []string, limit int) ([]internal.OutboxEvent, error)
+	GetLastOffset() (int64, error)
+	// GetSubscriptionOffset returns the offset of the last event acknowledged by the subscription
+	GetSubscriptionOffset(subscription string) (int64, error)
+	SetSubscriptionOffset(subscription string, offset int64) error
+}
+
 type RuntimeStates interface {
 	Insert(runtimeState internal.RuntimeState) error
 	GetByOperationID(operationID string) (internal.RuntimeState, error)
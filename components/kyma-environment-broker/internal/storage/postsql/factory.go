@@ -42,6 +42,11 @@ type ReadSession interface {
 	ListInstances(filter dbmodel.InstanceFilter) ([]dbmodel.InstanceDTO, int, int, error)
 	ListOperationsByOrchestrationID(orchestrationID string, filter dbmodel.OperationFilter) ([]dbmodel.OperationDTO, int, int, error)
 	GetOperationStatsForOrchestration(orchestrationID string) ([]dbmodel.OperationStatEntry, error)
+	ListAuditEvents(filter dbmodel.AuditEventFilter) ([]dbmodel.AuditEventDTO, int, int, error)
+	ListWebhookDeadLetters() ([]dbmodel.WebhookDeadLetterDTO, dberr.Error)
+	ListOutboxEvents(afterID int64, types []string, limit int) ([]dbmodel.OutboxEventDTO, dberr.Error)
+	GetLastOutboxEventID() (int64, dberr.Error)
+	GetOutboxSubscription(name string) (dbmodel.OutboxSubscriptionDTO, dberr.Error)
 }
 
 //go:generate mockery -name=WriteSession
@@ -60,6 +65,10 @@ type WriteSession interface {
 	DeleteCLSInstance(clsInstanceID string) dberr.Error
 	InsertCLSInstanceReference(dto dbmodel.CLSInstanceReferenceDTO) dberr.Error
 	DeleteCLSInstanceReference(dto dbmodel.CLSInstanceReferenceDTO) dberr.Error
+	InsertAuditEvent(dto dbmodel.AuditEventDTO) dberr.Error
+	InsertWebhookDeadLetter(dto dbmodel.WebhookDeadLetterDTO) dberr.Error
+	InsertOutboxEvent(dto dbmodel.OutboxEventDTO) (int64, dberr.Error)
+	UpsertOutboxSubscription(dto dbmodel.OutboxSubscriptionDTO) dberr.Error
 }
 
 type Transaction interface {
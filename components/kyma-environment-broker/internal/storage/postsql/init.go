@@ -21,6 +21,10 @@ const (
 	LMSTenantTableName            = "lms_tenants"
 	CLSInstanceTableName          = "cls_instances"
 	CLSInstanceReferenceTableName = "cls_instance_references"
+	AuditEventTableName           = "audit_events"
+	WebhookDeadLetterTableName    = "webhook_dead_letters"
+	OutboxEventTableName          = "outbox_events"
+	OutboxSubscriptionTableName   = "outbox_subscriptions"
 	CreatedAtField                = "created_at"
 )
 
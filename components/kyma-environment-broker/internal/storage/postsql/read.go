@@ -399,6 +399,102 @@ func (r readSession) getLastOperation(condition dbr.Builder) (dbmodel.OperationD
 	return operation, nil
 }
 
+func (r readSession) ListAuditEvents(filter dbmodel.AuditEventFilter) ([]dbmodel.AuditEventDTO, int, int, error) {
+	var events []dbmodel.AuditEventDTO
+
+	stmt := r.session.Select("*").
+		From(AuditEventTableName).
+		OrderBy(CreatedAtField)
+
+	// Add pagination if provided
+	if filter.Page > 0 && filter.PageSize > 0 {
+		stmt.Paginate(uint64(filter.Page), uint64(filter.PageSize))
+	}
+
+	// Apply filtering if provided
+	addAuditEventFilters(stmt, filter)
+
+	_, err := stmt.Load(&events)
+	if err != nil {
+		return nil, -1, -1, dberr.Internal("Failed to get audit events: %s", err)
+	}
+
+	totalCount, err := r.getAuditEventCount(filter)
+	if err != nil {
+		return nil, -1, -1, err
+	}
+
+	return events,
+		len(events),
+		totalCount,
+		nil
+}
+
+func (r readSession) ListWebhookDeadLetters() ([]dbmodel.WebhookDeadLetterDTO, dberr.Error) {
+	var letters []dbmodel.WebhookDeadLetterDTO
+
+	_, err := r.session.Select("*").
+		From(WebhookDeadLetterTableName).
+		OrderBy(CreatedAtField).
+		Load(&letters)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get webhook dead letters: %s", err)
+	}
+
+	return letters, nil
+}
+
+func (r readSession) ListOutboxEvents(afterID int64, types []string, limit int) ([]dbmodel.OutboxEventDTO, dberr.Error) {
+	var events []dbmodel.OutboxEventDTO
+
+	stmt := r.session.Select("*").
+		From(OutboxEventTableName).
+		Where(dbr.Gt("id", afterID)).
+		OrderBy("id").
+		Limit(uint64(limit))
+	if len(types) > 0 {
+		stmt.Where("type IN ?", types)
+	}
+
+	_, err := stmt.Load(&events)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get outbox events: %s", err)
+	}
+
+	return events, nil
+}
+
+func (r readSession) GetLastOutboxEventID() (int64, dberr.Error) {
+	var res struct {
+		ID int64
+	}
+	err := r.session.Select("coalesce(max(id), 0) as id").
+		From(OutboxEventTableName).
+		LoadOne(&res)
+	if err != nil {
+		return 0, dberr.Internal("Failed to get last outbox event ID: %s", err)
+	}
+
+	return res.ID, nil
+}
+
+func (r readSession) GetOutboxSubscription(name string) (dbmodel.OutboxSubscriptionDTO, dberr.Error) {
+	var subscription dbmodel.OutboxSubscriptionDTO
+
+	err := r.session.Select("*").
+		From(OutboxSubscriptionTableName).
+		Where(dbr.Eq("name", name)).
+		LoadOne(&subscription)
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return dbmodel.OutboxSubscriptionDTO{}, dberr.NotFound("Cannot find outbox subscription %s", name)
+		}
+		return dbmodel.OutboxSubscriptionDTO{}, dberr.Internal("Failed to get outbox subscription: %s", err)
+	}
+
+	return subscription, nil
+}
+
 func (r readSession) getOrchestration(condition dbr.Builder) (dbmodel.OrchestrationDTO, dberr.Error) {
 	var operation dbmodel.OrchestrationDTO
 
@@ -659,6 +755,27 @@ func addOrchestrationFilters(stmt *dbr.SelectStmt, filter dbmodel.OrchestrationF
 	}
 }
 
+func addAuditEventFilters(stmt *dbr.SelectStmt, filter dbmodel.AuditEventFilter) {
+	if len(filter.Actors) > 0 {
+		stmt.Where("actor IN ?", filter.Actors)
+	}
+	if len(filter.Actions) > 0 {
+		stmt.Where("action IN ?", filter.Actions)
+	}
+	if len(filter.OrchestrationIDs) > 0 {
+		stmt.Where("orchestration_id IN ?", filter.OrchestrationIDs)
+	}
+	if len(filter.InstanceIDs) > 0 {
+		stmt.Where("instance_id IN ?", filter.InstanceIDs)
+	}
+	if len(filter.RuntimeIDs) > 0 {
+		stmt.Where("runtime_id IN ?", filter.RuntimeIDs)
+	}
+	if len(filter.Results) > 0 {
+		stmt.Where("result IN ?", filter.Results)
+	}
+}
+
 func addOperationFilters(stmt *dbr.SelectStmt, filter dbmodel.OperationFilter) {
 	if len(filter.States) > 0 {
 		stmt.Where("state IN ?", filter.States)
@@ -700,3 +817,14 @@ func (r readSession) getOrchestrationCount(filter dbmodel.OrchestrationFilter) (
 
 	return res.Total, err
 }
+
+func (r readSession) getAuditEventCount(filter dbmodel.AuditEventFilter) (int, error) {
+	var res struct {
+		Total int
+	}
+	stmt := r.session.Select("count(*) as total").From(AuditEventTableName)
+	addAuditEventFilters(stmt, filter)
+	err := stmt.LoadOne(&res)
+
+	return res.Total, err
+}
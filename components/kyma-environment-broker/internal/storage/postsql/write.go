@@ -1,6 +1,7 @@
 package postsql
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dbmodel"
@@ -346,6 +347,114 @@ func (ws writeSession) UpdateOperation(op dbmodel.OperationDTO) dberr.Error {
 	return nil
 }
 
+func (ws writeSession) InsertAuditEvent(dto dbmodel.AuditEventDTO) dberr.Error {
+	_, err := ws.insertInto(AuditEventTableName).
+		Pair("id", dto.ID).
+		Pair("actor", dto.Actor).
+		Pair("action", dto.Action).
+		Pair("orchestration_id", dto.OrchestrationID).
+		Pair("instance_id", dto.InstanceID).
+		Pair("runtime_id", dto.RuntimeID).
+		Pair("parameters", dto.Parameters).
+		Pair("result", dto.Result).
+		Pair("error", dto.Error).
+		Pair("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("audit event with id %s already exist", dto.ID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to audit_events table: %s", err)
+	}
+
+	return nil
+}
+
+func (ws writeSession) InsertWebhookDeadLetter(dto dbmodel.WebhookDeadLetterDTO) dberr.Error {
+	_, err := ws.insertInto(WebhookDeadLetterTableName).
+		Pair("id", dto.ID).
+		Pair("url", dto.URL).
+		Pair("event_type", dto.EventType).
+		Pair("payload", dto.Payload).
+		Pair("attempts", dto.Attempts).
+		Pair("last_error", dto.LastError).
+		Pair("created_at", dto.CreatedAt).
+		Exec()
+
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("webhook dead letter with id %s already exist", dto.ID)
+			}
+		}
+		return dberr.Internal("Failed to insert record to webhook_dead_letters table: %s", err)
+	}
+
+	return nil
+}
+
+// InsertOutboxEvent must be called within a transaction. The table lock held until the end of the transaction
+// serializes the inserts, so the events are committed in the order of their IDs.
+func (ws writeSession) InsertOutboxEvent(dto dbmodel.OutboxEventDTO) (int64, dberr.Error) {
+	if ws.transaction == nil {
+		return 0, dberr.Internal("Failed to insert record to outbox_events table: transaction is required")
+	}
+	_, err := ws.transaction.Exec(fmt.Sprintf("LOCK TABLE %s IN SHARE ROW EXCLUSIVE MODE", OutboxEventTableName))
+	if err != nil {
+		return 0, dberr.Internal("Failed to lock outbox_events table: %s", err)
+	}
+
+	var id int64
+	err = ws.insertInto(OutboxEventTableName).
+		Pair("type", dto.Type).
+		Pair("payload", dto.Payload).
+		Pair("created_at", dto.CreatedAt).
+		Returning("id").
+		Load(&id)
+	if err != nil {
+		return 0, dberr.Internal("Failed to insert record to outbox_events table: %s", err)
+	}
+
+	return id, nil
+}
+
+func (ws writeSession) UpsertOutboxSubscription(dto dbmodel.OutboxSubscriptionDTO) dberr.Error {
+	res, err := ws.update(OutboxSubscriptionTableName).
+		Where(dbr.Eq("name", dto.Name)).
+		Set("event_offset", dto.EventOffset).
+		Set("updated_at", dto.UpdatedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to update record to outbox_subscriptions table: %s", err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return dberr.Internal("the DB driver does not support RowsAffected operation")
+	}
+	if rAffected > 0 {
+		return nil
+	}
+
+	_, err = ws.insertInto(OutboxSubscriptionTableName).
+		Pair("name", dto.Name).
+		Pair("event_offset", dto.EventOffset).
+		Pair("updated_at", dto.UpdatedAt).
+		Exec()
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("outbox subscription %s already exist", dto.Name)
+			}
+		}
+		return dberr.Internal("Failed to insert record to outbox_subscriptions table: %s", err)
+	}
+
+	return nil
+}
+
 func (ws writeSession) Commit() dberr.Error {
 	err := ws.transaction.Commit()
 	if err != nil {
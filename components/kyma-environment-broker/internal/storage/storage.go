@@ -17,6 +17,9 @@ type BrokerStorage interface {
 	Orchestrations() Orchestrations
 	RuntimeStates() RuntimeStates
 	CLSInstances() CLSInstances
+	AuditEvents() AuditEvents
+	WebhookDeadLetters() WebhookDeadLetters
+	OutboxEvents() OutboxEvents
 }
 
 const (
@@ -46,6 +49,9 @@ func NewFromConfig(cfg Config, cipher postgres.Cipher, log logrus.FieldLogger) (
 		orchestrations: postgres.NewOrchestrations(fact),
 		runtimeStates:  postgres.NewRuntimeStates(fact, cipher),
 		clsInstances:   postgres.NewCLSInstances(fact),
+		auditEvents:    postgres.NewAuditEvents(fact),
+		deadLetters:    postgres.NewWebhookDeadLetters(fact),
+		outboxEvents:   postgres.NewOutboxEvents(fact),
 	}, connection, nil
 }
 
@@ -58,6 +64,9 @@ func NewMemoryStorage() BrokerStorage {
 		orchestrations: memory.NewOrchestrations(),
 		runtimeStates:  memory.NewRuntimeStates(),
 		clsInstances:   memory.NewCLSInstances(),
+		auditEvents:    memory.NewAuditEvents(),
+		deadLetters:    memory.NewWebhookDeadLetters(),
+		outboxEvents:   memory.NewOutboxEvents(),
 	}
 }
 
@@ -68,6 +77,9 @@ type storage struct {
 	orchestrations Orchestrations
 	runtimeStates  RuntimeStates
 	clsInstances   CLSInstances
+	auditEvents    AuditEvents
+	deadLetters    WebhookDeadLetters
+	outboxEvents   OutboxEvents
 }
 
 func (s storage) Instances() Instances {
@@ -101,3 +113,15 @@ func (s storage) Orchestrations() Orchestrations {
 func (s storage) RuntimeStates() RuntimeStates {
 	return s.runtimeStates
 }
+
+func (s storage) AuditEvents() AuditEvents {
+	return s.auditEvents
+}
+
+func (s storage) WebhookDeadLetters() WebhookDeadLetters {
+	return s.deadLetters
+}
+
+func (s storage) OutboxEvents() OutboxEvents {
+	return s.outboxEvents
+}
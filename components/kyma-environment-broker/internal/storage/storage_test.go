@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/broker"
@@ -1226,6 +1227,194 @@ func TestPostgres(t *testing.T) {
 		assert.Equal(t, fixID, state.KymaConfig.Version)
 		assert.Equal(t, fixID, state.ClusterConfig.KubernetesVersion)
 	})
+	t.Run("Audit Events", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		now := time.Now()
+		givenEvents := []internal.AuditEvent{
+			{
+				ID:              "event-1",
+				Actor:           "john.doe@example.com",
+				Action:          audit.UpgradeKymaAction,
+				OrchestrationID: "orchestration-1",
+				Parameters:      `{"dryRun":true}`,
+				Result:          audit.Succeeded,
+				CreatedAt:       now.Add(-time.Minute),
+			},
+			{
+				ID:              "event-2",
+				Actor:           "jane.doe@example.com",
+				Action:          audit.CancelOrchestrationAction,
+				OrchestrationID: "orchestration-1",
+				Result:          audit.Failed,
+				Error:           "while updating orchestration",
+				CreatedAt:       now,
+			},
+		}
+
+		err = storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		svc := brokerStorage.AuditEvents()
+
+		for _, e := range givenEvents {
+			err = svc.Insert(e)
+			require.NoError(t, err)
+		}
+
+		// when
+		l, count, totalCount, err := svc.List(dbmodel.AuditEventFilter{PageSize: 10, Page: 1})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, l, 2)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, 2, totalCount)
+		assert.Equal(t, "event-1", l[0].ID)
+		assert.Equal(t, givenEvents[0].Parameters, l[0].Parameters)
+		assert.Equal(t, "event-2", l[1].ID)
+
+		// when
+		l, count, totalCount, err = svc.List(dbmodel.AuditEventFilter{
+			Actors:           []string{"jane.doe@example.com"},
+			OrchestrationIDs: []string{"orchestration-1"},
+			Results:          []string{string(audit.Failed)},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, l, 1)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 1, totalCount)
+		assert.Equal(t, audit.CancelOrchestrationAction, l[0].Action)
+		assert.Equal(t, givenEvents[1].Error, l[0].Error)
+	})
+	t.Run("Webhook Dead Letters", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		now := time.Now()
+		givenLetters := []internal.WebhookDeadLetter{
+			{
+				ID:        "letter-2",
+				URL:       "https://hooks.example.com/keb",
+				EventType: "orchestration",
+				Payload:   `{"id":"letter-2"}`,
+				Attempts:  5,
+				LastError: "unexpected status code 503",
+				CreatedAt: now,
+			},
+			{
+				ID:        "letter-1",
+				URL:       "https://hooks.example.com/keb",
+				EventType: "operation",
+				Payload:   `{"id":"letter-1"}`,
+				Attempts:  5,
+				LastError: "connection refused",
+				CreatedAt: now.Add(-time.Minute),
+			},
+		}
+
+		err = storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		svc := brokerStorage.WebhookDeadLetters()
+
+		for _, l := range givenLetters {
+			err = svc.Insert(l)
+			require.NoError(t, err)
+		}
+
+		// when
+		l, err := svc.List()
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, l, 2)
+		assert.Equal(t, "letter-1", l[0].ID)
+		assert.Equal(t, givenLetters[1].Payload, l[0].Payload)
+		assert.Equal(t, givenLetters[1].LastError, l[0].LastError)
+		assert.Equal(t, 5, l[0].Attempts)
+		assert.Equal(t, "letter-2", l[1].ID)
+	})
+	t.Run("Outbox Events", func(t *testing.T) {
+		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
+		require.NoError(t, err)
+		defer containerCleanupFunc()
+
+		err = storage.InitTestDBTables(t, cfg.ConnectionURL())
+		require.NoError(t, err)
+
+		cipher := storage.NewEncrypter(cfg.SecretKey)
+		brokerStorage, _, err := storage.NewFromConfig(cfg, cipher, logrus.StandardLogger())
+		require.NoError(t, err)
+
+		svc := brokerStorage.OutboxEvents()
+
+		// when
+		last, err := svc.GetLastOffset()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), last)
+
+		// when
+		var offsets []int64
+		for _, typ := range []string{"type-a", "type-b", "type-a"} {
+			offset, err := svc.Insert(internal.OutboxEvent{
+				Type:      typ,
+				Payload:   []byte(typ),
+				CreatedAt: time.Now(),
+			})
+			require.NoError(t, err)
+			offsets = append(offsets, offset)
+		}
+		events, err := svc.ListAfter(offsets[0], []string{"type-a"}, 10)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, offsets[2], events[0].Offset)
+		assert.Equal(t, []byte("type-a"), events[0].Payload)
+
+		events, err = svc.ListAfter(0, nil, 2)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, offsets[0], events[0].Offset)
+		assert.Equal(t, "type-b", events[1].Type)
+
+		last, err = svc.GetLastOffset()
+		require.NoError(t, err)
+		assert.Equal(t, offsets[2], last)
+
+		// when
+		_, err = svc.GetSubscriptionOffset("subscription")
+
+		// then
+		assert.True(t, dberr.IsNotFound(err))
+
+		// when
+		err = svc.SetSubscriptionOffset("subscription", offsets[0])
+		require.NoError(t, err)
+		err = svc.SetSubscriptionOffset("subscription", offsets[1])
+		require.NoError(t, err)
+		offset, err := svc.GetSubscriptionOffset("subscription")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, offsets[1], offset)
+	})
 	t.Run("LMS Tenants", func(t *testing.T) {
 		containerCleanupFunc, cfg, err := storage.InitTestDBContainer(t, ctx, "test_DB_1")
 		require.NoError(t, err)
@@ -277,5 +277,41 @@ func FixTables() map[string]string {
 			skr_instance_id varchar(255) NOT NULL,
 			FOREIGN KEY(cls_instance_id) REFERENCES %s(id) ON DELETE CASCADE);
 			`, postsql.CLSInstanceTableName, postsql.CLSInstanceReferenceTableName, postsql.CLSInstanceTableName),
+		postsql.AuditEventTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id varchar(255) PRIMARY KEY,
+			actor varchar(255) NOT NULL,
+			action varchar(64) NOT NULL,
+			orchestration_id varchar(255) NOT NULL DEFAULT '',
+			instance_id varchar(255) NOT NULL DEFAULT '',
+			runtime_id varchar(255) NOT NULL DEFAULT '',
+			parameters text NOT NULL DEFAULT '',
+			result varchar(32) NOT NULL,
+			error text NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL
+			)`, postsql.AuditEventTableName),
+		postsql.WebhookDeadLetterTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id varchar(255) PRIMARY KEY,
+			url text NOT NULL,
+			event_type varchar(64) NOT NULL,
+			payload text NOT NULL,
+			attempts integer NOT NULL,
+			last_error text NOT NULL DEFAULT '',
+			created_at TIMESTAMPTZ NOT NULL
+			)`, postsql.WebhookDeadLetterTableName),
+		postsql.OutboxEventTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			id bigserial PRIMARY KEY,
+			type varchar(255) NOT NULL,
+			payload bytea NOT NULL,
+			created_at TIMESTAMPTZ NOT NULL
+			)`, postsql.OutboxEventTableName),
+		postsql.OutboxSubscriptionTableName: fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s (
+			name varchar(512) PRIMARY KEY,
+			event_offset bigint NOT NULL,
+			updated_at TIMESTAMPTZ NOT NULL
+			)`, postsql.OutboxSubscriptionTableName),
 	}
 }
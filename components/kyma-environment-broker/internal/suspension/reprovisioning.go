@@ -0,0 +1,125 @@
+package suspension
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Reprovisioner reprovisions trial runtimes. The runtime is suspended first, and the unsuspension is started
+// by the handler of the event published when the suspension operation succeeds. The event is not persisted,
+// so the unsuspensions missed before a restart are started from the stored suspension operations by Resume.
+type Reprovisioner struct {
+	operations storage.Operations
+	instances  storage.Instances
+	handler    *ContextUpdateHandler
+
+	log logrus.FieldLogger
+}
+
+func NewReprovisioner(operations storage.Operations, instances storage.Instances, handler *ContextUpdateHandler, l logrus.FieldLogger) *Reprovisioner {
+	return &Reprovisioner{
+		operations: operations,
+		instances:  instances,
+		handler:    handler,
+		log:        l,
+	}
+}
+
+// Subscribe registers the handler which unsuspends the runtimes after their suspension for reprovisioning
+func (r *Reprovisioner) Subscribe(sub event.Subscriber) {
+	sub.Subscribe("suspension.reprovisioning", process.DeprovisioningStepProcessed{}, r.OnDeprovisioningStepProcessed)
+}
+
+// Reprovision starts the suspension of the runtime of the given instance and returns the ID of the suspension operation
+func (r *Reprovisioner) Reprovision(instance *internal.Instance) (string, error) {
+	operation := internal.NewSuspensionOperationWithID(uuid.New().String(), instance)
+	operation.Reprovisioning = true
+	err := r.operations.InsertDeprovisioningOperation(operation)
+	if err != nil {
+		return "", errors.Wrap(err, "while inserting suspension operation")
+	}
+	r.handler.deprovisioningQueue.Add(operation.ID)
+
+	return operation.ID, nil
+}
+
+// Resume unsuspends the runtimes whose suspension for reprovisioning succeeded, but which were not unsuspended yet
+func (r *Reprovisioner) Resume() error {
+	operations, err := r.operations.ListDeprovisioningOperations()
+	if err != nil {
+		return errors.Wrap(err, "while listing deprovisioning operations")
+	}
+	for _, operation := range operations {
+		if !operation.Reprovisioning || operation.State != domain.Succeeded {
+			continue
+		}
+		err := r.unsuspend(operation)
+		if err != nil {
+			return errors.Wrapf(err, "while resuming reprovisioning of instance %s", operation.InstanceID)
+		}
+	}
+	return nil
+}
+
+// OnDeprovisioningStepProcessed unsuspends the runtime when its suspension for reprovisioning succeeds
+func (r *Reprovisioner) OnDeprovisioningStepProcessed(_ context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected DeprovisioningStepProcessed but got %+v", ev)
+	}
+	operation := stepProcessed.Operation
+	if !operation.Reprovisioning || operation.State != domain.Succeeded || stepProcessed.OldOperation.State == domain.Succeeded {
+		return nil
+	}
+
+	return r.unsuspend(operation)
+}
+
+// unsuspend starts the unsuspension of the runtime suspended by the given operation, unless the runtime
+// was already unsuspended or suspended again after the operation
+func (r *Reprovisioner) unsuspend(operation internal.DeprovisioningOperation) error {
+	l := r.log.WithFields(logrus.Fields{
+		"instanceID":  operation.InstanceID,
+		"operationID": operation.ID,
+	})
+
+	// the event can be delivered more than once, and the operation is processed again by Resume after a restart
+	lastProvisioning, err := r.operations.GetProvisioningOperationByInstanceID(operation.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "while getting provisioning operation")
+	}
+	if lastProvisioning.CreatedAt.After(operation.CreatedAt) {
+		l.Info("Runtime already unsuspended after the reprovisioning suspension, skipping")
+		return nil
+	}
+	lastDeprovisioning, err := r.operations.GetDeprovisioningOperationByInstanceID(operation.InstanceID)
+	if err != nil {
+		return errors.Wrap(err, "while getting deprovisioning operation")
+	}
+	if lastDeprovisioning.ID != operation.ID {
+		l.Info("Runtime suspended or deprovisioned after the reprovisioning suspension, skipping")
+		return nil
+	}
+
+	instance, err := r.instances.GetByID(operation.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		l.Info("Instance already deprovisioned, skipping")
+		return nil
+	case err != nil:
+		return errors.Wrap(err, "while getting instance")
+	}
+	l.Info("Suspension for reprovisioning succeeded, unsuspending the runtime")
+
+	return r.handler.unsuspend(instance, l)
+}
@@ -0,0 +1,155 @@
+package suspension
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReprovisioner(t *testing.T) {
+	t.Run("should unsuspend runtime when suspension for reprovisioning succeeded", func(t *testing.T) {
+		// given
+		provisioning := NewDummyQueue()
+		deprovisioning := NewDummyQueue()
+		st := storage.NewMemoryStorage()
+		instance := fixInstance(fixActiveErsContext())
+		require.NoError(t, st.Instances().Insert(*instance))
+		provisioningOp := fixture.FixProvisioningOperation("provisioning-id", instance.InstanceID)
+		provisioningOp.CreatedAt = time.Now().Add(-time.Hour)
+		require.NoError(t, st.Operations().InsertProvisioningOperation(provisioningOp))
+
+		svc := NewReprovisioner(st.Operations(), st.Instances(), NewContextUpdateHandler(st.Operations(), provisioning, deprovisioning, logrus.New()), logrus.New())
+
+		// when
+		operationID, err := svc.Reprovision(instance)
+		require.NoError(t, err)
+
+		// then
+		suspensionOp, err := st.Operations().GetDeprovisioningOperationByID(operationID)
+		require.NoError(t, err)
+		assert.True(t, suspensionOp.Temporary)
+		assert.True(t, suspensionOp.Reprovisioning)
+		assertQueue(t, deprovisioning, operationID)
+		assertQueue(t, provisioning)
+
+		// when
+		stepProcessed := fixSuspensionSucceeded(*suspensionOp)
+		require.NoError(t, svc.OnDeprovisioningStepProcessed(context.Background(), stepProcessed))
+
+		// then
+		unsuspensionOp, err := st.Operations().GetProvisioningOperationByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		assert.NotEqual(t, provisioningOp.ID, unsuspensionOp.ID)
+		assertQueue(t, provisioning, unsuspensionOp.ID)
+
+		// when the event is delivered again
+		require.NoError(t, svc.OnDeprovisioningStepProcessed(context.Background(), stepProcessed))
+
+		// then
+		assertQueue(t, provisioning, unsuspensionOp.ID)
+	})
+
+	t.Run("should not unsuspend runtime after suspension not requested by reprovisioning", func(t *testing.T) {
+		// given
+		provisioning := NewDummyQueue()
+		st := storage.NewMemoryStorage()
+		instance := fixInstance(fixActiveErsContext())
+		require.NoError(t, st.Instances().Insert(*instance))
+
+		svc := NewReprovisioner(st.Operations(), st.Instances(), NewContextUpdateHandler(st.Operations(), provisioning, NewDummyQueue(), logrus.New()), logrus.New())
+		suspensionOp := fixture.FixDeprovisioningOperation("suspension-id", instance.InstanceID)
+		suspensionOp.Temporary = true
+
+		// when
+		err := svc.OnDeprovisioningStepProcessed(context.Background(), fixSuspensionSucceeded(suspensionOp))
+
+		// then
+		require.NoError(t, err)
+		assertQueue(t, provisioning)
+	})
+}
+
+func TestReprovisioner_Resume(t *testing.T) {
+	// given
+	provisioning := NewDummyQueue()
+	st := storage.NewMemoryStorage()
+	instance := fixInstance(fixActiveErsContext())
+	require.NoError(t, st.Instances().Insert(*instance))
+	provisioningOp := fixture.FixProvisioningOperation("provisioning-id", instance.InstanceID)
+	provisioningOp.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, st.Operations().InsertProvisioningOperation(provisioningOp))
+
+	suspensionOp := fixture.FixDeprovisioningOperation("suspension-id", instance.InstanceID)
+	suspensionOp.Temporary = true
+	suspensionOp.Reprovisioning = true
+	suspensionOp.State = domain.Succeeded
+	require.NoError(t, st.Operations().InsertDeprovisioningOperation(suspensionOp))
+
+	svc := NewReprovisioner(st.Operations(), st.Instances(), NewContextUpdateHandler(st.Operations(), provisioning, NewDummyQueue(), logrus.New()), logrus.New())
+
+	// when the unsuspension was not started before the restart
+	require.NoError(t, svc.Resume())
+
+	// then
+	unsuspensionOp, err := st.Operations().GetProvisioningOperationByInstanceID(instance.InstanceID)
+	require.NoError(t, err)
+	assert.NotEqual(t, provisioningOp.ID, unsuspensionOp.ID)
+	assertQueue(t, provisioning, unsuspensionOp.ID)
+
+	// when the unsuspension was already started
+	require.NoError(t, svc.Resume())
+
+	// then
+	assertQueue(t, provisioning, unsuspensionOp.ID)
+}
+
+func TestReprovisioner_Resume_SuspendedAgain(t *testing.T) {
+	// given
+	provisioning := NewDummyQueue()
+	st := storage.NewMemoryStorage()
+	instance := fixInstance(fixActiveErsContext())
+	require.NoError(t, st.Instances().Insert(*instance))
+	provisioningOp := fixture.FixProvisioningOperation("provisioning-id", instance.InstanceID)
+	provisioningOp.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, st.Operations().InsertProvisioningOperation(provisioningOp))
+
+	reprovisioningOp := fixture.FixDeprovisioningOperation("reprovisioning-id", instance.InstanceID)
+	reprovisioningOp.Temporary = true
+	reprovisioningOp.Reprovisioning = true
+	reprovisioningOp.State = domain.Succeeded
+	reprovisioningOp.CreatedAt = time.Now().Add(-time.Minute)
+	require.NoError(t, st.Operations().InsertDeprovisioningOperation(reprovisioningOp))
+	suspensionOp := fixture.FixDeprovisioningOperation("suspension-id", instance.InstanceID)
+	suspensionOp.Temporary = true
+	require.NoError(t, st.Operations().InsertDeprovisioningOperation(suspensionOp))
+
+	svc := NewReprovisioner(st.Operations(), st.Instances(), NewContextUpdateHandler(st.Operations(), provisioning, NewDummyQueue(), logrus.New()), logrus.New())
+
+	// when
+	err := svc.Resume()
+
+	// then
+	require.NoError(t, err)
+	assertQueue(t, provisioning)
+}
+
+func fixSuspensionSucceeded(operation internal.DeprovisioningOperation) process.DeprovisioningStepProcessed {
+	oldOperation := operation
+	oldOperation.State = domain.InProgress
+	operation.State = domain.Succeeded
+
+	return process.DeprovisioningStepProcessed{
+		StepProcessed: process.StepProcessed{StepName: "Deprovision_Initialization"},
+		OldOperation:  oldOperation,
+		Operation:     operation,
+	}
+}
@@ -0,0 +1,298 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	OperationEventType     = "operation"
+	OrchestrationEventType = "orchestration"
+
+	EventIDHeader   = "X-KEB-Event-ID"
+	EventTypeHeader = "X-KEB-Event-Type"
+	// SignatureHeader contains the hex encoded HMAC-SHA256 of the request body prefixed with "sha256="
+	SignatureHeader = "X-KEB-Signature"
+)
+
+type Config struct {
+	Disabled bool `envconfig:"default=true"`
+	// URLs is a comma separated list of endpoints which receive the notifications
+	URLs []string `envconfig:"optional"`
+	// Secret is the key used to sign the payloads, payloads are not signed if it is empty
+	Secret string `envconfig:"optional"`
+
+	MaxAttempts      int           `envconfig:"default=5"`
+	RetryInterval    time.Duration `envconfig:"default=5s"`
+	MaxRetryInterval time.Duration `envconfig:"default=5m"`
+	Timeout          time.Duration `envconfig:"default=10s"`
+}
+
+// Payload is the JSON body sent to the webhooks
+type Payload struct {
+	ID            string             `json:"id"`
+	Type          string             `json:"type"`
+	Timestamp     time.Time          `json:"timestamp"`
+	Operation     *OperationData     `json:"operation,omitempty"`
+	Orchestration *OrchestrationData `json:"orchestration,omitempty"`
+}
+
+type OperationData struct {
+	OperationID     string `json:"operationID"`
+	OperationType   string `json:"operationType"`
+	InstanceID      string `json:"instanceID"`
+	RuntimeID       string `json:"runtimeID,omitempty"`
+	OrchestrationID string `json:"orchestrationID,omitempty"`
+	State           string `json:"state"`
+	OldState        string `json:"oldState"`
+	Description     string `json:"description"`
+	StepName        string `json:"stepName"`
+	Error           string `json:"error,omitempty"`
+}
+
+type OrchestrationData struct {
+	OrchestrationID string `json:"orchestrationID"`
+	Type            string `json:"type"`
+	State           string `json:"state"`
+	OldState        string `json:"oldState"`
+	Description     string `json:"description"`
+}
+
+// Notifier sends the operation and orchestration state changes to the configured webhooks.
+// Notifications which cannot be delivered after all attempts are stored as dead letters.
+type Notifier struct {
+	config      Config
+	httpClient  *http.Client
+	deadLetters storage.WebhookDeadLetters
+	log         logrus.FieldLogger
+}
+
+func NewNotifier(config Config, deadLetters storage.WebhookDeadLetters, log logrus.FieldLogger) *Notifier {
+	return &Notifier{
+		config:      config,
+		httpClient:  &http.Client{Timeout: config.Timeout},
+		deadLetters: deadLetters,
+		log:         log,
+	}
+}
+
+// Subscribe registers the notifier handlers for all supported events
+func (n *Notifier) Subscribe(sub event.Subscriber) {
+	sub.Subscribe("webhook.provisioning", process.ProvisioningStepProcessed{}, n.OnProvisioningStepProcessed)
+	sub.Subscribe("webhook.deprovisioning", process.DeprovisioningStepProcessed{}, n.OnDeprovisioningStepProcessed)
+	sub.Subscribe("webhook.upgradeKyma", process.UpgradeKymaStepProcessed{}, n.OnUpgradeKymaStepProcessed)
+	sub.Subscribe("webhook.upgradeCluster", process.UpgradeClusterStepProcessed{}, n.OnUpgradeClusterStepProcessed)
+	sub.Subscribe("webhook.orchestration", process.OrchestrationStateChanged{}, n.OnOrchestrationStateChanged)
+}
+
+func (n *Notifier) OnProvisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.ProvisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected ProvisioningStepProcessed but got %+v", ev)
+	}
+	return n.onStepProcessed(ctx, stepProcessed.StepProcessed, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+}
+
+func (n *Notifier) OnDeprovisioningStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.DeprovisioningStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected DeprovisioningStepProcessed but got %+v", ev)
+	}
+	return n.onStepProcessed(ctx, stepProcessed.StepProcessed, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+}
+
+func (n *Notifier) OnUpgradeKymaStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.UpgradeKymaStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected UpgradeKymaStepProcessed but got %+v", ev)
+	}
+	return n.onStepProcessed(ctx, stepProcessed.StepProcessed, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+}
+
+func (n *Notifier) OnUpgradeClusterStepProcessed(ctx context.Context, ev interface{}) error {
+	stepProcessed, ok := ev.(process.UpgradeClusterStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected UpgradeClusterStepProcessed but got %+v", ev)
+	}
+	return n.onStepProcessed(ctx, stepProcessed.StepProcessed, stepProcessed.OldOperation.Operation, stepProcessed.Operation.Operation)
+}
+
+func (n *Notifier) OnOrchestrationStateChanged(ctx context.Context, ev interface{}) error {
+	stateChanged, ok := ev.(process.OrchestrationStateChanged)
+	if !ok {
+		return fmt.Errorf("expected OrchestrationStateChanged but got %+v", ev)
+	}
+	o := stateChanged.Orchestration
+
+	return n.notify(ctx, Payload{
+		ID:        uuid.New().String(),
+		Type:      OrchestrationEventType,
+		Timestamp: time.Now(),
+		Orchestration: &OrchestrationData{
+			OrchestrationID: o.OrchestrationID,
+			Type:            string(o.Type),
+			State:           o.State,
+			OldState:        stateChanged.OldState,
+			Description:     o.Description,
+		},
+	})
+}
+
+// onStepProcessed sends a notification only if the step changed the state of the operation,
+// steps which are retried or succeed without finishing the operation are not reported
+func (n *Notifier) onStepProcessed(ctx context.Context, step process.StepProcessed, oldOp, op internal.Operation) error {
+	if oldOp.State == op.State {
+		return nil
+	}
+
+	data := &OperationData{
+		OperationID:     op.ID,
+		OperationType:   string(op.Type),
+		InstanceID:      op.InstanceID,
+		RuntimeID:       op.RuntimeID,
+		OrchestrationID: op.OrchestrationID,
+		State:           string(op.State),
+		OldState:        string(oldOp.State),
+		Description:     op.Description,
+		StepName:        step.StepName,
+	}
+	if step.Error != nil {
+		data.Error = step.Error.Error()
+	}
+
+	return n.notify(ctx, Payload{
+		ID:        uuid.New().String(),
+		Type:      OperationEventType,
+		Timestamp: time.Now(),
+		Operation: data,
+	})
+}
+
+func (n *Notifier) notify(ctx context.Context, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "while marshalling webhook payload %s", payload.ID)
+	}
+
+	var wg sync.WaitGroup
+	for _, url := range n.config.URLs {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			n.deliver(ctx, url, payload, body)
+		}(url)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// deliver sends the payload to the given URL with an exponential backoff. If all attempts fail
+// or the webhook rejects the payload, the notification is stored as a dead letter.
+func (n *Notifier) deliver(ctx context.Context, url string, payload Payload, body []byte) {
+	log := n.log.WithField("webhook", url).WithField("eventID", payload.ID)
+
+	interval := n.config.RetryInterval
+	for attempt := 1; ; attempt++ {
+		retryable, err := n.post(ctx, url, payload, body)
+		if err == nil {
+			log.Debugf("webhook notified after %d attempt(s)", attempt)
+			return
+		}
+		log.Warnf("attempt %d of webhook notification failed: %v", attempt, err)
+
+		if !retryable || attempt >= n.config.MaxAttempts || !sleep(ctx, interval) {
+			log.Errorf("giving up webhook notification after %d attempt(s): %v", attempt, err)
+			n.storeDeadLetter(url, payload, body, attempt, err, log)
+			return
+		}
+
+		interval *= 2
+		if interval > n.config.MaxRetryInterval {
+			interval = n.config.MaxRetryInterval
+		}
+	}
+}
+
+func (n *Notifier) storeDeadLetter(url string, payload Payload, body []byte, attempts int, lastErr error, log logrus.FieldLogger) {
+	err := n.deadLetters.Insert(internal.WebhookDeadLetter{
+		ID:        uuid.New().String(),
+		URL:       url,
+		EventType: payload.Type,
+		Payload:   string(body),
+		Attempts:  attempts,
+		LastError: lastErr.Error(),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Errorf("while storing webhook dead letter: %v", err)
+	}
+}
+
+// post sends the payload and returns whether a failed request is worth retrying
+func (n *Notifier) post(ctx context.Context, url string, payload Payload, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "while creating request")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, payload.ID)
+	req.Header.Set(EventTypeHeader, payload.Type)
+	if n.config.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.config.Secret, body))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return true, errors.Wrap(err, "while sending request")
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, errors.Errorf("unexpected status code %d", resp.StatusCode)
+	default:
+		return false, errors.Errorf("webhook rejected the notification with status code %d", resp.StatusCode)
+	}
+}
+
+// sleep waits for the given duration, returns false if the context was canceled in the meantime
+func sleep(ctx context.Context, d time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,238 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v7/domain"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fixSecret = "secret"
+
+func TestNotifier_OnProvisioningStepProcessed(t *testing.T) {
+	t.Run("should send signed notification when operation state changed", func(t *testing.T) {
+		// given
+		srv := newWebhookServer(t, http.StatusOK)
+		defer srv.Close()
+		db := storage.NewMemoryStorage()
+		notifier := NewNotifier(fixConfig(srv.URL), db.WebhookDeadLetters(), logrus.New())
+
+		// when
+		err := notifier.OnProvisioningStepProcessed(context.TODO(), fixProvisioningStepProcessed(domain.InProgress, domain.Failed))
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, srv.requests(), 1)
+		req := srv.requests()[0]
+		assert.Equal(t, "sha256="+Sign(fixSecret, req.body), req.signature)
+		assert.Equal(t, OperationEventType, req.payload.Type)
+		require.NotNil(t, req.payload.Operation)
+		assert.Equal(t, "op-id", req.payload.Operation.OperationID)
+		assert.Equal(t, "runtime-id", req.payload.Operation.RuntimeID)
+		assert.Equal(t, string(domain.Failed), req.payload.Operation.State)
+		assert.Equal(t, string(domain.InProgress), req.payload.Operation.OldState)
+		assert.Equal(t, "Create_Runtime", req.payload.Operation.StepName)
+		assert.Equal(t, "provisioner unavailable", req.payload.Operation.Error)
+	})
+
+	t.Run("should not send notification when operation state did not change", func(t *testing.T) {
+		// given
+		srv := newWebhookServer(t, http.StatusOK)
+		defer srv.Close()
+		db := storage.NewMemoryStorage()
+		notifier := NewNotifier(fixConfig(srv.URL), db.WebhookDeadLetters(), logrus.New())
+
+		// when
+		err := notifier.OnProvisioningStepProcessed(context.TODO(), fixProvisioningStepProcessed(domain.InProgress, domain.InProgress))
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, srv.requests())
+	})
+}
+
+func TestNotifier_OnOrchestrationStateChanged(t *testing.T) {
+	// given
+	srv := newWebhookServer(t, http.StatusServiceUnavailable, http.StatusOK)
+	defer srv.Close()
+	db := storage.NewMemoryStorage()
+	notifier := NewNotifier(fixConfig(srv.URL), db.WebhookDeadLetters(), logrus.New())
+
+	// when
+	err := notifier.OnOrchestrationStateChanged(context.TODO(), process.OrchestrationStateChanged{
+		OldState: orchestration.InProgress,
+		Orchestration: internal.Orchestration{
+			OrchestrationID: "orchestration-id",
+			Type:            orchestration.UpgradeKymaOrchestration,
+			State:           orchestration.Failed,
+		},
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, srv.requests(), 2)
+	payload := srv.requests()[1].payload
+	assert.Equal(t, OrchestrationEventType, payload.Type)
+	require.NotNil(t, payload.Orchestration)
+	assert.Equal(t, "orchestration-id", payload.Orchestration.OrchestrationID)
+	assert.Equal(t, orchestration.Failed, payload.Orchestration.State)
+	assert.Equal(t, srv.requests()[0].payload.ID, payload.ID)
+
+	letters, err := db.WebhookDeadLetters().List()
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestNotifier_DeadLetters(t *testing.T) {
+	t.Run("should store dead letter after all attempts failed", func(t *testing.T) {
+		// given
+		srv := newWebhookServer(t, http.StatusInternalServerError)
+		defer srv.Close()
+		db := storage.NewMemoryStorage()
+		notifier := NewNotifier(fixConfig(srv.URL), db.WebhookDeadLetters(), logrus.New())
+
+		// when
+		err := notifier.OnProvisioningStepProcessed(context.TODO(), fixProvisioningStepProcessed(domain.InProgress, domain.Succeeded))
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, srv.requests(), 3)
+
+		letters, err := db.WebhookDeadLetters().List()
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, srv.URL, letters[0].URL)
+		assert.Equal(t, OperationEventType, letters[0].EventType)
+		assert.Equal(t, 3, letters[0].Attempts)
+		assert.Equal(t, string(srv.requests()[0].body), letters[0].Payload)
+		assert.Contains(t, letters[0].LastError, "500")
+	})
+
+	t.Run("should not retry rejected notification", func(t *testing.T) {
+		// given
+		srv := newWebhookServer(t, http.StatusBadRequest)
+		defer srv.Close()
+		db := storage.NewMemoryStorage()
+		notifier := NewNotifier(fixConfig(srv.URL), db.WebhookDeadLetters(), logrus.New())
+
+		// when
+		err := notifier.OnProvisioningStepProcessed(context.TODO(), fixProvisioningStepProcessed(domain.InProgress, domain.Succeeded))
+
+		// then
+		require.NoError(t, err)
+		assert.Len(t, srv.requests(), 1)
+
+		letters, err := db.WebhookDeadLetters().List()
+		require.NoError(t, err)
+		require.Len(t, letters, 1)
+		assert.Equal(t, 1, letters[0].Attempts)
+	})
+}
+
+func TestNotifier_WrongEvent(t *testing.T) {
+	// given
+	notifier := NewNotifier(fixConfig(), storage.NewMemoryStorage().WebhookDeadLetters(), logrus.New())
+
+	// when
+	err := notifier.OnDeprovisioningStepProcessed(context.TODO(), errors.New("not an event"))
+
+	// then
+	assert.Error(t, err)
+}
+
+func fixConfig(urls ...string) Config {
+	return Config{
+		URLs:             urls,
+		Secret:           fixSecret,
+		MaxAttempts:      3,
+		RetryInterval:    time.Millisecond,
+		MaxRetryInterval: 5 * time.Millisecond,
+		Timeout:          time.Second,
+	}
+}
+
+func fixProvisioningStepProcessed(oldState, newState domain.LastOperationState) process.ProvisioningStepProcessed {
+	op := internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         "op-id",
+			Type:       internal.OperationTypeProvision,
+			InstanceID: "instance-id",
+			State:      oldState,
+			InstanceDetails: internal.InstanceDetails{
+				RuntimeID: "runtime-id",
+			},
+		},
+	}
+	oldOp := op
+	op.State = newState
+
+	return process.ProvisioningStepProcessed{
+		StepProcessed: process.StepProcessed{
+			StepName: "Create_Runtime",
+			Error:    errors.New("provisioner unavailable"),
+		},
+		OldOperation: oldOp,
+		Operation:    op,
+	}
+}
+
+type receivedRequest struct {
+	signature string
+	body      []byte
+	payload   Payload
+}
+
+type webhookServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	received []receivedRequest
+}
+
+// newWebhookServer returns a server which responds with the given status codes, the last one is repeated
+func newWebhookServer(t *testing.T, statusCodes ...int) *webhookServer {
+	srv := &webhookServer{}
+	srv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var payload Payload
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, payload.ID, r.Header.Get(EventIDHeader))
+		assert.Equal(t, payload.Type, r.Header.Get(EventTypeHeader))
+
+		srv.mu.Lock()
+		defer srv.mu.Unlock()
+		srv.received = append(srv.received, receivedRequest{
+			signature: r.Header.Get(SignatureHeader),
+			body:      body,
+			payload:   payload,
+		})
+		code := statusCodes[len(statusCodes)-1]
+		if len(srv.received) <= len(statusCodes) {
+			code = statusCodes[len(srv.received)-1]
+		}
+		w.WriteHeader(code)
+	}))
+
+	return srv
+}
+
+func (s *webhookServer) requests() []receivedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedRequest(nil), s.received...)
+}
@@ -11,19 +11,23 @@ import (
 	"github.com/kyma-project/control-plane/components/metris/internal/log"
 	"github.com/kyma-project/control-plane/components/metris/internal/provider"
 	"github.com/kyma-project/control-plane/components/metris/internal/service"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
 	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
 	"github.com/kyma-project/control-plane/components/metris/internal/utils"
 	"github.com/kyma-project/control-plane/components/metris/internal/version"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	// import to initialize provider.
+	_ "github.com/kyma-project/control-plane/components/metris/internal/provider/aws"
 	_ "github.com/kyma-project/control-plane/components/metris/internal/provider/azure"
+	_ "github.com/kyma-project/control-plane/components/metris/internal/provider/gcp"
 )
 
 type cli struct {
 	EDPConfig      edp.Config       `kong:"embed=true,prefix='edp-'"`
-	ProviderType   string           `kong:"help='Provider to fetch metrics from. (${providers})',enum='${providers}',env='PROVIDER_TYPE',required=true,default='az',hidden=true"`
+	ProviderTypes  []string         `kong:"name='provider-type',help='Comma-separated list of providers to fetch metrics from. (${providers})',enum='${providers}',env='PROVIDER_TYPE',required=true,default='az',hidden=true"`
 	ProviderConfig provider.Config  `kong:"embed=true,prefix='provider-'"`
+	StorageConfig  storage.Config   `kong:"embed=true,prefix='storage-'"`
 	ListenAddr     string           `kong:"help='Address and port the metrics and health HTTP endpoints will bind to.',optional=true,env='METRIS_LISTEN_ADDRESS'"`
 	DebugPort      int              `kong:"help='Port the debug HTTP endpoint will bind to. Always listen on localhost.',optional=true,env='METRIS_DEBUG_PORT'"`
 	Tracing        tracing.Config   `kong:"embed=true"`
@@ -35,7 +39,7 @@ type cli struct {
 
 func main() {
 	app := cli{}
-	_ = kong.Parse(&app,
+	parser := kong.Parse(&app,
 		kong.Name("metris"),
 		kong.Description("Metris is a metering component that collects data and sends them to EDP."),
 		kong.UsageOnError(),
@@ -43,11 +47,13 @@ func main() {
 		kong.Vars{
 			"version":   version.Print(),
 			"loglevels": "debug,info,warn,error",
-			"providers": "az",
+			"providers": "az,aws,gcp",
 		},
 		kong.Configuration(kong.JSON, ""),
 	)
 
+	parser.FatalIfErrorf(app.EDPConfig.Validate())
+
 	log.SetLogLevel(app.LogLevel)
 
 	if app.Tracing.Enable {
@@ -69,19 +75,60 @@ func main() {
 
 	// start edp event handler
 	edpclient := edp.NewClient(&app.EDPConfig, nil, eventChannel, log.Named("edp"))
-	g.AddWithContext(edpclient.Start)
 
-	// start provider to fetch metrics from the clusters
-	app.ProviderConfig.ClusterChannel = clusterChannel
-	app.ProviderConfig.EventsChannel = eventChannel
-	app.ProviderConfig.Logger = log.Named(app.ProviderType)
+	if len(app.EDPConfig.SpoolDir) > 0 && app.EDPConfig.HasSink(edp.SinkEDP) {
+		spool, err := edp.OpenSpool(app.EDPConfig.SpoolDir, app.EDPConfig.SpoolMaxSize, app.EDPConfig.SpoolSegmentSize)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		edpclient.SetSpool(spool)
+	}
+
+	if app.EDPConfig.HasSink(edp.SinkFile) {
+		sink, err := edp.NewFileSink(app.EDPConfig.FileSinkDir)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		edpclient.AddSink(edp.SinkFile, sink)
+	}
 
-	pro, err := provider.NewProvider(app.ProviderType, &app.ProviderConfig)
+	if app.EDPConfig.HasSink(edp.SinkPrometheus) {
+		edpclient.AddSink(edp.SinkPrometheus, edp.NewPrometheusSink(app.EDPConfig.PrometheusSinkTTL))
+	}
+
+	g.AddWithContext(edpclient.Start)
+
+	// restore the provider storages before the gardener controller resyncs the clusters
+	storageBackend, err := storage.NewBackend(&app.StorageConfig, log.Named("storage"))
 	if err != nil {
 		log.Panic(err)
 	}
 
-	g.AddWithContext(pro.Run)
+	// start providers to fetch metrics from the clusters, the clusters are routed to them by provider type
+	router := provider.NewRouter(clusterChannel, log.Named("router"))
+	health := provider.NewHealth()
+
+	for _, providerType := range app.ProviderTypes {
+		providerConfig := app.ProviderConfig
+		providerConfig.Name = providerType
+		providerConfig.ClusterChannel = make(chan *gardener.Cluster, app.ProviderConfig.Buffer)
+		providerConfig.EventsChannel = eventChannel
+		providerConfig.Logger = log.Named(providerType)
+		providerConfig.Storage = storageBackend
+
+		pro, err := provider.NewProvider(providerType, &providerConfig)
+		if err != nil {
+			log.Panic(err)
+		}
+
+		router.AddRoute(providerType, providerConfig.ClusterChannel)
+		g.AddWithContext(health.Run(providerType, pro))
+	}
+
+	g.AddWithContext(router.Run)
+	g.AddWithContext(storageBackend.Run)
 
 	// start gardener controller to sync clusters with provider
 	gclient, err := gardener.NewClient(app.Kubeconfig)
@@ -89,7 +136,7 @@ func main() {
 		log.Panic(err)
 	}
 
-	ctrl, err := gardener.NewController(gclient, app.ProviderType, clusterChannel, log.Named("gardener"))
+	ctrl, err := gardener.NewController(gclient, app.ProviderTypes, clusterChannel, log.Named("gardener"))
 	if err != nil {
 		log.Panic(err)
 	}
@@ -105,9 +152,8 @@ func main() {
 		}
 
 		metrissvc.ServeMux.Handle("/metrics", promhttp.Handler())
-		metrissvc.ServeMux.Handle("/healthz", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-		}))
+		metrissvc.ServeMux.Handle("/healthz", health)
+		// provider health: curl http://127.0.0.1:8080/healthz?provider=az
 		metrissvc.ServeMux.HandleFunc("/logz", log.LevelHandler)
 		// set loglevel: curl -X PUT -d '{"level":"debug"}' http://127.0.0.1:8080/logz
 
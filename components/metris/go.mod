@@ -11,6 +11,7 @@ require (
 	github.com/Azure/go-autorest/autorest/date v0.3.0
 	github.com/Azure/go-autorest/tracing/opencensus v0.1.0
 	github.com/alecthomas/kong v0.2.9
+	github.com/aws/aws-sdk-go-v2 v1.16.16
 	github.com/gardener/gardener v1.12.8
 	github.com/kr/text v0.2.0 // indirect
 	github.com/mitchellh/mapstructure v1.3.3
@@ -20,6 +21,7 @@ require (
 	github.com/stretchr/testify v1.6.1
 	go.opencensus.io v0.22.5
 	go.uber.org/zap v1.16.0
+	golang.org/x/oauth2 v0.0.0-20191202225959-858c2ad4c8b6
 	gopkg.in/check.v1 v1.0.0-20200902074654-038fdea0a05b // indirect
 	k8s.io/api v0.18.16
 	k8s.io/apimachinery v0.18.16
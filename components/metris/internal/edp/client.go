@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
@@ -59,16 +60,50 @@ func NewClient(c *Config, httpClient *http.Client, eventsChannel <-chan *Event,
 	}
 }
 
+// SetSpool sets the spool keeping the events on disk until they are sent, it must be called before Start.
+func (c *Client) SetSpool(spool *Spool) {
+	c.spool = spool
+}
+
+// AddSink adds a sink the events are written to when they are received, in addition to EDP.
+// It must be called before Start.
+func (c *Client) AddSink(name string, sink Sink) {
+	if c.sinks == nil {
+		c.sinks = make(map[string]Sink)
+	}
+
+	c.sinks[name] = sink
+}
+
 // Start starts worker processes, which wait for event to process from the queue.
+// The events are only queued to be sent to EDP if the edp sink is enabled.
 func (c *Client) Start(ctx context.Context) {
 	c.logger.Info("ingester started")
 
+	sendToEDP := c.config.HasSink(SinkEDP)
+
+	if c.spool != nil && sendToEDP {
+		replay := c.spool.Replay()
+		c.logger.Infof("replaying %d events from the spool", len(replay))
+
+		for _, event := range replay {
+			c.queue.Add(event)
+		}
+	}
+
 	// start the event handler
 	go func() {
 		for {
 			select {
 			case event := <-c.eventsChannel:
 				c.logger.Debug("event received")
+
+				c.writeSinks(ctx, event)
+
+				if !sendToEDP || !c.spoolEvent(event) {
+					continue
+				}
+
 				c.queue.Add(event)
 			case <-ctx.Done():
 				c.logger.Debug("event queue shutting down")
@@ -104,14 +139,75 @@ func (c *Client) Start(ctx context.Context) {
 	}
 
 	wg.Wait()
+
+	if c.spool != nil {
+		if err := c.spool.Close(); err != nil {
+			c.logger.With("error", err).Error("could not close the spool")
+		}
+	}
+
+	for name, sink := range c.sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				c.logger.With("sink", name).With("error", err).Error("could not close the sink")
+			}
+		}
+	}
+
 	c.logger.Info("ingester stopped")
 }
 
+// writeSinks writes the event to the additional sinks, the event is not retried if a sink fails.
+func (c *Client) writeSinks(ctx context.Context, event *Event) {
+	for name, sink := range c.sinks {
+		if err := sink.Write(ctx, event, c.logger); err != nil {
+			c.logger.With("sink", name).With("datatenant", event.Datatenant).With("error", err).Error("could not write the event to the sink")
+			sinkErrors.WithLabelValues(name).Inc()
+		}
+	}
+}
+
+// spoolEvent writes the event to the spool, it returns false if the event must be dropped because the spool is full.
+func (c *Client) spoolEvent(event *Event) bool {
+	if c.spool == nil {
+		return true
+	}
+
+	err := c.spool.Append(event)
+
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, ErrSpoolFull):
+		c.logger.With("datatenant", event.Datatenant).Error("spool is full, dropping the event")
+		droppedEvent.WithLabelValues("spool_full").Inc()
+
+		return false
+	default:
+		// the event can still be sent, but it won't survive a restart
+		c.logger.With("error", err).Error("could not write the event to the spool, keeping it only in memory")
+
+		return true
+	}
+}
+
+// ack removes the event from the spool.
+func (c *Client) ack(event *Event, logger log.Logger) {
+	if c.spool == nil {
+		return
+	}
+
+	if err := c.spool.Ack(event); err != nil {
+		logger.With("error", err).Error("could not acknowledge the event in the spool, it will be sent again after a restart")
+	}
+}
+
 // handleErr checks if an error happened and requeue the event.
 func (c *Client) handleErr(err error, event *Event, logger log.Logger) {
 	if err == nil {
 		// if no error, clear number of queue history
 		c.queue.Forget(event)
+		c.ack(event, logger)
 
 		return
 	}
@@ -119,6 +215,8 @@ func (c *Client) handleErr(err error, event *Event, logger log.Logger) {
 	// if the error is an unmarshall one, we remove it from the queue
 	if errors.Is(err, ErrEventMarshal) {
 		logger.Error(err)
+		droppedEvent.WithLabelValues("marshal").Inc()
+		c.ack(event, logger)
 
 		return
 	}
@@ -138,8 +236,24 @@ func (c *Client) handleErr(err error, event *Event, logger log.Logger) {
 		return
 	}
 
+	// spooled events are kept until EDP is back, unless EDP rejected them
+	if c.spool != nil && event.id != 0 && !isRejected(err) {
+		logger.With("error", err).Errorf("failed %d times to send the event, keeping it in the spool and requeuing in %s", c.config.EventRetry, rateLimiterMaxDelay)
+
+		c.queue.AddAfter(event, rateLimiterMaxDelay)
+
+		return
+	}
+
 	logger.With("error", err, "event", fmt.Sprintf("%+v", event.Data)).Errorf("failed %d times to send the event, removing it out of the queue", c.config.EventRetry)
+	droppedEvent.WithLabelValues("retry").Inc()
 	c.queue.Forget(event)
+	c.ack(event, logger)
+}
+
+// isRejected returns true if EDP rejected the event, sending it again would fail.
+func isRejected(err error) bool {
+	return errors.Is(err, ErrEventInvalidRequest) || errors.Is(err, ErrEventMissingParameters) || errors.Is(err, ErrEventPayloadTooLarge)
 }
 
 // Write sends events(json) to EDP server.
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"testing"
 	"time"
 
@@ -201,3 +202,104 @@ func TestClient_handleErr(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_Spool(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantReplay int
+	}{
+		{
+			name:       "acknowledge sent event",
+			status:     http.StatusCreated,
+			wantReplay: 0,
+		},
+		{
+			name:       "keep event not sent",
+			status:     http.StatusInternalServerError,
+			wantReplay: 1,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // pin!
+
+		t.Run(tt.name, func(t *testing.T) {
+			asserts := assert.New(t)
+
+			dir, err := ioutil.TempDir("", "spool")
+			asserts.NoError(err)
+
+			defer os.RemoveAll(dir)
+
+			spool, err := OpenSpool(dir, 1024*1024, 1024*1024)
+			asserts.NoError(err)
+
+			eventsChannel := make(chan *Event, 1)
+			client := NewClient(defaultconfig, fakeTestClient(t, tt.status, nil), eventsChannel, defaultLogger)
+			client.SetSpool(spool)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			data := json.RawMessage(`{"event":[{"data":"test"}]}`)
+			eventsChannel <- &Event{Datatenant: "bob", Data: &data}
+
+			client.Start(ctx)
+
+			spool, err = OpenSpool(dir, 1024*1024, 1024*1024)
+			asserts.NoError(err)
+			asserts.Len(spool.Replay(), tt.wantReplay)
+			asserts.NoError(spool.Close())
+		})
+	}
+}
+
+func TestClient_handleErrSpool(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantPending int
+	}{
+		{
+			name:        "keep event until EDP is back",
+			err:         statusError(http.StatusInternalServerError),
+			wantPending: 1,
+		},
+		{
+			name:        "drop event rejected by EDP",
+			err:         statusError(http.StatusBadRequest),
+			wantPending: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt // pin!
+
+		t.Run(tt.name, func(t *testing.T) {
+			asserts := assert.New(t)
+
+			dir, err := ioutil.TempDir("", "spool")
+			asserts.NoError(err)
+
+			defer os.RemoveAll(dir)
+
+			spool, err := OpenSpool(dir, 1024*1024, 1024*1024)
+			asserts.NoError(err)
+
+			defer spool.Close()
+
+			client := NewClient(defaultconfig, fakeTestClient(t, http.StatusCreated, nil), nil, defaultLogger)
+			client.SetSpool(spool)
+
+			data := json.RawMessage(`{"event":[{"data":"test"}]}`)
+			event := &Event{Datatenant: "bob", Data: &data}
+			asserts.NoError(spool.Append(event))
+
+			// fail more times than the retries
+			for i := 0; i <= defaultconfig.EventRetry; i++ {
+				client.handleErr(tt.err, event, defaultLogger)
+			}
+
+			asserts.Len(spool.index, tt.wantPending)
+		})
+	}
+}
@@ -26,4 +26,72 @@ var (
 			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10},
 		},
 	)
+
+	droppedEvent = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "edp",
+			Name:      "dropped_events_total",
+			Help:      "Total number of events dropped without being sent to EDP.",
+		},
+		[]string{"reason"},
+	)
+
+	spoolSize = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "edp",
+			Name:      "spool_size_bytes",
+			Help:      "Size of the spool files in bytes.",
+		},
+	)
+
+	spoolPendingEvents = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "edp",
+			Name:      "spool_pending_events",
+			Help:      "Number of events in the spool not sent to EDP yet.",
+		},
+	)
+
+	sinkErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "edp",
+			Name:      "sink_errors_total",
+			Help:      "Total number of events which could not be written to a sink.",
+		},
+		[]string{"sink"},
+	)
+
+	subaccountCPUs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "subaccount",
+			Name:      "provisioned_cpus",
+			Help:      "Number of vCPUs provisioned for the subaccount in its last event.",
+		},
+		[]string{"subaccount"},
+	)
+
+	subaccountMemory = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "subaccount",
+			Name:      "provisioned_ram_gb",
+			Help:      "Memory in GB provisioned for the subaccount in its last event.",
+		},
+		[]string{"subaccount"},
+	)
+
+	subaccountStorage = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "subaccount",
+			Name:      "provisioned_volumes_gb",
+			Help:      "Size in GB of the volumes provisioned for the subaccount in its last event.",
+		},
+		[]string{"subaccount"},
+	)
 )
@@ -0,0 +1,184 @@
+package edp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+)
+
+const (
+	SinkEDP        = "edp"
+	SinkFile       = "file"
+	SinkPrometheus = "prometheus"
+
+	fileSinkPrefix     = "events-"
+	fileSinkExt        = ".ndjson"
+	fileSinkDateFormat = "2006-01-02"
+)
+
+// Sink is the interface that writes the events to a destination.
+type Sink interface {
+	Write(ctx context.Context, event *Event, logger log.Logger) error
+}
+
+var _ Sink = &Client{}
+
+// FileSink writes the events as newline-delimited JSON to a file per day.
+type FileSink struct {
+	dir string
+	// now returns the current time, the files are rotated by UTC day.
+	now func() time.Time
+
+	lock sync.Mutex
+	file *os.File
+	day  string
+}
+
+// fileSinkRecord is a line of the files written by the FileSink.
+type fileSinkRecord struct {
+	Datatenant string `json:"datatenant"`
+	Event      *Event `json:"event"`
+}
+
+// NewFileSink returns a FileSink writing the files to the directory, it is created if needed.
+func NewFileSink(dir string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create file sink directory: %w", err)
+	}
+
+	return &FileSink{dir: dir, now: time.Now}, nil
+}
+
+// Write appends the event to the file of the current day.
+func (s *FileSink) Write(ctx context.Context, event *Event, logger log.Logger) error {
+	line, err := json.Marshal(&fileSinkRecord{Datatenant: event.Datatenant, Event: event})
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEventMarshal, err)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.rotate(); err != nil {
+		return err
+	}
+
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("could not write to file sink: %w", err)
+	}
+
+	return nil
+}
+
+// rotate opens the file of the current day if it is not the one already opened.
+func (s *FileSink) rotate() error {
+	day := s.now().UTC().Format(fileSinkDateFormat)
+	if s.file != nil && s.day == day {
+		return nil
+	}
+
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			return fmt.Errorf("could not close file sink: %w", err)
+		}
+
+		s.file = nil
+	}
+
+	file, err := os.OpenFile(filepath.Join(s.dir, fileSinkPrefix+day+fileSinkExt), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open file sink: %w", err)
+	}
+
+	s.file = file
+	s.day = day
+
+	return nil
+}
+
+// Close closes the file of the current day.
+func (s *FileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+
+	return err
+}
+
+// PrometheusSink exports the consumption of the last event of each subaccount as Prometheus gauges.
+type PrometheusSink struct {
+	// ttl is the time after which the gauges of a subaccount without event are removed.
+	ttl time.Duration
+	now func() time.Time
+
+	lock     sync.Mutex
+	lastSeen map[string]time.Time
+}
+
+// consumption holds the consumption fields common to the events of all providers.
+type consumption struct {
+	Compute *struct {
+		ProvisionedCpus    float64 `json:"provisioned_cpus"`
+		ProvisionedRAMGB   float64 `json:"provisioned_ram_gb"`
+		ProvisionedVolumes struct {
+			SizeGBTotal float64 `json:"size_gb_total"`
+		} `json:"provisioned_volumes"`
+	} `json:"compute"`
+}
+
+// NewPrometheusSink returns a PrometheusSink removing the gauges of the subaccounts without event for longer than ttl.
+func NewPrometheusSink(ttl time.Duration) *PrometheusSink {
+	return &PrometheusSink{ttl: ttl, now: time.Now, lastSeen: make(map[string]time.Time)}
+}
+
+// Write sets the gauges of the event subaccount.
+func (s *PrometheusSink) Write(ctx context.Context, event *Event, logger log.Logger) error {
+	if event.Data == nil {
+		return fmt.Errorf("%w: event has no data", ErrEventMarshal)
+	}
+
+	data := &consumption{}
+	if err := json.Unmarshal(*event.Data, data); err != nil {
+		return fmt.Errorf("%w: %s", ErrEventMarshal, err)
+	}
+
+	if data.Compute == nil {
+		return fmt.Errorf("%w: event has no compute data", ErrEventMarshal)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+
+	subaccountCPUs.WithLabelValues(event.Datatenant).Set(data.Compute.ProvisionedCpus)
+	subaccountMemory.WithLabelValues(event.Datatenant).Set(data.Compute.ProvisionedRAMGB)
+	subaccountStorage.WithLabelValues(event.Datatenant).Set(data.Compute.ProvisionedVolumes.SizeGBTotal)
+	s.lastSeen[event.Datatenant] = now
+
+	// remove the subaccounts of the deleted clusters
+	if s.ttl > 0 {
+		for subaccount, lastSeen := range s.lastSeen {
+			if now.Sub(lastSeen) > s.ttl {
+				subaccountCPUs.DeleteLabelValues(subaccount)
+				subaccountMemory.DeleteLabelValues(subaccount)
+				subaccountStorage.DeleteLabelValues(subaccount)
+				delete(s.lastSeen, subaccount)
+			}
+		}
+	}
+
+	return nil
+}
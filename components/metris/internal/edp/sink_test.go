@@ -0,0 +1,149 @@
+package edp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSink(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "sink")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+
+	now := time.Date(2020, 9, 30, 23, 59, 0, 0, time.UTC)
+	sink.now = func() time.Time { return now }
+
+	// when
+	require.NoError(t, sink.Write(context.Background(), newSpoolTestEvent(1), defaultLogger))
+	require.NoError(t, sink.Write(context.Background(), newSpoolTestEvent(2), defaultLogger))
+
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, sink.Write(context.Background(), newSpoolTestEvent(3), defaultLogger))
+	require.NoError(t, sink.Close())
+
+	// then
+	readLines := func(name string) []map[string]interface{} {
+		file, err := os.Open(filepath.Join(dir, name))
+		require.NoError(t, err)
+
+		defer file.Close()
+
+		var lines []map[string]interface{}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+
+		return lines
+	}
+
+	first := readLines("events-2020-09-30.ndjson")
+	if asserts.Len(first, 2) {
+		asserts.Equal("tenant-1", first[0]["datatenant"])
+		event := first[0]["event"].(map[string]interface{})
+		asserts.Equal(float64(1), event["test"])
+		asserts.NotEmpty(event["timestamp"])
+	}
+
+	asserts.Len(readLines("events-2020-10-01.ndjson"), 1, "should rotate the file by day")
+}
+
+func TestPrometheusSink(t *testing.T) {
+	asserts := assert.New(t)
+
+	sink := NewPrometheusSink(time.Hour)
+
+	now := time.Now()
+	sink.now = func() time.Time { return now }
+
+	data := json.RawMessage(`{"compute": {"provisioned_cpus": 24, "provisioned_ram_gb": 96, "provisioned_volumes": {"size_gb_total": 150}}}`)
+
+	// when
+	require.NoError(t, sink.Write(context.Background(), &Event{Datatenant: "sink-subaccount-1", Data: &data}, defaultLogger))
+
+	// then
+	asserts.Equal(float64(24), testutil.ToFloat64(subaccountCPUs.WithLabelValues("sink-subaccount-1")))
+	asserts.Equal(float64(96), testutil.ToFloat64(subaccountMemory.WithLabelValues("sink-subaccount-1")))
+	asserts.Equal(float64(150), testutil.ToFloat64(subaccountStorage.WithLabelValues("sink-subaccount-1")))
+
+	// when the subaccount has no event for longer than the ttl
+	now = now.Add(2 * time.Hour)
+	require.NoError(t, sink.Write(context.Background(), &Event{Datatenant: "sink-subaccount-2", Data: &data}, defaultLogger))
+
+	// then
+	asserts.NotContains(sink.lastSeen, "sink-subaccount-1")
+	asserts.Contains(sink.lastSeen, "sink-subaccount-2")
+
+	// when the event has no consumption data
+	invalid := json.RawMessage(`{"networking": {}}`)
+	err := sink.Write(context.Background(), &Event{Datatenant: "sink-subaccount-3", Data: &invalid}, defaultLogger)
+
+	// then
+	asserts.Error(err)
+}
+
+func TestClient_Sinks(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "sink")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	sink, err := NewFileSink(dir)
+	require.NoError(t, err)
+
+	config := *defaultconfig
+	config.Sinks = []string{SinkFile}
+
+	eventsChannel := make(chan *Event, 1)
+	client := NewClient(&config, fakeTestClient(t, http.StatusCreated, nil), eventsChannel, defaultLogger)
+	client.AddSink(SinkFile, sink)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	eventsChannel <- newSpoolTestEvent(1)
+
+	// when
+	client.Start(ctx)
+
+	// then
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileSinkExt))
+	require.NoError(t, err)
+	asserts.Len(files, 1)
+	asserts.Equal(0, client.queue.Len(), "should not send the event to EDP")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	asserts := assert.New(t)
+
+	asserts.NoError(defaultconfig.Validate())
+	asserts.True(defaultconfig.HasSink(SinkEDP), "should send the events to EDP by default")
+
+	config := &Config{Sinks: []string{SinkEDP, SinkPrometheus}}
+	asserts.EqualError(config.Validate(), "missing flag --edp-token required by the edp sink")
+
+	config.Sinks = []string{SinkPrometheus}
+	asserts.NoError(config.Validate(), "should not require EDP parameters without the edp sink")
+}
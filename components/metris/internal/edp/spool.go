@@ -0,0 +1,374 @@
+package edp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentExt = ".wal"
+)
+
+var (
+	ErrSpoolFull   = errors.New("spool is full")
+	ErrSpoolClosed = errors.New("spool is closed")
+)
+
+// Spool is an on-disk write-ahead log of the events not sent to EDP yet.
+// Events and their acknowledgements are appended to segment files, a segment is deleted once all its events
+// and the ones of the previous segments are acknowledged.
+type Spool struct {
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	lock sync.Mutex
+	// segments are ordered by sequence number, the last one is the active segment the records are appended to.
+	segments []*segment
+	active   *os.File
+	// index maps the id of the pending events to their segment.
+	index  map[uint64]*segment
+	size   int64
+	nextID uint64
+	// replay holds the pending events found when the spool was opened.
+	replay []*Event
+}
+
+// segment is a file of the spool.
+type segment struct {
+	seq  uint64
+	path string
+	size int64
+	// pending is the number of events of the segment not acknowledged yet.
+	pending int
+}
+
+// record is a line of a segment file, it either holds an event or the acknowledgement of one.
+type record struct {
+	ID         uint64           `json:"id,omitempty"`
+	Ack        uint64           `json:"ack,omitempty"`
+	Datatenant string           `json:"datatenant,omitempty"`
+	Data       *json.RawMessage `json:"data,omitempty"`
+}
+
+// OpenSpool opens the spool in the directory, creating it if needed, and loads the pending events to replay.
+// Appending an event fails when the spool files exceed maxSize bytes, a new segment is started when
+// the active one exceeds segmentSize bytes.
+func OpenSpool(dir string, maxSize, segmentSize int64) (*Spool, error) {
+	if maxSize <= 0 || segmentSize <= 0 {
+		return nil, fmt.Errorf("spool max size and segment size must be positive")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create spool directory: %w", err)
+	}
+
+	s := &Spool{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: segmentSize,
+		index:       make(map[uint64]*segment),
+		nextID:      1,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	// always start a new segment so the loaded ones are never written again
+	if err := s.rotate(); err != nil {
+		return nil, err
+	}
+
+	s.compact()
+
+	return s, nil
+}
+
+// load reads the segments of the spool directory in order and collects the events not acknowledged.
+func (s *Spool) load() error {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("could not read spool directory: %w", err)
+	}
+
+	var (
+		events = make(map[uint64]*Event)
+		order  []uint64
+	)
+
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), segmentExt) {
+			continue
+		}
+
+		seq, err := strconv.ParseUint(strings.TrimSuffix(file.Name(), segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		s.segments = append(s.segments, &segment{seq: seq, path: filepath.Join(s.dir, file.Name())})
+	}
+
+	sort.Slice(s.segments, func(a, b int) bool { return s.segments[a].seq < s.segments[b].seq })
+
+	for _, seg := range s.segments {
+		err := seg.read(func(r *record) {
+			if r.Ack != 0 {
+				if r.Ack >= s.nextID {
+					s.nextID = r.Ack + 1
+				}
+
+				if acked, ok := s.index[r.Ack]; ok {
+					acked.pending--
+					delete(s.index, r.Ack)
+					delete(events, r.Ack)
+				}
+
+				return
+			}
+
+			seg.pending++
+			s.index[r.ID] = seg
+			events[r.ID] = &Event{Datatenant: r.Datatenant, Data: r.Data, id: r.ID}
+			order = append(order, r.ID)
+
+			if r.ID >= s.nextID {
+				s.nextID = r.ID + 1
+			}
+		})
+		if err != nil {
+			return err
+		}
+
+		s.size += seg.size
+	}
+
+	for _, id := range order {
+		if event, ok := events[id]; ok {
+			s.replay = append(s.replay, event)
+		}
+	}
+
+	s.updateMetrics()
+
+	return nil
+}
+
+// read calls fn for each record of the segment, the segment is truncated after the last valid record
+// in case it was not completely written.
+func (seg *segment) read(fn func(r *record)) error {
+	file, err := os.OpenFile(seg.path, os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("could not open spool segment: %w", err)
+	}
+	defer file.Close()
+
+	var (
+		reader = bufio.NewReader(file)
+		offset int64
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF && len(line) == 0 {
+			break
+		}
+
+		r := &record{}
+		if err != nil || json.Unmarshal(line, r) != nil || (r.ID == 0 && r.Ack == 0) {
+			// partially written record, drop everything after the last valid one
+			if err := file.Truncate(offset); err != nil {
+				return fmt.Errorf("could not truncate spool segment: %w", err)
+			}
+
+			break
+		}
+
+		offset += int64(len(line))
+
+		fn(r)
+	}
+
+	seg.size = offset
+
+	return nil
+}
+
+// Replay returns the events which were not acknowledged when the spool was opened, in the order they were appended.
+// The events are returned only once.
+func (s *Spool) Replay() []*Event {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	replay := s.replay
+	s.replay = nil
+
+	return replay
+}
+
+// Append writes the event to the spool and sets its id, it returns ErrSpoolFull if the spool reached its maximum size.
+func (s *Spool) Append(event *Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.active == nil {
+		return ErrSpoolClosed
+	}
+
+	r := &record{ID: s.nextID, Datatenant: event.Datatenant, Data: event.Data}
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrEventMarshal, err)
+	}
+
+	line = append(line, '\n')
+
+	if s.size+int64(len(line)) > s.maxSize {
+		return ErrSpoolFull
+	}
+
+	seg, err := s.write(line)
+	if err != nil {
+		return err
+	}
+
+	seg.pending++
+	s.index[r.ID] = seg
+	s.nextID++
+
+	event.id = r.ID
+
+	s.updateMetrics()
+
+	return nil
+}
+
+// Ack marks the event as sent, the segments with only acknowledged events are deleted.
+// Events which were not appended to the spool are ignored.
+func (s *Spool) Ack(event *Event) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.active == nil {
+		return ErrSpoolClosed
+	}
+
+	seg, ok := s.index[event.id]
+	if !ok {
+		return nil
+	}
+
+	line, err := json.Marshal(&record{Ack: event.id})
+	if err != nil {
+		return err
+	}
+
+	// acknowledgements are always written, even if the spool is full, to be able to compact it
+	if _, err := s.write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	seg.pending--
+	delete(s.index, event.id)
+
+	s.compact()
+	s.updateMetrics()
+
+	return nil
+}
+
+// write appends the line to the active segment, which is rotated first if it is too large, and returns the segment.
+func (s *Spool) write(line []byte) (*segment, error) {
+	if s.segments[len(s.segments)-1].size >= s.segmentSize {
+		if err := s.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.active.Write(line); err != nil {
+		return nil, fmt.Errorf("could not write to spool: %w", err)
+	}
+
+	if err := s.active.Sync(); err != nil {
+		return nil, fmt.Errorf("could not sync spool: %w", err)
+	}
+
+	seg := s.segments[len(s.segments)-1]
+	seg.size += int64(len(line))
+	s.size += int64(len(line))
+
+	return seg, nil
+}
+
+// rotate closes the active segment and starts a new one.
+func (s *Spool) rotate() error {
+	if s.active != nil {
+		if err := s.active.Close(); err != nil {
+			return fmt.Errorf("could not close spool segment: %w", err)
+		}
+
+		s.active = nil
+	}
+
+	var seq uint64 = 1
+	if len(s.segments) > 0 {
+		seq = s.segments[len(s.segments)-1].seq + 1
+	}
+
+	seg := &segment{seq: seq, path: filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentExt))}
+
+	file, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create spool segment: %w", err)
+	}
+
+	s.active = file
+	s.segments = append(s.segments, seg)
+
+	return nil
+}
+
+// compact deletes the oldest segments as long as all their events are acknowledged.
+// Only the oldest segments can be deleted since a segment may hold the acknowledgements of the events of the previous ones.
+func (s *Spool) compact() {
+	for len(s.segments) > 1 && s.segments[0].pending == 0 {
+		if err := os.Remove(s.segments[0].path); err != nil && !os.IsNotExist(err) {
+			return
+		}
+
+		s.size -= s.segments[0].size
+		s.segments = s.segments[1:]
+	}
+}
+
+// Close closes the active segment, the spool can't be used anymore.
+func (s *Spool) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.active == nil {
+		return nil
+	}
+
+	err := s.active.Close()
+	s.active = nil
+
+	return err
+}
+
+func (s *Spool) updateMetrics() {
+	spoolSize.Set(float64(s.size))
+	spoolPendingEvents.Set(float64(len(s.index)))
+}
@@ -0,0 +1,181 @@
+package edp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSpoolTestEvent(i int) *Event {
+	data := json.RawMessage(fmt.Sprintf(`{"test":%d}`, i))
+
+	return &Event{Datatenant: fmt.Sprintf("tenant-%d", i), Data: &data}
+}
+
+func spoolSegments(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	require.NoError(t, err)
+
+	return files
+}
+
+func TestSpool_Replay(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	spool, err := OpenSpool(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+	asserts.Empty(spool.Replay())
+
+	events := []*Event{newSpoolTestEvent(1), newSpoolTestEvent(2), newSpoolTestEvent(3)}
+	for _, event := range events {
+		require.NoError(t, spool.Append(event))
+	}
+
+	asserts.Equal(uint64(1), events[0].id)
+	asserts.Equal(uint64(3), events[2].id)
+
+	require.NoError(t, spool.Ack(events[1]))
+	require.NoError(t, spool.Close())
+
+	// when
+	spool, err = OpenSpool(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+
+	// then
+	replay := spool.Replay()
+	asserts.Equal([]*Event{events[0], events[2]}, replay, "should replay the events not acknowledged in order")
+	asserts.Empty(spool.Replay(), "should replay the events only once")
+
+	event := newSpoolTestEvent(4)
+	require.NoError(t, spool.Append(event))
+	asserts.Equal(uint64(4), event.id, "should continue the ids of the previous events")
+
+	require.NoError(t, spool.Close())
+}
+
+func TestSpool_Compact(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	// each event is written to its own segment
+	spool, err := OpenSpool(dir, 1024*1024, 1)
+	require.NoError(t, err)
+
+	events := []*Event{newSpoolTestEvent(1), newSpoolTestEvent(2), newSpoolTestEvent(3)}
+	for _, event := range events {
+		require.NoError(t, spool.Append(event))
+	}
+
+	asserts.Len(spoolSegments(t, dir), 3)
+
+	// when the newest events are acknowledged
+	require.NoError(t, spool.Ack(events[1]))
+	require.NoError(t, spool.Ack(events[2]))
+
+	// then segments are kept until the oldest is acknowledged
+	asserts.Len(spoolSegments(t, dir), 5)
+
+	// when
+	require.NoError(t, spool.Ack(events[0]))
+
+	// then only the active segment is left
+	asserts.Len(spoolSegments(t, dir), 1)
+	require.NoError(t, spool.Close())
+
+	spool, err = OpenSpool(dir, 1024*1024, 1)
+	require.NoError(t, err)
+	asserts.Empty(spool.Replay())
+	require.NoError(t, spool.Close())
+}
+
+func TestSpool_Full(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	spool, err := OpenSpool(dir, 100, 1024)
+	require.NoError(t, err)
+
+	first := newSpoolTestEvent(1)
+	require.NoError(t, spool.Append(first))
+
+	// when
+	err = spool.Append(newSpoolTestEvent(2))
+
+	// then
+	asserts.Equal(ErrSpoolFull, err)
+
+	require.NoError(t, spool.Ack(first))
+	require.NoError(t, spool.Close())
+}
+
+func TestSpool_PartialRecord(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	spool, err := OpenSpool(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+
+	event := newSpoolTestEvent(1)
+	require.NoError(t, spool.Append(event))
+	require.NoError(t, spool.Close())
+
+	// simulate a crash while writing a record
+	segments := spoolSegments(t, dir)
+	require.Len(t, segments, 1)
+
+	file, err := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":2,"datatenant":"ten`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	// when
+	spool, err = OpenSpool(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+
+	// then
+	asserts.Equal([]*Event{event}, spool.Replay())
+
+	next := newSpoolTestEvent(2)
+	require.NoError(t, spool.Append(next))
+	asserts.Equal(uint64(2), next.id)
+	require.NoError(t, spool.Close())
+}
+
+func TestSpool_Closed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spool")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	spool, err := OpenSpool(dir, 1024*1024, 1024*1024)
+	require.NoError(t, err)
+	require.NoError(t, spool.Close())
+
+	assert.Equal(t, ErrSpoolClosed, spool.Append(newSpoolTestEvent(1)))
+}
@@ -14,15 +14,62 @@ import (
 // Config holds EDP clients configuration.
 type Config struct {
 	URL               string        `kong:"help='EDP base URL',env='EDP_URL',default='https://input.yevents.io',required=true"`
-	Token             string        `kong:"help='EDP source token',placeholder='SECRET',env='EDP_TOKEN',required=true"`
-	Namespace         string        `kong:"help='EDP Namespace',env='EDP_NAMESPACE',required=true"`
-	DataStream        string        `kong:"help='EDP data stream name',env='EDP_DATASTREAM_NAME',required=true"`
-	DataStreamVersion string        `kong:"help='EDP data stream version',env='EDP_DATASTREAM_VERSION',required=true"`
-	DataStreamEnv     string        `kong:"help='EDP data stream environment',env='EDP_DATASTREAM_ENV',required=true"`
+	Token             string        `kong:"help='EDP source token, required by the edp sink',placeholder='SECRET',env='EDP_TOKEN'"`
+	Namespace         string        `kong:"help='EDP Namespace, required by the edp sink',env='EDP_NAMESPACE'"`
+	DataStream        string        `kong:"help='EDP data stream name, required by the edp sink',env='EDP_DATASTREAM_NAME'"`
+	DataStreamVersion string        `kong:"help='EDP data stream version, required by the edp sink',env='EDP_DATASTREAM_VERSION'"`
+	DataStreamEnv     string        `kong:"help='EDP data stream environment, required by the edp sink',env='EDP_DATASTREAM_ENV'"`
 	Timeout           time.Duration `kong:"help='Time limit for requests made by the EDP client',env='EDP_TIMEOUT',required=true,default='30s'"`
 	Buffer            int           `kong:"help='Number of events that the buffer can have.',env='EDP_BUFFER',required=true,default=100"`
 	Workers           int           `kong:"help='Number of workers to send metrics.',env='EDP_WORKERS',required=true,default=5"`
 	EventRetry        int           `kong:"help='Number of retries for sending event.',env='EDP_RETRY',required=true,default=5"`
+	SpoolDir          string        `kong:"help='Directory of the on-disk spool keeping the events until they are sent, the spool is disabled if empty.',env='EDP_SPOOL_DIR',optional=true"`
+	SpoolMaxSize      int64         `kong:"help='Maximum size of the spool in bytes, new events are dropped when it is full.',env='EDP_SPOOL_MAX_SIZE',default=104857600"`
+	SpoolSegmentSize  int64         `kong:"help='Size in bytes of the spool segment files.',env='EDP_SPOOL_SEGMENT_SIZE',default=8388608"`
+	Sinks             []string      `kong:"help='Comma-separated list of sinks the events are written to. (edp,file,prometheus)',enum='edp,file,prometheus',env='EDP_SINKS',default='edp'"`
+	FileSinkDir       string        `kong:"help='Directory of the daily event files written by the file sink.',env='EDP_FILE_SINK_DIR',default='/var/lib/metris/events'"`
+	PrometheusSinkTTL time.Duration `kong:"help='Time after which the gauges of a subaccount without event are removed by the prometheus sink.',env='EDP_PROMETHEUS_SINK_TTL',default='1h'"`
+}
+
+// HasSink returns true if the events are written to the sink, events are only sent to EDP if no sink is configured.
+func (c *Config) HasSink(name string) bool {
+	if len(c.Sinks) == 0 {
+		return name == SinkEDP
+	}
+
+	for _, sink := range c.Sinks {
+		if sink == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks that the parameters required by the sinks are set.
+func (c *Config) Validate() error {
+	if !c.HasSink(SinkEDP) {
+		return nil
+	}
+
+	required := []struct {
+		flag  string
+		value string
+	}{
+		{flag: "token", value: c.Token},
+		{flag: "namespace", value: c.Namespace},
+		{flag: "data-stream", value: c.DataStream},
+		{flag: "data-stream-version", value: c.DataStreamVersion},
+		{flag: "data-stream-env", value: c.DataStreamEnv},
+	}
+
+	for _, param := range required {
+		if param.value == "" {
+			return fmt.Errorf("missing flag --edp-%s required by the %s sink", param.flag, SinkEDP)
+		}
+	}
+
+	return nil
 }
 
 // Client has all the context and parameters needed to run a EDP worker pool.
@@ -37,6 +84,10 @@ type Client struct {
 	queue workqueue.RateLimitingInterface
 	// eventsChannel define the channel to exchange events with the provider.
 	eventsChannel <-chan *Event
+	// spool keeps the events on disk until they are sent, it is optional.
+	spool *Spool
+	// sinks are the additional sinks the events are written to when they are received.
+	sinks map[string]Sink
 }
 
 // Event has the information needed to send an event to EDP.
@@ -46,6 +97,9 @@ type Event struct {
 
 	// Data represent the provider specific event details in raw json, to delay json decoding.
 	Data *json.RawMessage
+
+	// id is the id of the event in the spool, it is 0 if the event was not written to the spool.
+	id uint64
 }
 
 // MarshalJSON is a helper function to marshal custom provider data and add timestamp field.
@@ -3,6 +3,7 @@ package gardener
 import (
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/kyma-project/control-plane/components/metris/internal/log"
@@ -11,6 +12,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/wait"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
 )
@@ -24,22 +27,39 @@ const (
 	fieldCloudProfileName  = "spec.cloudProfileName"
 
 	defaultResyncPeriod = time.Second * 30
+
+	syncPollInterval = time.Millisecond * 100
 )
 
-// NewController return a new controller for watching shoots and secrets.
-func NewController(client *Client, provider string, clusterChannel chan<- *Cluster, logger log.Logger) (*Controller, error) {
+// NewController return a new controller for watching shoots and secrets of the given providers.
+func NewController(client *Client, providers []string, clusterChannel chan<- *Cluster, logger log.Logger) (*Controller, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("no provider to watch shoots for")
+	}
+
+	providertypes := make([]string, 0, len(providers))
+	hyperscalertypes := make([]string, 0, len(providers))
+
+	for _, provider := range providers {
+		providertypes = append(providertypes, strings.ToLower(provider))
+		hyperscalertypes = append(hyperscalertypes, hyperscalerType(strings.ToLower(provider)))
+	}
+
 	gardenerInformerFactory := ginformers.NewSharedInformerFactoryWithOptions(
 		client.GClientset,
 		defaultResyncPeriod,
 		ginformers.WithNamespace(client.Namespace),
 		ginformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
-			opts.FieldSelector = fields.SelectorFromSet(fields.Set{fieldCloudProfileName: provider}).String()
+			// field selectors can't match a set of values, shoots of several providers are filtered by the handlers.
+			if len(providertypes) == 1 {
+				opts.FieldSelector = fields.SelectorFromSet(fields.Set{fieldCloudProfileName: providertypes[0]}).String()
+			}
 		}),
 	)
 
-	hyperscalertype := provider
-	if hyperscalertype == "az" {
-		hyperscalertype = "azure"
+	hyperscalerRequirement, err := labels.NewRequirement(labelHyperscalerType, selection.In, hyperscalertypes)
+	if err != nil {
+		return nil, err
 	}
 
 	kubeInformerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(
@@ -47,7 +67,7 @@ func NewController(client *Client, provider string, clusterChannel chan<- *Clust
 		defaultResyncPeriod,
 		kubeinformers.WithNamespace(client.Namespace),
 		kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
-			opts.LabelSelector = labels.SelectorFromSet(labels.Set{labelHyperscalerType: hyperscalertype}).String()
+			opts.LabelSelector = labels.NewSelector().Add(*hyperscalerRequirement).String()
 		}),
 	)
 
@@ -55,7 +75,7 @@ func NewController(client *Client, provider string, clusterChannel chan<- *Clust
 	secretInformer := kubeInformerFactory.Core().V1().Secrets()
 
 	controller := &Controller{
-		providertype:            strings.ToLower(provider),
+		providertypes:           providertypes,
 		client:                  client,
 		gardenerInformerFactory: gardenerInformerFactory,
 		kubeInformerFactory:     kubeInformerFactory,
@@ -67,7 +87,10 @@ func NewController(client *Client, provider string, clusterChannel chan<- *Clust
 
 	// Set up event handlers for Shoot resources
 	shootInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    controller.shootAddHandlerFunc,
+		AddFunc: func(obj interface{}) {
+			controller.shootAddHandlerFunc(obj)
+			atomic.AddInt64(&controller.shootsAdded, 1)
+		},
 		UpdateFunc: controller.shootUpdateHandlerFunc,
 		DeleteFunc: controller.shootDeleteHandlerFunc,
 	})
@@ -97,8 +120,45 @@ func (c *Controller) Run(stop <-chan struct{}) error {
 
 	c.logger.Debug("informer caches sync completed")
 
+	// the informer handlers are notified asynchronously, wait until the add events of the synced shoots are handled.
+	initialShoots := int64(len(c.shootInformer.Informer().GetStore().ListKeys()))
+
+	err := wait.PollImmediateUntil(syncPollInterval, func() (bool, error) {
+		return atomic.LoadInt64(&c.shootsAdded) >= initialShoots, nil
+	}, stop)
+	if err != nil {
+		return nil
+	}
+
+	select {
+	case c.clusterChannel <- &Cluster{Synced: true}:
+		c.logger.Debug("initial sync of the clusters sent")
+	case <-stop:
+		return nil
+	}
+
 	// wait for stop signal from the workgroup
 	<-stop
 
 	return nil
 }
+
+// hyperscalerType returns the hyperscaler type label value of the provider secrets.
+func hyperscalerType(provider string) string {
+	if provider == "az" {
+		return "azure"
+	}
+
+	return provider
+}
+
+// watches returns true if the controller watches the shoots of the provider type.
+func (c *Controller) watches(providertype string) bool {
+	for _, p := range c.providertypes {
+		if p == providertype {
+			return true
+		}
+	}
+
+	return false
+}
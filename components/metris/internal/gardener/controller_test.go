@@ -13,7 +13,7 @@ func TestController(t *testing.T) {
 	defaultLogger := log.NewNoopLogger()
 	clusterChannel := make(chan *Cluster, 1)
 
-	ctrl, err := NewController(newFakeClient(t), "az", clusterChannel, defaultLogger)
+	ctrl, err := NewController(newFakeClient(t), []string{"az"}, clusterChannel, defaultLogger)
 	if err != nil {
 		t.Errorf("NewController() error = %v", err)
 	}
@@ -28,4 +28,31 @@ func TestController(t *testing.T) {
 	err = ctrl.Run(stop)
 
 	assert.NoError(t, err)
+
+	select {
+	case cluster := <-clusterChannel:
+		assert.True(t, cluster.Synced, "initial sync should be sent")
+	default:
+		assert.Fail(t, "initial sync not sent")
+	}
+}
+
+func TestNewController(t *testing.T) {
+	defaultLogger := log.NewNoopLogger()
+	clusterChannel := make(chan *Cluster, 1)
+
+	t.Run("watch several providers", func(t *testing.T) {
+		ctrl, err := NewController(newFakeClient(t), []string{"az", "AWS"}, clusterChannel, defaultLogger)
+
+		assert.NoError(t, err)
+		assert.True(t, ctrl.watches("az"))
+		assert.True(t, ctrl.watches("aws"))
+		assert.False(t, ctrl.watches("gcp"))
+	})
+
+	t.Run("no provider", func(t *testing.T) {
+		_, err := NewController(newFakeClient(t), nil, clusterChannel, defaultLogger)
+
+		assert.Error(t, err)
+	})
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	gcorev1beta1 "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	commonpkg "github.com/gardener/gardener/pkg/operation/common"
@@ -40,11 +41,10 @@ func (c *Controller) secretUpdateHandlerFunc(oldObj, newObj interface{}) {
 		return
 	}
 
-	// check all shoots with that secret and update
+	// check all shoots with that secret and update, the shoots of other providers are skipped by the add handler
 	fselector := fields.SelectorFromSet(
 		fields.Set{
 			fieldSecretBindingName: newSecret.Name,
-			fieldCloudProfileName:  c.providertype,
 		}).String()
 
 	shootlist, err := c.client.GClientset.CoreV1beta1().Shoots(newSecret.Namespace).List(context.TODO(), metav1.ListOptions{FieldSelector: fselector})
@@ -87,6 +87,10 @@ func (c *Controller) shootDeleteHandlerFunc(obj interface{}) {
 		technicalid = delobj.Key
 	}
 
+	if !c.watches(strings.ToLower(shoot.Spec.CloudProfileName)) {
+		return
+	}
+
 	cluster, err := c.newCluster(shoot)
 
 	logger := c.logger.With("account", cluster.AccountID).With("subaccount", cluster.SubAccountID).With("shoot", shoot.Name)
@@ -116,6 +120,11 @@ func (c *Controller) shootAddHandlerFunc(obj interface{}) {
 		return
 	}
 
+	if !c.watches(strings.ToLower(shoot.Spec.CloudProfileName)) {
+		c.logger.With("shoot", shoot.Name).With("cloudprofile", shoot.Spec.CloudProfileName).Debug("received a shoot add event for an unwatched provider, skipping")
+		return
+	}
+
 	cluster, err := c.newCluster(shoot)
 
 	logger := c.logger.
@@ -191,7 +200,7 @@ func (c *Controller) getTechnicalID(shoot *gcorev1beta1.Shoot) (string, error) {
 // newCluster creates a Cluster definition based on the shoot information.
 func (c *Controller) newCluster(shoot *gcorev1beta1.Shoot) (*Cluster, error) {
 	var (
-		cluster = &Cluster{ProviderType: c.providertype}
+		cluster = &Cluster{ProviderType: strings.ToLower(shoot.Spec.CloudProfileName)}
 		err     error
 		ok      bool
 	)
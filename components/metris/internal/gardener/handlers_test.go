@@ -75,6 +75,7 @@ func TestShootAddHandlerFunc(t *testing.T) {
 			technicalID, err := ctrl.getTechnicalID(defaultShoot)
 			asserts.NoErrorf(err, "error getting technicalid %s", err)
 			asserts.Equal(technicalID, cluster.TechnicalID, "cluster should have technical id %s but got %s", technicalID, cluster.TechnicalID)
+			asserts.Equal("az", cluster.ProviderType, "cluster should have the provider type of the shoot cloud profile")
 
 		case <-time.After(500 * time.Millisecond):
 			asserts.Fail("timed out, did not get the cluster")
@@ -99,6 +100,20 @@ func TestShootAddHandlerFunc(t *testing.T) {
 		}
 	})
 
+	t.Run("adding a shoot of an unwatched provider", func(t *testing.T) {
+		newshoot := defaultShoot.DeepCopy()
+		newshoot.ObjectMeta.Name = "test-shoot-gcp"
+		newshoot.Spec.CloudProfileName = "gcp"
+
+		ctrl.shootAddHandlerFunc(newshoot)
+
+		select {
+		case <-clusterChannel:
+			asserts.Fail("should not have receive a new cluster")
+		case <-time.After(500 * time.Millisecond):
+		}
+	})
+
 	t.Run("adding a shoot in another namespace", func(t *testing.T) {
 		newshoot := defaultShoot.DeepCopy()
 		newshoot.ObjectMeta.Name = "test-shoot-2"
@@ -291,7 +306,7 @@ func testSetup(t *testing.T) {
 		defaultLogger = log.NewNoopLogger()
 	)
 
-	ctrl, err = NewController(newFakeClient(t), "az", clusterChannel, defaultLogger)
+	ctrl, err = NewController(newFakeClient(t), []string{"az"}, clusterChannel, defaultLogger)
 	if err != nil {
 		asserts.FailNowf("error creating controller", "%v", err)
 	}
@@ -24,8 +24,11 @@ type Client struct {
 
 // Controller represent the controller configuration needed to watch for shoots and secrets.
 type Controller struct {
-	// providertype is the name of the infrastructure provider.
-	providertype string
+	// shootsAdded counts the handled shoot add events, it tells when the shoots of the initial sync were sent.
+	// It is the first field to be 64-bit aligned for the atomic operations.
+	shootsAdded int64
+	// providertypes are the names of the infrastructure providers the shoots are watched for.
+	providertypes []string
 	// client is the gardener client that holds the clientsets for gardener and kubernetes.
 	client *Client
 	// gardenerInformerFactory
@@ -60,4 +63,7 @@ type Cluster struct {
 	Deleted bool
 	// Trial is a flag that tell if the cluster is a trial one or not
 	Trial bool
+	// Synced is a flag that mark the end of the initial sync, all clusters existing at startup were sent before.
+	// It does not describe a cluster and is sent to every provider.
+	Synced bool
 }
@@ -0,0 +1,111 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opencensus.io/trace"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/provider"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+)
+
+var (
+	// register the aws provider
+	_ = func() struct{} {
+		err := provider.RegisterProvider("aws", NewAWSProvider)
+		if err != nil {
+			panic(err)
+		}
+		return struct{}{}
+	}()
+)
+
+// NewAWSProvider returns a new AWS provider.
+func NewAWSProvider(config *provider.Config) provider.Provider {
+	return provider.NewPoller(config, newAWS(config))
+}
+
+func newAWS(config *provider.Config) *AWS {
+	return &AWS{
+		instanceTypesStorage: config.NewStorage("instance_types", storage.NewJSONCodec(InstanceType{})),
+		ClientFactory:        newClient,
+	}
+}
+
+// NewClient returns the AWS client for the cluster region.
+func (a *AWS) NewClient(cluster *gardener.Cluster, logger log.Logger) (interface{}, error) {
+	client, err := a.ClientFactory(cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetMetrics returns the event data of the cluster instance.
+func (a *AWS) GetMetrics(ctx context.Context, workerlogger log.Logger, instance *provider.Instance) (interface{}, error) {
+	client, ok := instance.Client.(Client)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client type %T", instance.Client)
+	}
+
+	return getMetricsFromAWS(ctx, workerlogger, &Instance{cluster: instance.Cluster, client: client}, a.instanceTypesStorage)
+}
+
+// IsNotFound returns true if the cluster VPC is not found.
+func (a *AWS) IsNotFound(err error) bool {
+	return errors.Is(err, ErrVPCNotFound)
+}
+
+// IsThrottled returns true if the error is a throttling error of the AWS API.
+func (a *AWS) IsThrottled(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && isThrottlingError(apiErr)
+}
+
+func isThrottlingError(err *APIError) bool {
+	switch err.Code {
+	case "Throttling", "ThrottlingException", "RequestLimitExceeded":
+		return true
+	}
+
+	return err.StatusCode == http.StatusTooManyRequests
+}
+
+// getMetricsFromAWS - collect results from different AWS API and create edp events.
+func getMetricsFromAWS(ctx context.Context, workerlogger log.Logger, instance *Instance, instanceTypesStorage storage.Storage) (*EventData, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/getMetrics")
+		defer span.End()
+
+		workerlogger = workerlogger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	workerlogger.Debug("getting metrics")
+
+	networkData, err := instance.getNetworkMetrics(ctx, workerlogger)
+	if err != nil {
+		return nil, err
+	}
+
+	computeData, err := instance.getComputeMetrics(ctx, workerlogger, instanceTypesStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventData{
+		ResourceGroups: []string{instance.cluster.TechnicalID},
+		Compute:        computeData,
+		Networking:     networkData,
+		EventHub:       &EventHub{},
+	}, nil
+}
@@ -0,0 +1,154 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/edp"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/provider"
+)
+
+var (
+	noopLogger = log.NewNoopLogger()
+
+	testCluster = &gardener.Cluster{
+		TechnicalID:  "shoot--kyma--c-1234567",
+		ProviderType: "aws",
+		Region:       "eu-central-1",
+		CredentialData: map[string][]byte{
+			"accessKeyID":     []byte("test-accesskeyid"),
+			"secretAccessKey": []byte("test-secretaccesskey"),
+		},
+		AccountID:    "test-accountid",
+		SubAccountID: "test-subaccountid",
+	}
+)
+
+func newTestProviderConfig() *provider.Config {
+	return &provider.Config{
+		PollInterval:    time.Minute,
+		PollingDuration: time.Minute,
+		Workers:         1,
+		Buffer:          1,
+		MaxRetries:      2,
+		ClusterChannel:  make(chan *gardener.Cluster, 1),
+		EventsChannel:   make(chan *edp.Event, 1),
+		Logger:          noopLogger,
+	}
+}
+
+func TestNewAWSProvider(t *testing.T) {
+	p := NewAWSProvider(newTestProviderConfig())
+	assert.Implements(t, (*provider.Provider)(nil), p, "")
+}
+
+func TestAWS_Run(t *testing.T) {
+	// given
+	config := newTestProviderConfig()
+	events := make(chan *edp.Event, 1)
+	config.EventsChannel = events
+
+	c := newAWS(config)
+	c.ClientFactory = func(cluster *gardener.Cluster, logger log.Logger) (Client, error) {
+		return newFakeClient(), nil
+	}
+	p := provider.NewPoller(config, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go p.Run(ctx)
+
+	// when
+	config.ClusterChannel <- testCluster
+
+	// then
+	select {
+	case event := <-events:
+		assert.Equal(t, testCluster.SubAccountID, event.Datatenant)
+
+		eventData := &EventData{}
+		require.NoError(t, json.Unmarshal(*event.Data, eventData))
+		assert.Equal(t, []string{testCluster.TechnicalID}, eventData.ResourceGroups)
+		assert.Equal(t, uint32(10), eventData.Compute.ProvisionedCpus)
+		assert.Equal(t, uint32(3), eventData.Networking.ProvisionedLoadBalancers)
+		assert.Equal(t, &EventHub{}, eventData.EventHub)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not sent")
+	}
+}
+
+func TestAWS_GetMetrics(t *testing.T) {
+	t.Run("should return the event data of the instance", func(t *testing.T) {
+		// given
+		c := newAWS(newTestProviderConfig())
+		instance := &provider.Instance{Cluster: testCluster, Client: newFakeClient()}
+
+		// when
+		obj, err := c.GetMetrics(context.Background(), noopLogger, instance)
+
+		// then
+		require.NoError(t, err)
+		eventData, ok := obj.(*EventData)
+		require.True(t, ok)
+		assert.Equal(t, []string{testCluster.TechnicalID}, eventData.ResourceGroups)
+	})
+
+	t.Run("should fail with an unexpected client", func(t *testing.T) {
+		// given
+		c := newAWS(newTestProviderConfig())
+		instance := &provider.Instance{Cluster: testCluster, Client: struct{}{}}
+
+		// when
+		_, err := c.GetMetrics(context.Background(), noopLogger, instance)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestAWS_errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantThrottled bool
+		wantNotFound  bool
+	}{
+		{
+			name:          "throttled",
+			err:           &APIError{StatusCode: 503, Code: "RequestLimitExceeded"},
+			wantThrottled: true,
+		},
+		{
+			name:          "too many requests",
+			err:           fmt.Errorf("wrapped: %w", &APIError{StatusCode: 429}),
+			wantThrottled: true,
+		},
+		{
+			name:         "vpc not found",
+			err:          fmt.Errorf("wrapped: %w", ErrVPCNotFound),
+			wantNotFound: true,
+		},
+		{
+			name: "other error",
+			err:  &APIError{StatusCode: 401, Code: "AuthFailure"},
+		},
+	}
+
+	c := newAWS(newTestProviderConfig())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantThrottled, c.IsThrottled(tt.err))
+			assert.Equal(t, tt.wantNotFound, c.IsNotFound(tt.err))
+		})
+	}
+}
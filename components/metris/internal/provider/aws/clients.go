@@ -0,0 +1,428 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/protocol/ec2query"
+	awsxml "github.com/aws/aws-sdk-go-v2/aws/protocol/xml"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+	"github.com/mitchellh/mapstructure"
+	"go.opencensus.io/trace"
+)
+
+// decode decodes and map kubernetes secret data into the ClientSecretMap structure.
+func (s *ClientSecretMap) decode(secrets map[string][]byte) error {
+	var (
+		decodedSecrets = make(map[string]string)
+	)
+
+	for k, v := range secrets {
+		decodedSecrets[k] = string(v)
+	}
+
+	return mapstructure.Decode(decodedSecrets, s)
+}
+
+// newClient return a new client for a cluster base on the cluster configuration provided.
+func newClient(cluster *gardener.Cluster, logger log.Logger) (Client, error) {
+	conf := &ClientSecretMap{}
+
+	if err := conf.decode(cluster.CredentialData); err != nil {
+		return nil, err
+	}
+
+	if conf.AccessKeyID == "" || conf.SecretAccessKey == "" {
+		return nil, fmt.Errorf("accessKeyID or secretAccessKey not found in the cluster credentials")
+	}
+
+	logger.
+		With("region", cluster.Region).
+		With("accesskeyid", conf.AccessKeyID).
+		Debug("generating new client")
+
+	return &client{
+		httpClient:      &http.Client{},
+		endpoint:        defaultEndpoint,
+		region:          cluster.Region,
+		accessKeyID:     conf.AccessKeyID,
+		secretAccessKey: conf.SecretAccessKey,
+		signer:          v4.NewSigner(),
+	}, nil
+}
+
+// defaultEndpoint returns the regional endpoint of the AWS service.
+func defaultEndpoint(service, region string) string {
+	return fmt.Sprintf("https://%s.%s.amazonaws.com/", service, region)
+}
+
+// GetInstances returns the EC2 instances of the cluster.
+func (c *client) GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]EC2Instance, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetInstances")
+		defer span.End()
+	}
+
+	var instances []EC2Instance
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			Instances []EC2Instance `xml:"reservationSet>item>instancesSet>item"`
+			NextToken string        `xml:"nextToken"`
+		}{}
+		err := c.call(ctx, "ec2", ec2APIVersion, "DescribeInstances", withToken(clusterFilter(technicalID), "NextToken", token), &response, logger)
+		instances = append(instances, response.Instances...)
+
+		return response.NextToken, err
+	})
+
+	return instances, err
+}
+
+// GetInstanceTypes returns the capabilities of the EC2 instance types.
+func (c *client) GetInstanceTypes(ctx context.Context, instanceTypes []string, logger log.Logger) ([]InstanceType, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetInstanceTypes")
+		defer span.End()
+	}
+
+	params := url.Values{}
+	for i, instanceType := range instanceTypes {
+		params.Set(fmt.Sprintf("InstanceType.%d", i+1), instanceType)
+	}
+
+	var result []InstanceType
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			InstanceTypes []InstanceType `xml:"instanceTypeSet>item"`
+			NextToken     string         `xml:"nextToken"`
+		}{}
+		err := c.call(ctx, "ec2", ec2APIVersion, "DescribeInstanceTypes", withToken(params, "NextToken", token), &response, logger)
+		result = append(result, response.InstanceTypes...)
+
+		return response.NextToken, err
+	})
+
+	return result, err
+}
+
+// GetVolumes returns the EBS volumes of the cluster.
+func (c *client) GetVolumes(ctx context.Context, technicalID string, logger log.Logger) ([]Volume, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetVolumes")
+		defer span.End()
+	}
+
+	var volumes []Volume
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			Volumes   []Volume `xml:"volumeSet>item"`
+			NextToken string   `xml:"nextToken"`
+		}{}
+		err := c.call(ctx, "ec2", ec2APIVersion, "DescribeVolumes", withToken(clusterFilter(technicalID), "NextToken", token), &response, logger)
+		volumes = append(volumes, response.Volumes...)
+
+		return response.NextToken, err
+	})
+
+	return volumes, err
+}
+
+// GetVPCs returns the VPCs of the cluster.
+func (c *client) GetVPCs(ctx context.Context, technicalID string, logger log.Logger) ([]VPC, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetVPCs")
+		defer span.End()
+	}
+
+	var vpcs []VPC
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			VPCs      []VPC  `xml:"vpcSet>item"`
+			NextToken string `xml:"nextToken"`
+		}{}
+		err := c.call(ctx, "ec2", ec2APIVersion, "DescribeVpcs", withToken(clusterFilter(technicalID), "NextToken", token), &response, logger)
+		vpcs = append(vpcs, response.VPCs...)
+
+		return response.NextToken, err
+	})
+
+	return vpcs, err
+}
+
+// GetAddresses returns the elastic IP addresses of the cluster.
+func (c *client) GetAddresses(ctx context.Context, technicalID string, logger log.Logger) ([]Address, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetAddresses")
+		defer span.End()
+	}
+
+	response := struct {
+		Addresses []Address `xml:"addressesSet>item"`
+	}{}
+	err := c.call(ctx, "ec2", ec2APIVersion, "DescribeAddresses", clusterFilter(technicalID), &response, logger)
+
+	return response.Addresses, err
+}
+
+// GetLoadBalancers returns the classic, network and application load balancers of the cluster.
+// Load balancers can't be filtered by tags, so the tags of all load balancers in the region are checked.
+func (c *client) GetLoadBalancers(ctx context.Context, technicalID string, logger log.Logger) ([]LoadBalancer, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/GetLoadBalancers")
+		defer span.End()
+	}
+
+	classic, err := c.describeLoadBalancers(ctx, elbAPIVersion, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.filterLoadBalancers(ctx, elbAPIVersion, classic, technicalID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	v2, err := c.describeLoadBalancers(ctx, elbv2APIVersion, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered, err := c.filterLoadBalancers(ctx, elbv2APIVersion, v2, technicalID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, filtered...), nil
+}
+
+func (c *client) describeLoadBalancers(ctx context.Context, version string, logger log.Logger) ([]LoadBalancer, error) {
+	var result []LoadBalancer
+
+	err := paginate(func(marker string) (string, error) {
+		response := struct {
+			// classic load balancers
+			Descriptions []struct {
+				Name string `xml:"LoadBalancerName"`
+			} `xml:"DescribeLoadBalancersResult>LoadBalancerDescriptions>member"`
+			// network and application load balancers
+			LoadBalancers []struct {
+				Name string `xml:"LoadBalancerName"`
+				ARN  string `xml:"LoadBalancerArn"`
+			} `xml:"DescribeLoadBalancersResult>LoadBalancers>member"`
+			NextMarker string `xml:"DescribeLoadBalancersResult>NextMarker"`
+		}{}
+		err := c.call(ctx, "elasticloadbalancing", version, "DescribeLoadBalancers", withToken(url.Values{}, "Marker", marker), &response, logger)
+
+		for _, lb := range response.Descriptions {
+			result = append(result, LoadBalancer{Name: lb.Name})
+		}
+		for _, lb := range response.LoadBalancers {
+			result = append(result, LoadBalancer{Name: lb.Name, ARN: lb.ARN})
+		}
+
+		return response.NextMarker, err
+	})
+
+	return result, err
+}
+
+// filterLoadBalancers returns the load balancers tagged with the cluster tag.
+func (c *client) filterLoadBalancers(ctx context.Context, version string, loadBalancers []LoadBalancer, technicalID string, logger log.Logger) ([]LoadBalancer, error) {
+	var result []LoadBalancer
+
+	for start := 0; start < len(loadBalancers); start += elbMaxTagsRequest {
+		end := start + elbMaxTagsRequest
+		if end > len(loadBalancers) {
+			end = len(loadBalancers)
+		}
+
+		params := url.Values{}
+		byID := make(map[string]LoadBalancer)
+
+		for i, lb := range loadBalancers[start:end] {
+			if version == elbAPIVersion {
+				params.Set(fmt.Sprintf("LoadBalancerNames.member.%d", i+1), lb.Name)
+				byID[lb.Name] = lb
+			} else {
+				params.Set(fmt.Sprintf("ResourceArns.member.%d", i+1), lb.ARN)
+				byID[lb.ARN] = lb
+			}
+		}
+
+		response := struct {
+			Descriptions []struct {
+				Name string `xml:"LoadBalancerName"`
+				ARN  string `xml:"ResourceArn"`
+				Tags []struct {
+					Key string `xml:"Key"`
+				} `xml:"Tags>member"`
+			} `xml:"DescribeTagsResult>TagDescriptions>member"`
+		}{}
+		if err := c.call(ctx, "elasticloadbalancing", version, "DescribeTags", params, &response, logger); err != nil {
+			return nil, err
+		}
+
+		for _, description := range response.Descriptions {
+			lb, ok := byID[description.Name+description.ARN]
+			if !ok {
+				continue
+			}
+
+			for _, tag := range description.Tags {
+				if tag.Key == tagKeyClusterPrefix+technicalID {
+					result = append(result, lb)
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// call calls the action of the AWS Query API and decodes the XML response into the result.
+func (c *client) call(ctx context.Context, service, version, action string, params url.Values, result interface{}, logger log.Logger) error {
+	metricfn := collectRequestMetrics(service, action)
+	defer metricfn()
+
+	form := url.Values{}
+	for key, values := range params {
+		form[key] = values
+	}
+
+	form.Set("Action", action)
+	form.Set("Version", version)
+
+	body := form.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(service, c.region), strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	if err := c.sign(ctx, request, []byte(body), service); err != nil {
+		return err
+	}
+
+	start := time.Now()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	apiRequestCounter.WithLabelValues(service, action, strconv.Itoa(response.StatusCode)).Inc()
+
+	logger.
+		With("service", service).
+		With("action", action).
+		With("status", response.StatusCode).
+		With("time", time.Since(start)).
+		Debug("request")
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		apiErr := decodeError(responseBody)
+		apiErr.StatusCode = response.StatusCode
+
+		return apiErr
+	}
+
+	return xml.Unmarshal(responseBody, result)
+}
+
+// sign adds the AWS Signature Version 4 to the request.
+func (c *client) sign(ctx context.Context, request *http.Request, body []byte, service string) error {
+	credentials := aws.Credentials{AccessKeyID: c.accessKeyID, SecretAccessKey: c.secretAccessKey}
+	payloadHash := sha256.Sum256(body)
+
+	return c.signer.SignHTTP(ctx, credentials, request, hex.EncodeToString(payloadHash[:]), service, c.region, time.Now().UTC())
+}
+
+// decodeError decodes the error response, EC2 returns <Response><Errors><Error>, ELB returns <ErrorResponse><Error>.
+func decodeError(body []byte) *APIError {
+	if components, err := ec2query.GetErrorResponseComponents(bytes.NewReader(body)); err == nil && components.Code != "" {
+		return &APIError{Code: components.Code, Message: components.Message}
+	}
+
+	components, _ := awsxml.GetErrorResponseComponents(bytes.NewReader(body), false)
+
+	return &APIError{Code: components.Code, Message: components.Message}
+}
+
+// clusterFilter returns the EC2 filter for the resources tagged with the cluster tag.
+func clusterFilter(technicalID string) url.Values {
+	return url.Values{
+		"Filter.1.Name":    {"tag-key"},
+		"Filter.1.Value.1": {tagKeyClusterPrefix + technicalID},
+	}
+}
+
+// withToken returns a copy of the params with the pagination token set.
+func withToken(params url.Values, name, token string) url.Values {
+	result := url.Values{}
+	for key, values := range params {
+		result[key] = values
+	}
+
+	if token != "" {
+		result.Set(name, token)
+	}
+
+	return result
+}
+
+// paginate fetches the pages until the returned token is empty.
+func paginate(fetch func(token string) (string, error)) error {
+	token := ""
+
+	for {
+		next, err := fetch(token)
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		token = next
+	}
+}
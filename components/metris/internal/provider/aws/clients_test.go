@@ -0,0 +1,212 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+)
+
+const testTagKey = tagKeyClusterPrefix + "shoot--kyma--c-1234567"
+
+// newTestServer returns a server answering the AWS Query API actions with the given XML responses,
+// the key is "<api>:<action>" and "<api>:<action>:<token>" for the next pages, where api is ec2, elb or elbv2.
+func newTestServer(t *testing.T, responses map[string]string) (*httptest.Server, *client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service := strings.Trim(r.URL.Path, "/")
+		credential := fmt.Sprintf("Credential=test-accesskeyid/%s/eu-central-1/%s/aws4_request", time.Now().UTC().Format("20060102"), service)
+		if !strings.Contains(r.Header.Get("Authorization"), credential) {
+			t.Errorf("request not signed for %s: %s", service, r.Header.Get("Authorization"))
+		}
+
+		require.NoError(t, r.ParseForm())
+
+		api := service
+		switch r.PostForm.Get("Version") {
+		case elbAPIVersion:
+			api = "elb"
+		case elbv2APIVersion:
+			api = "elbv2"
+		}
+
+		key := api + ":" + r.PostForm.Get("Action")
+		if token := r.PostForm.Get("NextToken") + r.PostForm.Get("Marker"); token != "" {
+			key += ":" + token
+		}
+
+		if service == "ec2" && r.PostForm.Get("Action") != "DescribeInstanceTypes" {
+			assert.Equal(t, "tag-key", r.PostForm.Get("Filter.1.Name"))
+			assert.Equal(t, testTagKey, r.PostForm.Get("Filter.1.Value.1"))
+		}
+
+		response, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprintf(w, "<Response><Errors><Error><Code>InvalidAction</Code><Message>unexpected call %s</Message></Error></Errors></Response>", key)
+
+			return
+		}
+
+		if strings.Contains(response, "<Error>") {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		_, _ = fmt.Fprint(w, response)
+	}))
+
+	return server, &client{
+		httpClient:      server.Client(),
+		endpoint:        func(service, region string) string { return server.URL + "/" + service + "/" },
+		region:          "eu-central-1",
+		accessKeyID:     "test-accesskeyid",
+		secretAccessKey: "test-secretaccesskey",
+		signer:          v4.NewSigner(),
+	}
+}
+
+func TestClient_GetInstances(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"ec2:DescribeInstances": `<DescribeInstancesResponse><reservationSet><item><instancesSet>
+			<item><instanceId>i-1</instanceId><instanceType>m5.xlarge</instanceType><instanceState><code>16</code><name>running</name></instanceState></item>
+			<item><instanceId>i-2</instanceId><instanceType>m5.large</instanceType><instanceState><code>0</code><name>pending</name></instanceState></item>
+			</instancesSet></item></reservationSet><nextToken>page-2</nextToken></DescribeInstancesResponse>`,
+		"ec2:DescribeInstances:page-2": `<DescribeInstancesResponse><reservationSet><item><instancesSet>
+			<item><instanceId>i-3</instanceId><instanceType>m5.xlarge</instanceType><instanceState><code>48</code><name>terminated</name></instanceState></item>
+			</instancesSet></item></reservationSet></DescribeInstancesResponse>`,
+	})
+	defer server.Close()
+
+	// when
+	instances, err := c.GetInstances(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []EC2Instance{
+		{ID: "i-1", Type: "m5.xlarge", State: "running"},
+		{ID: "i-2", Type: "m5.large", State: "pending"},
+		{ID: "i-3", Type: "m5.xlarge", State: "terminated"},
+	}, instances)
+}
+
+func TestClient_GetInstanceTypes(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"ec2:DescribeInstanceTypes": `<DescribeInstanceTypesResponse><instanceTypeSet>
+			<item><instanceType>m5.large</instanceType><vCpuInfo><defaultVCpus>2</defaultVCpus></vCpuInfo><memoryInfo><sizeInMiB>8192</sizeInMiB></memoryInfo></item>
+			</instanceTypeSet></DescribeInstanceTypesResponse>`,
+	})
+	defer server.Close()
+
+	// when
+	instanceTypes, err := c.GetInstanceTypes(context.Background(), []string{"m5.large"}, noopLogger)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []InstanceType{{Name: "m5.large", VCPUs: 2, MemoryMiB: 8192}}, instanceTypes)
+}
+
+func TestClient_GetVolumesVPCsAddresses(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"ec2:DescribeVolumes":   `<DescribeVolumesResponse><volumeSet><item><volumeId>vol-1</volumeId><size>50</size><status>in-use</status></item></volumeSet></DescribeVolumesResponse>`,
+		"ec2:DescribeVpcs":      `<DescribeVpcsResponse><vpcSet><item><vpcId>vpc-1</vpcId></item></vpcSet></DescribeVpcsResponse>`,
+		"ec2:DescribeAddresses": `<DescribeAddressesResponse><addressesSet><item><allocationId>eipalloc-1</allocationId><publicIp>1.2.3.4</publicIp></item></addressesSet></DescribeAddressesResponse>`,
+	})
+	defer server.Close()
+
+	// when
+	volumes, volumesErr := c.GetVolumes(context.Background(), testCluster.TechnicalID, noopLogger)
+	vpcs, vpcsErr := c.GetVPCs(context.Background(), testCluster.TechnicalID, noopLogger)
+	addresses, addressesErr := c.GetAddresses(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	require.NoError(t, volumesErr)
+	require.NoError(t, vpcsErr)
+	require.NoError(t, addressesErr)
+	assert.Equal(t, []Volume{{ID: "vol-1", SizeGB: 50, State: "in-use"}}, volumes)
+	assert.Equal(t, []VPC{{ID: "vpc-1"}}, vpcs)
+	assert.Equal(t, []Address{{AllocationID: "eipalloc-1", PublicIP: "1.2.3.4"}}, addresses)
+}
+
+func TestClient_GetLoadBalancers(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"elb:DescribeLoadBalancers": `<DescribeLoadBalancersResponse><DescribeLoadBalancersResult>
+			<LoadBalancerDescriptions><member><LoadBalancerName>a1</LoadBalancerName></member><member><LoadBalancerName>other</LoadBalancerName></member></LoadBalancerDescriptions>
+			<NextMarker>page-2</NextMarker></DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`,
+		"elb:DescribeLoadBalancers:page-2": `<DescribeLoadBalancersResponse><DescribeLoadBalancersResult>
+			<LoadBalancerDescriptions><member><LoadBalancerName>a2</LoadBalancerName></member></LoadBalancerDescriptions>
+			</DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`,
+		"elb:DescribeTags": `<DescribeTagsResponse><DescribeTagsResult><TagDescriptions>
+			<member><LoadBalancerName>a1</LoadBalancerName><Tags><member><Key>` + testTagKey + `</Key><Value>owned</Value></member></Tags></member>
+			<member><LoadBalancerName>other</LoadBalancerName><Tags><member><Key>kubernetes.io/cluster/other</Key><Value>owned</Value></member></Tags></member>
+			<member><LoadBalancerName>a2</LoadBalancerName><Tags><member><Key>` + testTagKey + `</Key><Value>owned</Value></member></Tags></member>
+			</TagDescriptions></DescribeTagsResult></DescribeTagsResponse>`,
+		"elbv2:DescribeLoadBalancers": `<DescribeLoadBalancersResponse><DescribeLoadBalancersResult><LoadBalancers>
+			<member><LoadBalancerName>nlb</LoadBalancerName><LoadBalancerArn>arn:nlb</LoadBalancerArn></member>
+			<member><LoadBalancerName>other-nlb</LoadBalancerName><LoadBalancerArn>arn:other-nlb</LoadBalancerArn></member>
+			</LoadBalancers></DescribeLoadBalancersResult></DescribeLoadBalancersResponse>`,
+		"elbv2:DescribeTags": `<DescribeTagsResponse><DescribeTagsResult><TagDescriptions>
+			<member><ResourceArn>arn:nlb</ResourceArn><Tags><member><Key>` + testTagKey + `</Key><Value>owned</Value></member></Tags></member>
+			<member><ResourceArn>arn:other-nlb</ResourceArn><Tags/></member>
+			</TagDescriptions></DescribeTagsResult></DescribeTagsResponse>`,
+	})
+	defer server.Close()
+
+	// when
+	loadBalancers, err := c.GetLoadBalancers(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []LoadBalancer{{Name: "a1"}, {Name: "a2"}, {Name: "nlb", ARN: "arn:nlb"}}, loadBalancers)
+}
+
+func TestClient_Error(t *testing.T) {
+	for name, response := range map[string]string{
+		"ec2": "<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></Errors></Response>",
+		"elb": "<ErrorResponse><Error><Type>Sender</Type><Code>RequestLimitExceeded</Code><Message>Request limit exceeded.</Message></Error></ErrorResponse>",
+	} {
+		t.Run(fmt.Sprintf("should decode %s error", name), func(t *testing.T) {
+			// given
+			server, c := newTestServer(t, map[string]string{"ec2:DescribeVpcs": response})
+			defer server.Close()
+
+			// when
+			_, err := c.GetVPCs(context.Background(), testCluster.TechnicalID, noopLogger)
+
+			// then
+			assert.Equal(t, &APIError{StatusCode: http.StatusServiceUnavailable, Code: "RequestLimitExceeded", Message: "Request limit exceeded."}, err)
+		})
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should create client for cluster region", func(t *testing.T) {
+		// when
+		c, err := newClient(testCluster, noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "eu-central-1", c.(*client).region)
+		assert.Equal(t, "test-accesskeyid", c.(*client).accessKeyID)
+		assert.Equal(t, "https://ec2.eu-central-1.amazonaws.com/", c.(*client).endpoint("ec2", "eu-central-1"))
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		// when
+		_, err := newClient(&gardener.Cluster{Region: "eu-central-1", CredentialData: map[string][]byte{"accessKeyID": []byte("key")}}, noopLogger)
+
+		// then
+		assert.Error(t, err)
+	})
+}
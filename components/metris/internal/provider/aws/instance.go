@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+	"go.opencensus.io/trace"
+)
+
+func (i *Instance) getComputeMetrics(ctx context.Context, logger log.Logger, instanceTypesStorage storage.Storage) (*Compute, error) {
+	var (
+		instances []EC2Instance
+		volumes   []Volume
+		err       error
+		vmt       = make(map[string]uint32)
+		result    = &Compute{
+			VMTypes:          make([]VMType, 0),
+			ProvisionedCpus:  0,
+			ProvisionedRAMGB: 0,
+			ProvisionedVolumes: ProvisionedVolume{
+				SizeGBTotal:   0,
+				SizeGBRounded: 0,
+				Count:         0,
+			},
+		}
+	)
+
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/getComputeMetrics")
+		defer span.End()
+
+		logger = logger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	if instances, err = i.client.GetInstances(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	for _, instance := range instances {
+		// only the instances which are or will be running are billed
+		if instance.State == "pending" || instance.State == "running" {
+			vmt[instance.Type]++
+		}
+	}
+
+	instanceTypes, err := i.getInstanceTypes(ctx, logger, vmt, instanceTypesStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	for vmtype, count := range vmt {
+		instanceType, ok := instanceTypes[vmtype]
+		if !ok {
+			return nil, fmt.Errorf("could not get instance type capabilities for type %s", vmtype)
+		}
+
+		result.ProvisionedCpus += instanceType.VCPUs * count
+		result.ProvisionedRAMGB += float64(instanceType.MemoryMiB) / 1024 * float64(count)
+		result.VMTypes = append(result.VMTypes, VMType{Name: vmtype, Count: count})
+	}
+
+	sort.Slice(result.VMTypes, func(a, b int) bool { return result.VMTypes[a].Name < result.VMTypes[b].Name })
+
+	if volumes, err = i.client.GetVolumes(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	for _, volume := range volumes {
+		if volume.State == "deleting" || volume.State == "deleted" {
+			continue
+		}
+
+		result.ProvisionedVolumes.Count++
+		result.ProvisionedVolumes.SizeGBTotal += volume.SizeGB
+		result.ProvisionedVolumes.SizeGBRounded += uint32(math.Ceil(float64(volume.SizeGB)/diskSizeFactor) * diskSizeFactor)
+	}
+
+	return result, nil
+}
+
+// getInstanceTypes returns the capabilities of the instance types, the capabilities not found in the storage are fetched from AWS.
+func (i *Instance) getInstanceTypes(ctx context.Context, logger log.Logger, vmt map[string]uint32, instanceTypesStorage storage.Storage) (map[string]InstanceType, error) {
+	var (
+		result  = make(map[string]InstanceType)
+		missing []string
+	)
+
+	for vmtype := range vmt {
+		if obj, exists := instanceTypesStorage.Get(instanceTypeKey(i.cluster.Region, vmtype)); exists {
+			if instanceType, ok := obj.(InstanceType); ok {
+				result[vmtype] = instanceType
+
+				continue
+			}
+		}
+
+		missing = append(missing, vmtype)
+	}
+
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	sort.Strings(missing)
+	logger.Debugf("getting capabilities of instance types %v in region %s", missing, i.cluster.Region)
+
+	instanceTypes, err := i.client.GetInstanceTypes(ctx, missing, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, instanceType := range instanceTypes {
+		instanceTypesStorage.Put(instanceTypeKey(i.cluster.Region, instanceType.Name), instanceType)
+		result[instanceType.Name] = instanceType
+	}
+
+	return result, nil
+}
+
+func (i *Instance) getNetworkMetrics(ctx context.Context, logger log.Logger) (*Networking, error) {
+	var (
+		result = &Networking{
+			ProvisionedLoadBalancers: 0,
+			ProvisionedIps:           0,
+			ProvisionedVnets:         0,
+		}
+		err       error
+		lbs       []LoadBalancer
+		vpcs      []VPC
+		addresses []Address
+	)
+
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/aws/getNetworkMetrics")
+		defer span.End()
+
+		logger = logger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	if vpcs, err = i.client.GetVPCs(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	// all shoots have a VPC, if none is found, the cluster may have been deleted
+	if len(vpcs) == 0 {
+		return nil, ErrVPCNotFound
+	}
+
+	result.ProvisionedVnets += uint32(len(vpcs))
+
+	if lbs, err = i.client.GetLoadBalancers(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	result.ProvisionedLoadBalancers += uint32(len(lbs))
+
+	if addresses, err = i.client.GetAddresses(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	result.ProvisionedIps += uint32(len(addresses))
+
+	return result, nil
+}
+
+func instanceTypeKey(region, instanceType string) string {
+	return region + "/" + instanceType
+}
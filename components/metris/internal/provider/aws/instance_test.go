@@ -0,0 +1,196 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+)
+
+// fakeClient is a Client returning the configured resources for the test cluster.
+type fakeClient struct {
+	instances     []EC2Instance
+	instanceTypes []InstanceType
+	volumes       []Volume
+	loadBalancers []LoadBalancer
+	vpcs          []VPC
+	addresses     []Address
+	err           error
+
+	instanceTypesRequests [][]string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		instances: []EC2Instance{
+			{ID: "i-1", Type: "m5.xlarge", State: "running"},
+			{ID: "i-2", Type: "m5.xlarge", State: "pending"},
+			{ID: "i-3", Type: "m5.large", State: "running"},
+			{ID: "i-4", Type: "m5.2xlarge", State: "terminated"},
+		},
+		instanceTypes: []InstanceType{
+			{Name: "m5.large", VCPUs: 2, MemoryMiB: 8192},
+			{Name: "m5.xlarge", VCPUs: 4, MemoryMiB: 16384},
+		},
+		volumes: []Volume{
+			{ID: "vol-1", SizeGB: 50, State: "in-use"},
+			{ID: "vol-2", SizeGB: 20, State: "available"},
+			{ID: "vol-3", SizeGB: 100, State: "deleting"},
+		},
+		loadBalancers: []LoadBalancer{{Name: "a1"}, {Name: "a2"}, {Name: "nlb", ARN: "arn:nlb"}},
+		vpcs:          []VPC{{ID: "vpc-1"}},
+		addresses:     []Address{{AllocationID: "eipalloc-1"}, {AllocationID: "eipalloc-2"}},
+	}
+}
+
+func (c *fakeClient) GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]EC2Instance, error) {
+	return c.instances, c.err
+}
+
+func (c *fakeClient) GetInstanceTypes(ctx context.Context, instanceTypes []string, logger log.Logger) ([]InstanceType, error) {
+	c.instanceTypesRequests = append(c.instanceTypesRequests, instanceTypes)
+
+	var result []InstanceType
+	for _, instanceType := range c.instanceTypes {
+		for _, name := range instanceTypes {
+			if instanceType.Name == name {
+				result = append(result, instanceType)
+			}
+		}
+	}
+
+	return result, c.err
+}
+
+func (c *fakeClient) GetVolumes(ctx context.Context, technicalID string, logger log.Logger) ([]Volume, error) {
+	return c.volumes, c.err
+}
+
+func (c *fakeClient) GetLoadBalancers(ctx context.Context, technicalID string, logger log.Logger) ([]LoadBalancer, error) {
+	return c.loadBalancers, c.err
+}
+
+func (c *fakeClient) GetVPCs(ctx context.Context, technicalID string, logger log.Logger) ([]VPC, error) {
+	return c.vpcs, c.err
+}
+
+func (c *fakeClient) GetAddresses(ctx context.Context, technicalID string, logger log.Logger) ([]Address, error) {
+	return c.addresses, c.err
+}
+
+func TestInstance_getComputeMetrics(t *testing.T) {
+	t.Run("should sum up instances and volumes", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		instance := &Instance{cluster: testCluster, client: client}
+		instanceTypesStorage := storage.NewMemoryStorage("instance_types")
+
+		// when
+		compute, err := instance.getComputeMetrics(context.Background(), noopLogger, instanceTypesStorage)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Compute{
+			VMTypes:          []VMType{{Name: "m5.large", Count: 1}, {Name: "m5.xlarge", Count: 2}},
+			ProvisionedCpus:  10,
+			ProvisionedRAMGB: 40,
+			ProvisionedVolumes: ProvisionedVolume{
+				SizeGBTotal:   70,
+				SizeGBRounded: 96,
+				Count:         2,
+			},
+		}, compute)
+		assert.Equal(t, [][]string{{"m5.large", "m5.xlarge"}}, client.instanceTypesRequests)
+	})
+
+	t.Run("should fetch only the instance types not cached", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		instance := &Instance{cluster: testCluster, client: client}
+		instanceTypesStorage := storage.NewMemoryStorage("instance_types")
+		instanceTypesStorage.Put(instanceTypeKey(testCluster.Region, "m5.xlarge"), InstanceType{Name: "m5.xlarge", VCPUs: 4, MemoryMiB: 16384})
+
+		// when
+		_, err := instance.getComputeMetrics(context.Background(), noopLogger, instanceTypesStorage)
+		require.NoError(t, err)
+		_, err = instance.getComputeMetrics(context.Background(), noopLogger, instanceTypesStorage)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, [][]string{{"m5.large"}}, client.instanceTypesRequests)
+	})
+
+	t.Run("should fail for unknown instance type", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.instanceTypes = client.instanceTypes[1:]
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getComputeMetrics(context.Background(), noopLogger, storage.NewMemoryStorage("instance_types"))
+
+		// then
+		assert.EqualError(t, err, "could not get instance type capabilities for type m5.large")
+	})
+
+	t.Run("should return empty metrics for cluster without instances", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.instances = nil
+		client.volumes = nil
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		compute, err := instance.getComputeMetrics(context.Background(), noopLogger, storage.NewMemoryStorage("instance_types"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Compute{VMTypes: []VMType{}}, compute)
+		assert.Empty(t, client.instanceTypesRequests)
+	})
+}
+
+func TestInstance_getNetworkMetrics(t *testing.T) {
+	t.Run("should count network resources", func(t *testing.T) {
+		// given
+		instance := &Instance{cluster: testCluster, client: newFakeClient()}
+
+		// when
+		networking, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Networking{ProvisionedLoadBalancers: 3, ProvisionedVnets: 1, ProvisionedIps: 2}, networking)
+	})
+
+	t.Run("should fail when vpc is not found", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.vpcs = nil
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		assert.True(t, errors.Is(err, ErrVPCNotFound))
+	})
+
+	t.Run("should return client error", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.err = &APIError{StatusCode: 401, Code: "AuthFailure"}
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		assert.Equal(t, client.err, err)
+	})
+}
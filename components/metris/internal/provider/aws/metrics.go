@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"github.com/kyma-project/control-plane/components/metris/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	apiRequestDurationHist = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "aws",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP request to AWS in seconds.",
+			// Duration buckets, in seconds
+			// 100ms, 200ms, 300ms, 400ms, 500ms, 750ms, 1s, 1.5s, 2s
+			Buckets: []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.75, 1, 1.5, 2.0},
+		},
+		[]string{"service", "action"},
+	)
+
+	apiRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "aws",
+			Name:      "request_total",
+			Help:      "Total number of HTTP request made to AWS.",
+		},
+		[]string{"service", "action", "status"},
+	)
+)
+
+// collectRequestMetrics collect AWS HTTP request metrics.
+func collectRequestMetrics(service, action string) func() {
+	metricTimer := prometheus.NewTimer(apiRequestDurationHist.WithLabelValues(service, action))
+
+	return func() {
+		_ = metricTimer.ObserveDuration()
+	}
+}
@@ -0,0 +1,176 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+)
+
+var (
+	ErrVPCNotFound = errors.New("vpc not found")
+)
+
+const (
+	// tagKeyClusterPrefix is the prefix of the tag key Gardener uses for tagging all the resources of a shoot,
+	// the shoot technical id is appended to the prefix.
+	tagKeyClusterPrefix string = "kubernetes.io/cluster/"
+
+	// diskSizeFactor is to calculate rounded value of disk size in gigabytes, example 17Gb->32Gb, 33Gb->64Gb.
+	diskSizeFactor float64 = 32
+
+	ec2APIVersion   string = "2016-11-15"
+	elbAPIVersion   string = "2012-06-01"
+	elbv2APIVersion string = "2015-12-01"
+
+	// elbMaxTagsRequest is the maximum number of load balancers the tags can be described for in one request.
+	elbMaxTagsRequest int = 20
+)
+
+// Client is the interface that provides the methods to get metrics from AWS Query API.
+type Client interface {
+	GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]EC2Instance, error)
+	GetInstanceTypes(ctx context.Context, instanceTypes []string, logger log.Logger) ([]InstanceType, error)
+	GetVolumes(ctx context.Context, technicalID string, logger log.Logger) ([]Volume, error)
+	GetLoadBalancers(ctx context.Context, technicalID string, logger log.Logger) ([]LoadBalancer, error)
+	GetVPCs(ctx context.Context, technicalID string, logger log.Logger) ([]VPC, error)
+	GetAddresses(ctx context.Context, technicalID string, logger log.Logger) ([]Address, error)
+}
+
+// client holds the AWS client configuration for a cluster region.
+type client struct {
+	httpClient      *http.Client
+	endpoint        func(service, region string) string
+	region          string
+	accessKeyID     string
+	secretAccessKey string
+	signer          *v4.Signer
+}
+
+var _ Client = (*client)(nil)
+
+// Instance is an instance of a cluster with its client configuration.
+type Instance struct {
+	// cluster holds the gardener cluster information.
+	cluster *gardener.Cluster
+	// client holds the AWS client for the cluster region.
+	client Client
+}
+
+// ClientFactory creates the AWS client for a cluster.
+type ClientFactory func(cluster *gardener.Cluster, logger log.Logger) (Client, error)
+
+// AWS collects the metrics of the clusters from the AWS APIs.
+type AWS struct {
+	instanceTypesStorage storage.Storage
+	ClientFactory        ClientFactory
+}
+
+// ClientSecretMap is a structure to decode and map kubernetes secret data values to aws client configuration.
+type ClientSecretMap struct {
+	AccessKeyID     string `mapstructure:"accessKeyID"`
+	SecretAccessKey string `mapstructure:"secretAccessKey"`
+}
+
+// APIError represent the error returned by AWS Query API.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("aws api responded with status %d: %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// EC2Instance is an EC2 virtual machine.
+type EC2Instance struct {
+	ID    string `xml:"instanceId"`
+	Type  string `xml:"instanceType"`
+	State string `xml:"instanceState>name"`
+}
+
+// InstanceType holds the capabilities of an EC2 instance type.
+type InstanceType struct {
+	Name      string `xml:"instanceType"`
+	VCPUs     uint32 `xml:"vCpuInfo>defaultVCpus"`
+	MemoryMiB uint64 `xml:"memoryInfo>sizeInMiB"`
+}
+
+// Volume is an EBS volume.
+type Volume struct {
+	ID     string `xml:"volumeId"`
+	SizeGB uint32 `xml:"size"`
+	State  string `xml:"status"`
+}
+
+// LoadBalancer is a classic, network or application load balancer.
+type LoadBalancer struct {
+	Name string
+	// ARN is set only for the network and application load balancers.
+	ARN string
+}
+
+// VPC is a virtual private cloud.
+type VPC struct {
+	ID string `xml:"vpcId"`
+}
+
+// Address is an elastic IP address.
+type Address struct {
+	AllocationID string `xml:"allocationId"`
+	PublicIP     string `xml:"publicIp"`
+}
+
+// VMType defines the event format for the virtual machine metrics.
+type VMType struct {
+	Name  string `json:"name"`
+	Count uint32 `json:"count"`
+}
+
+// ProvisionedVolume defines the event format for the volume metrics.
+type ProvisionedVolume struct {
+	SizeGBTotal   uint32 `json:"size_gb_total"`
+	SizeGBRounded uint32 `json:"size_gb_rounded"`
+	Count         uint32 `json:"count"`
+}
+
+// Compute defines the event format for the compute metrics.
+type Compute struct {
+	VMTypes            []VMType          `json:"vm_types"`
+	ProvisionedRAMGB   float64           `json:"provisioned_ram_gb"`
+	ProvisionedVolumes ProvisionedVolume `json:"provisioned_volumes"`
+	ProvisionedCpus    uint32            `json:"provisioned_cpus"`
+}
+
+// Networking defines the event format for the network metrics.
+type Networking struct {
+	ProvisionedLoadBalancers uint32 `json:"provisioned_loadbalancers"`
+	ProvisionedVnets         uint32 `json:"provisioned_vnets"`
+	ProvisionedIps           uint32 `json:"provisioned_ips"`
+}
+
+// EventHub defines the event format for the event hub metrics, it is always empty for AWS, but required by the EDP schema.
+type EventHub struct {
+	NumberNamespaces     uint32  `json:"number_namespaces"`
+	IncomingRequestsPT1M float64 `json:"incoming_requests_pt1m"`
+	MaxIncomingBytesPT1M float64 `json:"max_incoming_bytes_pt1m"`
+	MaxOutgoingBytesPT1M float64 `json:"max_outgoing_bytes_pt1m"`
+	IncomingRequestsPT5M float64 `json:"incoming_requests_pt5m"`
+	MaxIncomingBytesPT5M float64 `json:"max_incoming_bytes_pt5m"`
+	MaxOutgoingBytesPT5M float64 `json:"max_outgoing_bytes_pt5m"`
+}
+
+// EventData defines the event information to send to EDP.
+type EventData struct {
+	// ResourceGroups holds the shoot technical id, AWS has no resource groups, all resources are tagged with it.
+	ResourceGroups []string    `json:"resource_groups"`
+	Compute        *Compute    `json:"compute"`
+	Networking     *Networking `json:"networking"`
+	EventHub       *EventHub   `json:"event_hub"`
+}
@@ -46,9 +46,9 @@ func NewAzureProvider(config *provider.Config) provider.Provider {
 
 	return &Azure{
 		config:           config,
-		instanceStorage:  storage.NewMemoryStorage("clusters"),
-		vmCapsStorage:    storage.NewMemoryStorage("vm_capabilities"),
-		queue:            workqueue.NewNamedRateLimitingQueue(ratelimiter, "clients"),
+		instanceStorage:  config.NewStorage("clusters", instanceCodec{}),
+		vmCapsStorage:    config.NewStorage("vm_capabilities", storage.NewJSONCodec(&vmCapabilities{})),
+		queue:            workqueue.NewNamedRateLimitingQueue(ratelimiter, config.ResourceName("clients")),
 		ClientAuthConfig: &DefaultAuthConfig{},
 	}
 }
@@ -236,9 +236,18 @@ func processError(ctx context.Context, workerlogger log.Logger, instance *Instan
 func (a *Azure) clusterHandler(parentctx context.Context) {
 	a.config.Logger.Debug("starting cluster handler")
 
+	clusterSync := provider.NewClusterSync()
+
 	for {
 		select {
 		case cluster := <-a.config.ClusterChannel:
+			// clusters restored from storage which were deleted while metris was not running are removed after the initial sync.
+			if cluster.Synced {
+				clusterSync.Prune(a.instanceStorage, a.config.Logger)
+
+				continue
+			}
+
 			logger := a.config.Logger.
 				With("technicalid", cluster.TechnicalID).
 				With("accountid", cluster.AccountID).
@@ -255,12 +264,15 @@ func (a *Azure) clusterHandler(parentctx context.Context) {
 				continue
 			}
 
+			clusterSync.Confirm(cluster.TechnicalID)
+
 			instance := &Instance{cluster: cluster}
 
 			// recover instance from storage.
 			if obj, exists := a.instanceStorage.Get(cluster.TechnicalID); exists {
 				if i, ok := obj.(*Instance); ok {
 					instance.lastEvent = i.lastEvent
+					instance.retryAttempts = i.retryAttempts
 					instance.eventHubResourceGroupName = i.eventHubResourceGroupName
 				}
 			}
@@ -0,0 +1,45 @@
+package azure
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// persistedInstance is the part of an instance which is persisted, the cluster and its credentials are not.
+type persistedInstance struct {
+	LastEvent                 *EventData `json:"lastEvent,omitempty"`
+	EventHubResourceGroupName string     `json:"eventHubResourceGroupName,omitempty"`
+	RetryAttempts             int        `json:"retryAttempts,omitempty"`
+}
+
+// instanceCodec persists the instances of the instance storage, they are completed with the cluster and a new client
+// when the cluster is received from Gardener.
+type instanceCodec struct{}
+
+// Encode returns the instance marshaled to JSON.
+func (instanceCodec) Encode(obj interface{}) (json.RawMessage, error) {
+	instance, ok := obj.(*Instance)
+	if !ok {
+		return nil, fmt.Errorf("unexpected instance type %T", obj)
+	}
+
+	return json.Marshal(&persistedInstance{
+		LastEvent:                 instance.lastEvent,
+		EventHubResourceGroupName: instance.eventHubResourceGroupName,
+		RetryAttempts:             instance.retryAttempts,
+	})
+}
+
+// Decode returns the instance unmarshaled from JSON.
+func (instanceCodec) Decode(data json.RawMessage) (interface{}, error) {
+	persisted := &persistedInstance{}
+	if err := json.Unmarshal(data, persisted); err != nil {
+		return nil, err
+	}
+
+	return &Instance{
+		lastEvent:                 persisted.LastEvent,
+		eventHubResourceGroupName: persisted.EventHubResourceGroupName,
+		retryAttempts:             persisted.RetryAttempts,
+	}, nil
+}
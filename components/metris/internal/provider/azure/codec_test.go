@@ -0,0 +1,47 @@
+package azure
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstanceCodec(t *testing.T) {
+	asserts := assert.New(t)
+
+	// given
+	instance := &Instance{
+		cluster: &gardener.Cluster{
+			TechnicalID:    "test-technicalid",
+			CredentialData: map[string][]byte{"clientSecret": []byte("secret")},
+		},
+		lastEvent: &EventData{
+			ResourceGroups: []string{"test-technicalid"},
+			Compute:        &Compute{VMTypes: []VMType{{Name: "Standard_D8_v3", Count: 3}}, ProvisionedCpus: 24},
+			Networking:     &Networking{ProvisionedVnets: 1},
+			EventHub:       &EventHub{NumberNamespaces: 1},
+		},
+		eventHubResourceGroupName: "test-resourcegroup",
+		retryAttempts:             2,
+	}
+
+	// when
+	data, err := instanceCodec{}.Encode(instance)
+	require.NoError(t, err)
+
+	obj, err := instanceCodec{}.Decode(data)
+	require.NoError(t, err)
+
+	// then
+	asserts.NotContains(string(data), "secret", "credentials should not be persisted")
+	asserts.Equal(&Instance{
+		lastEvent:                 instance.lastEvent,
+		eventHubResourceGroupName: "test-resourcegroup",
+		retryAttempts:             2,
+	}, obj)
+
+	_, err = instanceCodec{}.Encode("not an instance")
+	asserts.Error(err)
+}
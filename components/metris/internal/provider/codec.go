@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// persistedInstance is the part of an instance which is persisted, the cluster and its client are not.
+type persistedInstance struct {
+	LastEvent     json.RawMessage `json:"lastEvent,omitempty"`
+	RetryAttempts int             `json:"retryAttempts,omitempty"`
+}
+
+// instanceCodec persists the instances of the instance storage, they are completed with the cluster and a new client
+// when the cluster is received from Gardener.
+type instanceCodec struct{}
+
+// Encode returns the instance marshaled to JSON.
+func (instanceCodec) Encode(obj interface{}) (json.RawMessage, error) {
+	instance, ok := obj.(*Instance)
+	if !ok {
+		return nil, fmt.Errorf("unexpected instance type %T", obj)
+	}
+
+	return json.Marshal(&persistedInstance{LastEvent: instance.LastEvent, RetryAttempts: instance.RetryAttempts})
+}
+
+// Decode returns the instance unmarshaled from JSON.
+func (instanceCodec) Decode(data json.RawMessage) (interface{}, error) {
+	persisted := &persistedInstance{}
+	if err := json.Unmarshal(data, persisted); err != nil {
+		return nil, err
+	}
+
+	return &Instance{LastEvent: persisted.LastEvent, RetryAttempts: persisted.RetryAttempts}, nil
+}
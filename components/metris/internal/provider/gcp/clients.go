@@ -0,0 +1,292 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+	"github.com/mitchellh/mapstructure"
+	"go.opencensus.io/trace"
+	"golang.org/x/oauth2/google"
+)
+
+// decode decodes and map kubernetes secret data into the ClientSecretMap structure.
+func (s *ClientSecretMap) decode(secrets map[string][]byte) error {
+	var (
+		decodedSecrets = make(map[string]string)
+	)
+
+	for k, v := range secrets {
+		decodedSecrets[k] = string(v)
+	}
+
+	return mapstructure.Decode(decodedSecrets, s)
+}
+
+// newClient return a new client for a cluster base on the cluster configuration provided.
+func newClient(cluster *gardener.Cluster, logger log.Logger) (Client, error) {
+	conf := &ClientSecretMap{}
+
+	if err := conf.decode(cluster.CredentialData); err != nil {
+		return nil, err
+	}
+
+	if conf.ServiceAccount == "" {
+		return nil, fmt.Errorf("serviceaccount.json not found in the cluster credentials")
+	}
+
+	account := &serviceAccount{}
+	if err := json.Unmarshal([]byte(conf.ServiceAccount), account); err != nil {
+		return nil, fmt.Errorf("could not decode service account: %w", err)
+	}
+
+	if account.ProjectID == "" {
+		return nil, fmt.Errorf("project_id not found in the service account")
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON([]byte(conf.ServiceAccount), computeReadOnlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse service account: %w", err)
+	}
+
+	logger.
+		With("region", cluster.Region).
+		With("project", account.ProjectID).
+		Debug("generating new client")
+
+	return &client{
+		// the token is fetched and refreshed on demand by the transport.
+		httpClient: jwtConfig.Client(context.Background()),
+		endpoint:   computeEndpoint,
+		project:    account.ProjectID,
+		region:     cluster.Region,
+	}, nil
+}
+
+// GetInstances returns the Compute Engine instances of the cluster in all the zones.
+func (c *client) GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]ComputeInstance, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/GetInstances")
+		defer span.End()
+	}
+
+	var instances []ComputeInstance
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			Items map[string]struct {
+				Instances []ComputeInstance `json:"instances"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}{}
+		err := c.call(ctx, "instances.aggregatedList", "aggregated/instances", withToken(clusterFilter(technicalID), token), &response, logger)
+
+		for _, scope := range response.Items {
+			instances = append(instances, scope.Instances...)
+		}
+
+		return response.NextPageToken, err
+	})
+
+	return instances, err
+}
+
+// GetMachineType returns the capabilities of the machine type in the zone.
+func (c *client) GetMachineType(ctx context.Context, zone, machineType string, logger log.Logger) (*MachineType, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/GetMachineType")
+		defer span.End()
+	}
+
+	result := &MachineType{}
+	if err := c.call(ctx, "machineTypes.get", fmt.Sprintf("zones/%s/machineTypes/%s", zone, machineType), url.Values{}, result, logger); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetDisks returns the persistent disks of the cluster in all the zones and regions.
+func (c *client) GetDisks(ctx context.Context, technicalID string, logger log.Logger) ([]Disk, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/GetDisks")
+		defer span.End()
+	}
+
+	var disks []Disk
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			Items map[string]struct {
+				Disks []Disk `json:"disks"`
+			} `json:"items"`
+			NextPageToken string `json:"nextPageToken"`
+		}{}
+		err := c.call(ctx, "disks.aggregatedList", "aggregated/disks", withToken(clusterFilter(technicalID), token), &response, logger)
+
+		for _, scope := range response.Items {
+			disks = append(disks, scope.Disks...)
+		}
+
+		return response.NextPageToken, err
+	})
+
+	return disks, err
+}
+
+// GetForwardingRules returns the forwarding rules of the cluster in the cluster region.
+func (c *client) GetForwardingRules(ctx context.Context, technicalID string, logger log.Logger) ([]ForwardingRule, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/GetForwardingRules")
+		defer span.End()
+	}
+
+	var rules []ForwardingRule
+
+	err := paginate(func(token string) (string, error) {
+		response := struct {
+			Items         []ForwardingRule `json:"items"`
+			NextPageToken string           `json:"nextPageToken"`
+		}{}
+		err := c.call(ctx, "forwardingRules.list", fmt.Sprintf("regions/%s/forwardingRules", c.region), withToken(clusterFilter(technicalID), token), &response, logger)
+		rules = append(rules, response.Items...)
+
+		return response.NextPageToken, err
+	})
+
+	return rules, err
+}
+
+// GetNetwork returns the network Gardener creates for the cluster, named after its technical id, or nil if it does not exist.
+func (c *client) GetNetwork(ctx context.Context, technicalID string, logger log.Logger) (*Network, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/GetNetwork")
+		defer span.End()
+	}
+
+	result := &Network{}
+	if err := c.call(ctx, "networks.get", "global/networks/"+technicalID, url.Values{}, result, logger); err != nil {
+		if apiErr, ok := err.(*APIError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// call gets the resource of the cluster project from the Compute Engine API and decodes the JSON response into the result.
+func (c *client) call(ctx context.Context, operation, resource string, params url.Values, result interface{}, logger log.Logger) error {
+	metricfn := collectRequestMetrics(operation)
+	defer metricfn()
+
+	endpoint := fmt.Sprintf("%sprojects/%s/%s", c.endpoint, c.project, resource)
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	apiRequestCounter.WithLabelValues(operation, strconv.Itoa(response.StatusCode)).Inc()
+
+	logger.
+		With("operation", operation).
+		With("status", response.StatusCode).
+		With("time", time.Since(start)).
+		Debug("request")
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		errorResponse := struct {
+			Error struct {
+				Message string `json:"message"`
+				Errors  []struct {
+					Reason string `json:"reason"`
+				} `json:"errors"`
+			} `json:"error"`
+		}{}
+		_ = json.Unmarshal(responseBody, &errorResponse)
+
+		apiErr := &APIError{StatusCode: response.StatusCode, Message: errorResponse.Error.Message}
+		if len(errorResponse.Error.Errors) > 0 {
+			apiErr.Reason = errorResponse.Error.Errors[0].Reason
+		}
+
+		return apiErr
+	}
+
+	return json.Unmarshal(responseBody, result)
+}
+
+// clusterFilter returns the filter for the resources labelled with the cluster technical id.
+func clusterFilter(technicalID string) url.Values {
+	return url.Values{
+		"filter": {fmt.Sprintf("labels.%s = %q", labelKeyCluster, technicalID)},
+	}
+}
+
+// withToken returns a copy of the params with the page token set.
+func withToken(params url.Values, token string) url.Values {
+	result := url.Values{}
+	for key, values := range params {
+		result[key] = values
+	}
+
+	if token != "" {
+		result.Set("pageToken", token)
+	}
+
+	return result
+}
+
+// paginate fetches the pages until the returned token is empty.
+func paginate(fetch func(token string) (string, error)) error {
+	token := ""
+
+	for {
+		next, err := fetch(token)
+		if err != nil {
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+
+		token = next
+	}
+}
@@ -0,0 +1,186 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+)
+
+// newTestServer returns a server answering the Compute Engine API resources with the given JSON responses,
+// the key is the resource path in the project and "<path>:<token>" for the next pages.
+func newTestServer(t *testing.T, responses map[string]string) (*httptest.Server, *client) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		key := strings.TrimPrefix(r.URL.Path, "/projects/test-project/")
+		if token := r.URL.Query().Get("pageToken"); token != "" {
+			key += ":" + token
+		}
+
+		if strings.HasPrefix(key, "aggregated/") || strings.HasSuffix(strings.Split(key, ":")[0], "/forwardingRules") {
+			assert.Equal(t, `labels.name = "shoot--kyma--c-1234567"`, r.URL.Query().Get("filter"))
+		}
+
+		response, ok := responses[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprintf(w, `{"error": {"code": 404, "message": "unexpected call %s", "errors": [{"reason": "notFound"}]}}`, key)
+
+			return
+		}
+
+		if strings.HasPrefix(response, `{"error"`) {
+			w.WriteHeader(http.StatusForbidden)
+		}
+
+		_, _ = fmt.Fprint(w, response)
+	}))
+
+	return server, &client{
+		httpClient: server.Client(),
+		endpoint:   server.URL + "/",
+		project:    "test-project",
+		region:     "europe-west1",
+	}
+}
+
+func TestClient_GetInstances(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"aggregated/instances": `{"items": {
+			"zones/europe-west1-b": {"instances": [{"name": "vm-1", "machineType": "zones/europe-west1-b/machineTypes/n1-standard-4", "zone": "zones/europe-west1-b", "status": "RUNNING"}]},
+			"zones/europe-west1-c": {"warning": {"code": "NO_RESULTS_ON_PAGE"}}
+			}, "nextPageToken": "page-2"}`,
+		"aggregated/instances:page-2": `{"items": {
+			"zones/europe-west1-c": {"instances": [{"name": "vm-2", "machineType": "zones/europe-west1-c/machineTypes/n1-standard-2", "zone": "zones/europe-west1-c", "status": "STOPPING"}]}
+			}}`,
+	})
+	defer server.Close()
+
+	// when
+	instances, err := c.GetInstances(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []ComputeInstance{
+		{Name: "vm-1", MachineType: "zones/europe-west1-b/machineTypes/n1-standard-4", Zone: "zones/europe-west1-b", Status: "RUNNING"},
+		{Name: "vm-2", MachineType: "zones/europe-west1-c/machineTypes/n1-standard-2", Zone: "zones/europe-west1-c", Status: "STOPPING"},
+	}, instances)
+}
+
+func TestClient_GetMachineType(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"zones/europe-west1-b/machineTypes/n1-standard-4": `{"name": "n1-standard-4", "guestCpus": 4, "memoryMb": 15360}`,
+	})
+	defer server.Close()
+
+	// when
+	machineType, err := c.GetMachineType(context.Background(), "europe-west1-b", "n1-standard-4", noopLogger)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, &MachineType{Name: "n1-standard-4", GuestCpus: 4, MemoryMb: 15360}, machineType)
+}
+
+func TestClient_GetDisksForwardingRules(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"aggregated/disks": `{"items": {
+			"zones/europe-west1-b": {"disks": [{"name": "disk-1", "sizeGb": "50", "status": "READY"}]},
+			"regions/europe-west1": {"disks": [{"name": "disk-2", "sizeGb": "200", "status": "READY"}]}
+			}}`,
+		"regions/europe-west1/forwardingRules":        `{"items": [{"name": "a1", "IPAddress": "1.2.3.4"}], "nextPageToken": "page-2"}`,
+		"regions/europe-west1/forwardingRules:page-2": `{"items": [{"name": "a2", "IPAddress": "1.2.3.5"}]}`,
+	})
+	defer server.Close()
+
+	// when
+	disks, disksErr := c.GetDisks(context.Background(), testCluster.TechnicalID, noopLogger)
+	rules, rulesErr := c.GetForwardingRules(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	require.NoError(t, disksErr)
+	require.NoError(t, rulesErr)
+	assert.ElementsMatch(t, []Disk{{Name: "disk-1", SizeGb: 50, Status: "READY"}, {Name: "disk-2", SizeGb: 200, Status: "READY"}}, disks)
+	assert.Equal(t, []ForwardingRule{{Name: "a1", IPAddress: "1.2.3.4"}, {Name: "a2", IPAddress: "1.2.3.5"}}, rules)
+}
+
+func TestClient_GetNetwork(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"global/networks/" + testCluster.TechnicalID: `{"name": "shoot--kyma--c-1234567"}`,
+	})
+	defer server.Close()
+
+	t.Run("should return network", func(t *testing.T) {
+		// when
+		network, err := c.GetNetwork(context.Background(), testCluster.TechnicalID, noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Network{Name: testCluster.TechnicalID}, network)
+	})
+
+	t.Run("should return nil when network is not found", func(t *testing.T) {
+		// when
+		network, err := c.GetNetwork(context.Background(), "shoot--kyma--c-deleted", noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, network)
+	})
+}
+
+func TestClient_Error(t *testing.T) {
+	// given
+	server, c := newTestServer(t, map[string]string{
+		"global/networks/" + testCluster.TechnicalID: `{"error": {"code": 403, "message": "Rate Limit Exceeded", "errors": [{"reason": "rateLimitExceeded"}]}}`,
+	})
+	defer server.Close()
+
+	// when
+	_, err := c.GetNetwork(context.Background(), testCluster.TechnicalID, noopLogger)
+
+	// then
+	assert.Equal(t, &APIError{StatusCode: http.StatusForbidden, Reason: "rateLimitExceeded", Message: "Rate Limit Exceeded"}, err)
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should create client for cluster project", func(t *testing.T) {
+		// when
+		c, err := newClient(testCluster, noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "test-project", c.(*client).project)
+		assert.Equal(t, "europe-west1", c.(*client).region)
+		assert.Equal(t, computeEndpoint, c.(*client).endpoint)
+	})
+
+	t.Run("should fail without credentials", func(t *testing.T) {
+		// when
+		_, err := newClient(&gardener.Cluster{Region: "europe-west1", CredentialData: map[string][]byte{}}, noopLogger)
+
+		// then
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail without project", func(t *testing.T) {
+		// when
+		_, err := newClient(&gardener.Cluster{Region: "europe-west1", CredentialData: map[string][]byte{
+			"serviceaccount.json": []byte(`{"type": "service_account"}`),
+		}}, noopLogger)
+
+		// then
+		assert.EqualError(t, err, "project_id not found in the service account")
+	})
+}
@@ -0,0 +1,111 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go.opencensus.io/trace"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/provider"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+)
+
+var (
+	// register the gcp provider
+	_ = func() struct{} {
+		err := provider.RegisterProvider("gcp", NewGCPProvider)
+		if err != nil {
+			panic(err)
+		}
+		return struct{}{}
+	}()
+)
+
+// NewGCPProvider returns a new GCP provider.
+func NewGCPProvider(config *provider.Config) provider.Provider {
+	return provider.NewPoller(config, newGCP(config))
+}
+
+func newGCP(config *provider.Config) *GCP {
+	return &GCP{
+		machineTypesStorage: config.NewStorage("machine_types", storage.NewJSONCodec(&MachineType{})),
+		ClientFactory:       newClient,
+	}
+}
+
+// NewClient returns the GCP client for the cluster project.
+func (g *GCP) NewClient(cluster *gardener.Cluster, logger log.Logger) (interface{}, error) {
+	client, err := g.ClientFactory(cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// GetMetrics returns the event data of the cluster instance.
+func (g *GCP) GetMetrics(ctx context.Context, workerlogger log.Logger, instance *provider.Instance) (interface{}, error) {
+	client, ok := instance.Client.(Client)
+	if !ok {
+		return nil, fmt.Errorf("unexpected client type %T", instance.Client)
+	}
+
+	return getMetricsFromGCP(ctx, workerlogger, &Instance{cluster: instance.Cluster, client: client}, g.machineTypesStorage)
+}
+
+// IsNotFound returns true if the cluster network is not found.
+func (g *GCP) IsNotFound(err error) bool {
+	return errors.Is(err, ErrNetworkNotFound)
+}
+
+// IsThrottled returns true if the error is a throttling error of the GCP API.
+func (g *GCP) IsThrottled(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && isThrottlingError(apiErr)
+}
+
+func isThrottlingError(err *APIError) bool {
+	switch err.Reason {
+	case "rateLimitExceeded", "userRateLimitExceeded":
+		return true
+	}
+
+	return err.StatusCode == http.StatusTooManyRequests
+}
+
+// getMetricsFromGCP - collect results from different GCP API and create edp events.
+func getMetricsFromGCP(ctx context.Context, workerlogger log.Logger, instance *Instance, machineTypesStorage storage.Storage) (*EventData, error) {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/getMetrics")
+		defer span.End()
+
+		workerlogger = workerlogger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	workerlogger.Debug("getting metrics")
+
+	networkData, err := instance.getNetworkMetrics(ctx, workerlogger)
+	if err != nil {
+		return nil, err
+	}
+
+	computeData, err := instance.getComputeMetrics(ctx, workerlogger, machineTypesStorage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventData{
+		ResourceGroups: []string{instance.cluster.TechnicalID},
+		Compute:        computeData,
+		Networking:     networkData,
+		EventHub:       &EventHub{},
+	}, nil
+}
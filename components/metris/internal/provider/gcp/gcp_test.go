@@ -0,0 +1,162 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/edp"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/provider"
+)
+
+const testServiceAccount = `{
+	"type": "service_account",
+	"project_id": "test-project",
+	"private_key_id": "test-privatekeyid",
+	"private_key": "test-privatekey",
+	"client_email": "test@test-project.iam.gserviceaccount.com",
+	"token_uri": "https://oauth2.googleapis.com/token"
+}`
+
+var (
+	noopLogger = log.NewNoopLogger()
+
+	testCluster = &gardener.Cluster{
+		TechnicalID:  "shoot--kyma--c-1234567",
+		ProviderType: "gcp",
+		Region:       "europe-west1",
+		CredentialData: map[string][]byte{
+			"serviceaccount.json": []byte(testServiceAccount),
+		},
+		AccountID:    "test-accountid",
+		SubAccountID: "test-subaccountid",
+	}
+)
+
+func newTestProviderConfig() *provider.Config {
+	return &provider.Config{
+		PollInterval:    time.Minute,
+		PollingDuration: time.Minute,
+		Workers:         1,
+		Buffer:          1,
+		MaxRetries:      2,
+		ClusterChannel:  make(chan *gardener.Cluster, 1),
+		EventsChannel:   make(chan *edp.Event, 1),
+		Logger:          noopLogger,
+	}
+}
+
+func TestNewGCPProvider(t *testing.T) {
+	p := NewGCPProvider(newTestProviderConfig())
+	assert.Implements(t, (*provider.Provider)(nil), p, "")
+}
+
+func TestGCP_Run(t *testing.T) {
+	// given
+	config := newTestProviderConfig()
+	events := make(chan *edp.Event, 1)
+	config.EventsChannel = events
+
+	c := newGCP(config)
+	c.ClientFactory = func(cluster *gardener.Cluster, logger log.Logger) (Client, error) {
+		return newFakeClient(), nil
+	}
+	p := provider.NewPoller(config, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go p.Run(ctx)
+
+	// when
+	config.ClusterChannel <- testCluster
+
+	// then
+	select {
+	case event := <-events:
+		assert.Equal(t, testCluster.SubAccountID, event.Datatenant)
+
+		eventData := &EventData{}
+		require.NoError(t, json.Unmarshal(*event.Data, eventData))
+		assert.Equal(t, []string{testCluster.TechnicalID}, eventData.ResourceGroups)
+		assert.Equal(t, uint32(10), eventData.Compute.ProvisionedCpus)
+		assert.Equal(t, uint32(2), eventData.Networking.ProvisionedLoadBalancers)
+		assert.Equal(t, &EventHub{}, eventData.EventHub)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not sent")
+	}
+}
+
+func TestGCP_GetMetrics(t *testing.T) {
+	t.Run("should return the event data of the instance", func(t *testing.T) {
+		// given
+		c := newGCP(newTestProviderConfig())
+		instance := &provider.Instance{Cluster: testCluster, Client: newFakeClient()}
+
+		// when
+		obj, err := c.GetMetrics(context.Background(), noopLogger, instance)
+
+		// then
+		require.NoError(t, err)
+		eventData, ok := obj.(*EventData)
+		require.True(t, ok)
+		assert.Equal(t, []string{testCluster.TechnicalID}, eventData.ResourceGroups)
+	})
+
+	t.Run("should fail with an unexpected client", func(t *testing.T) {
+		// given
+		c := newGCP(newTestProviderConfig())
+		instance := &provider.Instance{Cluster: testCluster, Client: struct{}{}}
+
+		// when
+		_, err := c.GetMetrics(context.Background(), noopLogger, instance)
+
+		// then
+		assert.Error(t, err)
+	})
+}
+
+func TestGCP_errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantThrottled bool
+		wantNotFound  bool
+	}{
+		{
+			name:          "throttled",
+			err:           &APIError{StatusCode: 403, Reason: "rateLimitExceeded"},
+			wantThrottled: true,
+		},
+		{
+			name:          "too many requests",
+			err:           fmt.Errorf("wrapped: %w", &APIError{StatusCode: 429}),
+			wantThrottled: true,
+		},
+		{
+			name:         "network not found",
+			err:          fmt.Errorf("wrapped: %w", ErrNetworkNotFound),
+			wantNotFound: true,
+		},
+		{
+			name: "other error",
+			err:  &APIError{StatusCode: 401, Reason: "authError"},
+		},
+	}
+
+	c := newGCP(newTestProviderConfig())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantThrottled, c.IsThrottled(tt.err))
+			assert.Equal(t, tt.wantNotFound, c.IsNotFound(tt.err))
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"sort"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+	"go.opencensus.io/trace"
+)
+
+func (i *Instance) getComputeMetrics(ctx context.Context, logger log.Logger, machineTypesStorage storage.Storage) (*Compute, error) {
+	var (
+		instances []ComputeInstance
+		disks     []Disk
+		err       error
+		vmt       = make(map[string]uint32)
+		// zones holds a zone of each machine type to get its capabilities from
+		zones  = make(map[string]string)
+		result = &Compute{
+			VMTypes:          make([]VMType, 0),
+			ProvisionedCpus:  0,
+			ProvisionedRAMGB: 0,
+			ProvisionedVolumes: ProvisionedVolume{
+				SizeGBTotal:   0,
+				SizeGBRounded: 0,
+				Count:         0,
+			},
+		}
+	)
+
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/getComputeMetrics")
+		defer span.End()
+
+		logger = logger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	if instances, err = i.client.GetInstances(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	for _, instance := range instances {
+		// only the instances which are or will be running are billed
+		if instance.Status != "PROVISIONING" && instance.Status != "STAGING" && instance.Status != "RUNNING" {
+			continue
+		}
+
+		// machine type and zone are URLs, the name is the last element
+		machineType := path.Base(instance.MachineType)
+		vmt[machineType]++
+		zones[machineType] = path.Base(instance.Zone)
+	}
+
+	for vmtype, count := range vmt {
+		machineType, err := i.getMachineType(ctx, logger, zones[vmtype], vmtype, machineTypesStorage)
+		if err != nil {
+			return nil, fmt.Errorf("could not get machine type capabilities for type %s: %w", vmtype, err)
+		}
+
+		result.ProvisionedCpus += machineType.GuestCpus * count
+		result.ProvisionedRAMGB += float64(machineType.MemoryMb) / 1024 * float64(count)
+		result.VMTypes = append(result.VMTypes, VMType{Name: vmtype, Count: count})
+	}
+
+	sort.Slice(result.VMTypes, func(a, b int) bool { return result.VMTypes[a].Name < result.VMTypes[b].Name })
+
+	if disks, err = i.client.GetDisks(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	for _, disk := range disks {
+		if disk.Status == "DELETING" || disk.Status == "FAILED" {
+			continue
+		}
+
+		result.ProvisionedVolumes.Count++
+		result.ProvisionedVolumes.SizeGBTotal += disk.SizeGb
+		result.ProvisionedVolumes.SizeGBRounded += uint32(math.Ceil(float64(disk.SizeGb)/diskSizeFactor) * diskSizeFactor)
+	}
+
+	return result, nil
+}
+
+// getMachineType returns the capabilities of the machine type, it is fetched from GCP if not found in the storage.
+func (i *Instance) getMachineType(ctx context.Context, logger log.Logger, zone, name string, machineTypesStorage storage.Storage) (*MachineType, error) {
+	key := machineTypeKey(i.cluster.Region, name)
+
+	if obj, exists := machineTypesStorage.Get(key); exists {
+		if machineType, ok := obj.(*MachineType); ok {
+			return machineType, nil
+		}
+	}
+
+	logger.Debugf("getting capabilities of machine type %s in zone %s", name, zone)
+
+	machineType, err := i.client.GetMachineType(ctx, zone, name, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	machineTypesStorage.Put(key, machineType)
+
+	return machineType, nil
+}
+
+func (i *Instance) getNetworkMetrics(ctx context.Context, logger log.Logger) (*Networking, error) {
+	var (
+		result = &Networking{
+			ProvisionedLoadBalancers: 0,
+			ProvisionedIps:           0,
+			ProvisionedVnets:         0,
+		}
+		err     error
+		network *Network
+		rules   []ForwardingRule
+		ips     = make(map[string]struct{})
+	)
+
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/gcp/getNetworkMetrics")
+		defer span.End()
+
+		logger = logger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	if network, err = i.client.GetNetwork(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	// all shoots have a network, if none is found, the cluster may have been deleted
+	if network == nil {
+		return nil, ErrNetworkNotFound
+	}
+
+	result.ProvisionedVnets++
+
+	if rules, err = i.client.GetForwardingRules(ctx, i.cluster.TechnicalID, logger); err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		result.ProvisionedLoadBalancers++
+
+		// several forwarding rules can share the same IP address
+		if rule.IPAddress != "" {
+			ips[rule.IPAddress] = struct{}{}
+		}
+	}
+
+	result.ProvisionedIps += uint32(len(ips))
+
+	return result, nil
+}
+
+func machineTypeKey(region, machineType string) string {
+	return region + "/" + machineType
+}
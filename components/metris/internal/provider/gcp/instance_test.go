@@ -0,0 +1,191 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+)
+
+const testZoneURL = "https://www.googleapis.com/compute/v1/projects/test-project/zones/europe-west1-b"
+
+// fakeClient is a Client returning the configured resources for the test cluster.
+type fakeClient struct {
+	instances       []ComputeInstance
+	machineTypes    map[string]*MachineType
+	disks           []Disk
+	forwardingRules []ForwardingRule
+	network         *Network
+	err             error
+
+	machineTypesRequests []string
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		instances: []ComputeInstance{
+			{Name: "vm-1", MachineType: testZoneURL + "/machineTypes/n1-standard-4", Zone: testZoneURL, Status: "RUNNING"},
+			{Name: "vm-2", MachineType: testZoneURL + "/machineTypes/n1-standard-4", Zone: testZoneURL, Status: "RUNNING"},
+			{Name: "vm-3", MachineType: testZoneURL + "/machineTypes/n1-standard-2", Zone: testZoneURL, Status: "STAGING"},
+			{Name: "vm-4", MachineType: testZoneURL + "/machineTypes/n1-standard-8", Zone: testZoneURL, Status: "TERMINATED"},
+		},
+		machineTypes: map[string]*MachineType{
+			"n1-standard-2": {Name: "n1-standard-2", GuestCpus: 2, MemoryMb: 7680},
+			"n1-standard-4": {Name: "n1-standard-4", GuestCpus: 4, MemoryMb: 15360},
+		},
+		disks: []Disk{
+			{Name: "disk-1", SizeGb: 50, Status: "READY"},
+			{Name: "disk-2", SizeGb: 20, Status: "CREATING"},
+			{Name: "disk-3", SizeGb: 100, Status: "DELETING"},
+		},
+		forwardingRules: []ForwardingRule{{Name: "a1", IPAddress: "1.2.3.4"}, {Name: "a2", IPAddress: "1.2.3.4"}},
+		network:         &Network{Name: testCluster.TechnicalID},
+	}
+}
+
+func (c *fakeClient) GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]ComputeInstance, error) {
+	return c.instances, c.err
+}
+
+func (c *fakeClient) GetMachineType(ctx context.Context, zone, machineType string, logger log.Logger) (*MachineType, error) {
+	c.machineTypesRequests = append(c.machineTypesRequests, zone+"/"+machineType)
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	result, ok := c.machineTypes[machineType]
+	if !ok {
+		return nil, &APIError{StatusCode: 404, Reason: "notFound"}
+	}
+
+	return result, nil
+}
+
+func (c *fakeClient) GetDisks(ctx context.Context, technicalID string, logger log.Logger) ([]Disk, error) {
+	return c.disks, c.err
+}
+
+func (c *fakeClient) GetForwardingRules(ctx context.Context, technicalID string, logger log.Logger) ([]ForwardingRule, error) {
+	return c.forwardingRules, c.err
+}
+
+func (c *fakeClient) GetNetwork(ctx context.Context, technicalID string, logger log.Logger) (*Network, error) {
+	return c.network, c.err
+}
+
+func TestInstance_getComputeMetrics(t *testing.T) {
+	t.Run("should sum up instances and disks", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		compute, err := instance.getComputeMetrics(context.Background(), noopLogger, storage.NewMemoryStorage("machine_types"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Compute{
+			VMTypes:          []VMType{{Name: "n1-standard-2", Count: 1}, {Name: "n1-standard-4", Count: 2}},
+			ProvisionedCpus:  10,
+			ProvisionedRAMGB: 37.5,
+			ProvisionedVolumes: ProvisionedVolume{
+				SizeGBTotal:   70,
+				SizeGBRounded: 96,
+				Count:         2,
+			},
+		}, compute)
+		assert.ElementsMatch(t, []string{"europe-west1-b/n1-standard-2", "europe-west1-b/n1-standard-4"}, client.machineTypesRequests)
+	})
+
+	t.Run("should fetch only the machine types not cached", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		instance := &Instance{cluster: testCluster, client: client}
+		machineTypesStorage := storage.NewMemoryStorage("machine_types")
+		machineTypesStorage.Put(machineTypeKey(testCluster.Region, "n1-standard-4"), client.machineTypes["n1-standard-4"])
+
+		// when
+		_, err := instance.getComputeMetrics(context.Background(), noopLogger, machineTypesStorage)
+		require.NoError(t, err)
+		_, err = instance.getComputeMetrics(context.Background(), noopLogger, machineTypesStorage)
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, []string{"europe-west1-b/n1-standard-2"}, client.machineTypesRequests)
+	})
+
+	t.Run("should fail for unknown machine type", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		delete(client.machineTypes, "n1-standard-2")
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getComputeMetrics(context.Background(), noopLogger, storage.NewMemoryStorage("machine_types"))
+
+		// then
+		assert.EqualError(t, err, "could not get machine type capabilities for type n1-standard-2: gcp api responded with status 404: notFound: ")
+	})
+
+	t.Run("should return empty metrics for cluster without instances", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.instances = nil
+		client.disks = nil
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		compute, err := instance.getComputeMetrics(context.Background(), noopLogger, storage.NewMemoryStorage("machine_types"))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Compute{VMTypes: []VMType{}}, compute)
+		assert.Empty(t, client.machineTypesRequests)
+	})
+}
+
+func TestInstance_getNetworkMetrics(t *testing.T) {
+	t.Run("should count network resources", func(t *testing.T) {
+		// given
+		instance := &Instance{cluster: testCluster, client: newFakeClient()}
+
+		// when
+		networking, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Networking{ProvisionedLoadBalancers: 2, ProvisionedVnets: 1, ProvisionedIps: 1}, networking)
+	})
+
+	t.Run("should fail when network is not found", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.network = nil
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		assert.True(t, errors.Is(err, ErrNetworkNotFound))
+	})
+
+	t.Run("should return client error", func(t *testing.T) {
+		// given
+		client := newFakeClient()
+		client.err = &APIError{StatusCode: 401, Reason: "authError"}
+		instance := &Instance{cluster: testCluster, client: client}
+
+		// when
+		_, err := instance.getNetworkMetrics(context.Background(), noopLogger)
+
+		// then
+		assert.Equal(t, client.err, err)
+	})
+}
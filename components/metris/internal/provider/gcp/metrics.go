@@ -0,0 +1,41 @@
+package gcp
+
+import (
+	"github.com/kyma-project/control-plane/components/metris/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	apiRequestDurationHist = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "gcp",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP request to GCP in seconds.",
+			// Duration buckets, in seconds
+			// 100ms, 200ms, 300ms, 400ms, 500ms, 750ms, 1s, 1.5s, 2s
+			Buckets: []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.75, 1, 1.5, 2.0},
+		},
+		[]string{"operation"},
+	)
+
+	apiRequestCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "gcp",
+			Name:      "request_total",
+			Help:      "Total number of HTTP request made to GCP.",
+		},
+		[]string{"operation", "status"},
+	)
+)
+
+// collectRequestMetrics collect GCP HTTP request metrics.
+func collectRequestMetrics(operation string) func() {
+	metricTimer := prometheus.NewTimer(apiRequestDurationHist.WithLabelValues(operation))
+
+	return func() {
+		_ = metricTimer.ObserveDuration()
+	}
+}
@@ -0,0 +1,169 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+)
+
+var (
+	ErrNetworkNotFound = errors.New("network not found")
+)
+
+const (
+	// labelKeyCluster is the key of the label Gardener sets with the shoot technical id on the resources of a shoot.
+	labelKeyCluster string = "name"
+
+	// diskSizeFactor is to calculate rounded value of disk size in gigabytes, example 17Gb->32Gb, 33Gb->64Gb.
+	diskSizeFactor float64 = 32
+
+	// computeReadOnlyScope is the OAuth2 scope required to read the compute resources.
+	computeReadOnlyScope string = "https://www.googleapis.com/auth/compute.readonly"
+
+	computeEndpoint string = "https://compute.googleapis.com/compute/v1/"
+)
+
+// Client is the interface that provides the methods to get metrics from GCP Compute Engine API.
+type Client interface {
+	GetInstances(ctx context.Context, technicalID string, logger log.Logger) ([]ComputeInstance, error)
+	GetMachineType(ctx context.Context, zone, machineType string, logger log.Logger) (*MachineType, error)
+	GetDisks(ctx context.Context, technicalID string, logger log.Logger) ([]Disk, error)
+	GetForwardingRules(ctx context.Context, technicalID string, logger log.Logger) ([]ForwardingRule, error)
+	GetNetwork(ctx context.Context, technicalID string, logger log.Logger) (*Network, error)
+}
+
+// client holds the GCP client configuration for a cluster project and region.
+type client struct {
+	// httpClient authorizes the requests with the service account token.
+	httpClient *http.Client
+	endpoint   string
+	project    string
+	region     string
+}
+
+var _ Client = (*client)(nil)
+
+// Instance is an instance of a cluster with its client configuration.
+type Instance struct {
+	// cluster holds the gardener cluster information.
+	cluster *gardener.Cluster
+	// client holds the GCP client for the cluster project.
+	client Client
+}
+
+// ClientFactory creates the GCP client for a cluster.
+type ClientFactory func(cluster *gardener.Cluster, logger log.Logger) (Client, error)
+
+// GCP collects the metrics of the clusters from the GCP APIs.
+type GCP struct {
+	machineTypesStorage storage.Storage
+	ClientFactory       ClientFactory
+}
+
+// ClientSecretMap is a structure to decode and map kubernetes secret data values to gcp client configuration.
+type ClientSecretMap struct {
+	ServiceAccount string `mapstructure:"serviceaccount.json"`
+}
+
+// serviceAccount holds the fields of the service account key used by the client besides the credentials.
+type serviceAccount struct {
+	ProjectID string `json:"project_id"`
+}
+
+// APIError represent the error returned by GCP Compute Engine API.
+type APIError struct {
+	StatusCode int
+	Reason     string
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("gcp api responded with status %d: %s: %s", e.StatusCode, e.Reason, e.Message)
+}
+
+// ComputeInstance is a Compute Engine virtual machine.
+type ComputeInstance struct {
+	Name string `json:"name"`
+	// MachineType and Zone are the URLs of the resources.
+	MachineType string `json:"machineType"`
+	Zone        string `json:"zone"`
+	Status      string `json:"status"`
+}
+
+// MachineType holds the capabilities of a Compute Engine machine type.
+type MachineType struct {
+	Name      string `json:"name"`
+	GuestCpus uint32 `json:"guestCpus"`
+	MemoryMb  uint64 `json:"memoryMb"`
+}
+
+// Disk is a persistent disk.
+type Disk struct {
+	Name   string `json:"name"`
+	SizeGb uint32 `json:"sizeGb,string"`
+	Status string `json:"status"`
+}
+
+// ForwardingRule is the frontend of a load balancer.
+type ForwardingRule struct {
+	Name      string `json:"name"`
+	IPAddress string `json:"IPAddress"`
+}
+
+// Network is a VPC network.
+type Network struct {
+	Name string `json:"name"`
+}
+
+// VMType defines the event format for the virtual machine metrics.
+type VMType struct {
+	Name  string `json:"name"`
+	Count uint32 `json:"count"`
+}
+
+// ProvisionedVolume defines the event format for the volume metrics.
+type ProvisionedVolume struct {
+	SizeGBTotal   uint32 `json:"size_gb_total"`
+	SizeGBRounded uint32 `json:"size_gb_rounded"`
+	Count         uint32 `json:"count"`
+}
+
+// Compute defines the event format for the compute metrics.
+type Compute struct {
+	VMTypes            []VMType          `json:"vm_types"`
+	ProvisionedRAMGB   float64           `json:"provisioned_ram_gb"`
+	ProvisionedVolumes ProvisionedVolume `json:"provisioned_volumes"`
+	ProvisionedCpus    uint32            `json:"provisioned_cpus"`
+}
+
+// Networking defines the event format for the network metrics.
+type Networking struct {
+	ProvisionedLoadBalancers uint32 `json:"provisioned_loadbalancers"`
+	ProvisionedVnets         uint32 `json:"provisioned_vnets"`
+	ProvisionedIps           uint32 `json:"provisioned_ips"`
+}
+
+// EventHub defines the event format for the event hub metrics, it is always empty for GCP, but required by the EDP schema.
+type EventHub struct {
+	NumberNamespaces     uint32  `json:"number_namespaces"`
+	IncomingRequestsPT1M float64 `json:"incoming_requests_pt1m"`
+	MaxIncomingBytesPT1M float64 `json:"max_incoming_bytes_pt1m"`
+	MaxOutgoingBytesPT1M float64 `json:"max_outgoing_bytes_pt1m"`
+	IncomingRequestsPT5M float64 `json:"incoming_requests_pt5m"`
+	MaxIncomingBytesPT5M float64 `json:"max_incoming_bytes_pt5m"`
+	MaxOutgoingBytesPT5M float64 `json:"max_outgoing_bytes_pt5m"`
+}
+
+// EventData defines the event information to send to EDP.
+type EventData struct {
+	// ResourceGroups holds the shoot technical id, GCP has no resource groups, all resources are labelled with it.
+	ResourceGroups []string    `json:"resource_groups"`
+	Compute        *Compute    `json:"compute"`
+	Networking     *Networking `json:"networking"`
+	EventHub       *EventHub   `json:"event_hub"`
+}
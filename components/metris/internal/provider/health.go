@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+const (
+	statusRunning = "running"
+	statusStopped = "stopped"
+)
+
+// Health tracks whether the providers of the process are running.
+type Health struct {
+	lock    sync.RWMutex
+	running map[string]bool
+}
+
+// NewHealth returns a new Health without any provider.
+func NewHealth() *Health {
+	return &Health{running: make(map[string]bool)}
+}
+
+// Run returns a function running the provider and tracking its state until it returns.
+func (h *Health) Run(name string, provider Provider) func(ctx context.Context) {
+	h.set(name, false)
+
+	return func(ctx context.Context) {
+		h.set(name, true)
+		defer h.set(name, false)
+
+		provider.Run(ctx)
+	}
+}
+
+func (h *Health) set(name string, running bool) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.running[name] = running
+
+	value := 0.0
+	if running {
+		value = 1
+	}
+
+	providerUpVec.WithLabelValues(name).Set(value)
+}
+
+// Status returns the status of each provider and whether all of them are running.
+func (h *Health) Status() (map[string]string, bool) {
+	h.lock.RLock()
+	defer h.lock.RUnlock()
+
+	var (
+		status  = make(map[string]string, len(h.running))
+		healthy = true
+	)
+
+	for name, running := range h.running {
+		if running {
+			status[name] = statusRunning
+		} else {
+			status[name] = statusStopped
+			healthy = false
+		}
+	}
+
+	return status, healthy
+}
+
+// ServeHTTP writes the status of the providers, it responds with 503 if any of them is not running.
+// The status of a single provider is returned if its name is given by the provider query parameter.
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, healthy := h.Status()
+
+	if name := r.URL.Query().Get("provider"); name != "" {
+		providerStatus, ok := status[name]
+		if !ok {
+			http.Error(w, "provider not found", http.StatusNotFound)
+			return
+		}
+
+		status = map[string]string{name: providerStatus}
+		healthy = providerStatus == statusRunning
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if !healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	_ = json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingProvider runs until its context is done.
+type blockingProvider struct{}
+
+func (p *blockingProvider) Run(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func TestHealth(t *testing.T) {
+	asserts := assert.New(t)
+
+	health := NewHealth()
+	runAz := health.Run("az", &blockingProvider{})
+	runAWS := health.Run("aws", &fakeTestProvider{})
+
+	t.Run("providers not started", func(t *testing.T) {
+		status, healthy := health.Status()
+
+		asserts.False(healthy)
+		asserts.Equal(map[string]string{"az": "stopped", "aws": "stopped"}, status)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		runAz(ctx)
+	}()
+
+	// the fake provider returns immediately
+	runAWS(ctx)
+
+	asserts.Eventually(func() bool {
+		status, _ := health.Status()
+		return status["az"] == "running"
+	}, time.Second, 10*time.Millisecond)
+
+	t.Run("healthz with a stopped provider", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		health.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		asserts.Equal(http.StatusServiceUnavailable, rr.Code)
+		asserts.JSONEq(`{"az": "running", "aws": "stopped"}`, rr.Body.String())
+	})
+
+	t.Run("healthz of a running provider", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		health.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz?provider=az", nil))
+
+		asserts.Equal(http.StatusOK, rr.Code)
+		asserts.JSONEq(`{"az": "running"}`, rr.Body.String())
+	})
+
+	t.Run("healthz of an unknown provider", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		health.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz?provider=gcp", nil))
+
+		asserts.Equal(http.StatusNotFound, rr.Code)
+	})
+
+	cancel()
+	<-done
+
+	t.Run("provider stopped", func(t *testing.T) {
+		status, _ := health.Status()
+
+		asserts.Equal("stopped", status["az"])
+	})
+}
+
+func TestConfig_ResourceName(t *testing.T) {
+	assert.Equal(t, "clusters", (&Config{}).ResourceName("clusters"))
+	assert.Equal(t, "aws_clusters", (&Config{Name: "aws"}).ResourceName("clusters"))
+}
@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"github.com/kyma-project/control-plane/components/metris/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	clusterRoutedVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "provider",
+			Name:      "clusters_routed_total",
+			Help:      "Total number of clusters routed to the provider.",
+		},
+		[]string{"provider"},
+	)
+
+	clusterUnroutedVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "provider",
+			Name:      "clusters_unrouted_total",
+			Help:      "Total number of clusters skipped because no provider is running for their provider type.",
+		},
+		[]string{"provider"},
+	)
+
+	providerUpVec = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "provider",
+			Name:      "up",
+			Help:      "Whether the provider is running (1) or not (0).",
+		},
+		[]string{"provider"},
+	)
+)
@@ -0,0 +1,298 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"go.opencensus.io/trace"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/edp"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/kyma-project/control-plane/components/metris/internal/tracing"
+)
+
+// Instance is an instance of a cluster with its client configuration.
+type Instance struct {
+	// Cluster holds the gardener cluster information.
+	Cluster *gardener.Cluster
+	// Client holds the client created by the collector for the cluster.
+	Client interface{}
+	// LastEvent store the last successful event data sent to EDP.
+	LastEvent json.RawMessage
+	// RetryAttempts store the number of retry attempts to get metrics.
+	RetryAttempts int
+}
+
+// Collector is the interface that provides the cloud specific methods to get the metrics of a cluster.
+type Collector interface {
+	// NewClient returns the client used to get the metrics of the cluster.
+	NewClient(cluster *gardener.Cluster, logger log.Logger) (interface{}, error)
+	// GetMetrics returns the event data of the cluster instance.
+	GetMetrics(ctx context.Context, logger log.Logger, instance *Instance) (interface{}, error)
+	// IsNotFound returns true if the error means that the cluster resources are not found.
+	IsNotFound(err error) bool
+	// IsThrottled returns true if the error means that the requests are throttled by the cloud provider.
+	IsThrottled(err error) bool
+}
+
+// Poller gets the metrics of all clusters returned by gardener with a collector and sends them to EDP.
+type Poller struct {
+	config          *Config
+	collector       Collector
+	instanceStorage storage.Storage
+	queue           workqueue.RateLimitingInterface
+}
+
+var _ Provider = (*Poller)(nil)
+
+// NewPoller returns a new Poller getting the metrics with the collector.
+func NewPoller(config *Config, collector Collector) *Poller {
+	// retry after baseDelay*2^<num-failures>
+	ratelimiter := workqueue.NewItemExponentialFailureRateLimiter(config.PollInterval, config.PollMaxInterval)
+
+	return &Poller{
+		config:          config,
+		collector:       collector,
+		instanceStorage: config.NewStorage("clusters", instanceCodec{}),
+		queue:           workqueue.NewNamedRateLimitingQueue(ratelimiter, config.ResourceName("clients")),
+	}
+}
+
+// Run starts metrics gathering for all clusters returned by gardener.
+func (p *Poller) Run(ctx context.Context) {
+	p.config.Logger.Info("provider started")
+
+	go p.clusterHandler(ctx)
+
+	var wg sync.WaitGroup
+
+	wg.Add(p.config.Workers)
+
+	for i := 0; i < p.config.Workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			for {
+				// lock till an item is available from the queue.
+				clusterid, quit := p.queue.Get()
+				workerlogger := p.config.Logger.With("worker", i).With("technicalid", clusterid)
+
+				if quit {
+					workerlogger.Debug("worker stopped")
+					return
+				}
+
+				obj, ok := p.instanceStorage.Get(clusterid.(string))
+				if !ok {
+					workerlogger.Warn("cluster not found in storage, must have been deleted")
+					p.queue.Done(clusterid)
+
+					continue
+				}
+
+				instance, ok := obj.(*Instance)
+				if !ok {
+					workerlogger.Error("cluster object is corrupted, removing it from storage")
+					p.instanceStorage.Delete(clusterid.(string))
+					p.queue.Done(clusterid)
+
+					continue
+				}
+
+				workerlogger = workerlogger.With("account", instance.Cluster.AccountID).With("subaccount", instance.Cluster.SubAccountID)
+				rateLimited := p.processInstance(ctx, workerlogger, instance)
+
+				p.queue.Done(clusterid)
+				if !rateLimited {
+					p.queue.Forget(clusterid)
+				}
+				if p.queue.ShuttingDown() {
+					workerlogger.Debugf("queue is shutting down, can't requeue cluster, processing cluster %s one last time", clusterid)
+				} else {
+					workerlogger.Debugf("enqueueing '%s'", clusterid)
+					p.queue.AddRateLimited(clusterid)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	p.config.Logger.Info("provider stopped")
+}
+
+// processInstance gets the metrics of the instance and sends them to EDP, it returns true if the instance must be rate limited.
+func (p *Poller) processInstance(ctx context.Context, workerlogger log.Logger, instance *Instance) bool {
+	if tracing.IsEnabled() {
+		var span *trace.Span
+
+		ctx, span = trace.StartSpan(ctx, "metris/provider/processInstance")
+		defer span.End()
+
+		workerlogger = workerlogger.With("traceID", span.SpanContext().TraceID).With("spanID", span.SpanContext().SpanID)
+	}
+
+	var (
+		rateLimited     bool
+		instanceDeleted bool
+	)
+
+	eventData, err := p.getMetrics(ctx, workerlogger, instance)
+	if err != nil {
+		eventData, rateLimited, instanceDeleted = p.processError(workerlogger, instance, err)
+	} else {
+		instance.RetryAttempts = 0
+		p.instanceStorage.Put(instance.Cluster.TechnicalID, instance)
+	}
+
+	if eventData != nil {
+		p.sendMetrics(workerlogger, instance, eventData)
+
+		if !instanceDeleted {
+			p.instanceStorage.Put(instance.Cluster.TechnicalID, instance)
+		}
+	} else {
+		workerlogger.With("error", err).Error("could not get metrics from cache, not sending to EDP")
+	}
+
+	return rateLimited
+}
+
+// getMetrics returns the event data of the instance marshaled to JSON.
+func (p *Poller) getMetrics(parentctx context.Context, workerlogger log.Logger, instance *Instance) (json.RawMessage, error) {
+	// Using a timeout context to prevent the provider api to hang for too long.
+	// If it reach the time limit, last successful event data will be returned.
+	ctx, cancel := context.WithTimeout(parentctx, p.config.PollingDuration)
+	defer cancel()
+
+	eventData, err := p.collector.GetMetrics(ctx, workerlogger, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(eventData)
+}
+
+// processError returns the last event data of the instance and tells if the instance must be rate limited or was removed from the storage.
+func (p *Poller) processError(workerlogger log.Logger, instance *Instance, err error) (json.RawMessage, bool, bool) {
+	eventData := instance.LastEvent
+	workerlogger.With("error", err).Error("could not get metrics, using information from cache")
+
+	var (
+		rateLimited bool
+		isDeleted   bool
+		maxRetries  = p.config.MaxRetries
+	)
+
+	switch {
+	// Check if the cluster resources are not found, then it would mean that the cluster may have been deleted,
+	// and gardener did not trigger the delete event or metris did not yet remove it from its cache.
+	// Start retry attempt, then remove from storage if it reach max attempt.
+	case p.collector.IsNotFound(err):
+		rateLimited = true
+		instance.RetryAttempts++
+
+		if instance.RetryAttempts < maxRetries {
+			p.instanceStorage.Put(instance.Cluster.TechnicalID, instance)
+			workerlogger.Warnf("can't find cluster resources, attempts: %d/%d", instance.RetryAttempts, maxRetries)
+		} else {
+			p.instanceStorage.Delete(instance.Cluster.TechnicalID)
+			workerlogger.Warnf("removing cluster after %d attempts", maxRetries)
+
+			isDeleted = true
+		}
+
+	case p.collector.IsThrottled(err):
+		workerlogger.With("error", err).Warn("received throttling error, throttling")
+
+		rateLimited = true
+
+	default:
+		workerlogger.With("error", err).Warn("check error")
+	}
+
+	return eventData, rateLimited, isDeleted
+}
+
+// clusterHandler listen on the cluster channel then update the storage and the queue.
+func (p *Poller) clusterHandler(parentctx context.Context) {
+	p.config.Logger.Debug("starting cluster handler")
+
+	clusterSync := NewClusterSync()
+
+	for {
+		select {
+		case cluster := <-p.config.ClusterChannel:
+			// clusters restored from storage which were deleted while metris was not running are removed after the initial sync.
+			if cluster.Synced {
+				clusterSync.Prune(p.instanceStorage, p.config.Logger)
+
+				continue
+			}
+
+			logger := p.config.Logger.
+				With("technicalid", cluster.TechnicalID).
+				With("accountid", cluster.AccountID).
+				With("subaccountid", cluster.SubAccountID)
+
+			logger.Debug("received cluster from gardener controller")
+
+			// if cluster was flag as deleted, remove it from storage and exit.
+			if cluster.Deleted {
+				logger.Info("removing cluster from storage")
+
+				p.instanceStorage.Delete(cluster.TechnicalID)
+
+				continue
+			}
+
+			clusterSync.Confirm(cluster.TechnicalID)
+
+			instance := &Instance{Cluster: cluster}
+
+			// recover instance from storage.
+			if obj, exists := p.instanceStorage.Get(cluster.TechnicalID); exists {
+				if i, ok := obj.(*Instance); ok {
+					instance.LastEvent = i.LastEvent
+					instance.RetryAttempts = i.RetryAttempts
+				}
+			}
+
+			client, err := p.collector.NewClient(cluster, logger)
+			if err != nil {
+				logger.With("error", err).Error("error while creating client configuration, cluster will be ignored")
+				p.instanceStorage.Delete(cluster.TechnicalID)
+
+				continue
+			}
+
+			instance.Client = client
+
+			p.instanceStorage.Put(cluster.TechnicalID, instance)
+			p.queue.Add(cluster.TechnicalID)
+		case <-parentctx.Done():
+			p.config.Logger.Debug("stopping cluster handler")
+			p.queue.ShutDown()
+
+			return
+		}
+	}
+}
+
+// sendMetrics - send events to EDP.
+func (p *Poller) sendMetrics(workerlogger log.Logger, instance *Instance, eventData json.RawMessage) {
+	// save a copy of the event data in case of error next time
+	instance.LastEvent = eventData
+
+	eventBuffer := edp.Event{
+		Datatenant: instance.Cluster.SubAccountID,
+		Data:       &eventData,
+	}
+
+	workerlogger.Debug("sending event to EDP")
+
+	p.config.EventsChannel <- &eventBuffer
+}
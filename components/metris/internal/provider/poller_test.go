@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/edp"
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errTestNotFound  = errors.New("not found")
+	errTestThrottled = errors.New("throttled")
+
+	testCluster = &gardener.Cluster{
+		TechnicalID:  "shoot--kyma--c-1234567",
+		ProviderType: "test",
+		AccountID:    "test-accountid",
+		SubAccountID: "test-subaccountid",
+	}
+)
+
+type fakeEventData struct {
+	ResourceGroups []string `json:"resource_groups"`
+}
+
+// fakeCollector returns the event data of the instance, or the error if it is set.
+type fakeCollector struct {
+	err error
+}
+
+func (c *fakeCollector) NewClient(cluster *gardener.Cluster, logger log.Logger) (interface{}, error) {
+	return "test-client", nil
+}
+
+func (c *fakeCollector) GetMetrics(ctx context.Context, logger log.Logger, instance *Instance) (interface{}, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &fakeEventData{ResourceGroups: []string{instance.Cluster.TechnicalID}}, nil
+}
+
+func (c *fakeCollector) IsNotFound(err error) bool {
+	return errors.Is(err, errTestNotFound)
+}
+
+func (c *fakeCollector) IsThrottled(err error) bool {
+	return errors.Is(err, errTestThrottled)
+}
+
+func newTestPollerConfig() *Config {
+	return &Config{
+		PollInterval:    time.Minute,
+		PollingDuration: time.Minute,
+		Workers:         1,
+		Buffer:          1,
+		MaxRetries:      2,
+		ClusterChannel:  make(chan *gardener.Cluster, 1),
+		EventsChannel:   make(chan *edp.Event, 1),
+		Logger:          log.NewNoopLogger(),
+	}
+}
+
+func TestPoller_Run(t *testing.T) {
+	// given
+	config := newTestPollerConfig()
+	events := make(chan *edp.Event, 1)
+	config.EventsChannel = events
+
+	p := NewPoller(config, &fakeCollector{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go p.Run(ctx)
+
+	// when
+	config.ClusterChannel <- testCluster
+
+	// then
+	select {
+	case event := <-events:
+		assert.Equal(t, testCluster.SubAccountID, event.Datatenant)
+
+		eventData := &fakeEventData{}
+		require.NoError(t, json.Unmarshal(*event.Data, eventData))
+		assert.Equal(t, []string{testCluster.TechnicalID}, eventData.ResourceGroups)
+	case <-time.After(5 * time.Second):
+		t.Fatal("event not sent")
+	}
+}
+
+func TestPoller_clusterHandler(t *testing.T) {
+	// given
+	config := newTestPollerConfig()
+	p := NewPoller(config, &fakeCollector{})
+	lastEvent := json.RawMessage(`{}`)
+	p.instanceStorage.Put(testCluster.TechnicalID, &Instance{LastEvent: lastEvent, RetryAttempts: 1})
+	p.instanceStorage.Put("deleted-technicalid", &Instance{LastEvent: lastEvent})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go p.clusterHandler(ctx)
+
+	// when
+	config.ClusterChannel <- testCluster
+	config.ClusterChannel <- &gardener.Cluster{Synced: true}
+
+	// then
+	assert.Eventually(t, func() bool {
+		_, exists := p.instanceStorage.Get("deleted-technicalid")
+		return !exists
+	}, 5*time.Second, 10*time.Millisecond, "cluster not confirmed by the initial sync should be removed")
+
+	obj, exists := p.instanceStorage.Get(testCluster.TechnicalID)
+	require.True(t, exists)
+	assert.Equal(t, testCluster, obj.(*Instance).Cluster)
+	assert.Equal(t, "test-client", obj.(*Instance).Client)
+	assert.Equal(t, lastEvent, obj.(*Instance).LastEvent)
+	assert.Equal(t, 1, obj.(*Instance).RetryAttempts)
+}
+
+func TestPoller_processInstance(t *testing.T) {
+	t.Run("should send metrics and store the last event", func(t *testing.T) {
+		// given
+		config := newTestPollerConfig()
+		p := NewPoller(config, &fakeCollector{})
+		instance := &Instance{Cluster: testCluster, RetryAttempts: 1}
+
+		// when
+		rateLimited := p.processInstance(context.Background(), config.Logger, instance)
+
+		// then
+		assert.False(t, rateLimited)
+		assert.Equal(t, 0, instance.RetryAttempts)
+		assert.NotNil(t, instance.LastEvent)
+		assert.Len(t, config.EventsChannel, 1)
+
+		obj, exists := p.instanceStorage.Get(testCluster.TechnicalID)
+		assert.True(t, exists)
+		assert.Equal(t, instance, obj)
+	})
+
+	t.Run("should send the last event when metrics can't be fetched", func(t *testing.T) {
+		// given
+		config := newTestPollerConfig()
+		p := NewPoller(config, &fakeCollector{err: errTestThrottled})
+		lastEvent := json.RawMessage(`{"resource_groups":["shoot--kyma--c-1234567"]}`)
+		instance := &Instance{Cluster: testCluster, LastEvent: lastEvent}
+
+		// when
+		rateLimited := p.processInstance(context.Background(), config.Logger, instance)
+
+		// then
+		assert.True(t, rateLimited)
+		assert.Equal(t, lastEvent, instance.LastEvent)
+		assert.Len(t, config.EventsChannel, 1)
+	})
+}
+
+func TestPoller_processError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		retryAttempts   int
+		wantRateLimited bool
+		wantDeleted     bool
+		wantAttempts    int
+	}{
+		{
+			name:            "throttled",
+			err:             fmt.Errorf("wrapped: %w", errTestThrottled),
+			wantRateLimited: true,
+		},
+		{
+			name:            "not found",
+			err:             errTestNotFound,
+			wantRateLimited: true,
+			wantAttempts:    1,
+		},
+		{
+			name:            "not found after max retries",
+			err:             errTestNotFound,
+			retryAttempts:   1,
+			wantRateLimited: true,
+			wantDeleted:     true,
+			wantAttempts:    2,
+		},
+		{
+			name: "other error",
+			err:  errors.New("other"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			config := newTestPollerConfig()
+			p := NewPoller(config, &fakeCollector{})
+			lastEvent := json.RawMessage(`{}`)
+			instance := &Instance{Cluster: testCluster, LastEvent: lastEvent, RetryAttempts: tt.retryAttempts}
+			p.instanceStorage.Put(testCluster.TechnicalID, instance)
+
+			// when
+			eventData, rateLimited, deleted := p.processError(config.Logger, instance, tt.err)
+
+			// then
+			assert.Equal(t, lastEvent, eventData)
+			assert.Equal(t, tt.wantRateLimited, rateLimited)
+			assert.Equal(t, tt.wantDeleted, deleted)
+			assert.Equal(t, tt.wantAttempts, instance.RetryAttempts)
+
+			_, exists := p.instanceStorage.Get(testCluster.TechnicalID)
+			assert.Equal(t, !tt.wantDeleted, exists)
+		})
+	}
+}
+
+func TestInstanceCodec(t *testing.T) {
+	// given
+	instance := &Instance{Cluster: testCluster, Client: "test-client", LastEvent: json.RawMessage(`{"resource_groups":[]}`), RetryAttempts: 3}
+
+	// when
+	data, err := instanceCodec{}.Encode(instance)
+	require.NoError(t, err)
+
+	obj, err := instanceCodec{}.Decode(data)
+	require.NoError(t, err)
+
+	// then
+	assert.Equal(t, &Instance{LastEvent: instance.LastEvent, RetryAttempts: 3}, obj)
+
+	_, err = instanceCodec{}.Encode("not an instance")
+	assert.Error(t, err)
+}
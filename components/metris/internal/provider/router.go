@@ -0,0 +1,93 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+)
+
+// Router routes the clusters received from the Gardener controller to the providers by their provider type.
+type Router struct {
+	clusterChannel <-chan *gardener.Cluster
+	routesLock     sync.RWMutex
+	routes         map[string]chan<- *gardener.Cluster
+	logger         log.Logger
+}
+
+// NewRouter returns a new Router reading clusters from the cluster channel.
+func NewRouter(clusterChannel <-chan *gardener.Cluster, logger log.Logger) *Router {
+	return &Router{
+		clusterChannel: clusterChannel,
+		routes:         make(map[string]chan<- *gardener.Cluster),
+		logger:         logger,
+	}
+}
+
+// AddRoute sends the clusters of the provider type to the channel.
+func (r *Router) AddRoute(providerType string, clusterChannel chan<- *gardener.Cluster) {
+	r.routesLock.Lock()
+	defer r.routesLock.Unlock()
+
+	r.routes[providerType] = clusterChannel
+}
+
+// Run routes the clusters until the context is done.
+func (r *Router) Run(ctx context.Context) {
+	r.logger.Debug("router started")
+
+	for {
+		select {
+		case cluster := <-r.clusterChannel:
+			r.route(ctx, cluster)
+		case <-ctx.Done():
+			r.logger.Debug("router stopped")
+
+			return
+		}
+	}
+}
+
+func (r *Router) route(ctx context.Context, cluster *gardener.Cluster) {
+	if cluster.Synced {
+		r.broadcast(ctx, cluster)
+
+		return
+	}
+
+	r.routesLock.RLock()
+	clusterChannel, ok := r.routes[cluster.ProviderType]
+	r.routesLock.RUnlock()
+
+	if !ok {
+		r.logger.
+			With("technicalid", cluster.TechnicalID).
+			With("providertype", cluster.ProviderType).
+			Warn("no provider found for the cluster, skipping")
+
+		clusterUnroutedVec.WithLabelValues(cluster.ProviderType).Inc()
+
+		return
+	}
+
+	select {
+	case clusterChannel <- cluster:
+		clusterRoutedVec.WithLabelValues(cluster.ProviderType).Inc()
+	case <-ctx.Done():
+	}
+}
+
+// broadcast sends the cluster to all providers.
+func (r *Router) broadcast(ctx context.Context, cluster *gardener.Cluster) {
+	r.routesLock.RLock()
+	defer r.routesLock.RUnlock()
+
+	for _, clusterChannel := range r.routes {
+		select {
+		case clusterChannel <- cluster:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	asserts := assert.New(t)
+
+	clusterChannel := make(chan *gardener.Cluster, 1)
+	azChannel := make(chan *gardener.Cluster, 1)
+	awsChannel := make(chan *gardener.Cluster, 1)
+
+	router := NewRouter(clusterChannel, log.NewNoopLogger())
+	router.AddRoute("az", azChannel)
+	router.AddRoute("aws", awsChannel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go router.Run(ctx)
+
+	t.Run("route cluster to its provider", func(t *testing.T) {
+		clusterChannel <- &gardener.Cluster{TechnicalID: "aws-cluster", ProviderType: "aws"}
+
+		select {
+		case cluster := <-awsChannel:
+			asserts.Equal("aws-cluster", cluster.TechnicalID)
+		case <-azChannel:
+			asserts.Fail("cluster routed to the wrong provider")
+		case <-time.After(500 * time.Millisecond):
+			asserts.Fail("timed out, cluster not routed")
+		}
+	})
+
+	t.Run("skip cluster without provider", func(t *testing.T) {
+		clusterChannel <- &gardener.Cluster{TechnicalID: "gcp-cluster", ProviderType: "gcp"}
+		clusterChannel <- &gardener.Cluster{TechnicalID: "az-cluster", ProviderType: "az"}
+
+		select {
+		case cluster := <-azChannel:
+			asserts.Equal("az-cluster", cluster.TechnicalID)
+		case <-awsChannel:
+			asserts.Fail("cluster routed to the wrong provider")
+		case <-time.After(500 * time.Millisecond):
+			asserts.Fail("timed out, cluster not routed")
+		}
+	})
+
+	t.Run("send initial sync to all providers", func(t *testing.T) {
+		clusterChannel <- &gardener.Cluster{Synced: true}
+
+		for name, providerChannel := range map[string]chan *gardener.Cluster{"az": azChannel, "aws": awsChannel} {
+			select {
+			case cluster := <-providerChannel:
+				asserts.True(cluster.Synced, name)
+			case <-time.After(500 * time.Millisecond):
+				asserts.Fail("timed out, initial sync not sent", name)
+			}
+		}
+	})
+}
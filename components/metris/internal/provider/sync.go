@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+)
+
+// ClusterSync tracks the clusters confirmed by the initial sync of the Gardener controller. The clusters restored
+// from a persistent storage which are not confirmed were deleted while metris was not running.
+type ClusterSync struct {
+	synced    bool
+	confirmed map[string]struct{}
+}
+
+// NewClusterSync returns a new ClusterSync, it must be used by a single goroutine.
+func NewClusterSync() *ClusterSync {
+	return &ClusterSync{confirmed: make(map[string]struct{})}
+}
+
+// Confirm marks the cluster as existing in Gardener.
+func (s *ClusterSync) Confirm(technicalID string) {
+	if !s.synced {
+		s.confirmed[technicalID] = struct{}{}
+	}
+}
+
+// Prune removes the clusters which were not confirmed from the storage, it only has an effect on the first call.
+func (s *ClusterSync) Prune(instances storage.Storage, logger log.Logger) {
+	if s.synced {
+		return
+	}
+
+	s.synced = true
+
+	for _, technicalID := range instances.ListKeys() {
+		if _, ok := s.confirmed[technicalID]; !ok {
+			logger.With("technicalid", technicalID).Info("cluster not found by the initial sync, removing it from storage")
+			instances.Delete(technicalID)
+		}
+	}
+
+	s.confirmed = nil
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterSync(t *testing.T) {
+	asserts := assert.New(t)
+
+	// given
+	instances := storage.NewMemoryStorage("test-clusters")
+	instances.Put("restored-confirmed", struct{}{})
+	instances.Put("restored-deleted", struct{}{})
+
+	clusterSync := NewClusterSync()
+	clusterSync.Confirm("restored-confirmed")
+	clusterSync.Confirm("new")
+	instances.Put("new", struct{}{})
+
+	// when
+	clusterSync.Prune(instances, log.NewNoopLogger())
+
+	// then
+	asserts.ElementsMatch([]string{"restored-confirmed", "new"}, instances.ListKeys())
+
+	// clusters added after the initial sync are not pruned
+	instances.Put("added-after-sync", struct{}{})
+	clusterSync.Prune(instances, log.NewNoopLogger())
+
+	asserts.ElementsMatch([]string{"restored-confirmed", "new", "added-after-sync"}, instances.ListKeys())
+}
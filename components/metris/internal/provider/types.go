@@ -7,6 +7,7 @@ import (
 	"github.com/kyma-project/control-plane/components/metris/internal/edp"
 	"github.com/kyma-project/control-plane/components/metris/internal/gardener"
 	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/kyma-project/control-plane/components/metris/internal/storage"
 )
 
 // Factory generates a Provider.
@@ -21,12 +22,44 @@ type Config struct {
 	Buffer          int           `kong:"help='Number of cluster that the buffer can have.',env='PROVIDER_BUFFER',required=true,default=100"`
 	MaxRetries      int           `kong:"help='Maximum number of retries before a cluster is removed from the cache if it is not found on the provider. NOTE: This will stop sending events for the removed cluster',env='PROVIDER_MAXRETRIES',required=true,default=20"`
 
+	// Name is the provider type, it prefixes the names of the provider storages and queues to report their metrics per provider.
+	Name string `kong:"-"`
 	// ClusterChannel define the channel to exchange clusters information with Gardener controller.
 	ClusterChannel chan *gardener.Cluster `kong:"-"`
 	// EventsChannel define the channel to exchange events with EDP.
 	EventsChannel chan<- *edp.Event `kong:"-"`
 	// logger is the standard logger for the provider.
 	Logger log.Logger `kong:"-"`
+	// Storage is the backend creating the provider storages, memory storages are used if nil.
+	Storage *storage.Backend `kong:"-"`
+}
+
+// ResourceName returns the name of a provider storage or queue.
+func (c *Config) ResourceName(name string) string {
+	if c.Name == "" {
+		return name
+	}
+
+	return c.Name + "_" + name
+}
+
+// NewStorage returns a provider storage from the storage backend, the objects are persisted with the codec if the
+// backend is persistent. It falls back to a memory storage if the storage could not be restored.
+func (c *Config) NewStorage(name string, codec storage.Codec) storage.Storage {
+	name = c.ResourceName(name)
+
+	if c.Storage == nil {
+		return storage.NewMemoryStorage(name)
+	}
+
+	s, err := c.Storage.NewStorage(name, codec)
+	if err != nil {
+		c.Logger.With("storage", name).With("error", err).Error("could not restore storage, starting with an empty one")
+
+		return storage.NewMemoryStorage(name)
+	}
+
+	return s
 }
 
 // Provider interface contains all behaviors for a provider.
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendFile   = "file"
+)
+
+// Config holds the storage backend configuration.
+type Config struct {
+	Backend          string        `kong:"help='Storage backend of the provider caches. (memory,file)',enum='memory,file',env='STORAGE_BACKEND',required=false,default='memory'"`
+	Dir              string        `kong:"help='Directory of the storage snapshots, used by the file backend.',env='STORAGE_DIR',required=false,default='/var/lib/metris'"`
+	SnapshotInterval time.Duration `kong:"help='Interval at which the storages are snapshotted, used by the file backend.',env='STORAGE_SNAPSHOTINTERVAL',required=false,default='1m'"`
+}
+
+// Backend creates the storages of the providers and snapshots the persistent ones.
+type Backend struct {
+	config *Config
+	logger log.Logger
+
+	storagesLock sync.Mutex
+	storages     []*fileStorage
+}
+
+// NewBackend returns a new storage backend, the snapshot directory is created for the file backend.
+func NewBackend(config *Config, logger log.Logger) (*Backend, error) {
+	switch config.Backend {
+	case BackendMemory:
+	case BackendFile:
+		if config.SnapshotInterval <= 0 {
+			return nil, fmt.Errorf("storage snapshot interval must be positive")
+		}
+
+		if err := os.MkdirAll(config.Dir, 0700); err != nil {
+			return nil, fmt.Errorf("could not create storage directory: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown storage backend %s", config.Backend)
+	}
+
+	return &Backend{config: config, logger: logger}, nil
+}
+
+// NewStorage returns a storage restored from its last snapshot when the backend is persistent.
+// A memory storage is returned for the memory backend or if the codec is nil.
+func (b *Backend) NewStorage(name string, codec Codec) (Storage, error) {
+	if b.config.Backend != BackendFile || codec == nil {
+		return NewMemoryStorage(name), nil
+	}
+
+	s, err := newFileStorage(name, b.config.Dir, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	b.storagesLock.Lock()
+	b.storages = append(b.storages, s)
+	b.storagesLock.Unlock()
+
+	b.logger.With("storage", name).With("items", len(s.ListKeys())).Debug("storage restored")
+
+	return s, nil
+}
+
+// Snapshot writes the persistent storages to their files.
+func (b *Backend) Snapshot() {
+	b.storagesLock.Lock()
+	storages := make([]*fileStorage, len(b.storages))
+	copy(storages, b.storages)
+	b.storagesLock.Unlock()
+
+	for _, s := range storages {
+		start := time.Now()
+
+		if err := s.Snapshot(); err != nil {
+			b.logger.With("storage", s.name).With("error", err).Error("could not snapshot storage")
+			storageSnapshotErrorsMetricVec.WithLabelValues(s.name).Inc()
+
+			continue
+		}
+
+		storageSnapshotDurationMetricVec.WithLabelValues(s.name).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Run snapshots the persistent storages periodically until the context is done, a last snapshot is taken before returning.
+func (b *Backend) Run(ctx context.Context) {
+	if b.config.Backend != BackendFile {
+		<-ctx.Done()
+		return
+	}
+
+	b.logger.Debug("storage snapshotting started")
+
+	ticker := time.NewTicker(b.config.SnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			b.Snapshot()
+		case <-ctx.Done():
+			b.Snapshot()
+			b.logger.Debug("storage snapshotting stopped")
+
+			return
+		}
+	}
+}
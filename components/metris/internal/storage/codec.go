@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Codec converts the objects of a storage to and from their persisted form.
+type Codec interface {
+	// Encode returns the persisted form of the object.
+	Encode(obj interface{}) (json.RawMessage, error)
+
+	// Decode returns the object from its persisted form.
+	Decode(data json.RawMessage) (interface{}, error)
+}
+
+type jsonCodec struct {
+	typ reflect.Type
+	ptr bool
+}
+
+// NewJSONCodec returns a codec marshaling the objects to JSON, the objects are decoded with the type of the prototype,
+// as a pointer if the prototype is a pointer.
+func NewJSONCodec(prototype interface{}) Codec {
+	typ := reflect.TypeOf(prototype)
+
+	codec := &jsonCodec{typ: typ}
+	if typ.Kind() == reflect.Ptr {
+		codec.typ = typ.Elem()
+		codec.ptr = true
+	}
+
+	return codec
+}
+
+// Encode returns the object marshaled to JSON.
+func (c *jsonCodec) Encode(obj interface{}) (json.RawMessage, error) {
+	return json.Marshal(obj)
+}
+
+// Decode returns the object unmarshaled from JSON.
+func (c *jsonCodec) Decode(data json.RawMessage) (interface{}, error) {
+	obj := reflect.New(c.typ)
+
+	if err := json.Unmarshal(data, obj.Interface()); err != nil {
+		return nil, err
+	}
+
+	if c.ptr {
+		return obj.Interface(), nil
+	}
+
+	return obj.Elem().Interface(), nil
+}
@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	snapshotExt = ".json"
+)
+
+// fileStorage is an in-memory storage which is snapshotted to a file and restored from it when created.
+// The objects are encoded when they are put so a snapshot never reads an object while its owner modifies it.
+type fileStorage struct {
+	*memoryStorage
+	path  string
+	codec Codec
+
+	encodedLock sync.Mutex
+	encoded     map[string]json.RawMessage
+}
+
+var _ Storage = &fileStorage{}
+
+// newFileStorage returns an in-memory storage restored from the snapshot of the storage in the directory, if any.
+// The objects are persisted with the codec when the storage is snapshotted.
+func newFileStorage(name, dir string, codec Codec) (*fileStorage, error) {
+	s := &fileStorage{
+		memoryStorage: &memoryStorage{
+			name:  name,
+			items: make(map[string]interface{}),
+		},
+		path:    filepath.Join(dir, name+snapshotExt),
+		codec:   codec,
+		encoded: make(map[string]json.RawMessage),
+	}
+
+	if err := s.restore(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// restore loads the objects of the snapshot file, a missing file is an empty storage.
+func (s *fileStorage) restore() error {
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return fmt.Errorf("could not read storage snapshot %s: %w", s.path, err)
+	}
+
+	items := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return fmt.Errorf("could not unmarshal storage snapshot %s: %w", s.path, err)
+	}
+
+	for key, item := range items {
+		obj, err := s.codec.Decode(item)
+		if err != nil {
+			return fmt.Errorf("could not decode item %s of storage snapshot %s: %w", key, s.path, err)
+		}
+
+		s.memoryStorage.Put(key, obj)
+		s.encoded[key] = item
+	}
+
+	return nil
+}
+
+// Put sets the value for a key, the object is not persisted if it can't be encoded.
+func (s *fileStorage) Put(key string, obj interface{}) {
+	item, err := s.codec.Encode(obj)
+
+	s.encodedLock.Lock()
+	if err != nil {
+		delete(s.encoded, key)
+	} else {
+		s.encoded[key] = item
+	}
+	s.encodedLock.Unlock()
+
+	s.memoryStorage.Put(key, obj)
+}
+
+// Update simply calls `Put`.
+func (s *fileStorage) Update(key string, obj interface{}) {
+	s.Put(key, obj)
+}
+
+// Delete deletes the value for a key.
+func (s *fileStorage) Delete(key string) {
+	s.encodedLock.Lock()
+	delete(s.encoded, key)
+	s.encodedLock.Unlock()
+
+	s.memoryStorage.Delete(key)
+}
+
+// Snapshot writes the objects of the storage to its file, the file is replaced atomically.
+func (s *fileStorage) Snapshot() error {
+	s.encodedLock.Lock()
+	data, err := json.Marshal(s.encoded)
+	s.encodedLock.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("could not marshal storage %s: %w", s.name, err)
+	}
+
+	tmpfile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("could not create storage snapshot: %w", err)
+	}
+
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.Write(data); err != nil {
+		tmpfile.Close()
+		return fmt.Errorf("could not write storage snapshot: %w", err)
+	}
+
+	if err := tmpfile.Sync(); err != nil {
+		tmpfile.Close()
+		return fmt.Errorf("could not sync storage snapshot: %w", err)
+	}
+
+	if err := tmpfile.Close(); err != nil {
+		return fmt.Errorf("could not close storage snapshot: %w", err)
+	}
+
+	if err := os.Rename(tmpfile.Name(), s.path); err != nil {
+		return fmt.Errorf("could not replace storage snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/metris/internal/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileStorage(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	s, err := newFileStorage("test", dir, NewJSONCodec(&FakeObj{}))
+	require.NoError(t, err)
+	asserts.Empty(s.ListKeys(), "should start empty without snapshot")
+
+	s.Put("item1", &FakeObj{Name: "item1"})
+	s.Put("item2", &FakeObj{Name: "item2"})
+	s.Update("item3", &FakeObj{Name: "item3"})
+	s.Delete("item2")
+
+	// when
+	require.NoError(t, s.Snapshot())
+	restored, err := newFileStorage("test", dir, NewJSONCodec(&FakeObj{}))
+	require.NoError(t, err)
+
+	// then
+	asserts.ElementsMatch([]string{"item1", "item3"}, restored.ListKeys())
+
+	obj, ok := restored.Get("item1")
+	asserts.True(ok)
+	asserts.Equal(&FakeObj{Name: "item1"}, obj)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+	asserts.Equal([]string{filepath.Join(dir, "test.json")}, files, "should not leave temporary files")
+}
+
+func Test_fileStorageCorrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "test.json"), []byte(`{"item1": {"Name"`), 0600))
+
+	// when
+	_, err = newFileStorage("test", dir, NewJSONCodec(&FakeObj{}))
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestNewJSONCodec(t *testing.T) {
+	asserts := assert.New(t)
+
+	t.Run("decode pointer", func(t *testing.T) {
+		obj, err := NewJSONCodec(&FakeObj{}).Decode([]byte(`{"Name": "item"}`))
+		require.NoError(t, err)
+		asserts.Equal(&FakeObj{Name: "item"}, obj)
+	})
+
+	t.Run("decode value", func(t *testing.T) {
+		obj, err := NewJSONCodec(FakeObj{}).Decode([]byte(`{"Name": "item"}`))
+		require.NoError(t, err)
+		asserts.Equal(FakeObj{Name: "item"}, obj)
+	})
+}
+
+func TestBackend(t *testing.T) {
+	asserts := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	t.Run("memory backend", func(t *testing.T) {
+		backend, err := NewBackend(&Config{Backend: BackendMemory}, log.NewNoopLogger())
+		require.NoError(t, err)
+
+		s, err := backend.NewStorage("test", NewJSONCodec(&FakeObj{}))
+		require.NoError(t, err)
+		asserts.IsType(&memoryStorage{}, s)
+	})
+
+	t.Run("file backend", func(t *testing.T) {
+		config := &Config{Backend: BackendFile, Dir: filepath.Join(dir, "snapshots"), SnapshotInterval: time.Hour}
+
+		backend, err := NewBackend(config, log.NewNoopLogger())
+		require.NoError(t, err)
+
+		s, err := backend.NewStorage("test", NewJSONCodec(&FakeObj{}))
+		require.NoError(t, err)
+
+		memory, err := backend.NewStorage("nocodec", nil)
+		require.NoError(t, err)
+		asserts.IsType(&memoryStorage{}, memory, "should not persist storage without codec")
+
+		s.Put("item", &FakeObj{Name: "item"})
+
+		// when the backend is stopped
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		backend.Run(ctx)
+
+		// then a last snapshot is taken
+		restored, err := backend.NewStorage("test", NewJSONCodec(&FakeObj{}))
+		require.NoError(t, err)
+
+		obj, ok := restored.Get("item")
+		asserts.True(ok)
+		asserts.Equal(&FakeObj{Name: "item"}, obj)
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		_, err := NewBackend(&Config{Backend: "bolt"}, log.NewNoopLogger())
+		asserts.Error(err)
+	})
+}
@@ -16,4 +16,24 @@ var (
 		},
 		[]string{"name"},
 	)
+
+	storageSnapshotDurationMetricVec = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "storage",
+			Name:      "snapshot_duration_seconds",
+			Help:      "Duration of the storage snapshots.",
+		},
+		[]string{"name"},
+	)
+
+	storageSnapshotErrorsMetricVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metrics.Namespace,
+			Subsystem: "storage",
+			Name:      "snapshot_errors_total",
+			Help:      "Total number of storage snapshots which failed.",
+		},
+		[]string{"name"},
+	)
 )
@@ -147,6 +147,24 @@ func (r *Resolver) RuntimeStatus(ctx context.Context, runtimeID string) (*gqlsch
 	return status, nil
 }
 
+func (r *Resolver) RuntimeKubeconfig(ctx context.Context, runtimeID string) (*string, error) {
+	log.Infof("Requested to get kubeconfig for Runtime %s.", runtimeID)
+
+	_, err := r.getAndValidateTenant(ctx, runtimeID)
+	if err != nil {
+		log.Errorf("Failed to get kubeconfig for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	kubeconfig, err := r.provisioning.RuntimeKubeconfig(runtimeID)
+	if err != nil {
+		log.Errorf("Failed to get kubeconfig for Runtime %s: %s", runtimeID, err)
+		return nil, err
+	}
+
+	return kubeconfig, nil
+}
+
 func (r *Resolver) RuntimeOperationStatus(ctx context.Context, operationID string) (*gqlschema.OperationStatus, error) {
 	log.Infof("Requested to get Runtime operation status for Operation %s.", operationID)
 
@@ -560,6 +560,66 @@ func TestResolver_RuntimeStatus(t *testing.T) {
 	})
 }
 
+func TestResolver_RuntimeKubeconfig(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
+	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
+
+	t.Run("Should return kubeconfig", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator)
+
+		kubeconfig := "kubeconfig"
+
+		provisioningService.On("RuntimeKubeconfig", runtimeID).Return(&kubeconfig, nil)
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		runtimeKubeconfig, err := provisioner.RuntimeKubeconfig(ctx, runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, &kubeconfig, runtimeKubeconfig)
+	})
+
+	t.Run("Should return error when getting kubeconfig fails", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator)
+
+		provisioningService.On("RuntimeKubeconfig", runtimeID).Return(nil, apperrors.Internal("Runtime kubeconfig fails"))
+		validator.On("ValidateTenant", runtimeID, tenant).Return(nil)
+
+		//when
+		kubeconfig, err := provisioner.RuntimeKubeconfig(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeInternal)
+		require.Nil(t, kubeconfig)
+	})
+
+	t.Run("Should return error when tenant header does not match tenant provided during provisioning", func(t *testing.T) {
+		//given
+		provisioningService := &mocks.Service{}
+		validator := &validatorMocks.Validator{}
+		provisioner := api.NewResolver(provisioningService, validator)
+
+		validator.On("ValidateTenant", runtimeID, tenant).Return(apperrors.BadRequest("Bad error"))
+
+		//when
+		kubeconfig, err := provisioner.RuntimeKubeconfig(ctx, runtimeID)
+
+		//then
+		require.Error(t, err)
+		util.CheckErrorType(t, err, apperrors.CodeBadRequest)
+		require.Nil(t, kubeconfig)
+		provisioningService.AssertNotCalled(t, "RuntimeKubeconfig", runtimeID)
+	})
+}
+
 func TestResolver_RuntimeOperationStatus(t *testing.T) {
 	ctx := context.WithValue(context.Background(), middlewares.Tenant, tenant)
 	runtimeID := "1100bb59-9c40-4ebb-b846-7477c4dc5bbd"
@@ -135,6 +135,31 @@ func (_m *Service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStat
 	return r0, r1
 }
 
+// RuntimeKubeconfig provides a mock function with given fields: id
+func (_m *Service) RuntimeKubeconfig(id string) (*string, apperrors.AppError) {
+	ret := _m.Called(id)
+
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(string) *string); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	var r1 apperrors.AppError
+	if rf, ok := ret.Get(1).(func(string) apperrors.AppError); ok {
+		r1 = rf(id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(apperrors.AppError)
+		}
+	}
+
+	return r0, r1
+}
+
 // RuntimeOperationStatus provides a mock function with given fields: id
 func (_m *Service) RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError) {
 	ret := _m.Called(id)
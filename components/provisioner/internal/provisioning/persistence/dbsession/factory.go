@@ -19,6 +19,7 @@ type Factory interface {
 //go:generate mockery -name=ReadSession
 type ReadSession interface {
 	GetCluster(runtimeID string) (model.Cluster, dberrors.Error)
+	GetKubeconfig(runtimeID string) (*string, dberrors.Error)
 	GetOperation(operationID string) (model.Operation, dberrors.Error)
 	GetLastOperation(runtimeID string) (model.Operation, dberrors.Error)
 	GetGardenerClusterByName(name string) (model.Cluster, dberrors.Error)
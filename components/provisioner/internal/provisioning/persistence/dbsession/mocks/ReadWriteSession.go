@@ -95,6 +95,31 @@ func (_m *ReadWriteSession) GetGardenerClusterByName(name string) (model.Cluster
 	return r0, r1
 }
 
+// GetKubeconfig provides a mock function with given fields: runtimeID
+func (_m *ReadWriteSession) GetKubeconfig(runtimeID string) (*string, dberrors.Error) {
+	ret := _m.Called(runtimeID)
+
+	var r0 *string
+	if rf, ok := ret.Get(0).(func(string) *string); ok {
+		r0 = rf(runtimeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*string)
+		}
+	}
+
+	var r1 dberrors.Error
+	if rf, ok := ret.Get(1).(func(string) dberrors.Error); ok {
+		r1 = rf(runtimeID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(dberrors.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetLastOperation provides a mock function with given fields: runtimeID
 func (_m *ReadWriteSession) GetLastOperation(runtimeID string) (model.Operation, dberrors.Error) {
 	ret := _m.Called(runtimeID)
@@ -55,6 +55,25 @@ func (r readSession) GetTenantForOperation(operationID string) (string, dberrors
 	return tenant, nil
 }
 
+func (r readSession) GetKubeconfig(runtimeID string) (*string, dberrors.Error) {
+	var kubeconfig *string
+
+	err := r.session.
+		Select("kubeconfig").
+		From("cluster").
+		Where(dbr.Eq("cluster.id", runtimeID)).
+		LoadOne(&kubeconfig)
+
+	if err != nil {
+		if err == dbr.ErrNotFound {
+			return nil, dberrors.NotFound("Cannot find Cluster for runtimeID: %s", runtimeID)
+		}
+
+		return nil, dberrors.Internal("Failed to get Kubeconfig: %s", err)
+	}
+	return kubeconfig, nil
+}
+
 func (r readSession) GetCluster(runtimeID string) (model.Cluster, dberrors.Error) {
 	var cluster model.Cluster
 
@@ -32,6 +32,7 @@ type Service interface {
 	ReconnectRuntimeAgent(id string) (string, apperrors.AppError)
 	RuntimeStatus(id string) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	RuntimeOperationStatus(id string) (*gqlschema.OperationStatus, apperrors.AppError)
+	RuntimeKubeconfig(id string) (*string, apperrors.AppError)
 	RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError)
 	HibernateCluster(clusterID string) (*gqlschema.OperationStatus, apperrors.AppError)
 }
@@ -350,6 +351,17 @@ func (r *service) RuntimeOperationStatus(operationID string) (*gqlschema.Operati
 	return r.graphQLConverter.OperationStatusToGQLOperationStatus(operation), nil
 }
 
+func (r *service) RuntimeKubeconfig(runtimeID string) (*string, apperrors.AppError) {
+	readSession := r.dbSessionFactory.NewReadSession()
+
+	kubeconfig, dberr := readSession.GetKubeconfig(runtimeID)
+	if dberr != nil {
+		return nil, apperrors.Internal("failed to get Runtime Kubeconfig: %s", dberr.Error())
+	}
+
+	return kubeconfig, nil
+}
+
 func (r *service) RollBackLastUpgrade(runtimeID string) (*gqlschema.RuntimeStatus, apperrors.AppError) {
 
 	readSession := r.dbSessionFactory.NewReadSession()
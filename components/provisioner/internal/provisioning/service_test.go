@@ -555,6 +555,51 @@ func TestService_RuntimeStatus(t *testing.T) {
 	})
 }
 
+func TestService_RuntimeKubeconfig(t *testing.T) {
+	uuidGenerator := &uuidMocks.UUIDGenerator{}
+	inputConverter := NewInputConverter(uuidGenerator, nil, gardenerProject, defaultEnableKubernetesVersionAutoUpdate, defaultEnableMachineImageVersionAutoUpdate, forceAllowPrivilegedContainers)
+	graphQLConverter := NewGraphQLConverter()
+
+	t.Run("Should return runtime kubeconfig", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetKubeconfig", runtimeID).Return(util.StringPtr("kubeconfig"), nil)
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil)
+
+		//when
+		kubeconfig, err := resolver.RuntimeKubeconfig(runtimeID)
+
+		//then
+		require.NoError(t, err)
+		assert.Equal(t, util.StringPtr("kubeconfig"), kubeconfig)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+
+	t.Run("Should return error when failed to get kubeconfig", func(t *testing.T) {
+		//given
+		sessionFactoryMock := &sessionMocks.Factory{}
+		readSession := &sessionMocks.ReadSession{}
+
+		sessionFactoryMock.On("NewReadSession").Return(readSession)
+		readSession.On("GetKubeconfig", runtimeID).Return(nil, dberrors.NotFound("error"))
+
+		resolver := NewProvisioningService(inputConverter, graphQLConverter, nil, sessionFactoryMock, nil, uuidGenerator, nil, nil, nil, nil, nil)
+
+		//when
+		_, err := resolver.RuntimeKubeconfig(runtimeID)
+
+		//then
+		require.Error(t, err)
+		sessionFactoryMock.AssertExpectations(t)
+		readSession.AssertExpectations(t)
+	})
+}
+
 func TestService_UpgradeRuntime(t *testing.T) {
 	releaseProvider := &releaseMocks.Provider{}
 	releaseProvider.On("GetReleaseByVersion", kymaVersion).Return(kymaRelease, nil)
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id varchar(255) PRIMARY KEY,
+    actor varchar(255) NOT NULL,
+    action varchar(64) NOT NULL,
+    orchestration_id varchar(255) NOT NULL DEFAULT '',
+    instance_id varchar(255) NOT NULL DEFAULT '',
+    runtime_id varchar(255) NOT NULL DEFAULT '',
+    parameters text NOT NULL DEFAULT '',
+    result varchar(32) NOT NULL,
+    error text NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX audit_events_by_created_at ON audit_events USING btree (created_at);
+CREATE INDEX audit_events_by_actor ON audit_events USING btree (actor);
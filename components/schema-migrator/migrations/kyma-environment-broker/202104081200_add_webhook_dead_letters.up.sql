@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id varchar(255) PRIMARY KEY,
+    url text NOT NULL,
+    event_type varchar(64) NOT NULL,
+    payload text NOT NULL,
+    attempts integer NOT NULL,
+    last_error text NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX webhook_dead_letters_by_created_at ON webhook_dead_letters USING btree (created_at);
@@ -0,0 +1,2 @@
+DROP TABLE outbox_subscriptions;
+DROP TABLE outbox_events;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id bigserial PRIMARY KEY,
+    type varchar(255) NOT NULL,
+    payload bytea NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX outbox_events_by_type ON outbox_events USING btree (type, id);
+
+CREATE TABLE IF NOT EXISTS outbox_subscriptions (
+    name varchar(512) PRIMARY KEY,
+    event_offset bigint NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
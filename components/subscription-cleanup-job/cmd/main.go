@@ -2,8 +2,10 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"strings"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/gardener"
 	"github.com/kyma-project/control-plane/components/subscription-cleanup-job/internal/cloudprovider"
@@ -21,6 +23,17 @@ type config struct {
 		KubeconfigPath string `envconfig:"default=/gardener/kubeconfig"`
 		Project        string `envconfig:"default="`
 	}
+	// DryRun only lists the resources which would be deleted
+	DryRun bool `envconfig:"default=false"`
+	// ResourceNames contains the patterns of the resource names which can (Allow) or must not (Deny) be deleted
+	ResourceNames struct {
+		Allow []string `envconfig:"optional"`
+		Deny  []string `envconfig:"optional"`
+	}
+	AWS struct {
+		// Regions are the regions cleaned in AWS accounts, all regions enabled for the account are cleaned if empty
+		Regions []string `envconfig:"optional"`
+	}
 }
 
 func main() {
@@ -28,6 +41,13 @@ func main() {
 	cfg := config{}
 	err := envconfig.InitWithPrefix(&cfg, "APP")
 	exitOnError(err, "Failed to load application config")
+	parseFlags(&cfg)
+
+	nameFilter, err := cloudprovider.NewNameFilter(cfg.ResourceNames.Allow, cfg.ResourceNames.Deny)
+	exitOnError(err, "Failed to create resource name filter")
+	if cfg.DryRun {
+		log.Info("Dry run, resources will not be deleted")
+	}
 
 	clusterConfig, err := newClusterConfig(cfg)
 	exitOnError(err, "Failed to create kubernetes cluster client")
@@ -39,12 +59,37 @@ func main() {
 	exitOnError(err, "Failed to create kubernetes client")
 	secretBindingsInterface := gardener.NewGardenerSecretBindingsInterface(gardenerClient, cfg.Gardener.Project)
 
-	err = job.NewCleaner(context.Background(), kubernetesInterface, secretBindingsInterface, cloudprovider.NewProviderFactory()).Do()
+	err = job.NewCleaner(context.Background(), kubernetesInterface, secretBindingsInterface, cloudprovider.NewProviderFactory(cloudprovider.Options{
+		DryRun:     cfg.DryRun,
+		Filter:     nameFilter,
+		AWSRegions: cfg.AWS.Regions,
+	})).Do()
 	exitOnError(err, "Job execution failed")
 
 	log.Info("Cleanup job finished successfully!")
 }
 
+// parseFlags overrides the values loaded from the environment with the command line flags
+func parseFlags(cfg *config) {
+	allow := flag.String("allow", strings.Join(cfg.ResourceNames.Allow, ","), "Comma-separated patterns of the resource names which can be deleted")
+	deny := flag.String("deny", strings.Join(cfg.ResourceNames.Deny, ","), "Comma-separated patterns of the resource names which must not be deleted")
+	flag.BoolVar(&cfg.DryRun, "dry-run", cfg.DryRun, "Only list the resources which would be deleted")
+	flag.Parse()
+
+	cfg.ResourceNames.Allow = splitPatterns(*allow)
+	cfg.ResourceNames.Deny = splitPatterns(*deny)
+}
+
+func splitPatterns(patterns string) []string {
+	var result []string
+	for _, pattern := range strings.Split(patterns, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			result = append(result, pattern)
+		}
+	}
+	return result
+}
+
 func exitOnError(err error, context string) {
 	if err != nil {
 		wrappedError := errors.Wrap(err, context)
@@ -1,6 +1,6 @@
 module github.com/kyma-project/control-plane/components/subscription-cleanup-job
 
-go 1.13
+go 1.15
 
 require (
 	github.com/Azure/azure-sdk-for-go v42.2.0+incompatible
@@ -8,6 +8,11 @@ require (
 	github.com/Azure/go-autorest/autorest/adal v0.9.13
 	github.com/Azure/go-autorest/autorest/to v0.4.0 // indirect
 	github.com/Azure/go-autorest/autorest/validation v0.3.0 // indirect
+	github.com/aws/aws-sdk-go-v2 v1.16.16
+	github.com/aws/aws-sdk-go-v2/service/ec2 v1.51.1
+	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing v1.14.7
+	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2 v1.18.7
+	github.com/aws/smithy-go v1.13.3
 	github.com/gardener/gardener v1.10.1-0.20200903060046-8bed4ed6c257
 	github.com/kyma-project/control-plane v0.0.0-20210308123720-0b44eb87eaaa
 	github.com/pkg/errors v0.9.1
@@ -15,7 +20,7 @@ require (
 	github.com/stretchr/testify v1.5.1
 	github.com/vrischmann/envconfig v1.2.0
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad // indirect
-	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d // indirect
+	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
 	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e // indirect
 	k8s.io/api v0.19.6
 	k8s.io/apimachinery v0.19.6
@@ -0,0 +1,553 @@
+package cloudprovider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	awsAccessKeyID     = "accessKeyID"
+	awsSecretAccessKey = "secretAccessKey"
+
+	awsPollInterval = 5 * time.Second
+	awsTimeout      = 10 * time.Minute
+)
+
+// awsResource is the resource to delete, delete also removes the associations which block the deletion
+type awsResource struct {
+	id     string
+	delete func(ctx context.Context) error
+}
+
+type awsResourceKind struct {
+	name string
+	// list returns the resources which must be deleted, resources of the default VPCs are kept
+	list func(ctx context.Context, defaultVPCs map[string]bool) ([]awsResource, error)
+	// wait is set for resources which must be gone before the resources they use can be deleted
+	wait bool
+	// cleanup is set for kinds which only modify the resources reported by other kinds
+	cleanup bool
+}
+
+type awsResourceCleaner struct {
+	config   awsConfig
+	endpoint awsEndpoint
+	options  Options
+	// regions are cleaned in the given order, all regions enabled for the account are cleaned if empty
+	regions      []string
+	pollInterval time.Duration
+	timeout      time.Duration
+}
+
+type awsConfig struct {
+	accessKeyID     string
+	secretAccessKey string
+}
+
+func NewAWSResourcesCleaner(secretData map[string][]byte, options Options) (ResourceCleaner, error) {
+	config, err := toAWSConfig(secretData)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAWSResourcesCleaner(config, nil, options.AWSRegions, options), nil
+}
+
+func newAWSResourcesCleaner(config awsConfig, endpoint awsEndpoint, regions []string, options Options) *awsResourceCleaner {
+	return &awsResourceCleaner{
+		config:       config,
+		endpoint:     endpoint,
+		options:      options,
+		regions:      regions,
+		pollInterval: awsPollInterval,
+		timeout:      awsTimeout,
+	}
+}
+
+// Do deletes the resources in all regions and reports the resources left
+func (rc awsResourceCleaner) Do() (Report, error) {
+	ctx := context.Background()
+	report := Report{DryRun: rc.options.DryRun}
+
+	regions, err := rc.regionsToClean(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, region := range regions {
+		client := newAWSClient(rc.config, region, rc.endpoint)
+		defaultVPCs, err := defaultVPCs(ctx, client)
+		if err != nil {
+			return report, errors.Wrapf(err, "while listing VPCs in %s", region)
+		}
+
+		for _, kind := range resourceKinds(client) {
+			resources, err := kind.list(ctx, defaultVPCs)
+			if err != nil {
+				return report, errors.Wrapf(err, "while listing %s in %s", kind.name, region)
+			}
+			deleted := rc.deleteResources(ctx, region, kind, resources, &report)
+
+			if kind.wait && deleted > 0 {
+				err := rc.waitUntilDeleted(ctx, kind, defaultVPCs)
+				if err != nil {
+					log.Errorf("failed to wait for deletion of %s in '%s': %s", kind.name, region, err.Error())
+				}
+			}
+		}
+	}
+
+	if rc.options.DryRun {
+		return report, nil
+	}
+
+	return report, rc.verify(ctx, regions, &report)
+}
+
+// regionsToClean returns the configured regions or the regions enabled for the account
+func (rc awsResourceCleaner) regionsToClean(ctx context.Context) ([]string, error) {
+	if len(rc.regions) > 0 {
+		return rc.regions, nil
+	}
+
+	regions, err := newAWSClient(rc.config, awsDefaultRegion, rc.endpoint).enabledRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Cleaning AWS regions enabled for the account: %v", regions)
+
+	return regions, nil
+}
+
+// resourceKinds are ordered, so that the resources are deleted before the resources they depend on
+func resourceKinds(c *awsClient) []awsResourceKind {
+	return []awsResourceKind{
+		{name: "load balancers", list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := elasticloadbalancing.NewDescribeLoadBalancersPaginator(c.elb, &elasticloadbalancing.DescribeLoadBalancersInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, loadBalancer := range page.LoadBalancerDescriptions {
+					name := loadBalancer.LoadBalancerName
+					resources = append(resources, awsResource{id: aws.ToString(name), delete: func(ctx context.Context) error {
+						_, err := c.elb.DeleteLoadBalancer(ctx, &elasticloadbalancing.DeleteLoadBalancerInput{LoadBalancerName: name})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "network load balancers", list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(c.elbv2, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, loadBalancer := range page.LoadBalancers {
+					arn := loadBalancer.LoadBalancerArn
+					resources = append(resources, awsResource{id: aws.ToString(loadBalancer.LoadBalancerName), delete: func(ctx context.Context) error {
+						_, err := c.elbv2.DeleteLoadBalancer(ctx, &elasticloadbalancingv2.DeleteLoadBalancerInput{LoadBalancerArn: arn})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "instances", wait: true, list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeInstancesPaginator(c.ec2, &ec2.DescribeInstancesInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, reservation := range page.Reservations {
+					for _, instance := range reservation.Instances {
+						if instance.State != nil && instance.State.Name == ec2types.InstanceStateNameTerminated {
+							continue
+						}
+						id := aws.ToString(instance.InstanceId)
+						resources = append(resources, awsResource{id: id, delete: func(ctx context.Context) error {
+							_, err := c.ec2.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{id}})
+							return err
+						}})
+					}
+				}
+			}
+			return resources, nil
+		}},
+		{name: "volumes", list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeVolumesPaginator(c.ec2, &ec2.DescribeVolumesInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, volume := range page.Volumes {
+					if volume.State == ec2types.VolumeStateDeleting || volume.State == ec2types.VolumeStateDeleted {
+						continue
+					}
+					id := volume.VolumeId
+					resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+						_, err := c.ec2.DeleteVolume(ctx, &ec2.DeleteVolumeInput{VolumeId: id})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "NAT gateways", wait: true, list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeNatGatewaysPaginator(c.ec2, &ec2.DescribeNatGatewaysInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, gateway := range page.NatGateways {
+					if gateway.State == ec2types.NatGatewayStateDeleted || gateway.State == ec2types.NatGatewayStateFailed {
+						continue
+					}
+					id := gateway.NatGatewayId
+					resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+						_, err := c.ec2.DeleteNatGateway(ctx, &ec2.DeleteNatGatewayInput{NatGatewayId: id})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "elastic IPs", list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			output, err := c.ec2.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+			if err != nil {
+				return nil, err
+			}
+			var resources []awsResource
+			for _, address := range output.Addresses {
+				address := address
+				resources = append(resources, awsResource{id: aws.ToString(address.PublicIp), delete: func(ctx context.Context) error {
+					if address.AssociationId != nil {
+						_, err := c.ec2.DisassociateAddress(ctx, &ec2.DisassociateAddressInput{AssociationId: address.AssociationId})
+						if err != nil && !isAWSNotFound(err) {
+							return err
+						}
+					}
+					_, err := c.ec2.ReleaseAddress(ctx, &ec2.ReleaseAddressInput{AllocationId: address.AllocationId})
+					return err
+				}})
+			}
+			return resources, nil
+		}},
+		{name: "internet gateways", list: func(ctx context.Context, defaultVPCs map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeInternetGatewaysPaginator(c.ec2, &ec2.DescribeInternetGatewaysInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+			gateways:
+				for _, gateway := range page.InternetGateways {
+					for _, attachment := range gateway.Attachments {
+						if defaultVPCs[aws.ToString(attachment.VpcId)] {
+							continue gateways
+						}
+					}
+					gateway := gateway
+					resources = append(resources, awsResource{id: aws.ToString(gateway.InternetGatewayId), delete: func(ctx context.Context) error {
+						for _, attachment := range gateway.Attachments {
+							_, err := c.ec2.DetachInternetGateway(ctx, &ec2.DetachInternetGatewayInput{
+								InternetGatewayId: gateway.InternetGatewayId,
+								VpcId:             attachment.VpcId,
+							})
+							if err != nil && !isAWSNotFound(err) {
+								return err
+							}
+						}
+						_, err := c.ec2.DeleteInternetGateway(ctx, &ec2.DeleteInternetGatewayInput{InternetGatewayId: gateway.InternetGatewayId})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "subnets", list: func(ctx context.Context, defaultVPCs map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeSubnetsPaginator(c.ec2, &ec2.DescribeSubnetsInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, subnet := range page.Subnets {
+					if subnet.DefaultForAz || defaultVPCs[aws.ToString(subnet.VpcId)] {
+						continue
+					}
+					id := subnet.SubnetId
+					resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+						_, err := c.ec2.DeleteSubnet(ctx, &ec2.DeleteSubnetInput{SubnetId: id})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "security group rules", cleanup: true, list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			groups, err := describeSecurityGroups(ctx, c)
+			var resources []awsResource
+			for _, group := range groups {
+				permissions := groupReferences(group)
+				if aws.ToString(group.GroupName) == "default" || len(permissions) == 0 {
+					continue
+				}
+				id := group.GroupId
+				resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+					_, err := c.ec2.RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{GroupId: id, IpPermissions: permissions})
+					return err
+				}})
+			}
+			return resources, err
+		}},
+		{name: "security groups", list: func(ctx context.Context, _ map[string]bool) ([]awsResource, error) {
+			groups, err := describeSecurityGroups(ctx, c)
+			var resources []awsResource
+			for _, group := range groups {
+				// default security groups are deleted together with the VPC
+				if aws.ToString(group.GroupName) == "default" {
+					continue
+				}
+				id := group.GroupId
+				resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+					_, err := c.ec2.DeleteSecurityGroup(ctx, &ec2.DeleteSecurityGroupInput{GroupId: id})
+					return err
+				}})
+			}
+			return resources, err
+		}},
+		{name: "route tables", list: func(ctx context.Context, defaultVPCs map[string]bool) ([]awsResource, error) {
+			var resources []awsResource
+			paginator := ec2.NewDescribeRouteTablesPaginator(c.ec2, &ec2.DescribeRouteTablesInput{})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, table := range page.RouteTables {
+					// main route tables are deleted together with the VPC
+					if defaultVPCs[aws.ToString(table.VpcId)] || isMainRouteTable(table) {
+						continue
+					}
+					table := table
+					resources = append(resources, awsResource{id: aws.ToString(table.RouteTableId), delete: func(ctx context.Context) error {
+						for _, association := range table.Associations {
+							_, err := c.ec2.DisassociateRouteTable(ctx, &ec2.DisassociateRouteTableInput{AssociationId: association.RouteTableAssociationId})
+							if err != nil && !isAWSNotFound(err) {
+								return err
+							}
+						}
+						_, err := c.ec2.DeleteRouteTable(ctx, &ec2.DeleteRouteTableInput{RouteTableId: table.RouteTableId})
+						return err
+					}})
+				}
+			}
+			return resources, nil
+		}},
+		{name: "VPCs", list: func(ctx context.Context, defaultVPCs map[string]bool) ([]awsResource, error) {
+			vpcs, err := describeVpcs(ctx, c)
+			var resources []awsResource
+			for _, vpc := range vpcs {
+				if defaultVPCs[aws.ToString(vpc.VpcId)] {
+					continue
+				}
+				id := vpc.VpcId
+				resources = append(resources, awsResource{id: aws.ToString(id), delete: func(ctx context.Context) error {
+					_, err := c.ec2.DeleteVpc(ctx, &ec2.DeleteVpcInput{VpcId: id})
+					return err
+				}})
+			}
+			return resources, err
+		}},
+	}
+}
+
+func describeSecurityGroups(ctx context.Context, c *awsClient) ([]ec2types.SecurityGroup, error) {
+	var groups []ec2types.SecurityGroup
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(c.ec2, &ec2.DescribeSecurityGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, page.SecurityGroups...)
+	}
+	return groups, nil
+}
+
+func describeVpcs(ctx context.Context, c *awsClient) ([]ec2types.Vpc, error) {
+	var vpcs []ec2types.Vpc
+	paginator := ec2.NewDescribeVpcsPaginator(c.ec2, &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		vpcs = append(vpcs, page.Vpcs...)
+	}
+	return vpcs, nil
+}
+
+// deleteResources deletes the resources of the given kind and returns the number of deleted resources,
+// failures are recorded in the report, so that the remaining resources can be deleted
+func (rc awsResourceCleaner) deleteResources(ctx context.Context, region string, kind awsResourceKind, resources []awsResource, report *Report) int {
+	deleted := 0
+	for _, resource := range resources {
+		id := awsResourceID(region, resource.id)
+		if kind.cleanup {
+			if rc.options.DryRun || !rc.options.Filter.Allowed(resource.id) {
+				continue
+			}
+		} else if !rc.options.include(report, id, resource.id) {
+			continue
+		}
+
+		log.Infof("Deleting AWS %s '%s' in region '%s'", kind.name, resource.id, region)
+		err := resource.delete(ctx)
+		if err != nil && !isAWSNotFound(err) {
+			log.Errorf("failed to delete %s '%s' in '%s': %s", kind.name, resource.id, region, err.Error())
+			report.failed(id, err)
+			continue
+		}
+		if !kind.cleanup {
+			report.deleted(id)
+		}
+		deleted++
+	}
+
+	return deleted
+}
+
+// waitUntilDeleted waits until the resources of the given kind allowed by the name filter are gone
+func (rc awsResourceCleaner) waitUntilDeleted(ctx context.Context, kind awsResourceKind, defaultVPCs map[string]bool) error {
+	deadline := time.Now().Add(rc.timeout)
+	for {
+		resources, err := kind.list(ctx, defaultVPCs)
+		if err != nil {
+			return err
+		}
+		left := 0
+		for _, resource := range resources {
+			if rc.options.Filter.Allowed(resource.id) {
+				left++
+			}
+		}
+		if left == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("%d %s not deleted after %s", left, kind.name, rc.timeout)
+		}
+		time.Sleep(rc.pollInterval)
+	}
+}
+
+// verify records the resources left in the cleaned regions, resources excluded by the name filter are not reported
+func (rc awsResourceCleaner) verify(ctx context.Context, regions []string, report *Report) error {
+	for _, region := range regions {
+		client := newAWSClient(rc.config, region, rc.endpoint)
+		defaultVPCs, err := defaultVPCs(ctx, client)
+		if err != nil {
+			return errors.Wrapf(err, "while listing VPCs in %s", region)
+		}
+		for _, kind := range resourceKinds(client) {
+			if kind.cleanup {
+				continue
+			}
+			resources, err := kind.list(ctx, defaultVPCs)
+			if err != nil {
+				return errors.Wrapf(err, "while listing %s in %s", kind.name, region)
+			}
+			for _, resource := range resources {
+				if rc.options.Filter.Allowed(resource.id) {
+					report.remaining(awsResourceID(region, resource.id))
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func awsResourceID(region, id string) string {
+	return fmt.Sprintf("%s/%s", region, id)
+}
+
+func defaultVPCs(ctx context.Context, c *awsClient) (map[string]bool, error) {
+	vpcs, err := describeVpcs(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defaultVPCs := make(map[string]bool)
+	for _, vpc := range vpcs {
+		if vpc.IsDefault {
+			defaultVPCs[aws.ToString(vpc.VpcId)] = true
+		}
+	}
+	return defaultVPCs, nil
+}
+
+// groupReferences returns the ingress rules which reference other security groups, such rules block the deletion
+// of the referenced groups
+func groupReferences(group ec2types.SecurityGroup) []ec2types.IpPermission {
+	var permissions []ec2types.IpPermission
+	for _, permission := range group.IpPermissions {
+		if len(permission.UserIdGroupPairs) == 0 {
+			continue
+		}
+		permission.IpRanges = nil
+		permission.Ipv6Ranges = nil
+		permission.PrefixListIds = nil
+		permissions = append(permissions, permission)
+	}
+	return permissions
+}
+
+func isMainRouteTable(table ec2types.RouteTable) bool {
+	for _, association := range table.Associations {
+		if association.Main {
+			return true
+		}
+	}
+	return false
+}
+
+func toAWSConfig(secretData map[string][]byte) (awsConfig, error) {
+	accessKeyID, exists := secretData[awsAccessKeyID]
+	if !exists {
+		return awsConfig{}, errors.Errorf("%s not provided in the secret", awsAccessKeyID)
+	}
+
+	secretAccessKey, exists := secretData[awsSecretAccessKey]
+	if !exists {
+		return awsConfig{}, errors.Errorf("%s not provided in the secret", awsSecretAccessKey)
+	}
+
+	return awsConfig{
+		accessKeyID:     string(accessKeyID),
+		secretAccessKey: string(secretAccessKey),
+	}, nil
+}
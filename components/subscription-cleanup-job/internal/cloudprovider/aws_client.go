@@ -0,0 +1,86 @@
+package cloudprovider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	awsEC2Service = "ec2"
+	awsELBService = "elasticloadbalancing"
+
+	// awsDefaultRegion is used to discover the regions enabled for the account
+	awsDefaultRegion = "us-east-1"
+)
+
+// awsEndpoint returns the URL of the service API in the region, the endpoints of AWS are used if it is not set
+type awsEndpoint func(service, region string) string
+
+// awsClient holds the clients of the services used by the cleaner in a single region
+type awsClient struct {
+	ec2   *ec2.Client
+	elb   *elasticloadbalancing.Client
+	elbv2 *elasticloadbalancingv2.Client
+}
+
+func newAWSClient(config awsConfig, region string, endpoint awsEndpoint) *awsClient {
+	cfg := aws.Config{
+		Region: region,
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     config.accessKeyID,
+				SecretAccessKey: config.secretAccessKey,
+				Source:          "subscription-cleanup-job",
+			}, nil
+		}),
+	}
+
+	return &awsClient{
+		ec2: ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+			if endpoint != nil {
+				o.EndpointResolver = ec2.EndpointResolverFromURL(endpoint(awsEC2Service, region))
+			}
+		}),
+		elb: elasticloadbalancing.NewFromConfig(cfg, func(o *elasticloadbalancing.Options) {
+			if endpoint != nil {
+				o.EndpointResolver = elasticloadbalancing.EndpointResolverFromURL(endpoint(awsELBService, region))
+			}
+		}),
+		elbv2: elasticloadbalancingv2.NewFromConfig(cfg, func(o *elasticloadbalancingv2.Options) {
+			if endpoint != nil {
+				o.EndpointResolver = elasticloadbalancingv2.EndpointResolverFromURL(endpoint(awsELBService, region))
+			}
+		}),
+	}
+}
+
+// enabledRegions returns the regions enabled for the account
+func (c *awsClient) enabledRegions(ctx context.Context) ([]string, error) {
+	output, err := c.ec2.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "while describing regions")
+	}
+
+	regions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+
+	return regions, nil
+}
+
+// isAWSNotFound returns true for the errors returned by the API when the resource is gone,
+// e.g. InvalidInstanceID.NotFound or LoadBalancerNotFound
+func isAWSNotFound(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && strings.HasSuffix(apiErr.ErrorCode(), "NotFound")
+}
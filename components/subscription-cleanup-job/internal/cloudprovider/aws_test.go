@@ -0,0 +1,709 @@
+package cloudprovider
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	awsTestRegion      = "eu-central-1"
+	awsTestEmptyRegion = "us-east-1"
+	awsTestAccessKeyID = "AKIDTEST"
+)
+
+func TestAWSResourceCleaner(t *testing.T) {
+	t.Run("should delete all resources except the default VPC", func(t *testing.T) {
+		// given
+		aws := newFakeAWS(t)
+		defer aws.Close()
+		aws.addShootResources()
+		cleaner := aws.cleaner(Options{})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.Clean())
+		assert.Contains(t, report.Deleted, "eu-central-1/i-1")
+		assert.Contains(t, report.Deleted, "eu-central-1/vpc-shoot")
+		assert.NotContains(t, report.Found, "eu-central-1/vpc-default")
+		assert.Equal(t, []string{
+			"internet gateway igw-default",
+			"route table rtb-default-main",
+			"security group sg-default-vpc",
+			"subnet subnet-default",
+			"vpc vpc-default",
+		}, aws.resourcesLeft(awsTestRegion))
+		assert.Empty(t, aws.resourcesLeft(awsTestEmptyRegion))
+	})
+
+	t.Run("should return error when resources are left", func(t *testing.T) {
+		// given
+		aws := newFakeAWS(t)
+		defer aws.Close()
+		aws.addShootResources()
+		aws.region(awsTestRegion).protected["i-2"] = true
+		cleaner := aws.cleaner(Options{})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, report.Clean())
+		assert.Contains(t, report.Failed["eu-central-1/i-2"], "OperationNotPermitted")
+		assert.Contains(t, report.Failed, "eu-central-1/subnet-nodes")
+		assert.Contains(t, report.Remaining, "eu-central-1/i-2")
+		assert.Contains(t, report.Remaining, "eu-central-1/subnet-nodes")
+		assert.Contains(t, report.Remaining, "eu-central-1/vpc-shoot")
+		assert.NotContains(t, report.Remaining, "eu-central-1/i-1")
+	})
+
+	t.Run("should only list resources in dry run", func(t *testing.T) {
+		// given
+		aws := newFakeAWS(t)
+		defer aws.Close()
+		aws.addShootResources()
+		resourcesBefore := aws.resourcesLeft(awsTestRegion)
+		cleaner := aws.cleaner(Options{DryRun: true})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, report.Clean())
+		assert.Equal(t, resourcesBefore, aws.resourcesLeft(awsTestRegion))
+		assert.Len(t, report.Found, 16)
+		assert.Contains(t, report.Found, "eu-central-1/i-1")
+		assert.Contains(t, report.Found, "eu-central-1/vpc-shoot")
+		assert.Empty(t, report.Deleted)
+		assert.Empty(t, report.Remaining)
+	})
+
+	t.Run("should keep resources excluded by the name filter", func(t *testing.T) {
+		// given
+		aws := newFakeAWS(t)
+		defer aws.Close()
+		aws.addShootResources()
+		filter, err := NewNameFilter(nil, []string{"sg-elb"})
+		require.NoError(t, err)
+		cleaner := aws.cleaner(Options{Filter: filter})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, report.Clean())
+		assert.Equal(t, []string{"eu-central-1/sg-elb"}, report.Skipped)
+		assert.Contains(t, report.Failed["eu-central-1/vpc-shoot"], "DependencyViolation")
+		assert.Equal(t, []string{"eu-central-1/vpc-shoot"}, report.Remaining)
+		assert.Contains(t, aws.resourcesLeft(awsTestRegion), "security group sg-elb")
+		assert.NotContains(t, aws.resourcesLeft(awsTestRegion), "security group sg-nodes")
+	})
+}
+
+func TestAWSResourceCleanerRegions(t *testing.T) {
+	// given
+	aws := newFakeAWS(t)
+	defer aws.Close()
+	aws.addShootResources()
+	aws.enabledRegions = []string{awsTestRegion, awsTestEmptyRegion}
+	cleaner := aws.cleaner(Options{})
+	cleaner.regions = nil
+
+	// when
+	report, err := cleaner.Do()
+
+	// then
+	require.NoError(t, err)
+	assert.True(t, report.Clean())
+	assert.Contains(t, report.Deleted, "eu-central-1/vpc-shoot")
+	assert.NotContains(t, aws.resourcesLeft(awsTestRegion), "vpc vpc-shoot")
+}
+
+func TestToAWSConfig(t *testing.T) {
+	t.Run("should read credentials", func(t *testing.T) {
+		// when
+		config, err := toAWSConfig(map[string][]byte{
+			"accessKeyID":     []byte("key"),
+			"secretAccessKey": []byte("secret"),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, awsConfig{accessKeyID: "key", secretAccessKey: "secret"}, config)
+	})
+
+	t.Run("should fail when credentials are missing", func(t *testing.T) {
+		// when
+		_, err := toAWSConfig(map[string][]byte{"accessKeyID": []byte("key")})
+
+		// then
+		assert.EqualError(t, err, "secretAccessKey not provided in the secret")
+	})
+}
+
+func TestIsAWSNotFound(t *testing.T) {
+	for name, tc := range map[string]struct {
+		err      error
+		expected bool
+	}{
+		"EC2 resource not found": {
+			err:      &smithy.GenericAPIError{Code: "InvalidVpcID.NotFound", Message: "not found"},
+			expected: true,
+		},
+		"load balancer not found": {
+			err:      errors.Wrap(&smithy.GenericAPIError{Code: "LoadBalancerNotFound"}, "while deleting"),
+			expected: true,
+		},
+		"dependency violation": {
+			err:      &smithy.GenericAPIError{Code: "DependencyViolation"},
+			expected: false,
+		},
+		"other error": {
+			err:      errors.New("connection refused"),
+			expected: false,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isAWSNotFound(tc.err))
+		})
+	}
+}
+
+// fakeAWS implements the parts of the EC2 and ELB Query APIs used by the cleaner. Like in AWS, resources
+// which are in use cannot be deleted, instances and NAT gateways are deleted asynchronously.
+type fakeAWS struct {
+	*httptest.Server
+	t *testing.T
+
+	mu      sync.Mutex
+	regions map[string]*fakeAWSRegion
+	// enabledRegions are returned by DescribeRegions
+	enabledRegions []string
+}
+
+type fakeAWSRegion struct {
+	instances     map[string]*fakeAWSInstance
+	protected     map[string]bool
+	volumes       map[string]string
+	natGateways   map[string]*fakeAWSNatGateway
+	addresses     map[string]string
+	igws          map[string]string
+	subnets       map[string]fakeAWSSubnet
+	groups        map[string]*fakeAWSGroup
+	routeTables   map[string]*fakeAWSRouteTable
+	vpcs          map[string]bool
+	loadBalancers map[string]string
+}
+
+type fakeAWSInstance struct {
+	state  string
+	subnet string
+}
+
+type fakeAWSNatGateway struct {
+	state  string
+	subnet string
+}
+
+type fakeAWSSubnet struct {
+	vpc          string
+	defaultForAz bool
+}
+
+type fakeAWSGroup struct {
+	name string
+	vpc  string
+	refs []string
+}
+
+type fakeAWSRouteTable struct {
+	vpc     string
+	main    bool
+	subnets []string
+}
+
+func newFakeAWS(t *testing.T) *fakeAWS {
+	aws := &fakeAWS{t: t, regions: make(map[string]*fakeAWSRegion)}
+	aws.Server = httptest.NewServer(http.HandlerFunc(aws.handle))
+	return aws
+}
+
+func (f *fakeAWS) cleaner(options Options) *awsResourceCleaner {
+	endpoint := func(service, region string) string {
+		return fmt.Sprintf("%s/%s/%s/", f.URL, service, region)
+	}
+	config := awsConfig{accessKeyID: awsTestAccessKeyID, secretAccessKey: "secret"}
+	cleaner := newAWSResourcesCleaner(config, endpoint, []string{awsTestRegion, awsTestEmptyRegion}, options)
+	cleaner.pollInterval = time.Millisecond
+	cleaner.timeout = 100 * time.Millisecond
+	return cleaner
+}
+
+func (f *fakeAWS) region(name string) *fakeAWSRegion {
+	r, found := f.regions[name]
+	if !found {
+		r = &fakeAWSRegion{
+			instances:     make(map[string]*fakeAWSInstance),
+			protected:     make(map[string]bool),
+			volumes:       make(map[string]string),
+			natGateways:   make(map[string]*fakeAWSNatGateway),
+			addresses:     make(map[string]string),
+			igws:          make(map[string]string),
+			subnets:       make(map[string]fakeAWSSubnet),
+			groups:        make(map[string]*fakeAWSGroup),
+			routeTables:   make(map[string]*fakeAWSRouteTable),
+			vpcs:          make(map[string]bool),
+			loadBalancers: make(map[string]string),
+		}
+		f.regions[name] = r
+	}
+	return r
+}
+
+func (f *fakeAWS) addShootResources() {
+	r := f.region(awsTestRegion)
+
+	r.vpcs["vpc-default"] = true
+	r.subnets["subnet-default"] = fakeAWSSubnet{vpc: "vpc-default", defaultForAz: true}
+	r.groups["sg-default-vpc"] = &fakeAWSGroup{name: "default", vpc: "vpc-default"}
+	r.routeTables["rtb-default-main"] = &fakeAWSRouteTable{vpc: "vpc-default", main: true}
+	r.igws["igw-default"] = "vpc-default"
+
+	r.vpcs["vpc-shoot"] = false
+	r.groups["sg-default-shoot"] = &fakeAWSGroup{name: "default", vpc: "vpc-shoot"}
+	r.routeTables["rtb-shoot-main"] = &fakeAWSRouteTable{vpc: "vpc-shoot", main: true}
+	r.igws["igw-shoot"] = "vpc-shoot"
+	r.subnets["subnet-nodes"] = fakeAWSSubnet{vpc: "vpc-shoot"}
+	r.subnets["subnet-public"] = fakeAWSSubnet{vpc: "vpc-shoot"}
+	r.routeTables["rtb-nodes"] = &fakeAWSRouteTable{vpc: "vpc-shoot", subnets: []string{"subnet-nodes"}}
+	r.groups["sg-elb"] = &fakeAWSGroup{name: "shoot-elb", vpc: "vpc-shoot"}
+	r.groups["sg-nodes"] = &fakeAWSGroup{name: "shoot-nodes", vpc: "vpc-shoot", refs: []string{"sg-elb", "sg-nodes"}}
+	r.instances["i-1"] = &fakeAWSInstance{state: "running", subnet: "subnet-nodes"}
+	r.instances["i-2"] = &fakeAWSInstance{state: "running", subnet: "subnet-nodes"}
+	r.instances["i-old"] = &fakeAWSInstance{state: "terminated", subnet: "subnet-nodes"}
+	r.volumes["vol-1"] = "i-1"
+	r.volumes["vol-2"] = ""
+	r.natGateways["nat-1"] = &fakeAWSNatGateway{state: "available", subnet: "subnet-public"}
+	r.addresses["eipalloc-1"] = "nat-1"
+	r.addresses["eipalloc-2"] = ""
+	r.loadBalancers["a1b2c3"] = "subnet-public"
+	r.loadBalancers["arn:aws:elasticloadbalancing:eu-central-1:123:loadbalancer/net/nlb/1"] = "subnet-public"
+}
+
+func (f *fakeAWS) resourcesLeft(region string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	r := f.region(region)
+	var left []string
+	for id, instance := range r.instances {
+		if instance.state != "terminated" {
+			left = append(left, "instance "+id)
+		}
+	}
+	for id, nat := range r.natGateways {
+		if nat.state != "deleted" {
+			left = append(left, "NAT gateway "+id)
+		}
+	}
+	for _, kind := range []struct {
+		name string
+		ids  []string
+	}{
+		{"volume", keys(r.volumes)},
+		{"address", keys(r.addresses)},
+		{"internet gateway", keys(r.igws)},
+		{"subnet", keys(r.subnets)},
+		{"security group", keys(r.groups)},
+		{"route table", keys(r.routeTables)},
+		{"vpc", keys(r.vpcs)},
+		{"load balancer", keys(r.loadBalancers)},
+	} {
+		for _, id := range kind.ids {
+			left = append(left, kind.name+" "+id)
+		}
+	}
+	sort.Strings(left)
+	return left
+}
+
+func (f *fakeAWS) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) != 2 {
+		f.t.Errorf("unexpected path %s", r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	service, regionName := parts[0], parts[1]
+	credential := fmt.Sprintf("Credential=%s/%s/%s/%s/aws4_request", awsTestAccessKeyID, time.Now().UTC().Format("20060102"), regionName, service)
+	if !strings.Contains(r.Header.Get("Authorization"), credential) {
+		f.t.Errorf("request not signed for %s in %s: %s", service, regionName, r.Header.Get("Authorization"))
+	}
+	if err := r.ParseForm(); err != nil {
+		f.t.Errorf("cannot parse form: %s", err)
+	}
+	action := r.PostForm.Get("Action")
+	if service == awsEC2Service && action == "DescribeRegions" {
+		fmt.Fprintf(w, "<DescribeRegionsResponse>%s</DescribeRegionsResponse>", items("regionInfo", f.enabledRegions, func(name string) string {
+			return fmt.Sprintf("<regionName>%s</regionName>", name)
+		}))
+		return
+	}
+	region := f.region(regionName)
+	response, err := region.handle(service+":"+r.PostForm.Get("Version")+":"+action, r.PostForm)
+	if err != nil {
+		code := strings.SplitN(err.Error(), ":", 2)[0]
+		w.WriteHeader(http.StatusBadRequest)
+		if service == awsELBService {
+			fmt.Fprintf(w, "<ErrorResponse><Error><Code>%s</Code><Message>%s</Message></Error></ErrorResponse>", code, err.Error())
+			return
+		}
+		fmt.Fprintf(w, "<Response><Errors><Error><Code>%s</Code><Message>%s</Message></Error></Errors></Response>", code, err.Error())
+		return
+	}
+	fmt.Fprintf(w, "<%sResponse>%s</%sResponse>", action, response, action)
+}
+
+func (r *fakeAWSRegion) handle(call string, form map[string][]string) (string, error) {
+	get := func(key string) string {
+		if values := form[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	switch call {
+	case "elasticloadbalancing:2012-06-01:DescribeLoadBalancers":
+		var members []string
+		for name := range r.loadBalancers {
+			if !strings.HasPrefix(name, "arn:") {
+				members = append(members, fmt.Sprintf("<member><LoadBalancerName>%s</LoadBalancerName></member>", name))
+			}
+		}
+		return "<DescribeLoadBalancersResult><LoadBalancerDescriptions>" + strings.Join(members, "") + "</LoadBalancerDescriptions></DescribeLoadBalancersResult>", nil
+	case "elasticloadbalancing:2015-12-01:DescribeLoadBalancers":
+		var members []string
+		for arn := range r.loadBalancers {
+			if strings.HasPrefix(arn, "arn:") {
+				members = append(members, fmt.Sprintf("<member><LoadBalancerArn>%s</LoadBalancerArn></member>", arn))
+			}
+		}
+		return "<DescribeLoadBalancersResult><LoadBalancers>" + strings.Join(members, "") + "</LoadBalancers></DescribeLoadBalancersResult>", nil
+	case "elasticloadbalancing:2012-06-01:DeleteLoadBalancer":
+		delete(r.loadBalancers, get("LoadBalancerName"))
+		return "", nil
+	case "elasticloadbalancing:2015-12-01:DeleteLoadBalancer":
+		delete(r.loadBalancers, get("LoadBalancerArn"))
+		return "", nil
+	}
+
+	switch strings.TrimPrefix(call, "ec2:2016-11-15:") {
+	case "DescribeInstances":
+		// one reservation per page to test the paging
+		ids := keys(r.instances)
+		page := 0
+		if token := get("NextToken"); token != "" {
+			fmt.Sscanf(token, "page-%d", &page)
+		}
+		if len(ids) == 0 {
+			return "<reservationSet/>", nil
+		}
+		id := ids[page]
+		instance := r.instances[id]
+		response := fmt.Sprintf("<reservationSet><item><instancesSet><item><instanceId>%s</instanceId><instanceState><name>%s</name></instanceState></item></instancesSet></item></reservationSet>", id, instance.state)
+		if instance.state == "shutting-down" {
+			r.terminate(id)
+		}
+		if page+1 < len(ids) {
+			response += fmt.Sprintf("<nextToken>page-%d</nextToken>", page+1)
+		}
+		return response, nil
+	case "TerminateInstances":
+		id := get("InstanceId.1")
+		if r.protected[id] {
+			return "", fmt.Errorf("OperationNotPermitted: instance %s has termination protection", id)
+		}
+		if _, found := r.instances[id]; !found {
+			return "", fmt.Errorf("InvalidInstanceID.NotFound: instance %s not found", id)
+		}
+		r.instances[id].state = "shutting-down"
+		return "", nil
+	case "DescribeVolumes":
+		return items("volumeSet", keys(r.volumes), func(id string) string {
+			status := "available"
+			if r.volumes[id] != "" {
+				status = "in-use"
+			}
+			return fmt.Sprintf("<volumeId>%s</volumeId><status>%s</status>", id, status)
+		}), nil
+	case "DeleteVolume":
+		id := get("VolumeId")
+		if r.volumes[id] != "" {
+			return "", fmt.Errorf("VolumeInUse: volume %s is attached to %s", id, r.volumes[id])
+		}
+		delete(r.volumes, id)
+		return "", nil
+	case "DescribeNatGateways":
+		return items("natGatewaySet", keys(r.natGateways), func(id string) string {
+			nat := r.natGateways[id]
+			response := fmt.Sprintf("<natGatewayId>%s</natGatewayId><state>%s</state>", id, nat.state)
+			if nat.state == "deleting" {
+				nat.state = "deleted"
+				for allocation, associated := range r.addresses {
+					if associated == id {
+						r.addresses[allocation] = ""
+					}
+				}
+			}
+			return response
+		}), nil
+	case "DeleteNatGateway":
+		r.natGateways[get("NatGatewayId")].state = "deleting"
+		return "", nil
+	case "DescribeAddresses":
+		return items("addressesSet", keys(r.addresses), func(id string) string {
+			return fmt.Sprintf("<allocationId>%s</allocationId><publicIp>1.2.3.4</publicIp>", id)
+		}), nil
+	case "ReleaseAddress":
+		id := get("AllocationId")
+		if r.addresses[id] != "" {
+			return "", fmt.Errorf("InvalidIPAddress.InUse: address %s is in use by %s", id, r.addresses[id])
+		}
+		delete(r.addresses, id)
+		return "", nil
+	case "DescribeInternetGateways":
+		return items("internetGatewaySet", keys(r.igws), func(id string) string {
+			attachments := ""
+			if vpc := r.igws[id]; vpc != "" {
+				attachments = fmt.Sprintf("<item><vpcId>%s</vpcId><state>available</state></item>", vpc)
+			}
+			return fmt.Sprintf("<internetGatewayId>%s</internetGatewayId><attachmentSet>%s</attachmentSet>", id, attachments)
+		}), nil
+	case "DetachInternetGateway":
+		r.igws[get("InternetGatewayId")] = ""
+		return "", nil
+	case "DeleteInternetGateway":
+		id := get("InternetGatewayId")
+		if r.igws[id] != "" {
+			return "", fmt.Errorf("DependencyViolation: gateway %s is attached to %s", id, r.igws[id])
+		}
+		delete(r.igws, id)
+		return "", nil
+	case "DescribeSubnets":
+		return items("subnetSet", keys(r.subnets), func(id string) string {
+			subnet := r.subnets[id]
+			return fmt.Sprintf("<subnetId>%s</subnetId><vpcId>%s</vpcId><defaultForAz>%t</defaultForAz>", id, subnet.vpc, subnet.defaultForAz)
+		}), nil
+	case "DeleteSubnet":
+		id := get("SubnetId")
+		if user := r.subnetUser(id); user != "" {
+			return "", fmt.Errorf("DependencyViolation: subnet %s is used by %s", id, user)
+		}
+		delete(r.subnets, id)
+		for _, table := range r.routeTables {
+			table.subnets = remove(table.subnets, id)
+		}
+		return "", nil
+	case "DescribeSecurityGroups":
+		return items("securityGroupInfo", keys(r.groups), func(id string) string {
+			group := r.groups[id]
+			permissions := ""
+			for _, ref := range group.refs {
+				permissions += fmt.Sprintf("<item><ipProtocol>-1</ipProtocol><groups><item><groupId>%s</groupId></item></groups></item>", ref)
+			}
+			return fmt.Sprintf("<groupId>%s</groupId><groupName>%s</groupName><vpcId>%s</vpcId><ipPermissions>%s</ipPermissions>", id, group.name, group.vpc, permissions)
+		}), nil
+	case "RevokeSecurityGroupIngress":
+		group := r.groups[get("GroupId")]
+		for i := 1; get(fmt.Sprintf("IpPermissions.%d.IpProtocol", i)) != ""; i++ {
+			for j := 1; get(fmt.Sprintf("IpPermissions.%d.Groups.%d.GroupId", i, j)) != ""; j++ {
+				group.refs = remove(group.refs, get(fmt.Sprintf("IpPermissions.%d.Groups.%d.GroupId", i, j)))
+			}
+		}
+		return "", nil
+	case "DeleteSecurityGroup":
+		id := get("GroupId")
+		for otherID, other := range r.groups {
+			for _, ref := range other.refs {
+				if ref == id && otherID != id {
+					return "", fmt.Errorf("DependencyViolation: group %s is referenced by %s", id, otherID)
+				}
+			}
+		}
+		delete(r.groups, id)
+		return "", nil
+	case "DescribeRouteTables":
+		return items("routeTableSet", keys(r.routeTables), func(id string) string {
+			table := r.routeTables[id]
+			associations := ""
+			if table.main {
+				associations = fmt.Sprintf("<item><routeTableAssociationId>rtbassoc-%s</routeTableAssociationId><main>true</main></item>", id)
+			}
+			for _, subnet := range table.subnets {
+				associations += fmt.Sprintf("<item><routeTableAssociationId>rtbassoc-%s</routeTableAssociationId><main>false</main></item>", subnet)
+			}
+			return fmt.Sprintf("<routeTableId>%s</routeTableId><vpcId>%s</vpcId><associationSet>%s</associationSet>", id, table.vpc, associations)
+		}), nil
+	case "DisassociateRouteTable":
+		subnet := strings.TrimPrefix(get("AssociationId"), "rtbassoc-")
+		for _, table := range r.routeTables {
+			table.subnets = remove(table.subnets, subnet)
+		}
+		return "", nil
+	case "DeleteRouteTable":
+		id := get("RouteTableId")
+		if table := r.routeTables[id]; table.main || len(table.subnets) > 0 {
+			return "", fmt.Errorf("DependencyViolation: route table %s has associations", id)
+		}
+		delete(r.routeTables, id)
+		return "", nil
+	case "DescribeVpcs":
+		return items("vpcSet", keys(r.vpcs), func(id string) string {
+			return fmt.Sprintf("<vpcId>%s</vpcId><isDefault>%t</isDefault>", id, r.vpcs[id])
+		}), nil
+	case "DeleteVpc":
+		id := get("VpcId")
+		if user := r.vpcUser(id); user != "" {
+			return "", fmt.Errorf("DependencyViolation: vpc %s is used by %s", id, user)
+		}
+		delete(r.vpcs, id)
+		for groupID, group := range r.groups {
+			if group.vpc == id {
+				delete(r.groups, groupID)
+			}
+		}
+		for tableID, table := range r.routeTables {
+			if table.vpc == id {
+				delete(r.routeTables, tableID)
+			}
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("InvalidAction: unexpected call %s", call)
+}
+
+func (r *fakeAWSRegion) terminate(id string) {
+	r.instances[id].state = "terminated"
+	for volume, instance := range r.volumes {
+		if instance == id {
+			r.volumes[volume] = ""
+		}
+	}
+}
+
+func (r *fakeAWSRegion) subnetUser(subnet string) string {
+	for id, instance := range r.instances {
+		if instance.subnet == subnet && instance.state != "terminated" {
+			return id
+		}
+	}
+	for id, nat := range r.natGateways {
+		if nat.subnet == subnet && nat.state != "deleted" {
+			return id
+		}
+	}
+	for name, lbSubnet := range r.loadBalancers {
+		if lbSubnet == subnet {
+			return name
+		}
+	}
+	return ""
+}
+
+func (r *fakeAWSRegion) vpcUser(vpc string) string {
+	for id, attached := range r.igws {
+		if attached == vpc {
+			return id
+		}
+	}
+	for id, subnet := range r.subnets {
+		if subnet.vpc == vpc {
+			return id
+		}
+	}
+	for id, group := range r.groups {
+		if group.vpc == vpc && group.name != "default" {
+			return id
+		}
+	}
+	for id, table := range r.routeTables {
+		if table.vpc == vpc && !table.main {
+			return id
+		}
+	}
+	return ""
+}
+
+func items(set string, ids []string, item func(id string) string) string {
+	var response []string
+	for _, id := range ids {
+		response = append(response, "<item>"+item(id)+"</item>")
+	}
+	return fmt.Sprintf("<%s>%s</%s>", set, strings.Join(response, ""), set)
+}
+
+func keys(m interface{}) []string {
+	var result []string
+	switch typed := m.(type) {
+	case map[string]*fakeAWSInstance:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]*fakeAWSNatGateway:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]fakeAWSSubnet:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]*fakeAWSGroup:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]*fakeAWSRouteTable:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]string:
+		for key := range typed {
+			result = append(result, key)
+		}
+	case map[string]bool:
+		for key := range typed {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func remove(ids []string, id string) []string {
+	var result []string
+	for _, other := range ids {
+		if other != id {
+			result = append(result, other)
+		}
+	}
+	return result
+}
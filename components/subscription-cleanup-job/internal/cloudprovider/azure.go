@@ -13,6 +13,7 @@ import (
 
 type azureResourceCleaner struct {
 	azureClient resources.GroupsClient
+	options     Options
 }
 
 type config struct {
@@ -23,7 +24,7 @@ type config struct {
 	userAgent      string
 }
 
-func NewAzureResourcesCleaner(secretData map[string][]byte) (ResourceCleaner, error) {
+func NewAzureResourcesCleaner(secretData map[string][]byte, options Options) (ResourceCleaner, error) {
 	config, err := toConfig(secretData)
 	if err != nil {
 		return nil, err
@@ -36,33 +37,83 @@ func NewAzureResourcesCleaner(secretData map[string][]byte) (ResourceCleaner, er
 
 	return &azureResourceCleaner{
 		azureClient: azureClient,
+		options:     options,
 	}, nil
 }
 
-func (ac azureResourceCleaner) Do() error {
+// Do deletes the resource groups of the subscription and reports the resource groups left
+func (ac azureResourceCleaner) Do() (Report, error) {
 	ctx := context.Background()
-	resourceGroups, err := ac.azureClient.List(ctx, "", nil)
+	report := Report{DryRun: ac.options.DryRun}
+
+	resourceGroups, err := ac.resourceGroups(ctx)
 	if err != nil {
-		return err
-	}
-
-	for _, resourceGroup := range resourceGroups.Values() {
-		if resourceGroup.Name != nil {
-			log.Infof("Deleting resource group '%s'", *resourceGroup.Name)
-			future, err := ac.azureClient.Delete(ctx, *resourceGroup.Name)
-			if err != nil {
-				log.Errorf("failed to init resource group '%s' deletion", *resourceGroup.Name)
-				continue
-			}
-
-			err = future.WaitForCompletionRef(ctx, ac.azureClient.Client)
-			if err != nil {
-				log.Errorf("failed to remove resource group '%s', %s: ", *resourceGroup.Name, err.Error())
-			}
+		return report, errors.Wrap(err, "while listing resource groups")
+	}
+
+	for _, resourceGroup := range resourceGroups {
+		resource := azureResourceID(resourceGroup)
+		if !ac.options.include(&report, resource, resourceGroup) {
+			continue
+		}
+
+		log.Infof("Deleting resource group '%s'", resourceGroup)
+		err := ac.deleteResourceGroup(ctx, resourceGroup)
+		if err != nil {
+			log.Errorf("failed to remove resource group '%s': %s", resourceGroup, err.Error())
+			report.failed(resource, err)
+			continue
 		}
+		report.deleted(resource)
+	}
+
+	if ac.options.DryRun {
+		return report, nil
 	}
 
-	return nil
+	resourceGroups, err = ac.resourceGroups(ctx)
+	if err != nil {
+		return report, errors.Wrap(err, "while verifying resource groups")
+	}
+	for _, resourceGroup := range resourceGroups {
+		if ac.options.Filter.Allowed(resourceGroup) {
+			report.remaining(azureResourceID(resourceGroup))
+		}
+	}
+
+	return report, nil
+}
+
+func (ac azureResourceCleaner) resourceGroups(ctx context.Context) ([]string, error) {
+	iterator, err := ac.azureClient.ListComplete(ctx, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for iterator.NotDone() {
+		if resourceGroup := iterator.Value(); resourceGroup.Name != nil {
+			names = append(names, *resourceGroup.Name)
+		}
+		if err := iterator.NextWithContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return names, nil
+}
+
+func (ac azureResourceCleaner) deleteResourceGroup(ctx context.Context, name string) error {
+	future, err := ac.azureClient.Delete(ctx, name)
+	if err != nil {
+		return errors.Wrap(err, "while initializing deletion")
+	}
+
+	return future.WaitForCompletionRef(ctx, ac.azureClient.Client)
+}
+
+func azureResourceID(resourceGroup string) string {
+	return "resourceGroups/" + resourceGroup
 }
 
 func toConfig(secretData map[string][]byte) (config, error) {
@@ -7,8 +7,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ResourceCleaner deletes the resources of the hyperscaler account. The error is returned only if the cleaner
+// could not check the account, failures of single resources are described by the report.
 type ResourceCleaner interface {
-	Do() error
+	Do() (Report, error)
 }
 
 //go:generate mockery -name=ProviderFactory
@@ -16,21 +18,29 @@ type ProviderFactory interface {
 	New(hyperscalerType model.HyperscalerType, secretData map[string][]byte) (ResourceCleaner, error)
 }
 
-type providerFactory struct{}
+type providerFactory struct {
+	options Options
+}
 
-func NewProviderFactory() ProviderFactory {
-	return &providerFactory{}
+func NewProviderFactory(options Options) ProviderFactory {
+	return &providerFactory{
+		options: options,
+	}
 }
 
 func (pf *providerFactory) New(hyperscalerType model.HyperscalerType, secretData map[string][]byte) (ResourceCleaner, error) {
 	switch hyperscalerType {
 	case model.GCP:
 		{
-			return NewGCPeResourcesCleaner(secretData), nil
+			return NewGCPResourcesCleaner(secretData, pf.options)
 		}
 	case model.Azure:
 		{
-			return NewAzureResourcesCleaner(secretData)
+			return NewAzureResourcesCleaner(secretData, pf.options)
+		}
+	case model.AWS:
+		{
+			return NewAWSResourcesCleaner(secretData, pf.options)
 		}
 	default:
 		return nil, errors.New(fmt.Sprintf("unknown hyperscaler type"))
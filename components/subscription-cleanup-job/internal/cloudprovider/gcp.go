@@ -1,12 +1,245 @@
 package cloudprovider
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpServiceAccountKey = "serviceaccount.json"
+	gcpComputeScope      = "https://www.googleapis.com/auth/compute"
+	gcpComputeEndpoint   = "https://compute.googleapis.com/compute/v1/"
+
+	gcpOperationPollInterval = 2 * time.Second
+	gcpOperationTimeout      = 10 * time.Minute
+)
+
+type gcpScope int
+
+const (
+	gcpZonal gcpScope = iota
+	gcpRegional
+	gcpGlobal
+)
+
+// gcpResourceKind describes the collection of Compute Engine resources deleted by the cleaner
+type gcpResourceKind struct {
+	name  string
+	scope gcpScope
+	// managed returns true for resources which are deleted by GCP together with the resources they belong to
+	managed func(resource gcpResource, autoNetworks map[string]bool) bool
+}
+
+// gcpResourceKinds are ordered, so that the resources are deleted before the resources they depend on
+var gcpResourceKinds = []gcpResourceKind{
+	{name: "instances", scope: gcpZonal},
+	{name: "disks", scope: gcpZonal},
+	{name: "forwardingRules", scope: gcpRegional},
+	{name: "targetPools", scope: gcpRegional},
+	{name: "firewalls", scope: gcpGlobal},
+	{name: "routes", scope: gcpGlobal, managed: func(resource gcpResource, _ map[string]bool) bool {
+		// routes of the subnetworks and peerings cannot be deleted, they are removed with the network
+		return resource.NextHopNetwork != "" || resource.NextHopPeering != ""
+	}},
+	{name: "subnetworks", scope: gcpRegional, managed: func(resource gcpResource, autoNetworks map[string]bool) bool {
+		// subnetworks of the auto mode networks cannot be deleted, they are removed with the network
+		return autoNetworks[resource.Network]
+	}},
+	{name: "networks", scope: gcpGlobal},
+}
+
 type gcpResourceCleaner struct {
+	compute      *gcpComputeClient
+	options      Options
+	pollInterval time.Duration
+	timeout      time.Duration
 }
 
-func NewGCPeResourcesCleaner(secretData map[string][]byte) ResourceCleaner {
-	return &gcpResourceCleaner{}
+type gcpConfig struct {
+	projectID string
+	key       []byte
 }
 
-func (rc gcpResourceCleaner) Do() error {
+func NewGCPResourcesCleaner(secretData map[string][]byte, options Options) (ResourceCleaner, error) {
+	config, err := toGCPConfig(secretData)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtConfig, err := google.JWTConfigFromJSON(config.key, gcpComputeScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "while creating GCP credentials")
+	}
+
+	return newGCPResourcesCleaner(jwtConfig.Client(context.Background()), gcpComputeEndpoint, config.projectID, options), nil
+}
+
+func newGCPResourcesCleaner(httpClient *http.Client, endpoint, projectID string, options Options) *gcpResourceCleaner {
+	return &gcpResourceCleaner{
+		compute: &gcpComputeClient{
+			httpClient: httpClient,
+			endpoint:   strings.TrimSuffix(endpoint, "/") + "/",
+			projectID:  projectID,
+		},
+		options:      options,
+		pollInterval: gcpOperationPollInterval,
+		timeout:      gcpOperationTimeout,
+	}
+}
+
+// Do deletes the Compute Engine resources of the project and reports the resources left
+func (rc gcpResourceCleaner) Do() (Report, error) {
+	ctx := context.Background()
+	report := Report{DryRun: rc.options.DryRun}
+
+	autoNetworks, err := rc.autoNetworks(ctx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, kind := range gcpResourceKinds {
+		resources, err := rc.compute.list(ctx, kind)
+		if err != nil {
+			return report, errors.Wrapf(err, "while listing %s", kind.name)
+		}
+		rc.deleteResources(ctx, kind, resources, autoNetworks, &report)
+	}
+
+	if rc.options.DryRun {
+		return report, nil
+	}
+
+	return report, rc.verify(ctx, &report)
+}
+
+func (rc gcpResourceCleaner) autoNetworks(ctx context.Context) (map[string]bool, error) {
+	networks, err := rc.compute.list(ctx, gcpResourceKind{name: "networks", scope: gcpGlobal})
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing networks")
+	}
+	autoNetworks := make(map[string]bool)
+	for _, network := range networks {
+		if network.AutoCreateSubnetworks {
+			autoNetworks[network.SelfLink] = true
+		}
+	}
+
+	return autoNetworks, nil
+}
+
+// deleteResources deletes the resources of the given kind and waits until the deletion is finished,
+// failures are recorded in the report, so that the remaining resources can be deleted
+func (rc gcpResourceCleaner) deleteResources(ctx context.Context, kind gcpResourceKind, resources []gcpResource, autoNetworks map[string]bool, report *Report) {
+	var operations []gcpOperation
+	for _, resource := range resources {
+		if kind.managed != nil && kind.managed(resource, autoNetworks) {
+			continue
+		}
+		id := gcpResourceID(kind, resource.Name)
+		if !rc.options.include(report, id, resource.Name) {
+			continue
+		}
+		log.Infof("Deleting GCP %s '%s' in project '%s'", kind.name, resource.Name, rc.compute.projectID)
+		operation, err := rc.compute.delete(ctx, kind, resource)
+		if err != nil {
+			log.Errorf("failed to init deletion of %s '%s': %s", kind.name, resource.Name, err.Error())
+			report.failed(id, err)
+			continue
+		}
+		if operation == nil {
+			report.deleted(id)
+			continue
+		}
+		operations = append(operations, *operation)
+	}
+
+	for _, operation := range operations {
+		id := gcpResourceID(kind, operation.TargetName())
+		err := rc.waitForOperation(ctx, operation)
+		if err != nil {
+			log.Errorf("failed to delete %s '%s': %s", kind.name, operation.TargetName(), err.Error())
+			report.failed(id, err)
+			continue
+		}
+		report.deleted(id)
+	}
+}
+
+func (rc gcpResourceCleaner) waitForOperation(ctx context.Context, operation gcpOperation) error {
+	deadline := time.Now().Add(rc.timeout)
+	for {
+		if operation.Status == "DONE" {
+			return operation.Err()
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("operation %s not finished after %s", operation.Name, rc.timeout)
+		}
+		time.Sleep(rc.pollInterval)
+
+		current, err := rc.compute.getOperation(ctx, operation)
+		if err != nil {
+			return errors.Wrapf(err, "while getting operation %s", operation.Name)
+		}
+		operation = *current
+	}
+}
+
+// verify records the resources left in the project, resources excluded by the name filter and resources deleted
+// together with their networks are not reported
+func (rc gcpResourceCleaner) verify(ctx context.Context, report *Report) error {
+	autoNetworks, err := rc.autoNetworks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range gcpResourceKinds {
+		resources, err := rc.compute.list(ctx, kind)
+		if err != nil {
+			return errors.Wrapf(err, "while listing %s", kind.name)
+		}
+		for _, resource := range resources {
+			if kind.managed != nil && kind.managed(resource, autoNetworks) {
+				continue
+			}
+			if rc.options.Filter.Allowed(resource.Name) {
+				report.remaining(gcpResourceID(kind, resource.Name))
+			}
+		}
+	}
+
 	return nil
 }
+
+func gcpResourceID(kind gcpResourceKind, name string) string {
+	return fmt.Sprintf("%s/%s", kind.name, name)
+}
+
+func toGCPConfig(secretData map[string][]byte) (gcpConfig, error) {
+	key, exists := secretData[gcpServiceAccountKey]
+	if !exists {
+		return gcpConfig{}, errors.Errorf("%s not provided in the secret", gcpServiceAccountKey)
+	}
+
+	serviceAccount := struct {
+		ProjectID string `json:"project_id"`
+	}{}
+	if err := json.Unmarshal(key, &serviceAccount); err != nil {
+		return gcpConfig{}, errors.Wrap(err, "while unmarshalling service account key")
+	}
+	if serviceAccount.ProjectID == "" {
+		return gcpConfig{}, errors.New("project_id not provided in the service account key")
+	}
+
+	return gcpConfig{
+		projectID: serviceAccount.ProjectID,
+		key:       key,
+	}, nil
+}
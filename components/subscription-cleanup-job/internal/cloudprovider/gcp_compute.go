@@ -0,0 +1,213 @@
+package cloudprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// gcpComputeClient calls the Compute Engine REST API, only the calls required by the cleaner are implemented
+type gcpComputeClient struct {
+	httpClient *http.Client
+	endpoint   string
+	projectID  string
+}
+
+type gcpResource struct {
+	Name                  string `json:"name"`
+	SelfLink              string `json:"selfLink"`
+	Zone                  string `json:"zone"`
+	Region                string `json:"region"`
+	Network               string `json:"network"`
+	NextHopNetwork        string `json:"nextHopNetwork"`
+	NextHopPeering        string `json:"nextHopPeering"`
+	AutoCreateSubnetworks bool   `json:"autoCreateSubnetworks"`
+}
+
+type gcpOperation struct {
+	Name       string `json:"name"`
+	Zone       string `json:"zone"`
+	Region     string `json:"region"`
+	Status     string `json:"status"`
+	TargetLink string `json:"targetLink"`
+	Error      *struct {
+		Errors []gcpOperationError `json:"errors"`
+	} `json:"error"`
+}
+
+type gcpOperationError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type gcpErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// TargetName returns the name of the resource the operation was run for
+func (o gcpOperation) TargetName() string {
+	return path.Base(o.TargetLink)
+}
+
+// Err returns the error of the finished operation
+func (o gcpOperation) Err() error {
+	if o.Error == nil || len(o.Error.Errors) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, e := range o.Error.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", e.Code, e.Message))
+	}
+	return errors.New(strings.Join(messages, ", "))
+}
+
+func (c *gcpComputeClient) list(ctx context.Context, kind gcpResourceKind) ([]gcpResource, error) {
+	var resources []gcpResource
+	pageToken := ""
+	for {
+		query := url.Values{}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		var nextPageToken string
+		var err error
+		if kind.scope == gcpGlobal {
+			page := struct {
+				Items         []gcpResource `json:"items"`
+				NextPageToken string        `json:"nextPageToken"`
+			}{}
+			err = c.call(ctx, http.MethodGet, c.projectURL("global", kind.name)+"?"+query.Encode(), &page)
+			resources = append(resources, page.Items...)
+			nextPageToken = page.NextPageToken
+		} else {
+			// aggregated list returns the resources grouped by zones or regions, e.g. {"zones/europe-west1-b": {"disks": [...]}}
+			page := struct {
+				Items         map[string]map[string]json.RawMessage `json:"items"`
+				NextPageToken string                                `json:"nextPageToken"`
+			}{}
+			err = c.call(ctx, http.MethodGet, c.projectURL("aggregated", kind.name)+"?"+query.Encode(), &page)
+			for scope, scoped := range page.Items {
+				raw, found := scoped[kind.name]
+				if !found {
+					continue
+				}
+				var items []gcpResource
+				if err := json.Unmarshal(raw, &items); err != nil {
+					return nil, errors.Wrapf(err, "while decoding %s in %s", kind.name, scope)
+				}
+				resources = append(resources, items...)
+			}
+			nextPageToken = page.NextPageToken
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if nextPageToken == "" {
+			return resources, nil
+		}
+		pageToken = nextPageToken
+	}
+}
+
+// delete starts the deletion of the resource, nil operation is returned if the resource does not exist
+func (c *gcpComputeClient) delete(ctx context.Context, kind gcpResourceKind, resource gcpResource) (*gcpOperation, error) {
+	operation := &gcpOperation{}
+	err := c.call(ctx, http.MethodDelete, c.resourceURL(kind.scope, resource.Zone, resource.Region, kind.name, resource.Name), operation)
+	if isGCPNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return operation, nil
+}
+
+func (c *gcpComputeClient) getOperation(ctx context.Context, operation gcpOperation) (*gcpOperation, error) {
+	scope := gcpGlobal
+	switch {
+	case operation.Zone != "":
+		scope = gcpZonal
+	case operation.Region != "":
+		scope = gcpRegional
+	}
+
+	current := &gcpOperation{}
+	err := c.call(ctx, http.MethodGet, c.resourceURL(scope, operation.Zone, operation.Region, "operations", operation.Name), current)
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+func (c *gcpComputeClient) projectURL(elements ...string) string {
+	return c.endpoint + path.Join(append([]string{"projects", c.projectID}, elements...)...)
+}
+
+// resourceURL builds the URL of the resource, zone and region are given as URLs returned by the API
+func (c *gcpComputeClient) resourceURL(scope gcpScope, zone, region, collection, name string) string {
+	switch scope {
+	case gcpZonal:
+		return c.projectURL("zones", path.Base(zone), collection, name)
+	case gcpRegional:
+		return c.projectURL("regions", path.Base(region), collection, name)
+	default:
+		return c.projectURL("global", collection, name)
+	}
+}
+
+func (c *gcpComputeClient) call(ctx context.Context, method, address string, result interface{}) error {
+	request, err := http.NewRequest(method, address, nil)
+	if err != nil {
+		return errors.Wrap(err, "while creating request")
+	}
+
+	response, err := c.httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return errors.Wrapf(err, "while calling %s %s", method, address)
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, response.Body)
+		response.Body.Close()
+	}()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		errorResponse := gcpErrorResponse{}
+		_ = json.NewDecoder(response.Body).Decode(&errorResponse)
+		return &gcpAPIError{statusCode: response.StatusCode, message: errorResponse.Error.Message}
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(result); err != nil {
+		return errors.Wrapf(err, "while decoding response of %s %s", method, address)
+	}
+
+	return nil
+}
+
+type gcpAPIError struct {
+	statusCode int
+	message    string
+}
+
+func (e *gcpAPIError) Error() string {
+	return fmt.Sprintf("GCP API responded with status %d: %s", e.statusCode, e.message)
+}
+
+func isGCPNotFound(err error) bool {
+	apiErr, ok := err.(*gcpAPIError)
+	return ok && apiErr.statusCode == http.StatusNotFound
+}
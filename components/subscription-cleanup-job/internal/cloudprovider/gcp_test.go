@@ -0,0 +1,408 @@
+package cloudprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	gcpTestProject = "test-project"
+	gcpTestZone    = "europe-west1-b"
+	gcpTestRegion  = "europe-west1"
+)
+
+func TestGCPResourceCleaner(t *testing.T) {
+	t.Run("should delete all resources of the project", func(t *testing.T) {
+		// given
+		gcp := newFakeGCP(t)
+		defer gcp.Close()
+		gcp.addShootResources()
+		cleaner := gcp.cleaner(Options{})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.Clean())
+		assert.Equal(t, report.Found, report.Deleted)
+		assert.Empty(t, gcp.resourcesLeft())
+		assert.Equal(t, []string{
+			"instances/vm-1",
+			"disks/disk-1",
+			"disks/disk-2",
+			"forwardingRules/lb-1",
+			"targetPools/pool-1",
+			"firewalls/fw-1",
+			"routes/route-1",
+			"subnetworks/shoot-nodes",
+			"networks/default",
+			"networks/shoot-net",
+		}, gcp.deletedResources())
+	})
+
+	t.Run("should return error when resources are left", func(t *testing.T) {
+		// given
+		gcp := newFakeGCP(t)
+		defer gcp.Close()
+		gcp.addShootResources()
+		gcp.failDeletion("firewalls/fw-1")
+		cleaner := gcp.cleaner(Options{})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, report.Clean())
+		assert.Equal(t, "INTERNAL_ERROR: deletion failed", report.Failed["firewalls/fw-1"])
+		assert.Contains(t, report.Failed["networks/shoot-net"], "networks/shoot-net is used by firewalls/fw-1")
+		assert.Equal(t, []string{"firewalls/fw-1", "networks/shoot-net"}, report.Remaining)
+		assert.Contains(t, report.Deleted, "instances/vm-1")
+	})
+
+	t.Run("should succeed for empty project", func(t *testing.T) {
+		// given
+		gcp := newFakeGCP(t)
+		defer gcp.Close()
+		cleaner := gcp.cleaner(Options{})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.Clean())
+		assert.Empty(t, gcp.deletedResources())
+	})
+
+	t.Run("should only list resources in dry run", func(t *testing.T) {
+		// given
+		gcp := newFakeGCP(t)
+		defer gcp.Close()
+		gcp.addShootResources()
+		cleaner := gcp.cleaner(Options{DryRun: true})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, report.Clean())
+		assert.Empty(t, gcp.deletedResources())
+		assert.Len(t, report.Found, 10)
+		assert.Contains(t, report.Found, "networks/shoot-net")
+		assert.Empty(t, report.Remaining)
+	})
+
+	t.Run("should keep resources excluded by the name filter", func(t *testing.T) {
+		// given
+		gcp := newFakeGCP(t)
+		defer gcp.Close()
+		gcp.addShootResources()
+		filter, err := NewNameFilter(nil, []string{"shoot-*"})
+		require.NoError(t, err)
+		cleaner := gcp.cleaner(Options{Filter: filter})
+
+		// when
+		report, err := cleaner.Do()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, report.Clean())
+		assert.Equal(t, []string{"subnetworks/shoot-nodes", "networks/shoot-net"}, report.Skipped)
+		assert.Equal(t, []string{"networks/shoot-net", "routes/shoot-subnet-route", "subnetworks/shoot-nodes"}, gcp.resourcesLeft())
+	})
+}
+
+func TestToGCPConfig(t *testing.T) {
+	t.Run("should parse service account key", func(t *testing.T) {
+		// when
+		config, err := toGCPConfig(map[string][]byte{
+			"serviceaccount.json": []byte(`{"type": "service_account", "project_id": "test-project"}`),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "test-project", config.projectID)
+	})
+
+	for name, secretData := range map[string]map[string][]byte{
+		"missing key":        {"credentials": []byte("secret")},
+		"invalid key":        {"serviceaccount.json": []byte("not json")},
+		"missing project ID": {"serviceaccount.json": []byte(`{"type": "service_account"}`)},
+	} {
+		t.Run(fmt.Sprintf("should fail for %s", name), func(t *testing.T) {
+			// when
+			_, err := toGCPConfig(secretData)
+
+			// then
+			assert.Error(t, err)
+		})
+	}
+}
+
+// fakeGCP implements the parts of the Compute Engine API used by the cleaner. Resources in use by other
+// resources cannot be deleted and deleting a network removes its auto subnetworks and routes.
+type fakeGCP struct {
+	*httptest.Server
+	t *testing.T
+
+	mu         sync.Mutex
+	resources  map[string]*fakeGCPResource
+	failing    map[string]bool
+	deleted    []string
+	operations map[string]gcpOperation
+}
+
+type fakeGCPResource struct {
+	kind     string
+	scope    gcpScope
+	resource gcpResource
+	// uses are resources which cannot be deleted before this resource
+	uses []string
+}
+
+func newFakeGCP(t *testing.T) *fakeGCP {
+	gcp := &fakeGCP{
+		t:          t,
+		resources:  make(map[string]*fakeGCPResource),
+		failing:    make(map[string]bool),
+		operations: make(map[string]gcpOperation),
+	}
+	gcp.Server = httptest.NewServer(http.HandlerFunc(gcp.handle))
+	return gcp
+}
+
+func (f *fakeGCP) cleaner(options Options) *gcpResourceCleaner {
+	cleaner := newGCPResourcesCleaner(http.DefaultClient, f.URL+"/compute/v1/", gcpTestProject, options)
+	cleaner.pollInterval = time.Millisecond
+	return cleaner
+}
+
+func (f *fakeGCP) addShootResources() {
+	f.add("networks", gcpGlobal, gcpResource{Name: "default", AutoCreateSubnetworks: true})
+	f.add("subnetworks", gcpRegional, gcpResource{Name: "default", Network: f.selfLink("networks", "default")})
+	f.add("routes", gcpGlobal, gcpResource{Name: "default-subnet-route", NextHopNetwork: f.selfLink("networks", "default")})
+
+	f.add("networks", gcpGlobal, gcpResource{Name: "shoot-net"})
+	f.add("subnetworks", gcpRegional, gcpResource{Name: "shoot-nodes", Network: f.selfLink("networks", "shoot-net")}, "networks/shoot-net")
+	f.add("routes", gcpGlobal, gcpResource{Name: "shoot-subnet-route", NextHopNetwork: f.selfLink("networks", "shoot-net")})
+	f.add("routes", gcpGlobal, gcpResource{Name: "route-1", Network: f.selfLink("networks", "shoot-net")}, "networks/shoot-net")
+	f.add("firewalls", gcpGlobal, gcpResource{Name: "fw-1", Network: f.selfLink("networks", "shoot-net")}, "networks/shoot-net")
+	f.add("targetPools", gcpRegional, gcpResource{Name: "pool-1"})
+	f.add("forwardingRules", gcpRegional, gcpResource{Name: "lb-1"}, "targetPools/pool-1")
+	f.add("disks", gcpZonal, gcpResource{Name: "disk-1"})
+	f.add("disks", gcpZonal, gcpResource{Name: "disk-2"})
+	f.add("instances", gcpZonal, gcpResource{Name: "vm-1"}, "disks/disk-1", "subnetworks/shoot-nodes", "networks/shoot-net")
+}
+
+func (f *fakeGCP) add(kind string, scope gcpScope, resource gcpResource, uses ...string) {
+	resource.SelfLink = f.selfLink(kind, resource.Name)
+	switch scope {
+	case gcpZonal:
+		resource.Zone = fmt.Sprintf("%s/compute/v1/projects/%s/zones/%s", f.URL, gcpTestProject, gcpTestZone)
+	case gcpRegional:
+		resource.Region = fmt.Sprintf("%s/compute/v1/projects/%s/regions/%s", f.URL, gcpTestProject, gcpTestRegion)
+	}
+	f.resources[kind+"/"+resource.Name] = &fakeGCPResource{kind: kind, scope: scope, resource: resource, uses: uses}
+}
+
+func (f *fakeGCP) selfLink(kind, name string) string {
+	return fmt.Sprintf("%s/compute/v1/projects/%s/global/%s/%s", f.URL, gcpTestProject, kind, name)
+}
+
+func (f *fakeGCP) failDeletion(id string) {
+	f.failing[id] = true
+}
+
+func (f *fakeGCP) resourcesLeft() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var left []string
+	for id := range f.resources {
+		left = append(left, id)
+	}
+	sort.Strings(left)
+	return left
+}
+
+func (f *fakeGCP) deletedResources() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.deleted...)
+}
+
+func (f *fakeGCP) handle(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := fmt.Sprintf("/compute/v1/projects/%s/", gcpTestProject)
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		f.writeError(w, http.StatusNotFound, "unknown project")
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "aggregated":
+		f.writeList(w, r, parts[1], true)
+	case r.Method == http.MethodGet && len(parts) == 2 && parts[0] == "global":
+		f.writeList(w, r, parts[1], false)
+	case r.Method == http.MethodGet && len(parts) >= 3 && parts[len(parts)-2] == "operations":
+		f.writeOperation(w, parts[len(parts)-1])
+	case r.Method == http.MethodDelete && len(parts) >= 3:
+		f.delete(w, parts[len(parts)-2], parts[len(parts)-1])
+	default:
+		f.t.Errorf("unexpected call %s %s", r.Method, r.URL.Path)
+		f.writeError(w, http.StatusBadRequest, "unexpected call")
+	}
+}
+
+// writeList returns the resources two per page to test the paging
+func (f *fakeGCP) writeList(w http.ResponseWriter, r *http.Request, kind string, aggregated bool) {
+	var items []gcpResource
+	for _, id := range f.sortedIDs() {
+		if res := f.resources[id]; res.kind == kind {
+			items = append(items, res.resource)
+		}
+	}
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("pageToken"))
+	end := start + 2
+	nextPageToken := strconv.Itoa(end)
+	if end >= len(items) {
+		end = len(items)
+		nextPageToken = ""
+	}
+	items = items[start:end]
+
+	var response interface{}
+	if aggregated {
+		scoped := map[string]map[string]interface{}{
+			"zones/" + gcpTestZone:     {"warning": map[string]string{"code": "NO_RESULTS_ON_PAGE"}},
+			"regions/" + gcpTestRegion: {"warning": map[string]string{"code": "NO_RESULTS_ON_PAGE"}},
+		}
+		for _, item := range items {
+			scope := "regions/" + gcpTestRegion
+			if item.Zone != "" {
+				scope = "zones/" + gcpTestZone
+			}
+			list, _ := scoped[scope][kind].([]gcpResource)
+			scoped[scope] = map[string]interface{}{kind: append(list, item)}
+		}
+		response = map[string]interface{}{"items": scoped, "nextPageToken": nextPageToken}
+	} else {
+		response = map[string]interface{}{"items": items, "nextPageToken": nextPageToken}
+	}
+	f.writeJSON(w, http.StatusOK, response)
+}
+
+func (f *fakeGCP) delete(w http.ResponseWriter, kind, name string) {
+	id := kind + "/" + name
+	res, found := f.resources[id]
+	if !found {
+		f.writeError(w, http.StatusNotFound, fmt.Sprintf("%s not found", id))
+		return
+	}
+	if kind == "subnetworks" || kind == "routes" {
+		if network, found := f.resources["networks/"+pathBase(res.resource.Network)]; found && network.resource.AutoCreateSubnetworks || res.resource.NextHopNetwork != "" {
+			f.t.Errorf("%s is managed by the network and cannot be deleted", id)
+			f.writeError(w, http.StatusBadRequest, "resource managed by the network")
+			return
+		}
+	}
+	for otherID, other := range f.resources {
+		for _, used := range other.uses {
+			if used == id {
+				f.writeError(w, http.StatusBadRequest, fmt.Sprintf("%s is used by %s", id, otherID))
+				return
+			}
+		}
+	}
+
+	operation := gcpOperation{
+		Name:       fmt.Sprintf("operation-%d", len(f.operations)+1),
+		Zone:       res.resource.Zone,
+		Region:     res.resource.Region,
+		Status:     "RUNNING",
+		TargetLink: res.resource.SelfLink,
+	}
+	done := operation
+	done.Status = "DONE"
+	if f.failing[id] {
+		done.Error = &struct {
+			Errors []gcpOperationError `json:"errors"`
+		}{Errors: []gcpOperationError{{Code: "INTERNAL_ERROR", Message: "deletion failed"}}}
+	} else {
+		f.remove(id)
+	}
+	f.operations[operation.Name] = done
+
+	f.writeJSON(w, http.StatusOK, operation)
+}
+
+func (f *fakeGCP) remove(id string) {
+	res := f.resources[id]
+	delete(f.resources, id)
+	f.deleted = append(f.deleted, id)
+	if res.kind != "networks" {
+		return
+	}
+	for otherID, other := range f.resources {
+		if other.resource.Network == res.resource.SelfLink && other.kind == "subnetworks" && res.resource.AutoCreateSubnetworks ||
+			other.resource.NextHopNetwork == res.resource.SelfLink {
+			delete(f.resources, otherID)
+		}
+	}
+}
+
+func (f *fakeGCP) writeOperation(w http.ResponseWriter, name string) {
+	operation, found := f.operations[name]
+	if !found {
+		f.writeError(w, http.StatusNotFound, fmt.Sprintf("operation %s not found", name))
+		return
+	}
+	f.writeJSON(w, http.StatusOK, operation)
+}
+
+func (f *fakeGCP) sortedIDs() []string {
+	var ids []string
+	for id := range f.resources {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (f *fakeGCP) writeError(w http.ResponseWriter, status int, message string) {
+	response := gcpErrorResponse{}
+	response.Error.Code = status
+	response.Error.Message = message
+	f.writeJSON(w, status, response)
+}
+
+func (f *fakeGCP) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		f.t.Errorf("cannot encode response: %s", err)
+	}
+}
+
+func pathBase(link string) string {
+	return link[strings.LastIndex(link, "/")+1:]
+}
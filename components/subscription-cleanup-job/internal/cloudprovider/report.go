@@ -0,0 +1,139 @@
+package cloudprovider
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Options configure all resource cleaners created by the ProviderFactory
+type Options struct {
+	// DryRun only lists the resources which would be deleted
+	DryRun bool
+	Filter NameFilter
+	// AWSRegions are the regions cleaned in AWS accounts, all regions enabled for the account are cleaned if empty
+	AWSRegions []string
+}
+
+// include records the found resource in the report and returns true if the resource should be deleted
+func (o Options) include(report *Report, resource, name string) bool {
+	if !o.Filter.Allowed(name) {
+		log.Infof("Skipping '%s' excluded by the resource name filter", resource)
+		report.skipped(resource)
+		return false
+	}
+	report.found(resource)
+	if o.DryRun {
+		log.Infof("Dry run, '%s' would be deleted", resource)
+		return false
+	}
+	return true
+}
+
+// NameFilter decides which resources can be deleted based on their names. Patterns use the path.Match syntax,
+// e.g. "shoot--*". A resource can be deleted if it matches any allow pattern (or no allow patterns are given)
+// and does not match any deny pattern.
+type NameFilter struct {
+	allow []string
+	deny  []string
+}
+
+func NewNameFilter(allow, deny []string) (NameFilter, error) {
+	for _, pattern := range append(append([]string{}, allow...), deny...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return NameFilter{}, errors.Wrapf(err, "invalid resource name pattern %q", pattern)
+		}
+	}
+
+	return NameFilter{allow: allow, deny: deny}, nil
+}
+
+// Allowed returns true if the resource with the given name can be deleted
+func (f NameFilter) Allowed(name string) bool {
+	for _, pattern := range f.deny {
+		if matches(pattern, name) {
+			return false
+		}
+	}
+	if len(f.allow) == 0 {
+		return true
+	}
+	for _, pattern := range f.allow {
+		if matches(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(pattern, name string) bool {
+	// patterns are validated by NewNameFilter
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// Report summarizes the work of the ResourceCleaner, resources are identified in the format specific for the provider,
+// e.g. "<kind>/<name>"
+type Report struct {
+	DryRun bool
+	// Found are the resources which were found and allowed by the NameFilter
+	Found []string
+	// Skipped are the resources which were found, but not allowed by the NameFilter
+	Skipped []string
+	Deleted []string
+	// Failed contains the deletion error for every resource which could not be deleted
+	Failed map[string]string
+	// Remaining are the allowed resources which still exist after the deletion
+	Remaining []string
+}
+
+func (r *Report) found(resource string) {
+	r.Found = append(r.Found, resource)
+}
+
+func (r *Report) skipped(resource string) {
+	r.Skipped = append(r.Skipped, resource)
+}
+
+func (r *Report) deleted(resource string) {
+	r.Deleted = append(r.Deleted, resource)
+}
+
+func (r *Report) failed(resource string, err error) {
+	if r.Failed == nil {
+		r.Failed = make(map[string]string)
+	}
+	r.Failed[resource] = err.Error()
+}
+
+func (r *Report) remaining(resource string) {
+	r.Remaining = append(r.Remaining, resource)
+}
+
+// Clean returns true if all found resources were deleted, the dry run report is never clean
+func (r Report) Clean() bool {
+	return !r.DryRun && len(r.Failed) == 0 && len(r.Remaining) == 0
+}
+
+// Err returns the error describing why the report is not clean
+func (r Report) Err() error {
+	if r.DryRun {
+		return errors.Errorf("dry run, %d resource(s) would be deleted", len(r.Found))
+	}
+	if r.Clean() {
+		return nil
+	}
+
+	var failed []string
+	for resource, err := range r.Failed {
+		failed = append(failed, fmt.Sprintf("%s (%s)", resource, err))
+	}
+	sort.Strings(failed)
+
+	return errors.Errorf("%d resource(s) failed to delete: [%s], %d resource(s) left: [%s]",
+		len(failed), strings.Join(failed, ", "), len(r.Remaining), strings.Join(r.Remaining, ", "))
+}
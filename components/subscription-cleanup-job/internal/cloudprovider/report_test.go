@@ -0,0 +1,85 @@
+package cloudprovider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameFilter_Allowed(t *testing.T) {
+	for name, tc := range map[string]struct {
+		allow    []string
+		deny     []string
+		resource string
+		allowed  bool
+	}{
+		"no patterns":             {resource: "shoot--kyma--c-1", allowed: true},
+		"allowed":                 {allow: []string{"shoot--*"}, resource: "shoot--kyma--c-1", allowed: true},
+		"not allowed":             {allow: []string{"shoot--*"}, resource: "shared-vpc", allowed: false},
+		"denied":                  {deny: []string{"shared-*"}, resource: "shared-vpc", allowed: false},
+		"deny overrides allow":    {allow: []string{"*"}, deny: []string{"shared-*"}, resource: "shared-vpc", allowed: false},
+		"any allow pattern":       {allow: []string{"shoot--*", "vpc-*"}, resource: "vpc-1", allowed: true},
+		"exact name":              {deny: []string{"default"}, resource: "default", allowed: false},
+		"pattern matches exactly": {deny: []string{"default"}, resource: "default-route", allowed: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			filter, err := NewNameFilter(tc.allow, tc.deny)
+			require.NoError(t, err)
+
+			// when
+			allowed := filter.Allowed(tc.resource)
+
+			// then
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+func TestNewNameFilter(t *testing.T) {
+	// when
+	_, err := NewNameFilter([]string{"shoot--*"}, []string{"[shared"})
+
+	// then
+	assert.EqualError(t, err, `invalid resource name pattern "[shared": syntax error in pattern`)
+}
+
+func TestReport_Err(t *testing.T) {
+	for name, tc := range map[string]struct {
+		report Report
+		err    string
+	}{
+		"clean": {
+			report: Report{Found: []string{"a"}, Deleted: []string{"a"}},
+		},
+		"dry run": {
+			report: Report{DryRun: true, Found: []string{"a", "b"}},
+			err:    "dry run, 2 resource(s) would be deleted",
+		},
+		"not clean": {
+			report: Report{
+				Found:     []string{"a", "b", "c"},
+				Deleted:   []string{"a"},
+				Failed:    map[string]string{"c": "in use", "b": "timeout"},
+				Remaining: []string{"b", "c"},
+			},
+			err: "2 resource(s) failed to delete: [b (timeout), c (in use)], 2 resource(s) left: [b, c]",
+		},
+	} {
+		t.Run(fmt.Sprintf("should describe %s report", name), func(t *testing.T) {
+			// when
+			err := tc.report.Err()
+
+			// then
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.True(t, tc.report.Clean())
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.False(t, tc.report.Clean())
+			}
+		})
+	}
+}
@@ -49,11 +49,20 @@ func (p *cleaner) Do() error {
 	}
 
 	for _, secretBinding := range secretBindings {
-		err := p.releaseResources(secretBinding)
+		report, err := p.releaseResources(secretBinding)
 		if err != nil {
 			logrus.Errorf("Failed to release resources for '%s' secret binding: %s", secretBinding.Name, err.Error())
 			continue
 		}
+		logReport(secretBinding, report)
+		if report.DryRun {
+			logrus.Infof("Dry run, '%s' secret binding is not returned to the pool", secretBinding.Name)
+			continue
+		}
+		if err := report.Err(); err != nil {
+			logrus.Errorf("Resources left for '%s' secret binding, keeping it dirty: %s", secretBinding.Name, err.Error())
+			continue
+		}
 		err = p.returnSecretBindingToThePool(secretBinding)
 		if err != nil {
 			logrus.Errorf("Failed returning '%s' secret binding to the pool: %s", secretBinding.Name, err.Error())
@@ -66,25 +75,37 @@ func (p *cleaner) Do() error {
 	return nil
 }
 
-func (p *cleaner) releaseResources(secretBinding v1beta1.SecretBinding) error {
+func (p *cleaner) releaseResources(secretBinding v1beta1.SecretBinding) (cloudprovider.Report, error) {
 	hyperscalerType, err := model.NewHyperscalerType(secretBinding.Labels["hyperscalerType"])
 	if err != nil {
-		return errors.Wrap(err, "starting releasing resources")
+		return cloudprovider.Report{}, errors.Wrap(err, "starting releasing resources")
 	}
 
 	secret, err := p.getBoundSecret(secretBinding)
 	if err != nil {
-		return errors.Wrap(err, "getting referenced secret")
+		return cloudprovider.Report{}, errors.Wrap(err, "getting referenced secret")
 	}
 
 	cleaner, err := p.providerFactory.New(hyperscalerType, secret.Data)
 	if err != nil {
-		return errors.Wrap(err, "initializing cloud provider cleaner")
+		return cloudprovider.Report{}, errors.Wrap(err, "initializing cloud provider cleaner")
 	}
 
 	return cleaner.Do()
 }
 
+func logReport(secretBinding v1beta1.SecretBinding, report cloudprovider.Report) {
+	logrus.WithFields(logrus.Fields{
+		"secretBinding": secretBinding.Name,
+		"dryRun":        report.DryRun,
+		"found":         len(report.Found),
+		"skipped":       len(report.Skipped),
+		"deleted":       len(report.Deleted),
+		"failed":        len(report.Failed),
+		"remaining":     len(report.Remaining),
+	}).Info("Resources cleanup report")
+}
+
 func (p *cleaner) getBoundSecret(secretBinding v1beta1.SecretBinding) (*apiv1.Secret, error) {
 	secret, err := p.kubernetesInterface.CoreV1().
 		Secrets(secretBinding.SecretRef.Namespace).
@@ -7,6 +7,7 @@ import (
 	gardener_types "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	gardener_fake "github.com/gardener/gardener/pkg/client/core/clientset/versioned/fake"
 
+	"github.com/kyma-project/control-plane/components/subscription-cleanup-job/internal/cloudprovider"
 	"github.com/kyma-project/control-plane/components/subscription-cleanup-job/internal/cloudprovider/mocks"
 	"github.com/kyma-project/control-plane/components/subscription-cleanup-job/internal/model"
 	"github.com/stretchr/testify/assert"
@@ -55,7 +56,9 @@ func TestCleanerJob(t *testing.T) {
 		gardenerFake := gardener_fake.NewSimpleClientset(secretBinding)
 		mockSecretBindings := gardenerFake.CoreV1beta1().SecretBindings(namespace)
 
-		resCleaner := &azureMockResourceCleaner{}
+		resCleaner := &azureMockResourceCleaner{
+			report: cloudprovider.Report{Found: []string{"resourceGroups/rg1"}, Deleted: []string{"resourceGroups/rg1"}},
+		}
 		providerFactory := &mocks.ProviderFactory{}
 		providerFactory.On("New", model.Azure, mock.Anything).Return(resCleaner, nil)
 
@@ -72,12 +75,72 @@ func TestCleanerJob(t *testing.T) {
 		assert.Equal(t, "", cleanedSecretBinding.Labels["dirty"])
 		assert.Equal(t, "", cleanedSecretBinding.Labels["tenantName"])
 	})
+
+	t.Run("should keep secret binding dirty when resources are left", func(t *testing.T) {
+		//given
+		secret := &v1.Secret{
+			ObjectMeta: machineryv1.ObjectMeta{
+				Name: "secret1", Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				"credentials":    []byte("secret1"),
+				"clientID":       []byte("tenant1"),
+				"clientSecret":   []byte("secret"),
+				"subscriptionID": []byte("12344"),
+				"tenantID":       []byte("tenant1"),
+			},
+		}
+		secretBinding := &gardener_types.SecretBinding{
+			ObjectMeta: machineryv1.ObjectMeta{
+				Name:      "secretBinding1",
+				Namespace: namespace,
+				Labels: map[string]string{
+					"tenantName":      "tenant1",
+					"hyperscalerType": "azure",
+					"dirty":           "true",
+				},
+			},
+			SecretRef: v1.SecretReference{
+				Name:      "secret1",
+				Namespace: namespace,
+			},
+		}
+
+		mockClient := fake.NewSimpleClientset(secret)
+
+		gardenerFake := gardener_fake.NewSimpleClientset(secretBinding)
+		mockSecretBindings := gardenerFake.CoreV1beta1().SecretBindings(namespace)
+
+		resCleaner := &azureMockResourceCleaner{
+			report: cloudprovider.Report{
+				Found:     []string{"resourceGroups/rg1"},
+				Failed:    map[string]string{"resourceGroups/rg1": "deletion failed"},
+				Remaining: []string{"resourceGroups/rg1"},
+			},
+		}
+		providerFactory := &mocks.ProviderFactory{}
+		providerFactory.On("New", model.Azure, mock.Anything).Return(resCleaner, nil)
+
+		cleaner := NewCleaner(context.Background(), mockClient, mockSecretBindings, providerFactory)
+
+		//when
+		err := cleaner.Do()
+
+		//then
+		require.NoError(t, err)
+		dirtySecretBinding, err := mockSecretBindings.Get(context.Background(), secretBinding.Name, machineryv1.GetOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "true", dirtySecretBinding.Labels["dirty"])
+		assert.Equal(t, "tenant1", dirtySecretBinding.Labels["tenantName"])
+	})
 }
 
 type azureMockResourceCleaner struct {
-	error error
+	report cloudprovider.Report
+	error  error
 }
 
-func (am *azureMockResourceCleaner) Do() error {
-	return am.error
+func (am *azureMockResourceCleaner) Do() (cloudprovider.Report, error) {
+	return am.report, am.error
 }
@@ -14,6 +14,7 @@ require (
 	github.com/spf13/cobra v1.1.1
 	github.com/spf13/pflag v1.0.5
 	github.com/spf13/viper v1.7.1
+	github.com/stretchr/testify v1.6.1
 	golang.org/x/mod v0.3.0
 	golang.org/x/oauth2 v0.0.0-20201109201403-9fd604954f58
 	gopkg.in/yaml.v2 v2.3.0
@@ -24,6 +25,8 @@ replace (
 	github.com/census-instrumentation/opencensus-proto v0.1.0-0.20181214143942-ba49f56771b8 => github.com/census-instrumentation/opencensus-proto v0.0.3-0.20181214143942-ba49f56771b8
 	github.com/gardener/gardener => github.com/gardener/gardener v1.2.3
 	github.com/googleapis/gnostic => github.com/googleapis/gnostic v0.3.1
+	github.com/kyma-project/control-plane => ../..
+	github.com/kyma-project/control-plane/components/kubeconfig-service => ../../components/kubeconfig-service
 	k8s.io/api => k8s.io/api v0.17.14
 	k8s.io/apimachinery => k8s.io/apimachinery v0.17.14
 	k8s.io/apiserver => k8s.io/apiserver v0.17.14
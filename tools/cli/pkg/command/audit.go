@@ -0,0 +1,132 @@
+package command
+
+import (
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/audit"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/printer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// AuditCommand represents an execution of the kcp audit command
+type AuditCommand struct {
+	cobraCmd  *cobra.Command
+	log       logger.Logger
+	output    string
+	noHeaders bool
+	sortBy    string
+	params    audit.ListParameters
+	actions   []string
+	results   []string
+}
+
+var auditColumns = []printer.Column{
+	{
+		Header:         "CREATED AT",
+		FieldFormatter: auditEventCreatedAt,
+	},
+	{
+		Header:    "ACTOR",
+		FieldSpec: "{.Actor}",
+	},
+	{
+		Header:    "ACTION",
+		FieldSpec: "{.Action}",
+	},
+	{
+		Header:    "ORCHESTRATION ID",
+		FieldSpec: "{.OrchestrationID}",
+	},
+	{
+		Header:    "RUNTIME ID",
+		FieldSpec: "{.RuntimeID}",
+	},
+	{
+		Header:    "RESULT",
+		FieldSpec: "{.Result}",
+	},
+}
+
+// NewAuditCmd constructs a new instance of AuditCommand and configures it in terms of a cobra.Command
+func NewAuditCmd() *cobra.Command {
+	cmd := AuditCommand{}
+	cobraCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Displays the audit trail of operator actions.",
+		Long: `Displays the audit events of the operator actions performed through the Kyma Environment Broker API, such as triggering or canceling orchestrations, or suspending Runtimes.
+The command supports filtering audit events based on various attributes. See the list of options for more details.`,
+		Example: `  kcp audit                                              Display all audit events.
+  kcp audit --actor admin@example.com                    Display all actions performed by the given user.
+  kcp audit --orchestration-id 0c4357f5-83e0-4b72-9472-49b5cd417c00 -o json
+                                                         Display all actions performed on the given orchestration in the JSON format.
+  kcp audit --result failed                              Display all actions which failed.`,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	cmd.cobraCmd = cobraCmd
+
+	SetOutputOpt(cobraCmd, &cmd.output)
+	SetListOutputOpts(cobraCmd, &cmd.noHeaders, &cmd.sortBy)
+	cobraCmd.Flags().StringSliceVar(&cmd.params.Actors, "actor", nil, "Filter by the actor who performed the action. You can provide multiple values, either separated by a comma (e.g. user1,user2), or by specifying the option multiple times.")
+	cobraCmd.Flags().StringSliceVar(&cmd.actions, "action", nil, "Filter by action. The possible values are: upgradeKyma, upgradeCluster, cancelOrchestration, suspendRuntime, unsuspendRuntime, deprovisionRuntime, reprovisionRuntime. You can provide multiple values, either separated by a comma, or by specifying the option multiple times.")
+	cobraCmd.Flags().StringSliceVar(&cmd.params.OrchestrationIDs, "orchestration-id", nil, "Filter by orchestration ID. You can provide multiple values, either separated by a comma (e.g. ID1,ID2), or by specifying the option multiple times.")
+	cobraCmd.Flags().StringSliceVarP(&cmd.params.RuntimeIDs, "runtime-id", "i", nil, "Filter by Runtime ID. You can provide multiple values, either separated by a comma (e.g. ID1,ID2), or by specifying the option multiple times.")
+	cobraCmd.Flags().StringSliceVar(&cmd.params.InstanceIDs, "instance-id", nil, "Filter by instance ID. You can provide multiple values, either separated by a comma (e.g. ID1,ID2), or by specifying the option multiple times.")
+	cobraCmd.Flags().StringSliceVar(&cmd.results, "result", nil, "Filter by the result of the action. The possible values are: succeeded, failed.")
+
+	return cobraCmd
+}
+
+// Run executes the audit command
+func (cmd *AuditCommand) Run() error {
+	cmd.log = logger.New()
+	client := audit.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), CLICredentialManager(cmd.log))
+
+	ep, err := client.ListEvents(cmd.params)
+	if err != nil {
+		return errors.Wrap(err, "while listing audit events")
+	}
+	err = cmd.printEvents(ep)
+	if err != nil {
+		return errors.Wrap(err, "while printing audit events")
+	}
+
+	return nil
+}
+
+// Validate checks the input parameters of the audit command
+func (cmd *AuditCommand) Validate() error {
+	err := ValidateOutputOpt(cmd.output)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range cmd.actions {
+		switch action := audit.Action(a); action {
+		case audit.UpgradeKymaAction, audit.UpgradeClusterAction, audit.CancelOrchestrationAction,
+			audit.SuspendRuntimeAction, audit.UnsuspendRuntimeAction, audit.DeprovisionRuntimeAction, audit.ReprovisionRuntimeAction:
+			cmd.params.Actions = append(cmd.params.Actions, action)
+		default:
+			return errors.Errorf("invalid value for action: %s", a)
+		}
+	}
+	for _, r := range cmd.results {
+		switch result := audit.Result(r); result {
+		case audit.Succeeded, audit.Failed:
+			cmd.params.Results = append(cmd.params.Results, result)
+		default:
+			return errors.Errorf("invalid value for result: %s", r)
+		}
+	}
+
+	return nil
+}
+
+func (cmd *AuditCommand) printEvents(events audit.EventsPage) error {
+	return printList(cmd.output, auditColumns, cmd.noHeaders, cmd.sortBy, events, events.Data)
+}
+
+func auditEventCreatedAt(obj interface{}) string {
+	e := obj.(audit.EventDTO)
+	return e.CreatedAt.Format("2006/01/02 15:04:05")
+}
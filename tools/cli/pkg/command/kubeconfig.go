@@ -5,15 +5,26 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/pkg/errors"
 
 	"github.com/kyma-project/control-plane/components/kubeconfig-service/pkg/client"
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/credential"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// mergeBatchSize is the number of Runtimes requested from the kubeconfig-service in a single merged kubeconfig
+	mergeBatchSize = 50
+	// defaultTargetOutput is the default output file name of the kubeconfig of Runtime targets
+	defaultTargetOutput = "kubeconfig.yaml"
 )
 
 // KubeconfigCommand represents an execution of the kcp kubeconfig command
@@ -25,6 +36,11 @@ type KubeconfigCommand struct {
 	subAccountID    string
 	runtimeID       string
 	outputPath      string
+	merge           bool
+
+	targetInputs        []string
+	targetExcludeInputs []string
+	targets             orchestration.TargetSpec
 }
 
 type kubeconfig struct {
@@ -43,26 +59,33 @@ func NewKubeconfigCmd() *cobra.Command {
 		Use:     "kubeconfig",
 		Aliases: []string{"kc"},
 		Short:   "Downloads the kubeconfig file for a given Kyma Runtime",
-		Long: `Downloads the kubeconfig file for a given Kyma Runtime.
+		Long: `Downloads the kubeconfig file for a given Kyma Runtime, or a single kubeconfig file for multiple Kyma Runtimes.
 The Runtime can be specified by one of the following:
   - Global account / subaccount pair with the --account and --subaccount options
   - Global account / Runtime ID pair with the --account and --runtime-id options
   - Shoot cluster name with the --shoot option.
 
-By default, the kubeconfig file is saved to the current directory. The output file name can be specified using the --output option.`,
+Multiple Runtimes can be specified with the --target and --target-exclude options. The downloaded kubeconfig file contains a cluster, a context, and a user for each Runtime, named after the Runtime's Shoot cluster.
+
+By default, the kubeconfig file is saved to the current directory. The output file name can be specified using the --output option.
+With the --merge option, the downloaded kubeconfig is merged into an existing kubeconfig file instead. The clusters, contexts, and users with the same names are replaced, and the current context of the existing file is kept.`,
 		Example: `  kcp kubeconfig -g GAID -s SAID -o /my/path/runtime.config  Downloads the kubeconfig file using global account ID and subaccount ID.
   kcp kubeconfig -g GAID -r RUNTIMEID                    Downloads the kubeconfig file using global account ID and Runtime ID.
-  kcp kubeconfig -c c-178e034                            Downloads the kubeconfig file using a Shoot cluster name.`,
+  kcp kubeconfig -c c-178e034                            Downloads the kubeconfig file using a Shoot cluster name.
+  kcp kubeconfig -t account=GAID -o runtimes.yaml        Downloads a single kubeconfig file for all Runtimes of a global account.
+  kcp kubeconfig -t region=europe --merge                Merges the kubeconfig of all Runtimes in European regions into the default kubeconfig file.`,
 		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
 		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 	}
 	cmd.cobraCmd = cobraCmd
 
-	cobraCmd.Flags().StringVarP(&cmd.outputPath, "output", "o", "", "Path to the file to save the downloaded kubeconfig to. Defaults to {CLUSTER NAME}.yaml in the current directory if not specified.")
+	cobraCmd.Flags().StringVarP(&cmd.outputPath, "output", "o", "", "Path to the file to save the downloaded kubeconfig to. Defaults to {CLUSTER NAME}.yaml, or to kubeconfig.yaml for --target, in the current directory if not specified. With --merge, defaults to the first file of the KUBECONFIG environment variable, or to $HOME/.kube/config.")
 	cobraCmd.Flags().StringVarP(&cmd.globalAccountID, "account", "g", "", "Global account ID of the specific Kyma Runtime.")
 	cobraCmd.Flags().StringVarP(&cmd.subAccountID, "subaccount", "s", "", "Subccount ID of the specific Kyma Runtime.")
 	cobraCmd.Flags().StringVarP(&cmd.runtimeID, "runtime-id", "r", "", "Runtime ID of the specific Kyma Runtime.")
 	cobraCmd.Flags().StringVarP(&cmd.shoot, "shoot", "c", "", "Shoot cluster name of the specific Kyma Runtime.")
+	cobraCmd.Flags().BoolVar(&cmd.merge, "merge", false, "Option that merges the downloaded kubeconfig into the existing kubeconfig file instead of overwriting it.")
+	SetRuntimeTargetOpts(cobraCmd, &cmd.targetInputs, &cmd.targetExcludeInputs)
 
 	return cobraCmd
 }
@@ -73,6 +96,14 @@ func (cmd *KubeconfigCommand) Run() error {
 	cred := CLICredentialManager(cmd.log)
 	client := client.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KubeconfigAPIURL(), cred)
 
+	if len(cmd.targetInputs) > 0 {
+		kc, err := cmd.targetKubeconfig(cred, client)
+		if err != nil {
+			return err
+		}
+		return cmd.saveKubeconfig(kc)
+	}
+
 	// Resolve Global Account / Subaccount, or Shoot name to Global Account / Runtime ID
 	if cmd.globalAccountID == "" || cmd.runtimeID == "" {
 		err := cmd.resolveRuntimeAttributes(cmd.cobraCmd.Context(), cred)
@@ -93,10 +124,61 @@ func (cmd *KubeconfigCommand) Validate() error {
 	if GlobalOpts.KubeconfigAPIURL() == "" {
 		return fmt.Errorf("missing required %s option", GlobalOpts.kubeconfigAPIURL)
 	}
+	if len(cmd.targetInputs) > 0 || len(cmd.targetExcludeInputs) > 0 {
+		if cmd.globalAccountID != "" || cmd.subAccountID != "" || cmd.runtimeID != "" || cmd.shoot != "" {
+			return errors.New("--target cannot be used together with the account, subaccount, runtime-id and shoot options")
+		}
+		if GlobalOpts.GardenerKubeconfig() == "" || GlobalOpts.GardenerNamespace() == "" {
+			return fmt.Errorf("missing required %s/%s options", GlobalOpts.gardenerKubeconfig, GlobalOpts.gardenerNamespace)
+		}
+		return ValidateTransformRuntimeTargetOpts(cmd.targetInputs, cmd.targetExcludeInputs, &cmd.targets)
+	}
 	if cmd.globalAccountID != "" && (cmd.subAccountID != "" || cmd.runtimeID != "") || cmd.shoot != "" {
 		return nil
 	}
-	return errors.New("at least one of the following options have to be specified: account/subaccount, account/runtime-id, shoot")
+	return errors.New("at least one of the following options have to be specified: account/subaccount, account/runtime-id, shoot, target")
+}
+
+// targetKubeconfig resolves the Runtime targets and returns a single kubeconfig of the resolved Runtimes
+func (cmd *KubeconfigCommand) targetKubeconfig(cred credential.Manager, kcClient client.Client) (string, error) {
+	runtimes, err := resolveRuntimeTargets(cmd.cobraCmd.Context(), cred, cmd.targets, cmd.log)
+	if err != nil {
+		return "", err
+	}
+	if len(runtimes) == 0 {
+		return "", errors.New("no runtimes matched the target options")
+	}
+	cmd.log.Infof("Number of resolved runtimes: %d\n", len(runtimes))
+
+	// The kubeconfig-service limits the number of Runtimes of a merged kubeconfig, so the Runtimes are requested in batches
+	merged := clientcmdapi.NewConfig()
+	for start := 0; start < len(runtimes); start += mergeBatchSize {
+		end := start + mergeBatchSize
+		if end > len(runtimes) {
+			end = len(runtimes)
+		}
+
+		batch := make([]client.Runtime, 0, end-start)
+		for _, rt := range runtimes[start:end] {
+			batch = append(batch, client.Runtime{TenantID: rt.GlobalAccountID, RuntimeID: rt.RuntimeID})
+		}
+		kc, err := kcClient.GetMergedKubeConfig(batch)
+		if err != nil {
+			return "", errors.Wrap(err, "while getting kubeconfig")
+		}
+		config, err := clientcmd.Load([]byte(kc))
+		if err != nil {
+			return "", errors.Wrap(err, "while parsing kubeconfig")
+		}
+		mergeKubeconfig(merged, config)
+	}
+
+	data, err := clientcmd.Write(*merged)
+	if err != nil {
+		return "", errors.Wrap(err, "while serializing kubeconfig")
+	}
+
+	return string(data), nil
 }
 
 func (cmd *KubeconfigCommand) resolveRuntimeAttributes(ctx context.Context, cred credential.Manager) error {
@@ -126,17 +208,25 @@ func (cmd *KubeconfigCommand) resolveRuntimeAttributes(ctx context.Context, cred
 }
 
 func (cmd *KubeconfigCommand) saveKubeconfig(kubeconfig string) error {
+	if cmd.merge {
+		return cmd.mergeIntoKubeconfigFile(kubeconfig)
+	}
+
 	// Assemble default output path based on cluster name if output path was not given
 	if cmd.outputPath == "" {
-		clusterName, err := clusterNameFromKubeconfig(kubeconfig)
-		if err != nil {
-			return errors.Wrap(err, "while getting cluster name from kubeconfig")
+		fileName := defaultTargetOutput
+		if len(cmd.targetInputs) == 0 {
+			clusterName, err := clusterNameFromKubeconfig(kubeconfig)
+			if err != nil {
+				return errors.Wrap(err, "while getting cluster name from kubeconfig")
+			}
+			fileName = fmt.Sprintf("%s.yaml", clusterName)
 		}
 		dir, err := os.Getwd()
 		if err != nil {
 			return errors.Wrap(err, "while getting current directory")
 		}
-		cmd.outputPath = fmt.Sprintf("%s/%s.yaml", dir, clusterName)
+		cmd.outputPath = filepath.Join(dir, fileName)
 	}
 
 	err := ioutil.WriteFile(cmd.outputPath, []byte(kubeconfig), 0600)
@@ -148,6 +238,65 @@ func (cmd *KubeconfigCommand) saveKubeconfig(kubeconfig string) error {
 	return nil
 }
 
+// mergeIntoKubeconfigFile merges the kubeconfig into the kubeconfig file given by the --output option, or into the default kubeconfig file
+func (cmd *KubeconfigCommand) mergeIntoKubeconfigFile(kubeconfig string) error {
+	if cmd.outputPath == "" {
+		cmd.outputPath = defaultKubeconfigPath()
+	}
+
+	downloaded, err := clientcmd.Load([]byte(kubeconfig))
+	if err != nil {
+		return errors.Wrap(err, "while parsing kubeconfig")
+	}
+
+	existing := clientcmdapi.NewConfig()
+	if _, err := os.Stat(cmd.outputPath); err == nil {
+		existing, err = clientcmd.LoadFromFile(cmd.outputPath)
+		if err != nil {
+			return errors.Wrapf(err, "while loading kubeconfig file %s", cmd.outputPath)
+		}
+	} else if !os.IsNotExist(err) {
+		return errors.Wrapf(err, "while checking kubeconfig file %s", cmd.outputPath)
+	}
+
+	mergeKubeconfig(existing, downloaded)
+
+	if err := os.MkdirAll(filepath.Dir(cmd.outputPath), 0700); err != nil {
+		return errors.Wrap(err, "while creating kubeconfig directory")
+	}
+	if err := clientcmd.WriteToFile(*existing, cmd.outputPath); err != nil {
+		return errors.Wrap(err, "while saving kubeconfig")
+	}
+	fmt.Printf("Kubeconfig of %d cluster(s) merged into %s\n", len(downloaded.Clusters), cmd.outputPath)
+
+	return nil
+}
+
+// mergeKubeconfig adds the clusters, contexts and users of src to dst, replacing the ones with the same names.
+// The current context of dst is kept, unless it is not set.
+func mergeKubeconfig(dst, src *clientcmdapi.Config) {
+	for name, cluster := range src.Clusters {
+		dst.Clusters[name] = cluster
+	}
+	for name, authInfo := range src.AuthInfos {
+		dst.AuthInfos[name] = authInfo
+	}
+	for name, ctx := range src.Contexts {
+		dst.Contexts[name] = ctx
+	}
+	if dst.CurrentContext == "" {
+		dst.CurrentContext = src.CurrentContext
+	}
+}
+
+// defaultKubeconfigPath returns the first file of the KUBECONFIG environment variable, or the default kubeconfig file in the home directory
+func defaultKubeconfigPath() string {
+	if paths := filepath.SplitList(os.Getenv(clientcmd.RecommendedConfigPathEnvVar)); len(paths) > 0 && paths[0] != "" {
+		return paths[0]
+	}
+	return clientcmd.RecommendedHomeFile
+}
+
 func clusterNameFromKubeconfig(rawKubeConfig string) (string, error) {
 	var kubeCfg kubeconfig
 	var clusterName string
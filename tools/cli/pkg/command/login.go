@@ -9,10 +9,11 @@ import (
 
 // LoginCommand represents an execution of the kcp login command
 type LoginCommand struct {
-	cobraCmd *cobra.Command
-	log      logger.Logger
-	username string
-	password string
+	cobraCmd          *cobra.Command
+	log               logger.Logger
+	username          string
+	password          string
+	clientCredentials bool
 }
 
 // NewLoginCmd constructs a new instance of LoginCommand and configures it in terms of a cobra.Command
@@ -24,13 +25,16 @@ func NewLoginCmd() *cobra.Command {
 		Short:   "Performs OIDC login required by all commands.",
 		Long: `Initiates OIDC login to obtain the ID token which is required by all CLI commands.
 By default, without any options, the OIDC authorization code flow is executed. It prompts the user to navigate to a local address in the browser and get redirected to the OIDC Authentication Server login page.
-Service accounts can execute the resource owner credentials flow by specifying the --username and --password options.`,
+Service accounts can execute the resource owner credentials flow by specifying the --username and --password options.
+Service principals, such as CI jobs, can execute the client credentials flow of the configured OIDC client by specifying the --client-credentials option. The flow can also be enabled for all commands using the KCP_CLIENT_CREDENTIALS environment variable, so that no explicit login is needed.
+The obtained ID token is cached locally and reused by the other commands until it expires.`,
 		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
 		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 	}
 	cmd.cobraCmd = cobraCmd
 	cobraCmd.Flags().StringVarP(&cmd.username, "username", "u", "", "Username to use for the resource owner credentials flow.")
 	cobraCmd.Flags().StringVarP(&cmd.password, "password", "p", "", "Password to use for the resource owner credentials flow.")
+	cobraCmd.Flags().BoolVar(&cmd.clientCredentials, "client-credentials", false, "Executes the client credentials flow with the OIDC client ID and secret instead of logging in as a user.")
 
 	return cobraCmd
 }
@@ -40,10 +44,13 @@ func (cmd *LoginCommand) Run() error {
 	cmd.log = logger.New()
 	cred := CLICredentialManager(cmd.log)
 	var err error
-	if cmd.username == "" {
-		_, err = cred.GetTokenByAuthCode(cmd.cobraCmd.Context())
-	} else {
+	switch {
+	case cmd.clientCredentials || GlobalOpts.ClientCredentials():
+		_, err = cred.GetTokenByClientCredentials(cmd.cobraCmd.Context())
+	case cmd.username != "":
 		_, err = cred.GetTokenByROPC(cmd.cobraCmd.Context(), cmd.username, cmd.password)
+	default:
+		_, err = cred.GetTokenByAuthCode(cmd.cobraCmd.Context())
 	}
 
 	if err != nil {
@@ -57,5 +64,8 @@ func (cmd *LoginCommand) Validate() error {
 	if cmd.username != "" && cmd.password == "" || cmd.username == "" && cmd.password != "" {
 		return errors.New("both username and password must be specified for resource owner credentials login")
 	}
+	if cmd.clientCredentials && cmd.username != "" {
+		return errors.New("--client-credentials cannot be used together with --username and --password")
+	}
 	return nil
 }
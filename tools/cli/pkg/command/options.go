@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
 	"github.com/spf13/cobra"
@@ -18,9 +19,12 @@ const (
 )
 
 const (
-	tableOutput  string = "table"
-	jsonOutput   string = "json"
-	customOutput string = "custom"
+	tableOutput      string = "table"
+	jsonOutput       string = "json"
+	yamlOutput       string = "yaml"
+	customOutput     string = "custom"
+	jsonPathOutput   string = "jsonpath"
+	goTemplateOutput string = "go-template"
 )
 
 const (
@@ -49,6 +53,9 @@ type GlobalOptionsKey struct {
 	gardenerKubeconfig string
 	gardenerNamespace  string
 	username           string
+	clientCredentials  string
+	token              string
+	tokenFile          string
 }
 
 // GlobalOpts is the convenience object for storing the fixed global conifguration (parameter) keys
@@ -61,6 +68,9 @@ var GlobalOpts = GlobalOptionsKey{
 	gardenerKubeconfig: "gardener-kubeconfig",
 	gardenerNamespace:  "gardener-namespace",
 	username:           "username",
+	clientCredentials:  "client-credentials",
+	token:              "token",
+	tokenFile:          "token-file",
 }
 
 // SetGlobalOpts configures the global parameters on the given root command
@@ -86,12 +96,21 @@ func SetGlobalOpts(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(GlobalOpts.gardenerNamespace, "", "Gardener Namespace (project) to use. Can also be set using the KCP_GARDENER_NAMESPACE environment variable.")
 	viper.BindPFlag(GlobalOpts.gardenerNamespace, cmd.PersistentFlags().Lookup(GlobalOpts.gardenerNamespace))
 
+	cmd.PersistentFlags().String(GlobalOpts.tokenFile, "", "Path to the file containing a pre-issued ID token to use instead of login, for example a token mounted by the CI system. The file is read again whenever the token is needed. Can also be set using the KCP_TOKEN_FILE environment variable.")
+	viper.BindPFlag(GlobalOpts.tokenFile, cmd.PersistentFlags().Lookup(GlobalOpts.tokenFile))
+
 	viper.BindEnv(GlobalOpts.username)
+	viper.BindEnv(GlobalOpts.clientCredentials)
+	viper.BindEnv(GlobalOpts.token)
 }
 
 // ValidateGlobalOpts checks the presence of the required global configuration parameters
 func ValidateGlobalOpts() error {
 	var reqGlobalOpts = []string{GlobalOpts.oidcIssuerURL, GlobalOpts.oidcClientID, GlobalOpts.oidcClientSecret, GlobalOpts.kebAPIURL}
+	if GlobalOpts.Token() != "" || GlobalOpts.TokenFile() != "" {
+		// The OIDC options are only needed to obtain a token, which is already given
+		reqGlobalOpts = []string{GlobalOpts.kebAPIURL}
+	}
 	var missingGlobalOpts []string
 	for _, opt := range reqGlobalOpts {
 		if viper.GetString(opt) == "" {
@@ -145,22 +164,65 @@ func (keys *GlobalOptionsKey) Username() string {
 	return viper.GetString(keys.username)
 }
 
+// ClientCredentials gets whether to use the client credentials flow for auth
+func (keys *GlobalOptionsKey) ClientCredentials() bool {
+	return viper.GetBool(keys.clientCredentials)
+}
+
+// Token gets the pre-issued ID token to use for auth
+func (keys *GlobalOptionsKey) Token() string {
+	return viper.GetString(keys.token)
+}
+
+// TokenFile gets the token-file global parameter
+func (keys *GlobalOptionsKey) TokenFile() string {
+	return viper.GetString(keys.tokenFile)
+}
+
 // SetOutputOpt configures the optput type option on the given command
 func SetOutputOpt(cmd *cobra.Command, opt *string) {
-	cmd.Flags().StringVarP(opt, "output", "o", tableOutput, fmt.Sprintf("Output type of displayed Runtime(s). The possible values are: %s, %s, %s(e.g. custom=<header>:<jsonpath-field-spec>.", tableOutput, jsonOutput, customOutput))
+	cmd.Flags().StringVarP(opt, "output", "o", tableOutput, fmt.Sprintf("Output type of displayed Runtime(s). The possible values are: %s, %s, %s, %s(e.g. custom=<header>:<jsonpath-field-spec>), %s(e.g. jsonpath=<jsonpath-expression>), %s(e.g. go-template=<template>).", tableOutput, jsonOutput, yamlOutput, customOutput, jsonPathOutput, goTemplateOutput))
 }
 
 // ValidateOutputOpt checks whether the given optput type is one of the valid values
 func ValidateOutputOpt(opt string) error {
 	switch {
-	case opt == tableOutput, opt == jsonOutput:
+	case opt == tableOutput, opt == jsonOutput, opt == yamlOutput:
 		return nil
 	case strings.HasPrefix(opt, customOutput):
 		return nil
+	case strings.HasPrefix(opt, jsonPathOutput+"="), strings.HasPrefix(opt, goTemplateOutput+"="):
+		return nil
 	}
 	return fmt.Errorf("invalid value for output: %s", opt)
 }
 
+// SetListOutputOpts configures the options of the commands displaying lists of objects in table format
+func SetListOutputOpts(cmd *cobra.Command, noHeaders *bool, sortBy *string) {
+	cmd.Flags().BoolVar(noHeaders, "no-headers", false, fmt.Sprintf("Option that omits the header row in the %s and %s outputs.", tableOutput, customOutput))
+	cmd.Flags().StringVar(sortBy, "sort-by", "", "Sort the displayed list by the field specified using a JSONPath expression on the JSON output, e.g. shootName or {.status.createdAt}.")
+}
+
+// SetWatchOpts configures the watch mode options on the given command
+func SetWatchOpts(cmd *cobra.Command, watch *bool, interval *time.Duration) {
+	cmd.Flags().BoolVarP(watch, "watch", "w", false, "Option that keeps polling KEB and refreshes the displayed table in place. The rows whose state changed since the previous refresh are highlighted.")
+	cmd.Flags().DurationVar(interval, "interval", defaultWatchInterval, "Interval between the refreshes in watch mode, e.g. 30s or 1m.")
+}
+
+// ValidateWatchOpts checks whether the watch mode options are valid with the given output type
+func ValidateWatchOpts(watch bool, interval time.Duration, output string) error {
+	if !watch {
+		return nil
+	}
+	if output != tableOutput {
+		return fmt.Errorf("--watch can only be used with the %s output", tableOutput)
+	}
+	if interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+	return nil
+}
+
 // SetRuntimeTargetOpts configures runtime target options on the given command
 func SetRuntimeTargetOpts(cmd *cobra.Command, targetInputs *[]string, targetExcludeInputs *[]string) {
 	cmd.Flags().StringArrayVarP(targetInputs, "target", "t", nil,
@@ -1,12 +1,13 @@
 package command
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -28,10 +29,14 @@ type OrchestrationCommand struct {
 	log        logger.Logger
 	client     orchestration.Client
 	output     string
+	noHeaders  bool
+	sortBy     string
 	states     []string
 	operation  string
 	subCommand string
 	listParams orchestration.ListParameters
+	watch      bool
+	interval   time.Duration
 }
 
 var cliStates = map[string]string{
@@ -146,14 +151,20 @@ The command has the following modes:
   - When specifying an orchestration ID as an argument. In this mode, the command displays details about the specific orchestration.
       If the optional --operation flag is provided, it displays details of the specified Runtime operation within the orchestration.
   - When specifying an orchestration ID and ` + "`operations` or `ops`" + ` as arguments. In this mode, the command displays the Runtime operations for the given orchestration.
-  - When specifying an orchestration ID and ` + "`cancel`" + ` as arguments. In this mode, the command cancels the orchestration and all pending Runtime operations.`,
+  - When specifying an orchestration ID and ` + "`cancel`" + ` as arguments. In this mode, the command cancels the orchestration and all pending Runtime operations.
+The orchestration details and the Runtime operations can be watched with the --watch option, which keeps refreshing them until the orchestration finishes.
+When watching, the command exits with a non-zero code if the orchestration fails, so it can be used in scripts to wait for an orchestration.`,
 		Example: `  kcp orchestrations --state inprogress                                   Display all orchestrations which are in progress.
   kcp orchestration -o custom="Orchestration ID:{.OrchestrationID},STATE:{.State},CREATED AT:{.createdAt}"
                                                                           Display all orchestations with specific custom fields.
   kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00                  Display details about a specific orchestration.
   kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 --operation OID  Display details of the specified Runtime operation within the orchestration.
   kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 operations       Display the operations of the given orchestration.
-  kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 cancel           Cancel the given orchestration.`,
+  kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 cancel           Cancel the given orchestration.
+  kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 ops --watch      Watch the operations of the given orchestration until it finishes.
+  kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 ops --sort-by shootName --no-headers
+                                                                          Display the operations of the given orchestration sorted by Shoot names, without the header row.
+  kcp orchestration 0c4357f5-83e0-4b72-9472-49b5cd417c00 -o yaml          Display details about a specific orchestration in the YAML format.`,
 		Args:    cobra.MaximumNArgs(2),
 		PreRunE: func(_ *cobra.Command, args []string) error { return cmd.Validate(args) },
 		RunE:    func(_ *cobra.Command, args []string) error { return cmd.Run(args) },
@@ -161,8 +172,10 @@ The command has the following modes:
 	cmd.cobraCmd = cobraCmd
 
 	SetOutputOpt(cobraCmd, &cmd.output)
+	SetListOutputOpts(cobraCmd, &cmd.noHeaders, &cmd.sortBy)
 	cobraCmd.Flags().StringSliceVarP(&cmd.states, "state", "s", nil, fmt.Sprintf("Filter output by state. You can provide multiple values, either separated by a comma (e.g. failed,inprogress), or by specifying the option multiple times. The possible values are: %s.", strings.Join(cliOrchestrationStates(), ", ")))
 	cobraCmd.Flags().StringVar(&cmd.operation, "operation", "", "Option that displays details of the specified Runtime operation when a given orchestration is selected.")
+	SetWatchOpts(cobraCmd, &cmd.watch, &cmd.interval)
 	return cobraCmd
 }
 
@@ -210,6 +223,9 @@ func (cmd *OrchestrationCommand) Run(args []string) error {
 	case 1:
 		// Called with orchestration ID but without subcommand
 		if cmd.operation == "" {
+			if cmd.watch {
+				return cmd.watchOrchestration(args[0], cmd.printOrchestrationDetails)
+			}
 			return cmd.showOneOrchestration(args[0])
 		}
 		return cmd.showOperationDetails(args[0])
@@ -219,6 +235,9 @@ func (cmd *OrchestrationCommand) Run(args []string) error {
 		case cancelCommand:
 			return cmd.cancelOrchestration(args[0])
 		case operationsCommand, opsCommand:
+			if cmd.watch {
+				return cmd.watchOrchestration(args[0], cmd.operationsView(args[0]))
+			}
 			return cmd.showOperations(args[0])
 		}
 	}
@@ -254,6 +273,19 @@ func (cmd *OrchestrationCommand) Validate(args []string) error {
 		}
 	}
 
+	listing := len(args) == 0 || cmd.subCommand == operationsCommand || cmd.subCommand == opsCommand
+	if (cmd.noHeaders || cmd.sortBy != "") && !listing {
+		return errors.New("--no-headers and --sort-by should only be used when listing orchestrations or the operations of a given orchestration")
+	}
+
+	err = ValidateWatchOpts(cmd.watch, cmd.interval, cmd.output)
+	if err != nil {
+		return err
+	}
+	if cmd.watch && (len(args) == 0 || cmd.operation != "" || cmd.subCommand == cancelCommand) {
+		return errors.New("--watch should only be used when displaying the details or the operations of a given orchestration")
+	}
+
 	return nil
 }
 
@@ -263,30 +295,7 @@ func (cmd *OrchestrationCommand) showOrchestrations() error {
 		return errors.Wrap(err, "while listing orchestrations")
 	}
 
-	switch {
-	case cmd.output == tableOutput:
-		tp, err := printer.NewTablePrinter(orchestrationColumns, false)
-		if err != nil {
-			return err
-		}
-		return tp.PrintObj(srl.Data)
-	case cmd.output == jsonOutput:
-		jp := printer.NewJSONPrinter("  ")
-		jp.PrintObj(srl)
-	case strings.HasPrefix(cmd.output, customOutput):
-		_, templateFile := printer.ParseOutputToTemplateTypeAndElement(cmd.output)
-		column, err := printer.ParseColumnToHeaderAndFieldSpec(templateFile)
-		if err != nil {
-			return err
-		}
-
-		ccp, err := printer.NewTablePrinter(column, false)
-		if err != nil {
-			return err
-		}
-		return ccp.PrintObj(srl.Data)
-	}
-	return nil
+	return printList(cmd.output, orchestrationColumns, cmd.noHeaders, cmd.sortBy, srl, srl.Data)
 }
 
 func (cmd *OrchestrationCommand) showOneOrchestration(orchestrationID string) error {
@@ -295,27 +304,86 @@ func (cmd *OrchestrationCommand) showOneOrchestration(orchestrationID string) er
 		return errors.Wrap(err, "while getting orchestration")
 	}
 
-	switch cmd.output {
-	case tableOutput:
-		// Print orchestration details via template
-		funcMap := template.FuncMap{
-			"orchestrationTarget": orchestrationTarget,
-			"orchestrationStates": orchestrationStates,
+	if cmd.output == tableOutput {
+		return cmd.printOrchestrationDetails(os.Stdout, sr)
+	}
+	return printObject(cmd.output, sr)
+}
+
+// printOrchestrationDetails prints the orchestration details via template
+func (cmd *OrchestrationCommand) printOrchestrationDetails(w io.Writer, sr orchestration.StatusResponse) error {
+	funcMap := template.FuncMap{
+		"orchestrationTarget": orchestrationTarget,
+		"orchestrationStates": orchestrationStates,
+	}
+	tmpl, err := template.New("orchestrationDetails").Funcs(funcMap).Parse(orchestrationDetailsTpl)
+	if err != nil {
+		return errors.Wrap(err, "while parsing orchestration details template")
+	}
+	err = tmpl.Execute(w, sr)
+	if err != nil {
+		return errors.Wrap(err, "while printing orchestration details")
+	}
+	return nil
+}
+
+// watchOrchestration keeps refreshing the given view of the orchestration until the orchestration finishes.
+// It returns an error if the orchestration failed, so that the command exits with a non-zero code.
+func (cmd *OrchestrationCommand) watchOrchestration(orchestrationID string, view func(w io.Writer, sr orchestration.StatusResponse) error) error {
+	var last orchestration.StatusResponse
+	err := watch(cmd.cobraCmd.Context(), fmt.Sprintf("kcp orchestrations %s", orchestrationID), cmd.interval, func(w io.Writer) (bool, error) {
+		sr, err := cmd.client.GetOrchestration(orchestrationID)
+		if err != nil {
+			return false, errors.Wrap(err, "while getting orchestration")
 		}
-		tmpl, err := template.New("orchestrationDetails").Funcs(funcMap).Parse(orchestrationDetailsTpl)
+		last = sr
+		return orchestrationFinished(sr.State), view(w, sr)
+	})
+	if err != nil {
+		return err
+	}
+
+	if last.State == orchestration.Failed {
+		return fmt.Errorf("orchestration %s failed: %s", orchestrationID, last.Description)
+	}
+	return nil
+}
+
+// operationsView returns the watch view of the operations table of the given orchestration, which highlights the operations whose state changed since the previous refresh
+func (cmd *OrchestrationCommand) operationsView(orchestrationID string) func(w io.Writer, sr orchestration.StatusResponse) error {
+	states := newStatusTracker()
+	highlight := func(obj interface{}) bool {
+		op := obj.(orchestration.OperationResponse)
+		return states.changed(op.OperationID, op.State)
+	}
+
+	return func(w io.Writer, sr orchestration.StatusResponse) error {
+		orl, err := cmd.client.ListOperations(orchestrationID, cmd.listParams)
 		if err != nil {
-			return errors.Wrap(err, "while parsing orchestration details template")
+			return errors.Wrap(err, "while listing operations")
+		}
+		states.next()
+		if cmd.sortBy != "" {
+			err = printer.SortObjects(orl.Data, cmd.sortBy)
+			if err != nil {
+				return errors.Wrap(err, "while sorting operations")
+			}
 		}
-		err = tmpl.Execute(os.Stdout, sr)
+		fmt.Fprintf(w, "State: %s\n\n", sr.State)
+		tp, err := printer.NewHighlightingTablePrinter(operationColumns, cmd.noHeaders, w, highlight)
 		if err != nil {
-			return errors.Wrap(err, "while printing orchestration details")
+			return err
 		}
-	case jsonOutput:
-		jp := printer.NewJSONPrinter("  ")
-		jp.PrintObj(sr)
+		return tp.PrintObj(orl.Data)
 	}
+}
 
-	return nil
+func orchestrationFinished(state string) bool {
+	switch state {
+	case orchestration.Succeeded, orchestration.Failed, orchestration.Canceled:
+		return true
+	}
+	return false
 }
 
 func (cmd *OrchestrationCommand) showOperations(orchestrationID string) error {
@@ -324,22 +392,11 @@ func (cmd *OrchestrationCommand) showOperations(orchestrationID string) error {
 		return errors.Wrap(err, "while listing operations")
 	}
 
-	switch cmd.output {
-	case tableOutput:
-		// Print operation table
-		if len(orl.Data) > 0 {
-			tp, err := printer.NewTablePrinter(operationColumns, false)
-			if err != nil {
-				return err
-			}
-			return tp.PrintObj(orl.Data)
-		}
-	case jsonOutput:
-		jp := printer.NewJSONPrinter("  ")
-		jp.PrintObj(orl)
+	// The operation table is not printed at all if there are no operations
+	if cmd.output == tableOutput && len(orl.Data) == 0 {
+		return nil
 	}
-
-	return nil
+	return printList(cmd.output, operationColumns, cmd.noHeaders, cmd.sortBy, orl, orl.Data)
 }
 
 func (cmd *OrchestrationCommand) showOperationDetails(orchestrationID string) error {
@@ -348,19 +405,16 @@ func (cmd *OrchestrationCommand) showOperationDetails(orchestrationID string) er
 		return errors.Wrap(err, "while getting operation details")
 	}
 
-	switch cmd.output {
-	case tableOutput:
-		tmpl, err := template.New("operationDetails").Parse(operationDetailsTpl)
-		if err != nil {
-			return errors.Wrap(err, "while parsing operation details template")
-		}
-		err = tmpl.Execute(os.Stdout, odr)
-		if err != nil {
-			return errors.Wrap(err, "while printing operation details")
-		}
-	case jsonOutput:
-		jp := printer.NewJSONPrinter("  ")
-		jp.PrintObj(odr)
+	if cmd.output != tableOutput {
+		return printObject(cmd.output, odr)
+	}
+	tmpl, err := template.New("operationDetails").Parse(operationDetailsTpl)
+	if err != nil {
+		return errors.Wrap(err, "while parsing operation details template")
+	}
+	err = tmpl.Execute(os.Stdout, odr)
+	if err != nil {
+		return errors.Wrap(err, "while printing operation details")
 	}
 
 	return nil
@@ -379,11 +433,8 @@ func (cmd *OrchestrationCommand) cancelOrchestration(orchestrationID string) err
 		return fmt.Errorf("orchestration is already %s", sr.State)
 	}
 
-	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Printf("%d pending operations(s) will be canceled, %d in progress operation(s) will still be completed.\n", sr.OperationStats[orchestration.Pending], sr.OperationStats[orchestration.InProgress])
-	fmt.Print("Do you want to continue? (Y/N) ")
-	scanner.Scan()
-	if scanner.Text() != "Y" {
+	if !confirmed() {
 		fmt.Println("Aborted.")
 		return nil
 	}
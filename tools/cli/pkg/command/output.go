@@ -0,0 +1,80 @@
+package command
+
+import (
+	"strings"
+
+	"github.com/kyma-project/control-plane/tools/cli/pkg/printer"
+	"github.com/pkg/errors"
+)
+
+// printList prints the page of a list in the given output type. If sortBy is set, the items of the page are sorted in place beforehand.
+// The table and custom outputs print the items as rows, the other outputs print the whole page, e.g. including the total count.
+func printList(output string, columns []printer.Column, noHeaders bool, sortBy string, page interface{}, items interface{}) error {
+	if sortBy != "" {
+		err := printer.SortObjects(items, sortBy)
+		if err != nil {
+			return errors.Wrap(err, "while sorting")
+		}
+	}
+
+	switch {
+	case output == tableOutput:
+		tp, err := printer.NewTablePrinter(columns, noHeaders)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(items)
+	case strings.HasPrefix(output, customOutput):
+		columns, err := customColumns(output)
+		if err != nil {
+			return err
+		}
+		tp, err := printer.NewTablePrinter(columns, noHeaders)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(items)
+	}
+
+	return printObject(output, page)
+}
+
+// printObject prints the object in the given output type, except for the table output, which is specific to each view
+func printObject(output string, obj interface{}) error {
+	templateType, templateElement := printer.ParseOutputToTemplateTypeAndElement(output)
+	switch {
+	case output == jsonOutput:
+		return printer.NewJSONPrinter("  ").PrintObj(obj)
+	case output == yamlOutput:
+		return printer.NewYAMLPrinter().PrintObj(obj)
+	case strings.HasPrefix(output, customOutput):
+		columns, err := customColumns(output)
+		if err != nil {
+			return err
+		}
+		tp, err := printer.NewTablePrinter(columns, false)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(obj)
+	case templateType == jsonPathOutput:
+		tp, err := printer.NewJSONPathPrinter(templateElement)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(obj)
+	case templateType == goTemplateOutput:
+		tp, err := printer.NewGoTemplatePrinter(templateElement)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(obj)
+	}
+
+	return nil
+}
+
+func customColumns(output string) ([]printer.Column, error) {
+	_, templateElement := printer.ParseOutputToTemplateTypeAndElement(output)
+	return printer.ParseColumnToHeaderAndFieldSpec(templateElement)
+}
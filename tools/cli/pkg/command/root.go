@@ -26,8 +26,13 @@ The CLI supports configuration file for common (global) options needed for all c
   - KCPCONFIG environment variable which contains the path
   - $HOME/.kcp/config.yaml (default path).
 
-The configuration file is in YAML format and supports the following global options: %s, %s, %s, %s, %s, %s.
-See the **Global Options** section of each command for the description of these options.`, GlobalOpts.oidcIssuerURL, GlobalOpts.oidcClientID, GlobalOpts.oidcClientSecret, GlobalOpts.kebAPIURL, GlobalOpts.kubeconfigAPIURL, GlobalOpts.gardenerKubeconfig)
+The configuration file is in YAML format and supports the following global options: %s, %s, %s, %s, %s, %s, %s, %s.
+See the **Global Options** section of each command for the description of these options.
+
+Non-interactive clients, such as CI jobs, can authenticate without the kcp login command:
+  - KCP_TOKEN environment variable or --%s option which provide a pre-issued ID token
+  - KCP_CLIENT_CREDENTIALS=true environment variable, or %s: true in the config file, which execute the OIDC client credentials flow with the configured OIDC client.
+The obtained tokens are cached locally and refreshed transparently when they expire or are rejected.`, GlobalOpts.oidcIssuerURL, GlobalOpts.oidcClientID, GlobalOpts.oidcClientSecret, GlobalOpts.kebAPIURL, GlobalOpts.kubeconfigAPIURL, GlobalOpts.gardenerKubeconfig, GlobalOpts.clientCredentials, GlobalOpts.tokenFile, GlobalOpts.tokenFile, GlobalOpts.clientCredentials)
 
 	cmd := &cobra.Command{
 		Use:     "kcp",
@@ -52,6 +57,7 @@ See the **Global Options** section of each command for the description of these
 		NewLoginCmd(),
 		NewRuntimeCmd(),
 		NewOrchestrationCmd(),
+		NewAuditCmd(),
 		NewKubeconfigCmd(),
 		NewUpgradeCmd(),
 		NewTaskRunCmd(),
@@ -91,5 +97,10 @@ func initConfig() {
 
 // CLICredentialManager returns a credential.Manager configured using the CLI global options
 func CLICredentialManager(logger logger.Logger) credential.Manager {
-	return credential.NewManager(GlobalOpts.OIDCIssuerURL(), GlobalOpts.OIDCClientID(), GlobalOpts.OIDCClientSecret(), GlobalOpts.Username(), logger)
+	return credential.NewManager(GlobalOpts.OIDCIssuerURL(), GlobalOpts.OIDCClientID(), GlobalOpts.OIDCClientSecret(), credential.Options{
+		Username:          GlobalOpts.Username(),
+		ClientCredentials: GlobalOpts.ClientCredentials(),
+		Token:             GlobalOpts.Token(),
+		TokenFile:         GlobalOpts.TokenFile(),
+	}, logger)
 }
@@ -2,7 +2,8 @@ package command
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"time"
 
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
@@ -13,10 +14,14 @@ import (
 
 // RuntimeCommand represents an execution of the kcp runtimes command
 type RuntimeCommand struct {
-	cobraCmd *cobra.Command
-	log      logger.Logger
-	output   string
-	params   runtime.ListParameters
+	cobraCmd  *cobra.Command
+	log       logger.Logger
+	output    string
+	noHeaders bool
+	sortBy    string
+	params    runtime.ListParameters
+	watch     bool
+	interval  time.Duration
 }
 
 const (
@@ -81,13 +86,19 @@ The command supports filtering Runtimes based on various attributes. See the lis
   kcp runtimes -c bbc3ee7 -o custom="INSTANCE ID:instanceID,SHOOTNAME:shootName"
                                                          Display the custom fields about one Runtime identified by a Shoot name.
   kcp runtimes -o custom="INSTANCE ID:instanceID,SHOOTNAME:shootName,runtimeID:runtimeID,STATUS:{status.provisioning}"
-                                                         Display all Runtimes with specific custom fields.`,
+                                                         Display all Runtimes with specific custom fields.
+  kcp runtimes --account CA4836781TID000000000123456789 --watch
+                                                         Keep refreshing the Runtimes of a given global account, and highlight the ones whose state changed.
+  kcp runtimes --plan trial --sort-by status.createdAt   Display all trial Runtimes sorted by their creation time.
+  kcp runtimes -o jsonpath="{.data[*].shootName}"        Display the Shoot names of all Runtimes.`,
 		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
 		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
 	}
 	cmd.cobraCmd = cobraCmd
 
 	SetOutputOpt(cobraCmd, &cmd.output)
+	SetListOutputOpts(cobraCmd, &cmd.noHeaders, &cmd.sortBy)
+	SetWatchOpts(cobraCmd, &cmd.watch, &cmd.interval)
 	cobraCmd.Flags().StringSliceVarP(&cmd.params.Shoots, "shoot", "c", nil, "Filter by Shoot cluster name. You can provide multiple values, either separated by a comma (e.g. shoot1,shoot2), or by specifying the option multiple times.")
 	cobraCmd.Flags().StringSliceVarP(&cmd.params.GlobalAccountIDs, "account", "g", nil, "Filter by global account ID. You can provide multiple values, either separated by a comma (e.g. GAID1,GAID2), or by specifying the option multiple times.")
 	cobraCmd.Flags().StringSliceVarP(&cmd.params.SubAccountIDs, "subaccount", "s", nil, "Filter by subaccount ID. You can provide multiple values, either separated by a comma (e.g. SAID1,SAID2), or by specifying the option multiple times.")
@@ -95,6 +106,12 @@ The command supports filtering Runtimes based on various attributes. See the lis
 	cobraCmd.Flags().StringSliceVarP(&cmd.params.Regions, "region", "r", nil, "Filter by provider region. You can provide multiple values, either separated by a comma (e.g. westeurope,northeurope), or by specifying the option multiple times.")
 	cobraCmd.Flags().StringSliceVarP(&cmd.params.Plans, "plan", "p", nil, "Filter by service plan name. You can provide multiple values, either separated by a comma (e.g. azure,trial), or by specifying the option multiple times.")
 
+	cobraCmd.AddCommand(
+		NewRuntimeSuspendCmd(),
+		NewRuntimeUnsuspendCmd(),
+		NewRuntimeDeprovisionCmd(),
+		NewRuntimeReprovisionCmd(),
+	)
 	return cobraCmd
 }
 
@@ -102,6 +119,9 @@ The command supports filtering Runtimes based on various attributes. See the lis
 func (cmd *RuntimeCommand) Run() error {
 	cmd.log = logger.New()
 	client := runtime.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), CLICredentialManager(cmd.log))
+	if cmd.watch {
+		return cmd.watchRuntimes(client)
+	}
 
 	rp, err := client.ListRuntimes(cmd.params)
 	if err != nil {
@@ -121,34 +141,39 @@ func (cmd *RuntimeCommand) Validate() error {
 	if err != nil {
 		return err
 	}
-	return nil
+	return ValidateWatchOpts(cmd.watch, cmd.interval, cmd.output)
 }
 
-func (cmd *RuntimeCommand) printRuntimes(runtimes runtime.RuntimesPage) error {
-	switch {
-	case cmd.output == tableOutput:
-		tp, err := printer.NewTablePrinter(tableColumns, false)
+// watchRuntimes keeps refreshing the runtimes table, highlighting the runtimes whose state changed since the previous refresh
+func (cmd *RuntimeCommand) watchRuntimes(client runtime.Client) error {
+	states := newStatusTracker()
+	highlight := func(obj interface{}) bool {
+		rt := obj.(runtime.RuntimeDTO)
+		return states.changed(rt.InstanceID, runtimeStatus(rt))
+	}
+
+	return watch(cmd.cobraCmd.Context(), "kcp runtimes", cmd.interval, func(w io.Writer) (bool, error) {
+		rp, err := client.ListRuntimes(cmd.params)
 		if err != nil {
-			return err
+			return false, errors.Wrap(err, "while listing runtimes")
 		}
-		return tp.PrintObj(runtimes.Data)
-	case cmd.output == jsonOutput:
-		jp := printer.NewJSONPrinter("  ")
-		jp.PrintObj(runtimes)
-	case strings.HasPrefix(cmd.output, customOutput):
-		_, templateFile := printer.ParseOutputToTemplateTypeAndElement(cmd.output)
-		column, err := printer.ParseColumnToHeaderAndFieldSpec(templateFile)
-		if err != nil {
-			return err
+		states.next()
+		if cmd.sortBy != "" {
+			err = printer.SortObjects(rp.Data, cmd.sortBy)
+			if err != nil {
+				return false, errors.Wrap(err, "while sorting runtimes")
+			}
 		}
-
-		ccp, err := printer.NewTablePrinter(column, false)
+		tp, err := printer.NewHighlightingTablePrinter(tableColumns, cmd.noHeaders, w, highlight)
 		if err != nil {
-			return err
+			return false, err
 		}
-		return ccp.PrintObj(runtimes.Data)
-	}
-	return nil
+		return false, tp.PrintObj(rp.Data)
+	})
+}
+
+func (cmd *RuntimeCommand) printRuntimes(runtimes runtime.RuntimesPage) error {
+	return printList(cmd.output, tableColumns, cmd.noHeaders, cmd.sortBy, runtimes, runtimes.Data)
 }
 
 func runtimeStatus(obj interface{}) string {
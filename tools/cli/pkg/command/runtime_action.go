@@ -0,0 +1,167 @@
+package command
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type runtimeAction string
+
+const (
+	suspendAction     runtimeAction = "suspend"
+	unsuspendAction   runtimeAction = "unsuspend"
+	deprovisionAction runtimeAction = "deprovision"
+	reprovisionAction runtimeAction = "reprovision"
+)
+
+// RuntimeActionCommand represents an execution of one of the kcp runtimes suspend, unsuspend, deprovision, or reprovision commands
+type RuntimeActionCommand struct {
+	cobraCmd *cobra.Command
+	log      logger.Logger
+	client   runtime.Client
+	action   runtimeAction
+	yes      bool
+}
+
+// NewRuntimeSuspendCmd constructs the kcp runtimes suspend command
+func NewRuntimeSuspendCmd() *cobra.Command {
+	return newRuntimeActionCmd(suspendAction,
+		"Suspends a trial Kyma Runtime.",
+		`Suspends a trial Kyma Runtime, in the same way as the deactivation of the trial in the global account.
+The Runtime is deprovisioned, but the instance is kept, so the Runtime can be unsuspended later.`,
+		`  kcp runtimes suspend c-178e034    Suspend the trial Runtime identified by a Shoot name.`)
+}
+
+// NewRuntimeUnsuspendCmd constructs the kcp runtimes unsuspend command
+func NewRuntimeUnsuspendCmd() *cobra.Command {
+	return newRuntimeActionCmd(unsuspendAction,
+		"Unsuspends a suspended trial Kyma Runtime.",
+		`Unsuspends a suspended trial Kyma Runtime, in the same way as the activation of the trial in the global account.
+A new Runtime is provisioned for the instance with the parameters of the original provisioning.`,
+		`  kcp runtimes unsuspend c-178e034    Unsuspend the trial Runtime identified by a Shoot name.`)
+}
+
+// NewRuntimeDeprovisionCmd constructs the kcp runtimes deprovision command
+func NewRuntimeDeprovisionCmd() *cobra.Command {
+	return newRuntimeActionCmd(deprovisionAction,
+		"Deprovisions a Kyma Runtime.",
+		`Deprovisions a Kyma Runtime in the same way as the deprovisioning requested through the OSB API.
+The Runtime and the instance are removed permanently.`,
+		`  kcp runtimes deprovision 6d2e1a5c-0b4f-4f2a-9e1d-5c0e6c3b8a71    Deprovision the Runtime identified by a Runtime ID.`)
+}
+
+// NewRuntimeReprovisionCmd constructs the kcp runtimes reprovision command
+func NewRuntimeReprovisionCmd() *cobra.Command {
+	return newRuntimeActionCmd(reprovisionAction,
+		"Reprovisions a trial Kyma Runtime.",
+		`Reprovisions a broken trial Kyma Runtime. Kyma Environment Broker suspends the Runtime, and unsuspends it when the suspension succeeds.
+The instance is kept, and the new Runtime is provisioned with the parameters of the original provisioning. The command returns the ID of the suspension operation.`,
+		`  kcp runtimes reprovision c-178e034    Reprovision the trial Runtime identified by a Shoot name.`)
+}
+
+func newRuntimeActionCmd(action runtimeAction, short, long, example string) *cobra.Command {
+	cmd := RuntimeActionCommand{action: action}
+	cobraCmd := &cobra.Command{
+		Use:     fmt.Sprintf("%s <id>", action),
+		Short:   short,
+		Long:    long + "\nThe Runtime can be identified by its Runtime ID, instance ID, or Shoot name. The command asks for a confirmation before performing the action.",
+		Example: example,
+		Args:    cobra.ExactArgs(1),
+		RunE:    func(_ *cobra.Command, args []string) error { return cmd.Run(args[0]) },
+	}
+	cmd.cobraCmd = cobraCmd
+
+	cobraCmd.Flags().BoolVarP(&cmd.yes, "yes", "y", false, "Perform the action without asking for a confirmation.")
+
+	return cobraCmd
+}
+
+// Run executes the runtime action command on the Runtime identified by the given ID
+func (cmd *RuntimeActionCommand) Run(id string) error {
+	cmd.log = logger.New()
+	cmd.client = runtime.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), CLICredentialManager(cmd.log))
+
+	rt, err := cmd.findRuntime(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Runtime %s (Shoot: %s, instance ID: %s, plan: %s, state: %s) will be %s.\n", rt.RuntimeID, rt.ShootName, rt.InstanceID, rt.ServicePlanName, runtimeStatus(rt), actionParticiple(cmd.action))
+	if !cmd.yes && !confirmed() {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	switch cmd.action {
+	case suspendAction:
+		return cmd.runAction(cmd.client.SuspendRuntime, rt.InstanceID)
+	case unsuspendAction:
+		return cmd.runAction(cmd.client.UnsuspendRuntime, rt.InstanceID)
+	case deprovisionAction:
+		return cmd.runAction(cmd.client.DeprovisionRuntime, rt.InstanceID)
+	case reprovisionAction:
+		return cmd.runAction(cmd.client.ReprovisionRuntime, rt.InstanceID)
+	}
+
+	return fmt.Errorf("unknown runtime action: %s", cmd.action)
+}
+
+// findRuntime looks up the Runtime by its Runtime ID, instance ID, or Shoot name
+func (cmd *RuntimeActionCommand) findRuntime(id string) (runtime.RuntimeDTO, error) {
+	for _, params := range []runtime.ListParameters{
+		{RuntimeIDs: []string{id}},
+		{InstanceIDs: []string{id}},
+		{Shoots: []string{id}},
+	} {
+		rp, err := cmd.client.ListRuntimes(params)
+		if err != nil {
+			return runtime.RuntimeDTO{}, errors.Wrap(err, "while listing runtimes")
+		}
+		switch {
+		case rp.Count == 1:
+			return rp.Data[0], nil
+		case rp.Count > 1:
+			return runtime.RuntimeDTO{}, fmt.Errorf("%d runtimes match %s, use the instance ID to identify the runtime", rp.Count, id)
+		}
+	}
+
+	return runtime.RuntimeDTO{}, fmt.Errorf("runtime %s not found", id)
+}
+
+func (cmd *RuntimeActionCommand) runAction(action func(instanceID string) (runtime.ActionResponse, error), instanceID string) error {
+	resp, err := action(instanceID)
+	if err != nil {
+		return errors.Wrapf(err, "while running %s action", cmd.action)
+	}
+	fmt.Printf("Operation %s started.\n", resp.OperationID)
+
+	return nil
+}
+
+// confirmed asks the user whether to continue, and returns true if the answer is Y
+func confirmed() bool {
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print("Do you want to continue? (Y/N) ")
+	scanner.Scan()
+	return scanner.Text() == "Y"
+}
+
+func actionParticiple(action runtimeAction) string {
+	switch action {
+	case suspendAction:
+		return "suspended"
+	case unsuspendAction:
+		return "unsuspended"
+	case deprovisionAction:
+		return "deprovisioned"
+	case reprovisionAction:
+		return "reprovisioned"
+	}
+	return string(action)
+}
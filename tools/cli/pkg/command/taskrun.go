@@ -2,12 +2,14 @@ package command
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -18,7 +20,9 @@ import (
 	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/credential"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/printer"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -36,7 +40,11 @@ type TaskRunCommand struct {
 	keepKubeconfigs     bool
 	noKubeconfig        bool
 	noPrefixOutput      bool
-	taskCommand         *exec.Cmd
+	outputDir           string
+	report              string
+	timeout             time.Duration
+	retryFailed         string
+	taskArgs            []string
 }
 
 // RuntimeLister implements the interface to obtains runtimes info from KEB for resolver
@@ -48,6 +56,10 @@ type RuntimeLister struct {
 type RuntimeTask struct {
 	operation orchestration.RuntimeOperation
 	result    error
+	exitCode  int
+	duration  time.Duration
+	lastLines []string
+	mux       sync.Mutex
 }
 
 // RuntimeTaskMakager implements Executor interface needed by strategy to execute the runtime task operations.
@@ -85,13 +97,22 @@ For each subprocess, the following Runtime-specific data are passed as environme
   - RUNTIME_ID       : Runtime ID of the Runtime
   - INSTANCE_ID      : Instance ID of the Runtime
 
-  If all subprocesses finish successfully with the zero status code, the exit status is zero (0). If one or more subprocesses exit with a non-zero status, the command will also exit with a non-zero status.`,
+  If all subprocesses finish successfully with the zero status code, the exit status is zero (0). If one or more subprocesses exit with a non-zero status, the command will also exit with a non-zero status.
+
+The results of the subprocesses can be collected with the following options:
+  - --output-dir stores the stdout and stderr of each subprocess in the {SHOOT}.stdout and {SHOOT}.stderr files, and the JSON report of the execution in the report.json file of the given directory.
+  - --report prints the summary of the execution, listing each Runtime with the exit code, duration, and the last lines of the output.
+  - --retry-failed executes the command only on the Runtimes which failed according to the JSON report of a previous execution, instead of the --target options.`,
 		Example: `  kcp taskrun --target all -- kubectl patch deployment valid-deployment -p '{"metadata":{"labels":{"my-label": "my-value"}}}'
     Execute a kubectl patch operation for all Runtimes.
   kcp taskrun --target account=CA4836781TID000000000123456789 /usr/local/bin/awesome-script.sh
     Run a maintenance script for all Runtimes of a given global account.
   kcp taskrun --target all -- helm upgrade -i -n kyma-system my-kyma-addon --values overrides.yaml
-    Deploy a Helm chart on all Runtimes.`,
+    Deploy a Helm chart on all Runtimes.
+  kcp taskrun --target all --output-dir results --report table --timeout 10m -- /usr/local/bin/awesome-script.sh
+    Run a maintenance script for all Runtimes, stop it after 10 minutes on each Runtime, and collect the results in the results directory.
+  kcp taskrun --retry-failed results/report.json --output-dir retry -- /usr/local/bin/awesome-script.sh
+    Run the maintenance script again on the Runtimes on which it failed in the previous execution.`,
 		Args:    cobra.MinimumNArgs(1),
 		PreRunE: func(_ *cobra.Command, args []string) error { return cmd.Validate(args) },
 		RunE:    func(_ *cobra.Command, args []string) error { return cmd.Run(args) },
@@ -104,6 +125,10 @@ For each subprocess, the following Runtime-specific data are passed as environme
 	cobraCmd.Flags().BoolVar(&cmd.keepKubeconfigs, "keep-kubeconfig", false, "Option that allows you to keep downloaded kubeconfig files after execution for caching purposes.")
 	cobraCmd.Flags().BoolVar(&cmd.noKubeconfig, "no-kubeconfig", false, "Option that turns off the downloading and exposure of the kubeconfig file for each Runtime.")
 	cobraCmd.Flags().BoolVar(&cmd.noPrefixOutput, "no-prefix-output", false, "Option that omits the prefixing of each output line with the Runtime name. By default, all output lines are prepended for better traceability.")
+	cobraCmd.Flags().StringVar(&cmd.outputDir, "output-dir", "", "Directory to store the stdout and stderr of the command for each Runtime, and the JSON report of the execution. The directory is created if it does not exist.")
+	cobraCmd.Flags().StringVar(&cmd.report, "report", "", fmt.Sprintf("Prints the summary report of the execution in the given format. The possible values are: %s, %s.", jsonOutput, tableOutput))
+	cobraCmd.Flags().DurationVar(&cmd.timeout, "timeout", 0, "Maximum duration of the command on each Runtime, e.g. 30s or 10m. The command is killed and considered failed when it exceeds the timeout. By default, there is no timeout.")
+	cobraCmd.Flags().StringVar(&cmd.retryFailed, "retry-failed", "", "Path to the JSON report of a previous execution. The command is executed only on the Runtimes on which it failed according to the report. This option cannot be used together with the --target options.")
 	return cobraCmd
 }
 
@@ -113,13 +138,19 @@ func (cmd *TaskRunCommand) Run(args []string) error {
 	cmd.cred = CLICredentialManager(cmd.log)
 	defer cmd.cleanupTempKubeConfigDir()
 
+	if len(cmd.targets.Include) == 0 {
+		// Only possible with --retry-failed
+		fmt.Println("No failed Runtimes found in the report, there is nothing to retry.")
+		return nil
+	}
+
 	operations, err := cmd.resolveOperations()
 	if err != nil {
 		return err
 	}
 
 	mgr := NewRuntimeTaskMakager(cmd, operations)
-	strategy := strategies.NewParallelOrchestrationStrategy(mgr, cmd.log)
+	strategy := strategies.NewParallelOrchestrationStrategy(mgr, cmd.log, 0)
 	execID, err := strategy.Execute(operations, orchestration.StrategySpec{
 		Type:     orchestration.ParallelStrategy,
 		Schedule: orchestration.Immediate,
@@ -130,6 +161,11 @@ func (cmd *TaskRunCommand) Run(args []string) error {
 	}
 	strategy.Wait(execID)
 
+	err = cmd.reportResults(newTaskRunReport(cmd.taskArgs, mgr.tasks))
+	if err != nil {
+		return err
+	}
+
 	return mgr.exitStatus()
 }
 
@@ -145,10 +181,39 @@ func (cmd *TaskRunCommand) Validate(args []string) error {
 		return fmt.Errorf("missing required %s/%s options", GlobalOpts.gardenerKubeconfig, GlobalOpts.gardenerNamespace)
 	}
 
-	// Validate target options
-	err := ValidateTransformRuntimeTargetOpts(cmd.targetInputs, cmd.targetExcludeInputs, &cmd.targets)
-	if err != nil {
-		return err
+	// Validate target options, or read them from the report of the previous execution
+	var err error
+	if cmd.retryFailed != "" {
+		if len(cmd.targetInputs) > 0 || len(cmd.targetExcludeInputs) > 0 {
+			return errors.New("--retry-failed cannot be used together with --target or --target-exclude")
+		}
+		var report TaskRunReport
+		report, err = readTaskRunReport(cmd.retryFailed)
+		if err != nil {
+			return err
+		}
+		cmd.targets = failedRuntimeTargets(report)
+	} else {
+		err = ValidateTransformRuntimeTargetOpts(cmd.targetInputs, cmd.targetExcludeInputs, &cmd.targets)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Validate result options
+	switch cmd.report {
+	case "", jsonOutput, tableOutput:
+	default:
+		return fmt.Errorf("invalid value for report: %s", cmd.report)
+	}
+	if cmd.timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	if cmd.outputDir != "" {
+		err = os.MkdirAll(cmd.outputDir, 0755)
+		if err != nil {
+			return errors.Wrap(err, "while creating output directory")
+		}
 	}
 
 	// Validate kubeconfig directory
@@ -172,26 +237,15 @@ func (cmd *TaskRunCommand) Validate(args []string) error {
 	if _, err := exec.LookPath(args[0]); err != nil {
 		return err
 	}
-	cmd.taskCommand = exec.CommandContext(cmd.cobraCmd.Context(), args[0], args[1:]...)
+	cmd.taskArgs = args
 
 	return nil
 }
 
 func (cmd *TaskRunCommand) resolveOperations() ([]orchestration.RuntimeOperation, error) {
-	gardenCfg, err := gardener.NewGardenerClusterConfig(GlobalOpts.GardenerKubeconfig())
+	runtimes, err := resolveRuntimeTargets(cmd.cobraCmd.Context(), cmd.cred, cmd.targets, cmd.log)
 	if err != nil {
-		return nil, errors.Wrap(err, "while getting Gardener kubeconfig")
-	}
-	gardenClient, err := gardener.NewClient(gardenCfg)
-	if err != nil {
-		return nil, errors.Wrap(err, "while getting Gardener client")
-	}
-
-	lister := NewRuntimeLister(runtime.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), cmd.cred))
-	resolver := orchestration.NewGardenerRuntimeResolver(gardenClient, GlobalOpts.GardenerNamespace(), lister, cmd.log)
-	runtimes, err := resolver.Resolve(cmd.targets)
-	if err != nil {
-		return nil, errors.Wrap(err, "while resolving targets")
+		return nil, err
 	}
 
 	cmd.log.Infof("Number of resolved runtimes: %d\n", len(runtimes))
@@ -206,6 +260,30 @@ func (cmd *TaskRunCommand) resolveOperations() ([]orchestration.RuntimeOperation
 	return operations, nil
 }
 
+// reportResults stores the report in the output directory, and prints it in the requested format
+func (cmd *TaskRunCommand) reportResults(report TaskRunReport) error {
+	if cmd.outputDir != "" {
+		err := writeTaskRunReport(filepath.Join(cmd.outputDir, reportFile), report)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch cmd.report {
+	case tableOutput:
+		tp, err := printer.NewTablePrinter(reportColumns, false)
+		if err != nil {
+			return err
+		}
+		return tp.PrintObj(report.Results)
+	case jsonOutput:
+		jp := printer.NewJSONPrinter("  ")
+		return jp.PrintObj(report)
+	}
+
+	return nil
+}
+
 func (cmd *TaskRunCommand) cleanupTempKubeConfigDir() error {
 	var err error = nil
 	if cmd.kubeconfingDirTemp {
@@ -230,6 +308,27 @@ func (rl RuntimeLister) ListAllRuntimes() ([]runtime.RuntimeDTO, error) {
 	return res.Data, nil
 }
 
+// resolveRuntimeTargets resolves the runtime targets to the matching runtimes using KEB and Gardener
+func resolveRuntimeTargets(ctx context.Context, cred credential.Manager, targets orchestration.TargetSpec, log logger.Logger) ([]orchestration.Runtime, error) {
+	gardenCfg, err := gardener.NewGardenerClusterConfig(GlobalOpts.GardenerKubeconfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting Gardener kubeconfig")
+	}
+	gardenClient, err := gardener.NewClient(gardenCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "while getting Gardener client")
+	}
+
+	lister := NewRuntimeLister(runtime.NewClient(ctx, GlobalOpts.KEBAPIURL(), cred))
+	resolver := orchestration.NewGardenerRuntimeResolver(gardenClient, GlobalOpts.GardenerNamespace(), lister, log)
+	runtimes, err := resolver.Resolve(targets)
+	if err != nil {
+		return nil, errors.Wrap(err, "while resolving targets")
+	}
+
+	return runtimes, nil
+}
+
 // NewRuntimeTaskMakager constructs a new RuntimeTaskMakager for the given runtime operations
 func NewRuntimeTaskMakager(cmd *TaskRunCommand, operations []orchestration.RuntimeOperation) *RuntimeTaskMakager {
 	mgr := &RuntimeTaskMakager{
@@ -250,15 +349,41 @@ func NewRuntimeTaskMakager(cmd *TaskRunCommand, operations []orchestration.Runti
 func (mgr *RuntimeTaskMakager) Execute(operationID string) (time.Duration, error) {
 	task := mgr.tasks[operationID]
 	log := mgr.cmd.log.WithField("shoot", task.operation.ShootName)
+	start := time.Now()
+	defer func() {
+		task.mux.Lock()
+		task.duration = time.Since(start)
+		task.mux.Unlock()
+	}()
+
+	err := mgr.execute(task, log)
+	task.mux.Lock()
+	task.result = err
+	task.exitCode = exitCode(err)
+	task.mux.Unlock()
+
+	return 0, err
+}
 
+// Reschedule is a no-op, the tasks are executed immediately and never wait for a maintenance window
+func (mgr *RuntimeTaskMakager) Reschedule(operationID string, maintenanceWindowBegin, maintenanceWindowEnd time.Time) error {
+	return nil
+}
+
+func (mgr *RuntimeTaskMakager) execute(task *RuntimeTask, log logrus.FieldLogger) error {
 	kubeconfigPath, err := mgr.getKubeconfig(task)
 	if err != nil {
 		log.Errorf("Error: while getting kubeconfig: %s\n", err.Error())
-		task.result = err
-		return 0, err
+		return errors.Wrap(err, "while getting kubeconfig")
 	}
 
-	command := *mgr.cmd.taskCommand
+	ctx := mgr.cmd.cobraCmd.Context()
+	if mgr.cmd.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, mgr.cmd.timeout)
+		defer cancel()
+	}
+	command := exec.CommandContext(ctx, mgr.cmd.taskArgs[0], mgr.cmd.taskArgs[1:]...)
 
 	// Prepare environment variables
 	command.Env = os.Environ()
@@ -277,47 +402,82 @@ func (mgr *RuntimeTaskMakager) Execute(operationID string) (time.Duration, error
 	stdout, err := command.StdoutPipe()
 	if err != nil {
 		log.Errorf("Error: while creating stdout: %s\n", err.Error())
-		task.result = err
-		return 0, err
+		return err
 	}
 	stderr, err := command.StderrPipe()
 	if err != nil {
 		log.Errorf("Error: while creating stderr: %s\n", err.Error())
-		task.result = err
-		return 0, err
+		return err
 	}
 
-	// Prepare echoer stdout / stderr writers
+	// Prepare the output files of the command
+	stdoutFile, stderrFile := ioutil.Discard, ioutil.Discard
+	if mgr.cmd.outputDir != "" {
+		outFile, err := os.Create(filepath.Join(mgr.cmd.outputDir, fmt.Sprintf("%s.stdout", task.operation.ShootName)))
+		if err != nil {
+			log.Errorf("Error: while creating stdout file: %s\n", err.Error())
+			return err
+		}
+		defer outFile.Close()
+		errFile, err := os.Create(filepath.Join(mgr.cmd.outputDir, fmt.Sprintf("%s.stderr", task.operation.ShootName)))
+		if err != nil {
+			log.Errorf("Error: while creating stderr file: %s\n", err.Error())
+			return err
+		}
+		defer errFile.Close()
+		stdoutFile, stderrFile = outFile, errFile
+	}
+
+	// Prepare echoer stdout / stderr writers, which also store the output in the files and keep the last lines for the report
 	echoerWg := sync.WaitGroup{}
-	echoer := func(src io.Reader, dst io.Writer) {
+	echoer := func(src io.Reader, dst io.Writer, file io.Writer) {
 		scanner := bufio.NewScanner(src)
 		for scanner.Scan() {
 			if !mgr.cmd.noPrefixOutput {
 				fmt.Fprintf(dst, "%s ", task.operation.ShootName)
 			}
 			fmt.Fprintln(dst, scanner.Text())
+			fmt.Fprintln(file, scanner.Text())
+			task.recordLine(scanner.Text())
 		}
 		if err := scanner.Err(); err != nil {
 			log.Errorf("Error: while reading from child process: %s\n", err)
 		}
 		echoerWg.Done()
 	}
-	echoerWg.Add(2)
-	go echoer(stdout, os.Stdout)
-	go echoer(stderr, os.Stderr)
 
 	// Start execution of the command
 	err = command.Start()
+	if err != nil {
+		log.Errorf("Error: while starting command: %s\n", err.Error())
+		return err
+	}
+	echoerWg.Add(2)
+	go echoer(stdout, os.Stdout, stdoutFile)
+	go echoer(stderr, os.Stderr, stderrFile)
 
 	// Wait for the command subprocess to finish
 	echoerWg.Wait()
 	err = command.Wait()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("command timed out after %s", mgr.cmd.timeout)
+	}
 	if err != nil {
 		log.Errorf("Error: command exited with error: %s\n", err.Error())
 	}
-	task.result = err
 
-	return 0, err
+	return err
+}
+
+// exitCode returns the exit code of the command based on the error of its execution, or -1 if the command did not exit by itself
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
 }
 
 func (mgr *RuntimeTaskMakager) getKubeconfig(task *RuntimeTask) (string, error) {
@@ -0,0 +1,168 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/printer"
+	"github.com/pkg/errors"
+)
+
+const (
+	reportFile = "report.json"
+	// reportOutputLines is the number of the last output lines of a task kept in the report
+	reportOutputLines = 5
+)
+
+// TaskRunReport is the summary of a taskrun execution, which lists the result of the task for each Runtime
+type TaskRunReport struct {
+	Command []string     `json:"command"`
+	Results []TaskResult `json:"results"`
+}
+
+// TaskResult is the result of the task executed on one Runtime
+type TaskResult struct {
+	GlobalAccountID string `json:"globalAccountID"`
+	SubAccountID    string `json:"subAccountID"`
+	RuntimeID       string `json:"runtimeID"`
+	InstanceID      string `json:"instanceID"`
+	ShootName       string `json:"shootName"`
+	// ExitCode is the exit code of the command, or -1 if the command did not exit by itself, e.g. it could not be started or it timed out
+	ExitCode int    `json:"exitCode"`
+	Duration string `json:"duration"`
+	// Error describes why the task failed, if it did
+	Error string `json:"error,omitempty"`
+	// Output holds the last lines of the combined stdout and stderr of the command
+	Output []string `json:"output"`
+}
+
+var reportColumns = []printer.Column{
+	{
+		Header:    "SHOOT",
+		FieldSpec: "{.ShootName}",
+	},
+	{
+		Header:    "RUNTIME ID",
+		FieldSpec: "{.RuntimeID}",
+	},
+	{
+		Header:    "EXIT CODE",
+		FieldSpec: "{.ExitCode}",
+	},
+	{
+		Header:    "DURATION",
+		FieldSpec: "{.Duration}",
+	},
+	{
+		Header:         "LAST OUTPUT",
+		FieldFormatter: taskResultLastOutput,
+	},
+}
+
+// Failed returns whether the task failed on the Runtime
+func (r TaskResult) Failed() bool {
+	return r.ExitCode != 0 || r.Error != ""
+}
+
+// newTaskRunReport creates the report of the given tasks, sorted by the Shoot names
+func newTaskRunReport(command []string, tasks map[string]*RuntimeTask) TaskRunReport {
+	report := TaskRunReport{Command: command}
+	for _, task := range tasks {
+		report.Results = append(report.Results, task.toResult())
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].ShootName < report.Results[j].ShootName
+	})
+
+	return report
+}
+
+// readTaskRunReport reads the JSON report of a previous taskrun execution from the given file
+func readTaskRunReport(path string) (TaskRunReport, error) {
+	report := TaskRunReport{}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return report, errors.Wrap(err, "while reading report")
+	}
+	err = json.Unmarshal(data, &report)
+	if err != nil {
+		return report, errors.Wrapf(err, "while decoding report %s", path)
+	}
+
+	return report, nil
+}
+
+// writeTaskRunReport writes the report in JSON format to the given file
+func writeTaskRunReport(path string, report TaskRunReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "while encoding report")
+	}
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		return errors.Wrap(err, "while writing report")
+	}
+
+	return nil
+}
+
+// failedRuntimeTargets returns the target spec selecting the Runtimes on which the task failed according to the report
+func failedRuntimeTargets(report TaskRunReport) orchestration.TargetSpec {
+	targets := orchestration.TargetSpec{}
+	for _, result := range report.Results {
+		if result.Failed() {
+			targets.Include = append(targets.Include, orchestration.RuntimeTarget{RuntimeID: result.RuntimeID})
+		}
+	}
+
+	return targets
+}
+
+func (t *RuntimeTask) toResult() TaskResult {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	result := TaskResult{
+		GlobalAccountID: t.operation.GlobalAccountID,
+		SubAccountID:    t.operation.SubAccountID,
+		RuntimeID:       t.operation.RuntimeID,
+		InstanceID:      t.operation.InstanceID,
+		ShootName:       t.operation.ShootName,
+		ExitCode:        t.exitCode,
+		Duration:        t.duration.Round(time.Millisecond).String(),
+		Output:          append([]string{}, t.lastLines...),
+	}
+	if t.result != nil {
+		result.Error = t.result.Error()
+	}
+
+	return result
+}
+
+// recordLine keeps the given output line among the last lines of the task
+func (t *RuntimeTask) recordLine(line string) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	t.lastLines = append(t.lastLines, line)
+	if len(t.lastLines) > reportOutputLines {
+		t.lastLines = t.lastLines[len(t.lastLines)-reportOutputLines:]
+	}
+}
+
+// taskResultLastOutput returns the last output line of the task, or the error if the task failed without an exit code
+func taskResultLastOutput(obj interface{}) string {
+	result := obj.(TaskResult)
+	if result.ExitCode == -1 && result.Error != "" {
+		return fmt.Sprintf("Error: %s", result.Error)
+	}
+	if len(result.Output) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(result.Output[len(result.Output)-1])
+}
@@ -0,0 +1,190 @@
+package command
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskRunReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		tasks map[string]*RuntimeTask
+		want  []TaskResult
+	}{
+		{
+			name:  "no tasks",
+			tasks: map[string]*RuntimeTask{},
+			want:  nil,
+		},
+		{
+			name: "results sorted by shoot name",
+			tasks: map[string]*RuntimeTask{
+				"runtime-b": fixRuntimeTask("runtime-b", "shoot-b", 0, nil, time.Second, "done"),
+				"runtime-a": fixRuntimeTask("runtime-a", "shoot-a", 1, nil, 1500*time.Microsecond, "failed"),
+			},
+			want: []TaskResult{
+				{RuntimeID: "runtime-a", ShootName: "shoot-a", ExitCode: 1, Duration: "2ms", Output: []string{"failed"}},
+				{RuntimeID: "runtime-b", ShootName: "shoot-b", ExitCode: 0, Duration: "1s", Output: []string{"done"}},
+			},
+		},
+		{
+			name: "error of task not started",
+			tasks: map[string]*RuntimeTask{
+				"runtime-a": fixRuntimeTask("runtime-a", "shoot-a", -1, errors.New("kubeconfig not found"), 0),
+			},
+			want: []TaskResult{
+				{RuntimeID: "runtime-a", ShootName: "shoot-a", ExitCode: -1, Duration: "0s", Error: "kubeconfig not found", Output: []string{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			report := newTaskRunReport([]string{"kubectl", "get", "pods"}, tt.tasks)
+
+			// then
+			assert.Equal(t, []string{"kubectl", "get", "pods"}, report.Command)
+			assert.Equal(t, tt.want, report.Results)
+		})
+	}
+}
+
+func TestRuntimeTask_recordLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{
+			name:  "fewer lines than kept",
+			lines: []string{"1", "2"},
+			want:  []string{"1", "2"},
+		},
+		{
+			name:  "more lines than kept",
+			lines: []string{"1", "2", "3", "4", "5", "6", "7"},
+			want:  []string{"3", "4", "5", "6", "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			task := &RuntimeTask{}
+
+			// when
+			for _, line := range tt.lines {
+				task.recordLine(line)
+			}
+
+			// then
+			assert.Equal(t, tt.want, task.lastLines)
+		})
+	}
+}
+
+func TestFailedRuntimeTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []TaskResult
+		want    []orchestration.RuntimeTarget
+	}{
+		{
+			name:    "all succeeded",
+			results: []TaskResult{{RuntimeID: "runtime-a"}, {RuntimeID: "runtime-b"}},
+			want:    nil,
+		},
+		{
+			name: "failed with exit code or error",
+			results: []TaskResult{
+				{RuntimeID: "runtime-a", ExitCode: 2},
+				{RuntimeID: "runtime-b"},
+				{RuntimeID: "runtime-c", ExitCode: -1, Error: "timed out"},
+			},
+			want: []orchestration.RuntimeTarget{{RuntimeID: "runtime-a"}, {RuntimeID: "runtime-c"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			targets := failedRuntimeTargets(TaskRunReport{Results: tt.results})
+
+			// then
+			assert.Equal(t, tt.want, targets.Include)
+			assert.Empty(t, targets.Exclude)
+		})
+	}
+}
+
+func TestTaskResultLastOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		result TaskResult
+		want   string
+	}{
+		{
+			name:   "no output",
+			result: TaskResult{},
+			want:   "",
+		},
+		{
+			name:   "last output line",
+			result: TaskResult{ExitCode: 1, Error: "exit status 1", Output: []string{"first", "  last  "}},
+			want:   "last",
+		},
+		{
+			name:   "error of task without exit code",
+			result: TaskResult{ExitCode: -1, Error: "timed out", Output: []string{"partial"}},
+			want:   "Error: timed out",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, taskResultLastOutput(tt.result))
+		})
+	}
+}
+
+func TestTaskRunReport_WriteRead(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), reportFile)
+	report := TaskRunReport{
+		Command: []string{"kubectl", "get", "pods"},
+		Results: []TaskResult{{RuntimeID: "runtime-a", ShootName: "shoot-a", ExitCode: 1, Duration: "1s", Output: []string{"failed"}}},
+	}
+
+	// when
+	require.NoError(t, writeTaskRunReport(path, report))
+	read, err := readTaskRunReport(path)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, report, read)
+
+	_, err = readTaskRunReport(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func fixRuntimeTask(runtimeID, shootName string, exitCode int, err error, duration time.Duration, lines ...string) *RuntimeTask {
+	task := &RuntimeTask{
+		operation: orchestration.RuntimeOperation{
+			Runtime: orchestration.Runtime{RuntimeID: runtimeID, ShootName: shootName},
+		},
+		result:   err,
+		exitCode: exitCode,
+		duration: duration,
+	}
+	for _, line := range lines {
+		task.recordLine(line)
+	}
+	return task
+}
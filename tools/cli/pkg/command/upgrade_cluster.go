@@ -0,0 +1,86 @@
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kyma-project/control-plane/components/kyma-environment-broker/common/orchestration"
+	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+// UpgradeClusterCommand represents an execution of the kcp upgrade cluster command. Inherits fields and methods of UpgradeCommand
+type UpgradeClusterCommand struct {
+	UpgradeCommand
+	cobraCmd *cobra.Command
+}
+
+// NewUpgradeClusterCmd constructs a new instance of UpgradeClusterCommand and configures it in terms of a cobra.Command
+func NewUpgradeClusterCmd() *cobra.Command {
+	cmd := UpgradeClusterCommand{UpgradeCommand: UpgradeCommand{}}
+	cobraCmd := &cobra.Command{
+		Use:   "cluster --target {TARGET SPEC} ... [--target-exclude {TARGET SPEC} ...]",
+		Short: "Upgrades or reconfigures the Kubernetes cluster of one or more Kyma Runtimes.",
+		Long: `Upgrades or reconfigures the Gardener Shoot clusters of targets of Runtimes.
+The upgrade is performed by Kyma Control Plane (KCP) within a new orchestration asynchronously. The ID of the orchestration is returned by the command upon success.
+The targets of Runtimes are specified via the --target and --target-exclude options. At least one --target must be specified.
+The Kubernetes version and the machine image version are specified using the --kubernetes-version and --machine-image-version options. If not specified, the versions are configured by Kyma Environment Broker (KEB).
+Additional cluster configurations to use for the upgrade are taken from Kyma Control Plane during the processing of the orchestration.`,
+		Example: `  kcp upgrade cluster --target all --schedule maintenancewindow       Upgrade the clusters of all Runtimes in their next respective maintenance window hours.
+  kcp upgrade cluster --target "account=CA.*"                         Upgrade the clusters of Runtimes of all global accounts starting with CA.
+  kcp upgrade cluster --target all --target-exclude "account=CA.*"    Upgrade the clusters of Runtimes of all global accounts not starting with CA.
+  kcp upgrade cluster --target all --kubernetes-version 1.19.8        Upgrade the clusters of all Runtimes to Kubernetes 1.19.8.
+  kcp upgrade cluster --target "plan=trial" --machine-image-version 184.0.0
+                                                                      Upgrade the machine image of the clusters of all trial Runtimes to version 184.0.0.`,
+		PreRunE: func(_ *cobra.Command, _ []string) error { return cmd.Validate() },
+		RunE:    func(_ *cobra.Command, _ []string) error { return cmd.Run() },
+	}
+	cmd.cobraCmd = cobraCmd
+
+	cmd.SetUpgradeOpts(cobraCmd)
+	return cobraCmd
+}
+
+// SetUpgradeOpts configures the upgrade cluster specific options on the given command
+func (cmd *UpgradeClusterCommand) SetUpgradeOpts(cobraCmd *cobra.Command) {
+	cmd.UpgradeCommand.SetUpgradeOpts(cobraCmd)
+	cobraCmd.Flags().StringVar(&cmd.orchestrationParams.Kubernetes.KubernetesVersion, "kubernetes-version", "", "Kubernetes version to use, e.g. 1.19.8. The version must be supported by Gardener for the cloud provider of the Runtime.")
+	cobraCmd.Flags().StringVar(&cmd.orchestrationParams.Kubernetes.MachineImageVersion, "machine-image-version", "", "Version of the machine image to use for the worker nodes, e.g. 184.0.0. The version must be supported by Gardener for the machine image of the Runtime.")
+}
+
+// Run executes the upgrade cluster command
+func (cmd *UpgradeClusterCommand) Run() error {
+	cmd.log = logger.New()
+	client := orchestration.NewClient(cmd.cobraCmd.Context(), GlobalOpts.KEBAPIURL(), CLICredentialManager(cmd.log))
+	ur, err := client.UpgradeCluster(cmd.orchestrationParams)
+	if err != nil {
+		return errors.Wrap(err, "while triggering cluster upgrade")
+	}
+	fmt.Println("OrchestrationID:", ur.OrchestrationID)
+	return nil
+}
+
+// Validate checks the input parameters of the upgrade cluster command
+func (cmd *UpgradeClusterCommand) Validate() error {
+	err := cmd.ValidateTransformUpgradeOpts()
+	if err != nil {
+		return err
+	}
+
+	// Validate versions
+	// Whether the versions are supported by the cloud provider is verified by Gardener during the upgrade
+	if err = validateUpgradeClusterVersionFmt("Kubernetes", cmd.orchestrationParams.Kubernetes.KubernetesVersion); err != nil {
+		return err
+	}
+	return validateUpgradeClusterVersionFmt("machine image", cmd.orchestrationParams.Kubernetes.MachineImageVersion)
+}
+
+func validateUpgradeClusterVersionFmt(name, version string) error {
+	if version == "" || semver.IsValid(fmt.Sprintf("v%s", version)) && strings.Count(version, ".") == 2 {
+		return nil
+	}
+
+	return fmt.Errorf("unsupported %s version format: %s, expected <major>.<minor>.<patch>", name, version)
+}
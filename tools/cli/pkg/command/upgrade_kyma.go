@@ -73,7 +73,7 @@ func (cmd *UpgradeKymaCommand) Validate() error {
 	if err = ValidateUpgradeKymaVersionFmt(cmd.version); err != nil {
 		return err
 	}
-	cmd.orchestrationParams.Version = cmd.version
+	cmd.orchestrationParams.Kyma.Version = cmd.version
 
 	return nil
 }
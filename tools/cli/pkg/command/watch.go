@@ -0,0 +1,72 @@
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// clearScreen moves the cursor to the top left corner of the terminal and clears the screen
+const clearScreen = "\033[H\033[2J"
+
+const defaultWatchInterval = 10 * time.Second
+
+// watchView renders one refresh of a watched view to the given writer.
+// It returns true when the view reached its final state and watching should stop.
+type watchView func(w io.Writer) (bool, error)
+
+// watch renders the view at every interval in place of the previous refresh, until the view reaches its final state or the context is done.
+// The errors of a refresh, e.g. a temporarily unavailable KEB, are displayed instead of the view, and watching continues.
+func watch(ctx context.Context, title string, interval time.Duration, view watchView) error {
+	for {
+		buf := &bytes.Buffer{}
+		done, err := view(buf)
+		if err != nil {
+			buf.Reset()
+			fmt.Fprintf(buf, "Error: %s\n", err)
+		}
+
+		fmt.Fprint(os.Stdout, clearScreen)
+		fmt.Fprintf(os.Stdout, "Every %s: %s\t%s\n\n", interval, title, time.Now().Format("2006/01/02 15:04:05"))
+		buf.WriteTo(os.Stdout)
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// statusTracker remembers the statuses of the objects displayed in watch mode to tell which ones changed since the previous refresh
+type statusTracker struct {
+	previous map[string]string
+	current  map[string]string
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{}
+}
+
+// next starts tracking a new refresh, it has to be called before recording the statuses of the refresh
+func (t *statusTracker) next() {
+	t.previous = t.current
+	t.current = map[string]string{}
+}
+
+// changed records the status of the object identified by the given key, and returns whether it differs from the previous refresh.
+// Nothing is reported as changed on the first refresh, objects appearing later are reported as changed.
+func (t *statusTracker) changed(key, status string) bool {
+	t.current[key] = status
+	if t.previous == nil {
+		return false
+	}
+	previous, ok := t.previous[key]
+	return !ok || previous != status
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatch(t *testing.T) {
+	type refresh struct {
+		done bool
+		err  error
+	}
+	tests := []struct {
+		name      string
+		refreshes []refresh
+		canceled  bool
+		wantCalls int
+	}{
+		{
+			name:      "view final on first refresh",
+			refreshes: []refresh{{done: true}},
+			wantCalls: 1,
+		},
+		{
+			name:      "view final after several refreshes",
+			refreshes: []refresh{{}, {}, {done: true}},
+			wantCalls: 3,
+		},
+		{
+			name:      "refresh errors do not stop watching",
+			refreshes: []refresh{{err: errors.New("KEB unavailable")}, {err: errors.New("KEB unavailable")}, {done: true}},
+			wantCalls: 3,
+		},
+		{
+			name:      "error of final refresh stops watching",
+			refreshes: []refresh{{done: true, err: errors.New("partial result")}},
+			wantCalls: 1,
+		},
+		{
+			name:      "context done",
+			refreshes: []refresh{{}},
+			canceled:  true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			interval := time.Millisecond
+			if tt.canceled {
+				cancel()
+				interval = time.Hour
+			}
+
+			calls := 0
+			view := func(w io.Writer) (bool, error) {
+				if calls >= len(tt.refreshes) {
+					t.Fatalf("unexpected refresh %d", calls+1)
+				}
+				r := tt.refreshes[calls]
+				calls++
+				fmt.Fprintf(w, "refresh %d\n", calls)
+				return r.done, r.err
+			}
+
+			// when
+			err := watch(ctx, "test", interval, view)
+
+			// then
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestStatusTracker(t *testing.T) {
+	// given
+	tracker := newStatusTracker()
+
+	// when the first refresh is recorded
+	tracker.next()
+
+	// then
+	assert.False(t, tracker.changed("op-1", "pending"))
+	assert.False(t, tracker.changed("op-2", "in progress"))
+
+	// when the next refresh is recorded
+	tracker.next()
+
+	// then
+	assert.True(t, tracker.changed("op-1", "in progress"))
+	assert.False(t, tracker.changed("op-2", "in progress"))
+	assert.True(t, tracker.changed("op-3", "pending"))
+}
@@ -2,6 +2,11 @@ package credential
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,38 +17,69 @@ import (
 	"github.com/int128/kubelogin/pkg/adaptors/oidcclient"
 	"github.com/int128/kubelogin/pkg/adaptors/reader"
 	"github.com/int128/kubelogin/pkg/adaptors/tokencache"
+	"github.com/int128/kubelogin/pkg/oidc"
 	"github.com/int128/kubelogin/pkg/usecases/authentication"
 	"github.com/int128/kubelogin/pkg/usecases/authentication/authcode"
 	"github.com/int128/kubelogin/pkg/usecases/authentication/ropc"
 	"github.com/int128/kubelogin/pkg/usecases/credentialplugin"
 	"github.com/kyma-project/control-plane/tools/cli/pkg/logger"
+	"github.com/pkg/errors"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 	"k8s.io/client-go/util/homedir"
 )
 
 var defaultTokenCacheDir = homedir.HomeDir() + "/.kube/cache/oidc-login"
 var defaultListenAddress = []string{"127.0.0.1:8000", "127.0.0.1:18000"}
 
-const defaultAuthenticationTimeout = 180 * time.Second
+const (
+	defaultAuthenticationTimeout = 180 * time.Second
+	// expiryDelta is how long before its expiry a token is considered expired, so that it does not expire in the middle of a request
+	expiryDelta = 30 * time.Second
+	// clientCredentialsCacheUser is the user in the token cache key of the client credentials flow, which distinguishes it from the authorization code flow of the same client
+	clientCredentialsCacheUser = "client-credentials"
+)
 
 // Manager is a client for an OIDC provider capable of authenticating users and retrieving ID tokens through
 //   - Authorization code grant flow using browser for interactive use
 //   - Resource owner password credentials flow for non-interactive use
+//   - Client credentials flow for non-interactive use by service principals, e.g. CI jobs
+// Alternatively, Manager provides a pre-issued ID token given directly or in a file, without any flow.
 // Manager implements the oauth2.TokenSource interface to interact with client libraries depending on the oauth2 package for obtaining auth token.
+// The tokens of all flows are cached in the local token cache directory, so that they are reused across invocations until they expire.
 type Manager interface {
 	GetTokenByAuthCode(ctx context.Context) (string, error)
 	GetTokenByROPC(ctx context.Context, username, password string) (string, error)
+	GetTokenByClientCredentials(ctx context.Context) (string, error)
 	TokenExpiry() time.Time
 	Token() (*oauth2.Token, error)
+	Invalidate()
+}
+
+// Options holds the optional settings which select the flow used by Manager.Token
+type Options struct {
+	// Username selects the resource owner password credentials flow
+	Username string
+	// ClientCredentials selects the client credentials flow
+	ClientCredentials bool
+	// Token is a pre-issued ID token used instead of any flow
+	Token string
+	// TokenFile is the path of a file containing a pre-issued ID token used instead of any flow.
+	// The file is read again whenever the token is needed, so it can be rotated by another process.
+	TokenFile string
 }
 
 type manager struct {
-	getter   *credentialplugin.GetToken
-	input    credentialplugin.Input
-	token    string
-	expiry   time.Time
-	mux      sync.Mutex
-	username string
+	getter     *credentialplugin.GetToken
+	input      credentialplugin.Input
+	tokenCache tokencache.Interface
+	httpClient *http.Client
+	options    Options
+	token      string
+	expiry     time.Time
+	// skipTokenCache makes the client credentials flow request a new token instead of using the cached one which was rejected
+	skipTokenCache bool
+	mux            sync.Mutex
 }
 
 type tokenWriter struct {
@@ -56,7 +92,7 @@ func (w *tokenWriter) Write(out credentialpluginwriter.Output) error {
 }
 
 // NewManager Constructs a new credential.Manager using the given OIDC provider and client credentials
-func NewManager(oidcIssuerURL, oidcClientID, oidcClientSecret, username string, log logger.Logger) Manager {
+func NewManager(oidcIssuerURL, oidcClientID, oidcClientSecret string, options Options, log logger.Logger) Manager {
 	clock := &clock.Real{}
 	reader := &reader.Reader{}
 	auth := &authentication.Authentication{
@@ -80,8 +116,11 @@ func NewManager(oidcIssuerURL, oidcClientID, oidcClientSecret, username string,
 		},
 	}
 
+	tokenCache := &tokencache.Repository{}
 	mgr := &manager{
-		username: username,
+		options:    options,
+		tokenCache: tokenCache,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
 		input: credentialplugin.Input{
 			IssuerURL:     oidcIssuerURL,
 			ClientID:      oidcClientID,
@@ -93,7 +132,7 @@ func NewManager(oidcIssuerURL, oidcClientID, oidcClientSecret, username string,
 	getToken := &credentialplugin.GetToken{
 		Logger:               log,
 		Authentication:       auth,
-		TokenCacheRepository: &tokencache.Repository{},
+		TokenCacheRepository: tokenCache,
 		Writer:               writer,
 		Mutex: &mutex.Mutex{
 			Logger: log,
@@ -138,12 +177,45 @@ func (mgr *manager) GetTokenByROPC(ctx context.Context, username, password strin
 	return mgr.token, nil
 }
 
-// Token uses auth code grant flow to obtain an ID token in oauth2.Token format. This method implements the oauth2.TokenSource interface
+// GetTokenByClientCredentials fetches an ID token from local cache if a valid token is found, or else requests a new ID token with the client credentials flow
+func (mgr *manager) GetTokenByClientCredentials(ctx context.Context) (string, error) {
+	mgr.mux.Lock()
+	defer mgr.mux.Unlock()
+	err := mgr.getTokenByClientCredentials(ctx)
+	if err != nil {
+		return "", err
+	}
+	return mgr.token, nil
+}
+
+// Token obtains an ID token in oauth2.Token format, using the pre-issued token if given,
+// or else the flow selected by the options: client credentials, resource owner password credentials, or auth code grant by default.
+// This method implements the oauth2.TokenSource interface
 func (mgr *manager) Token() (*oauth2.Token, error) {
+	if mgr.options.Token != "" || mgr.options.TokenFile != "" {
+		mgr.mux.Lock()
+		defer mgr.mux.Unlock()
+		err := mgr.readToken()
+		if err != nil {
+			return nil, err
+		}
+		return &oauth2.Token{AccessToken: mgr.token, Expiry: mgr.expiry}, nil
+	}
+
+	if mgr.options.ClientCredentials {
+		mgr.mux.Lock()
+		defer mgr.mux.Unlock()
+		err := mgr.getTokenByClientCredentials(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		return &oauth2.Token{AccessToken: mgr.token, Expiry: mgr.expiry}, nil
+	}
+
 	in := mgr.input
-	if mgr.username != "" {
+	if mgr.options.Username != "" {
 		in.GrantOptionSet.ROPCOption = &ropc.Option{
-			Username: mgr.username,
+			Username: mgr.options.Username,
 		}
 	} else {
 		in.GrantOptionSet.AuthCodeBrowserOption = &authcode.BrowserOption{
@@ -169,7 +241,137 @@ func (mgr *manager) TokenExpiry() time.Time {
 	return mgr.expiry
 }
 
+// Invalidate discards the current token, e.g. when it was rejected before its expiry, so that the next Token call obtains a new one.
+// This method implements the oauth.Invalidator interface of the KEB clients, which retry the rejected requests with the new token
+func (mgr *manager) Invalidate() {
+	mgr.mux.Lock()
+	defer mgr.mux.Unlock()
+	mgr.token = ""
+	mgr.expiry = time.Time{}
+	mgr.skipTokenCache = true
+}
+
 func (mgr *manager) cacheToken(token string, expiry time.Time) {
 	mgr.token = token
 	mgr.expiry = expiry
 }
+
+func (mgr *manager) validToken() bool {
+	return mgr.token != "" && time.Now().Add(expiryDelta).Before(mgr.expiry)
+}
+
+// readToken reads the pre-issued ID token, from the file if given
+func (mgr *manager) readToken() error {
+	token := mgr.options.Token
+	if mgr.options.TokenFile != "" {
+		data, err := ioutil.ReadFile(mgr.options.TokenFile)
+		if err != nil {
+			return errors.Wrap(err, "while reading token file")
+		}
+		token = string(data)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return errors.New("the pre-issued token is empty")
+	}
+
+	claims, err := oidc.TokenSet{IDToken: token}.DecodeWithoutVerify()
+	if err != nil {
+		return errors.Wrap(err, "while decoding pre-issued token")
+	}
+	if !claims.Expiry.IsZero() && time.Now().After(claims.Expiry) {
+		return fmt.Errorf("the pre-issued token expired at %s", claims.Expiry.Format(time.RFC3339))
+	}
+
+	mgr.cacheToken(token, claims.Expiry)
+	return nil
+}
+
+func (mgr *manager) clientCredentialsCacheKey() tokencache.Key {
+	return tokencache.Key{
+		IssuerURL:    mgr.input.IssuerURL,
+		ClientID:     mgr.input.ClientID,
+		ClientSecret: mgr.input.ClientSecret,
+		Username:     clientCredentialsCacheUser,
+	}
+}
+
+// getTokenByClientCredentials obtains an ID token with the client credentials flow, unless a valid one is held in memory or in the token cache
+func (mgr *manager) getTokenByClientCredentials(ctx context.Context) error {
+	if mgr.validToken() {
+		return nil
+	}
+
+	key := mgr.clientCredentialsCacheKey()
+	if !mgr.skipTokenCache {
+		if cached, err := mgr.tokenCache.FindByKey(mgr.input.TokenCacheDir, key); err == nil {
+			claims, err := cached.DecodeWithoutVerify()
+			if err == nil && time.Now().Add(expiryDelta).Before(claims.Expiry) {
+				mgr.cacheToken(cached.IDToken, claims.Expiry)
+				return nil
+			}
+		}
+	}
+
+	tokenURL, err := mgr.discoverTokenURL(ctx)
+	if err != nil {
+		return err
+	}
+	config := clientcredentials.Config{
+		ClientID:     mgr.input.ClientID,
+		ClientSecret: mgr.input.ClientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       []string{"openid"},
+	}
+	token, err := config.Token(context.WithValue(ctx, oauth2.HTTPClient, mgr.httpClient))
+	if err != nil {
+		return errors.Wrap(err, "while requesting token with client credentials")
+	}
+
+	// OIDC providers return the ID token next to the access token, the access token is used if it is a JWT itself
+	idToken, _ := token.Extra("id_token").(string)
+	if idToken == "" {
+		idToken = token.AccessToken
+	}
+	expiry := token.Expiry
+	if claims, err := (oidc.TokenSet{IDToken: idToken}).DecodeWithoutVerify(); err == nil {
+		expiry = claims.Expiry
+	}
+
+	err = mgr.tokenCache.Save(mgr.input.TokenCacheDir, key, oidc.TokenSet{IDToken: idToken})
+	if err != nil {
+		return errors.Wrap(err, "while writing token cache")
+	}
+	mgr.skipTokenCache = false
+	mgr.cacheToken(idToken, expiry)
+	return nil
+}
+
+// discoverTokenURL returns the token endpoint of the OIDC provider from its discovery document
+func (mgr *manager) discoverTokenURL(ctx context.Context) (string, error) {
+	discoveryURL := strings.TrimSuffix(mgr.input.IssuerURL, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "while creating OIDC discovery request")
+	}
+	resp, err := mgr.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrapf(err, "while calling %s", discoveryURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("calling %s returned %s status", discoveryURL, resp.Status)
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&discovery)
+	if err != nil {
+		return "", errors.Wrap(err, "while decoding OIDC discovery document")
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("OIDC discovery document of %s has no token endpoint", mgr.input.IssuerURL)
+	}
+	return discovery.TokenEndpoint, nil
+}
@@ -7,7 +7,7 @@ import (
 )
 
 var jsonRegexp = regexp.MustCompile(`^\{\.?([^{}]+)\}$|^\.?([^{}]+)$`)
-var templateFormat = []string{"custom="}
+var templateFormat = []string{"custom=", "jsonpath=", "go-template="}
 
 // RelaxedJSONPathExpression attempts to be flexible with JSONPath expressions, it accepts:
 //   * metadata.name (no leading '.' or curly braces '{...}'
@@ -39,6 +39,7 @@ func RelaxedJSONPathExpression(pathExpression string) (string, error) {
 //ParseOutputToTemplateTypeAndElement parses the output into templateType and templateElement
 //e.g. kcp runtimes  -o custom="INSTANCE ID:instanceID,SHOOTNAME:shootName"
 //After parsing, the templateType = "custom" and  templateElement = "INSTANCE ID:instanceID,SHOOTNAME:shootName"
+//The jsonpath= and go-template= outputs are parsed in the same way, e.g. the templateType of -o jsonpath="{.data[*].shootName}" is "jsonpath"
 func ParseOutputToTemplateTypeAndElement(output string) (string, string) {
 	var templateType, templateElement string
 	for _, format := range templateFormat {
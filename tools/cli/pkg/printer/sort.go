@@ -0,0 +1,77 @@
+package printer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// SortObjects sorts the given slice of objects in place by the field selected by the JSONPath expression, as kubectl does with the --sort-by option.
+// The field is selected from the JSON representation of the objects, e.g. shootName or {.status.createdAt}.
+// Numbers are compared numerically, other values by their string representation. The objects missing the field come first.
+func SortObjects(objs interface{}, fieldSpec string) error {
+	s := reflect.ValueOf(objs)
+	if s.Kind() != reflect.Slice {
+		return fmt.Errorf("expected a slice of objects to sort, got %s", s.Kind())
+	}
+	expression, err := RelaxedJSONPathExpression(fieldSpec)
+	if err != nil {
+		return err
+	}
+	parser := jsonpath.New("sort").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return errors.Wrap(err, "while parsing sort field")
+	}
+
+	keys := make([]interface{}, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		generic, err := toGeneric(s.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+		results, err := parser.FindResults(generic)
+		if err != nil {
+			return errors.Wrapf(err, "while finding sort field %s", fieldSpec)
+		}
+		if len(results) > 0 && len(results[0]) > 0 {
+			keys[i] = results[0][0].Interface()
+		}
+	}
+
+	sort.Stable(&objectSorter{keys: keys, swap: reflect.Swapper(objs)})
+	return nil
+}
+
+// objectSorter sorts the objects by their precomputed keys, swapping the keys along with the objects
+type objectSorter struct {
+	keys []interface{}
+	swap func(i, j int)
+}
+
+func (o *objectSorter) Len() int {
+	return len(o.keys)
+}
+
+func (o *objectSorter) Less(i, j int) bool {
+	a, b := o.keys[i], o.keys[j]
+	switch {
+	case a == nil:
+		return b != nil
+	case b == nil:
+		return false
+	}
+	af, aok := a.(float64)
+	bf, bok := b.(float64)
+	if aok && bok {
+		return af < bf
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+func (o *objectSorter) Swap(i, j int) {
+	o.keys[i], o.keys[j] = o.keys[j], o.keys[i]
+	o.swap(i, j)
+}
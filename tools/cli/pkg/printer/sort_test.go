@@ -0,0 +1,85 @@
+package printer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testStatus struct {
+	Count int `json:"count"`
+}
+
+type testObject struct {
+	Name   string      `json:"name"`
+	Status *testStatus `json:"status,omitempty"`
+}
+
+func TestSortObjects(t *testing.T) {
+	tests := []struct {
+		name      string
+		objs      []testObject
+		fieldSpec string
+		want      []string
+	}{
+		{
+			name:      "by string field",
+			objs:      []testObject{{Name: "c"}, {Name: "a"}, {Name: "b"}},
+			fieldSpec: "name",
+			want:      []string{"a", "b", "c"},
+		},
+		{
+			name:      "by numeric field in JSONPath expression",
+			objs:      []testObject{{Name: "ten", Status: &testStatus{Count: 10}}, {Name: "two", Status: &testStatus{Count: 2}}, {Name: "one", Status: &testStatus{Count: 1}}},
+			fieldSpec: "{.status.count}",
+			want:      []string{"one", "two", "ten"},
+		},
+		{
+			name:      "missing field first and stable order",
+			objs:      []testObject{{Name: "b", Status: &testStatus{Count: 1}}, {Name: "x"}, {Name: "a", Status: &testStatus{Count: 1}}, {Name: "y"}},
+			fieldSpec: ".status.count",
+			want:      []string{"x", "y", "b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := SortObjects(tt.objs, tt.fieldSpec)
+
+			// then
+			require.NoError(t, err)
+			names := make([]string, 0, len(tt.objs))
+			for _, obj := range tt.objs {
+				names = append(names, obj.Name)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestSortObjects_errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		objs      interface{}
+		fieldSpec string
+	}{
+		{
+			name:      "not a slice",
+			objs:      testObject{Name: "a"},
+			fieldSpec: "name",
+		},
+		{
+			name:      "invalid field",
+			objs:      []testObject{{Name: "a"}},
+			fieldSpec: "{.name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, SortObjects(tt.objs, tt.fieldSpec))
+		})
+	}
+}
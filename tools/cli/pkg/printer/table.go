@@ -18,6 +18,13 @@ const (
 	tabwriterFlags    = tabwriter.RememberWidths
 )
 
+// The highlighted rows are printed in reverse video. Every row starts with an escape sequence of the same length,
+// either turning the highlighting on or resetting it, so that the escape sequences do not break the alignment of the columns.
+const (
+	highlightOn  = "\033[7m"
+	highlightOff = "\033[0m"
+)
+
 // newTabWriter returns a tabwriter that translates tabbed columns in input into properly aligned text.
 func newTabWriter(output io.Writer) *tabwriter.Writer {
 	return tabwriter.NewWriter(output, tabwriterMinWidth, tabwriterWidth, tabwriterPadding, tabwriterPadChar, tabwriterFlags)
@@ -26,6 +33,9 @@ func newTabWriter(output io.Writer) *tabwriter.Writer {
 // FieldFormatterFunc is a function type to format and return the string representation of an object field.
 type FieldFormatterFunc func(obj interface{}) string
 
+// HighlightFunc is a function type to decide whether the row of an object is highlighted.
+type HighlightFunc func(obj interface{}) bool
+
 // Column represents a user specified column.
 // If FieldFormatter is not nil, it takes precedence over FieldSpec
 type Column struct {
@@ -47,16 +57,24 @@ type tablePrinter struct {
 	columns        []Column
 	noHeaders      bool
 	headersPrinted bool
+	highlight      HighlightFunc
 }
 
 // NewTablePrinter creates a new TablePrinter.
 // The parameter columns holds the non-empty list of Column specifications which comprises the table.
 // If the parameter noHeaders is true, the first header row will not be displayed.
 func NewTablePrinter(columns []Column, noHeaders bool) (TablePrinter, error) {
+	return NewHighlightingTablePrinter(columns, noHeaders, os.Stdout, nil)
+}
+
+// NewHighlightingTablePrinter creates a new TablePrinter which prints to the given output.
+// If the parameter highlight is not nil, the rows of the objects for which it returns true are highlighted, e.g. the changed ones in watch mode.
+func NewHighlightingTablePrinter(columns []Column, noHeaders bool, output io.Writer, highlight HighlightFunc) (TablePrinter, error) {
 	t := &tablePrinter{
-		writer:    newTabWriter(os.Stdout),
+		writer:    newTabWriter(output),
 		columns:   columns,
 		noHeaders: noHeaders,
+		highlight: highlight,
 	}
 	for idx := range t.columns {
 		if t.columns[idx].FieldFormatter == nil && t.columns[idx].FieldSpec != "" {
@@ -108,6 +126,9 @@ func toInterfaceSlice(obj interface{}) []interface{} {
 }
 
 func (t *tablePrinter) printHeader() {
+	if t.highlight != nil {
+		fmt.Fprint(t.writer, highlightOff)
+	}
 	for idx := range t.columns {
 		fmt.Fprintf(t.writer, "%s\t", t.columns[idx].Header)
 	}
@@ -115,6 +136,13 @@ func (t *tablePrinter) printHeader() {
 }
 
 func (t *tablePrinter) printOneObj(obj interface{}) error {
+	if t.highlight != nil {
+		if t.highlight(obj) {
+			fmt.Fprint(t.writer, highlightOn)
+		} else {
+			fmt.Fprint(t.writer, highlightOff)
+		}
+	}
 	for idx := range t.columns {
 		if t.columns[idx].FieldFormatter != nil {
 			fmt.Fprintf(t.writer, "%s\t", t.columns[idx].FieldFormatter(obj))
@@ -127,6 +155,9 @@ func (t *tablePrinter) printOneObj(obj interface{}) error {
 		}
 	}
 
+	if t.highlight != nil {
+		fmt.Fprint(t.writer, highlightOff)
+	}
 	fmt.Fprint(t.writer, "\n")
 	return nil
 }
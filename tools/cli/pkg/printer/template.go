@@ -0,0 +1,88 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// TemplatePrinter prints objects according to a user specified JSONPath expression or Go template, as kubectl does with the jsonpath= and go-template= outputs.
+// The template is applied to the JSON representation of the objects, so the field names are the same as in the JSON output, e.g. {.data[*].shootName}.
+type TemplatePrinter interface {
+	PrintObj(obj interface{}) error
+}
+
+type jsonPathPrinter struct {
+	output io.Writer
+	parser *jsonpath.JSONPath
+}
+
+type goTemplatePrinter struct {
+	output   io.Writer
+	template *template.Template
+}
+
+// NewJSONPathPrinter creates a new TemplatePrinter for the given JSONPath expression, e.g. {.data[*].shootName} or {range .data[*]}{.shootName}{"\n"}{end}
+func NewJSONPathPrinter(expression string) (TemplatePrinter, error) {
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, errors.Wrap(err, "while parsing jsonpath expression")
+	}
+
+	return &jsonPathPrinter{output: os.Stdout, parser: parser}, nil
+}
+
+// NewGoTemplatePrinter creates a new TemplatePrinter for the given Go template, e.g. {{range .data}}{{.shootName}}{{"\n"}}{{end}}
+func NewGoTemplatePrinter(text string) (TemplatePrinter, error) {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "while parsing go template")
+	}
+
+	return &goTemplatePrinter{output: os.Stdout, template: tmpl}, nil
+}
+
+func (p jsonPathPrinter) PrintObj(obj interface{}) error {
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return err
+	}
+	err = p.parser.Execute(p.output, generic)
+	if err != nil {
+		return errors.Wrap(err, "while executing jsonpath expression")
+	}
+	fmt.Fprintln(p.output)
+	return nil
+}
+
+func (p goTemplatePrinter) PrintObj(obj interface{}) error {
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return err
+	}
+	err = p.template.Execute(p.output, generic)
+	if err != nil {
+		return errors.Wrap(err, "while executing go template")
+	}
+	return nil
+}
+
+// toGeneric converts the object to its JSON representation consisting of maps, slices and primitive values
+func toGeneric(obj interface{}) (interface{}, error) {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, "while converting object to JSON")
+	}
+	var generic interface{}
+	err = json.Unmarshal(data, &generic)
+	if err != nil {
+		return nil, errors.Wrap(err, "while converting object from JSON")
+	}
+
+	return generic, nil
+}
@@ -0,0 +1,112 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPage struct {
+	Data  []testObject `json:"data"`
+	Count int          `json:"count"`
+}
+
+var testTemplatePage = testPage{
+	Data:  []testObject{{Name: "a", Status: &testStatus{Count: 1}}, {Name: "b"}},
+	Count: 2,
+}
+
+func TestJSONPathPrinter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       string
+	}{
+		{
+			name:       "field of all items",
+			expression: "{.data[*].name}",
+			want:       "a b\n",
+		},
+		{
+			name:       "range over items",
+			expression: `{range .data[*]}{.name}={.status.count}{"\n"}{end}`,
+			want:       "a=1\nb=\n\n",
+		},
+		{
+			name:       "missing field",
+			expression: "{.missing}",
+			want:       "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := &bytes.Buffer{}
+			p, err := NewJSONPathPrinter(tt.expression)
+			require.NoError(t, err)
+			p.(*jsonPathPrinter).output = buf
+
+			// when
+			err = p.PrintObj(testTemplatePage)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	_, err := NewJSONPathPrinter("{.data[*]")
+	assert.Error(t, err)
+}
+
+func TestGoTemplatePrinter(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "JSON field names",
+			text: `{{range .data}}{{.name}}{{"\n"}}{{end}}`,
+			want: "a\nb\n",
+		},
+		{
+			name: "numbers",
+			text: "{{.count}}",
+			want: "2",
+		},
+		{
+			name:    "execution error",
+			text:    "{{index .data 5}}",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := &bytes.Buffer{}
+			p, err := NewGoTemplatePrinter(tt.text)
+			require.NoError(t, err)
+			p.(*goTemplatePrinter).output = buf
+
+			// when
+			err = p.PrintObj(testTemplatePage)
+
+			// then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	_, err := NewGoTemplatePrinter("{{.data")
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package printer
+
+import (
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// YAMLPrinter prints objects in YAML format
+type YAMLPrinter interface {
+	PrintObj(obj interface{}) error
+}
+
+type yamlPrinter struct {
+	output io.Writer
+}
+
+// NewYAMLPrinter creates a new YAMLPrinter.
+// The objects are converted to YAML through their JSON representation, so the field names are the same as in the JSON output.
+func NewYAMLPrinter() YAMLPrinter {
+	return &yamlPrinter{output: os.Stdout}
+}
+
+func (y yamlPrinter) PrintObj(obj interface{}) error {
+	generic, err := toGeneric(obj)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(generic)
+	if err != nil {
+		return errors.Wrap(err, "while converting object to YAML")
+	}
+	_, err = y.output.Write(data)
+	return err
+}
@@ -0,0 +1,59 @@
+package printer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLPrinter(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "JSON field names",
+			obj:  testObject{Name: "a", Status: &testStatus{Count: 1}},
+			want: "name: a\nstatus:\n  count: 1\n",
+		},
+		{
+			name: "omitted empty fields",
+			obj:  testObject{Name: "a"},
+			want: "name: a\n",
+		},
+		{
+			name: "list",
+			obj:  []testObject{{Name: "a"}, {Name: "b"}},
+			want: "- name: a\n- name: b\n",
+		},
+		{
+			name:    "object not convertible to JSON",
+			obj:     make(chan int),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			buf := &bytes.Buffer{}
+			p := NewYAMLPrinter()
+			p.(*yamlPrinter).output = buf
+
+			// when
+			err := p.PrintObj(tt.obj)
+
+			// then
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}